@@ -0,0 +1,33 @@
+{{/*
+Expand the name of the chart.
+*/}}
+{{- define "ck-node-local-dns.name" -}}
+{{- default "node-local-dns" .Values.nameOverride | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Create chart name and version as used by the chart label.
+*/}}
+{{- define "ck-node-local-dns.chart" -}}
+{{- printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Common labels
+*/}}
+{{- define "ck-node-local-dns.labels" -}}
+helm.sh/chart: {{ include "ck-node-local-dns.chart" . }}
+{{ include "ck-node-local-dns.selectorLabels" . }}
+{{- if .Chart.AppVersion }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+{{- end }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end }}
+
+{{/*
+Selector labels
+*/}}
+{{- define "ck-node-local-dns.selectorLabels" -}}
+app.kubernetes.io/name: {{ include "ck-node-local-dns.name" . }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end }}
@@ -0,0 +1,26 @@
+package v1
+
+// APIServerProxyStatus is the status of the k8s-apiserver-proxy of a worker node.
+// It is served by the status endpoint of the proxy.
+type APIServerProxyStatus struct {
+	// ListenAddress is the address where the proxy accepts connections.
+	ListenAddress string `json:"listen-address" yaml:"listen-address"`
+	// Strategy is how the proxy picks the kube-apiserver endpoint for new connections.
+	Strategy string `json:"strategy" yaml:"strategy"`
+	// Endpoints are the kube-apiserver endpoints known to the proxy.
+	Endpoints []APIServerProxyEndpoint `json:"endpoints" yaml:"endpoints"`
+}
+
+// APIServerProxyEndpoint is the status of a kube-apiserver endpoint of the k8s-apiserver-proxy.
+type APIServerProxyEndpoint struct {
+	// Address is the "host:port" address of the kube-apiserver.
+	Address string `json:"address" yaml:"address"`
+	// Active is true if the endpoint receives new connections.
+	Active bool `json:"active" yaml:"active"`
+	// Connections is the number of open connections to the endpoint.
+	Connections int `json:"connections" yaml:"connections"`
+	// ConnectionsTotal is the number of connections to the endpoint since the proxy started using it.
+	ConnectionsTotal uint64 `json:"connections-total" yaml:"connections-total"`
+	// DialFailuresTotal is the number of failed connection attempts to the endpoint.
+	DialFailuresTotal uint64 `json:"dial-failures-total" yaml:"dial-failures-total"`
+}
@@ -26,6 +26,9 @@ type BootstrapConfig struct {
 	// Seed configuration for certificates
 	ExtraSANs []string `json:"extra-sans,omitempty" yaml:"extra-sans,omitempty"`
 
+	// Zone is the failure domain of the node. It is set as the "topology.kubernetes.io/zone" label of the node.
+	Zone *string `json:"zone,omitempty" yaml:"zone,omitempty"`
+
 	// Seed configuration for external certificates (cluster-wide)
 	CACert                          *string `json:"ca-crt,omitempty" yaml:"ca-crt,omitempty"`
 	CAKey                           *string `json:"ca-key,omitempty" yaml:"ca-key,omitempty"`
@@ -61,6 +64,7 @@ func (b *BootstrapConfig) GetDatastoreCACert() string      { return getField(b.D
 func (b *BootstrapConfig) GetDatastoreClientCert() string  { return getField(b.DatastoreClientCert) }
 func (b *BootstrapConfig) GetDatastoreClientKey() string   { return getField(b.DatastoreClientKey) }
 func (b *BootstrapConfig) GetK8sDqlitePort() int           { return getField(b.K8sDqlitePort) }
+func (b *BootstrapConfig) GetZone() string                 { return getField(b.Zone) }
 func (b *BootstrapConfig) GetCACert() string               { return getField(b.CACert) }
 func (b *BootstrapConfig) GetCAKey() string                { return getField(b.CAKey) }
 func (b *BootstrapConfig) GetClientCACert() string         { return getField(b.ClientCACert) }
@@ -1,7 +1,11 @@
 package v1
 
 // GetClusterStatusRequest is used to request the current status of the cluster.
-type GetClusterStatusRequest struct{}
+type GetClusterStatusRequest struct {
+	// Detailed requests the Kubernetes node status and the service health of each member.
+	// The members also include worker nodes that are registered in Kubernetes.
+	Detailed bool `json:"detailed,omitempty"`
+}
 
 // GetClusterStatusResponse is the response for "GET 1.0/k8sd/cluster".
 type GetClusterStatusResponse struct {
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -10,25 +11,83 @@ type GetClusterConfigRequest struct{}
 
 type GetClusterConfigResponse struct {
 	Config UserFacingClusterConfig
+	// Revision is the latest revision of the cluster configuration, 0 if the configuration was never updated.
+	Revision int
 }
 
 type UpdateClusterConfigRequest struct {
 	Config    UserFacingClusterConfig   `json:"config,omitempty" yaml:"config,omitempty"`
 	Datastore UserFacingDatastoreConfig `json:"datastore,omitempty" yaml:"datastore,omitempty"`
+	// Unset lists options of the cluster configuration that are cleared, e.g. "load-balancer.bgp-local-asn".
+	// Options that are set in Config are applied before the options in Unset are cleared.
+	Unset []string `json:"unset,omitempty" yaml:"unset,omitempty"`
+	// ExpectedRevision rejects the update with a conflict if the latest revision of the cluster configuration is different.
+	// This is used to prevent concurrent read-modify-write updates from overwriting each other.
+	ExpectedRevision *int `json:"expected-revision,omitempty" yaml:"expected-revision,omitempty"`
 }
 
 type UpdateClusterConfigResponse struct {
+	// Revision is the revision that was created by the update.
+	Revision int `json:"revision,omitempty"`
+}
+
+// GetClusterConfigRevisionsRequest is used to list the revisions of the cluster configuration.
+type GetClusterConfigRevisionsRequest struct{}
+
+// GetClusterConfigRevisionsResponse is the response for "GET 1.0/k8sd/cluster/config/revisions".
+type GetClusterConfigRevisionsResponse struct {
+	// Revisions are sorted from oldest to newest.
+	Revisions []ClusterConfigRevision `json:"revisions"`
+}
+
+// RollbackClusterConfigRequest is used to restore the cluster configuration of a previous revision.
+type RollbackClusterConfigRequest struct {
+	// Revision is the revision to restore.
+	Revision int `json:"revision"`
+}
+
+// RollbackClusterConfigResponse is the response for "POST 1.0/k8sd/cluster/config/rollback".
+type RollbackClusterConfigResponse struct{}
+
+// ClusterConfigRevision is a change of the cluster configuration.
+type ClusterConfigRevision struct {
+	// Revision is the sequence number of the revision.
+	Revision int `json:"revision" yaml:"revision"`
+	// CreatedAt is the time the change was made.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+	// Identity describes who requested the change.
+	Identity string `json:"identity" yaml:"identity"`
+	// Changes are the options that were changed by the revision.
+	Changes []ClusterConfigChange `json:"changes" yaml:"changes"`
+	// Config is the cluster configuration after the change.
+	Config UserFacingClusterConfig `json:"config" yaml:"config"`
+}
+
+// ClusterConfigChange is a change of a single cluster configuration option.
+type ClusterConfigChange struct {
+	// Key is the dotted path of the option, e.g. "load-balancer.cidrs".
+	Key string `json:"key" yaml:"key"`
+	// Old is the previous value. It is nil if the option was not set.
+	Old any `json:"old,omitempty" yaml:"old,omitempty"`
+	// New is the new value. It is nil if the option was removed.
+	New any `json:"new,omitempty" yaml:"new,omitempty"`
 }
 
 type UserFacingClusterConfig struct {
 	Network       NetworkConfig       `json:"network,omitempty" yaml:"network,omitempty"`
 	DNS           DNSConfig           `json:"dns,omitempty" yaml:"dns,omitempty"`
+	NodeLocalDNS  NodeLocalDNSConfig  `json:"node-local-dns,omitempty" yaml:"node-local-dns,omitempty"`
 	Ingress       IngressConfig       `json:"ingress,omitempty" yaml:"ingress,omitempty"`
 	LoadBalancer  LoadBalancerConfig  `json:"load-balancer,omitempty" yaml:"load-balancer,omitempty"`
 	LocalStorage  LocalStorageConfig  `json:"local-storage,omitempty" yaml:"local-storage,omitempty"`
 	Gateway       GatewayConfig       `json:"gateway,omitempty" yaml:"gateway,omitempty"`
 	MetricsServer MetricsServerConfig `json:"metrics-server,omitempty" yaml:"metrics-server,omitempty"`
 	CloudProvider *string             `json:"cloud-provider,omitempty" yaml:"cloud-provider,omitempty"`
+	Containerd    ContainerdConfig    `json:"containerd,omitempty" yaml:"containerd,omitempty"`
+	// ImageRegistry overrides the registry of all images deployed by the cluster, e.g. "registry.internal:5000".
+	ImageRegistry *string `json:"image-registry,omitempty" yaml:"image-registry,omitempty"`
+	// ControlPlaneEndpoint is an external endpoint of the control plane, e.g. a load balancer or a VIP.
+	ControlPlaneEndpoint ControlPlaneEndpointConfig `json:"control-plane-endpoint,omitempty" yaml:"control-plane-endpoint,omitempty"`
 }
 
 type DNSConfig struct {
@@ -36,12 +95,72 @@ type DNSConfig struct {
 	ClusterDomain       *string   `json:"cluster-domain,omitempty" yaml:"cluster-domain,omitempty"`
 	ServiceIP           *string   `json:"service-ip,omitempty" yaml:"service-ip,omitempty"`
 	UpstreamNameservers *[]string `json:"upstream-nameservers,omitempty" yaml:"upstream-nameservers,omitempty"`
+	// CacheTTL is the maximum TTL in seconds of cached DNS responses. Set to 0 to disable caching.
+	CacheTTL *int `json:"cache-ttl,omitempty" yaml:"cache-ttl,omitempty"`
+	// StubZones forwards queries for specific domains to dedicated nameservers.
+	StubZones *[]DNSStubZoneConfig `json:"stub-zones,omitempty" yaml:"stub-zones,omitempty"`
+	// Hosts are static host entries that are resolved by the cluster DNS.
+	Hosts *[]DNSHostConfig `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	// ExtraPlugins are additional CoreDNS plugins for the cluster domain server block.
+	ExtraPlugins *[]DNSPluginConfig `json:"extra-plugins,omitempty" yaml:"extra-plugins,omitempty"`
+	// ExtraServers are additional CoreDNS server blocks.
+	ExtraServers *[]DNSServerConfig `json:"extra-servers,omitempty" yaml:"extra-servers,omitempty"`
+}
+
+func (c DNSConfig) GetEnabled() bool                   { return getField(c.Enabled) }
+func (c DNSConfig) GetClusterDomain() string           { return getField(c.ClusterDomain) }
+func (c DNSConfig) GetServiceIP() string               { return getField(c.ServiceIP) }
+func (c DNSConfig) GetUpstreamNameservers() []string   { return getField(c.UpstreamNameservers) }
+func (c DNSConfig) GetCacheTTL() int                   { return getField(c.CacheTTL) }
+func (c DNSConfig) GetStubZones() []DNSStubZoneConfig  { return getField(c.StubZones) }
+func (c DNSConfig) GetHosts() []DNSHostConfig          { return getField(c.Hosts) }
+func (c DNSConfig) GetExtraPlugins() []DNSPluginConfig { return getField(c.ExtraPlugins) }
+func (c DNSConfig) GetExtraServers() []DNSServerConfig { return getField(c.ExtraServers) }
+
+// DNSStubZoneConfig forwards queries for a domain to a list of nameservers.
+type DNSStubZoneConfig struct {
+	// Zone is the domain to forward, e.g. "corp.internal".
+	Zone string `json:"zone" yaml:"zone"`
+	// Nameservers is the list of nameserver addresses, e.g. "10.0.0.10" or "10.0.0.10:5353".
+	Nameservers []string `json:"nameservers" yaml:"nameservers"`
+}
+
+// DNSHostConfig is a static host entry.
+type DNSHostConfig struct {
+	// IP is the address the hostnames resolve to.
+	IP string `json:"ip" yaml:"ip"`
+	// Hostnames is the list of names that resolve to IP.
+	Hostnames []string `json:"hostnames" yaml:"hostnames"`
+}
+
+// DNSPluginConfig is a CoreDNS plugin in a server block.
+type DNSPluginConfig struct {
+	// Name of the plugin, e.g. "log".
+	Name string `json:"name" yaml:"name"`
+	// Parameters are appended to the plugin name, e.g. ". 10.0.0.10" for "forward".
+	Parameters string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// ConfigBlock is the raw Corefile snippet that is placed in the braces after the plugin.
+	ConfigBlock string `json:"config-block,omitempty" yaml:"config-block,omitempty"`
+}
+
+// DNSServerConfig is an additional CoreDNS server block.
+type DNSServerConfig struct {
+	// Zones served by the server block, e.g. "example.com".
+	Zones []string `json:"zones" yaml:"zones"`
+	// Port the server block listens on. Defaults to 53.
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+	// Plugins of the server block.
+	Plugins []DNSPluginConfig `json:"plugins" yaml:"plugins"`
+}
+
+type NodeLocalDNSConfig struct {
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// LocalIP is the link-local address the node-local DNS cache listens on. Defaults to "169.254.20.10".
+	LocalIP *string `json:"local-ip,omitempty" yaml:"local-ip,omitempty"`
 }
 
-func (c DNSConfig) GetEnabled() bool                 { return getField(c.Enabled) }
-func (c DNSConfig) GetClusterDomain() string         { return getField(c.ClusterDomain) }
-func (c DNSConfig) GetServiceIP() string             { return getField(c.ServiceIP) }
-func (c DNSConfig) GetUpstreamNameservers() []string { return getField(c.UpstreamNameservers) }
+func (c NodeLocalDNSConfig) GetEnabled() bool   { return getField(c.Enabled) }
+func (c NodeLocalDNSConfig) GetLocalIP() string { return getField(c.LocalIP) }
 
 type IngressConfig struct {
 	Enabled             *bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
@@ -105,6 +224,65 @@ type MetricsServerConfig struct {
 
 func (c MetricsServerConfig) GetEnabled() bool { return getField(c.Enabled) }
 
+type ControlPlaneEndpointConfig struct {
+	// Address is the host or host:port of the endpoint, e.g. "10.0.0.100" or "k8s.internal:443".
+	// The port defaults to the kube-apiserver port. The host is added to the kube-apiserver certificates.
+	Address *string `json:"address,omitempty" yaml:"address,omitempty"`
+	// UseForWorkers makes worker nodes use the endpoint instead of the kube-apiserver endpoints of the control plane nodes.
+	UseForWorkers *bool `json:"use-for-workers,omitempty" yaml:"use-for-workers,omitempty"`
+}
+
+func (c ControlPlaneEndpointConfig) GetAddress() string     { return getField(c.Address) }
+func (c ControlPlaneEndpointConfig) GetUseForWorkers() bool { return getField(c.UseForWorkers) }
+
+type ContainerdConfig struct {
+	Registries      *[]ContainerdRegistryConfig       `json:"registries,omitempty" yaml:"registries,omitempty"`
+	RuntimeHandlers *[]ContainerdRuntimeHandlerConfig `json:"runtime-handlers,omitempty" yaml:"runtime-handlers,omitempty"`
+}
+
+func (c ContainerdConfig) GetRegistries() []ContainerdRegistryConfig { return getField(c.Registries) }
+func (c ContainerdConfig) GetRuntimeHandlers() []ContainerdRuntimeHandlerConfig {
+	return getField(c.RuntimeHandlers)
+}
+
+// ContainerdRegistryConfig configures mirrors, authentication and TLS for an image registry.
+type ContainerdRegistryConfig struct {
+	// Host is the name of the registry, e.g. "docker.io".
+	Host string `json:"host" yaml:"host"`
+	// URLs is the list of endpoints to use for pulling images from the registry.
+	URLs []string `json:"urls,omitempty" yaml:"urls,omitempty"`
+	// Username and Password are used for basic authentication with the registry.
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	// Token is used for token authentication with the registry.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+	// OverridePath is set when the URLs include the API root path.
+	OverridePath bool `json:"override-path,omitempty" yaml:"override-path,omitempty"`
+	// SkipVerify disables TLS certificate verification for the registry endpoints.
+	SkipVerify bool `json:"skip-verify,omitempty" yaml:"skip-verify,omitempty"`
+	// Capabilities of the registry endpoints. One or more of "pull", "resolve", "push".
+	Capabilities []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	// CACert is the PEM encoded CA certificate to verify the registry endpoints.
+	CACert string `json:"ca-crt,omitempty" yaml:"ca-crt,omitempty"`
+	// ClientCert and ClientKey are the PEM encoded certificate and key to authenticate with the registry endpoints.
+	ClientCert string `json:"client-crt,omitempty" yaml:"client-crt,omitempty"`
+	ClientKey  string `json:"client-key,omitempty" yaml:"client-key,omitempty"`
+}
+
+// ContainerdRuntimeHandlerConfig configures an additional containerd runtime handler and its matching RuntimeClass.
+type ContainerdRuntimeHandlerConfig struct {
+	// Name of the runtime handler. It is also used as the name of the RuntimeClass.
+	Name string `json:"name" yaml:"name"`
+	// RuntimeType is the containerd runtime type, e.g. "io.containerd.runsc.v1". Defaults to "io.containerd.runc.v2".
+	RuntimeType string `json:"runtime-type,omitempty" yaml:"runtime-type,omitempty"`
+	// BinaryPath is the path to the runtime shim binary on the nodes.
+	BinaryPath string `json:"binary-path,omitempty" yaml:"binary-path,omitempty"`
+	// Options are passed to the runtime handler as-is.
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+	// NodeSelector restricts scheduling of pods using the RuntimeClass to matching nodes.
+	NodeSelector map[string]string `json:"node-selector,omitempty" yaml:"node-selector,omitempty"`
+}
+
 type UserFacingDatastoreConfig struct {
 	// Type of the datastore. Needs to be "external".
 	Type       *string   `json:"type,omitempty" yaml:"type,omitempty"`
@@ -120,6 +298,8 @@ func (c UserFacingDatastoreConfig) GetCACert() string     { return getField(c.CA
 func (c UserFacingDatastoreConfig) GetClientCert() string { return getField(c.ClientCert) }
 func (c UserFacingDatastoreConfig) GetClientKey() string  { return getField(c.ClientKey) }
 
+func (c UserFacingClusterConfig) GetImageRegistry() string { return getField(c.ImageRegistry) }
+
 func (c UserFacingClusterConfig) String() string {
 	b, err := yaml.Marshal(c)
 	if err != nil {
@@ -144,6 +324,14 @@ func (c DNSConfig) String() string {
 	return string(b)
 }
 
+func (c NodeLocalDNSConfig) String() string {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("%#v\n", c)
+	}
+	return string(b)
+}
+
 func (c IngressConfig) String() string {
 	b, err := yaml.Marshal(c)
 	if err != nil {
@@ -176,6 +364,22 @@ func (c GatewayConfig) String() string {
 	return string(b)
 }
 
+func (c ContainerdConfig) String() string {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("%#v\n", c)
+	}
+	return string(b)
+}
+
+func (c ControlPlaneEndpointConfig) String() string {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("%#v\n", c)
+	}
+	return string(b)
+}
+
 func (c MetricsServerConfig) String() string {
 	b, err := yaml.Marshal(c)
 	if err != nil {
@@ -1,5 +1,7 @@
 package v1
 
+import "time"
+
 // JoinClusterRequest is used to request to add a node to the cluster.
 type JoinClusterRequest struct {
 	Name    string `json:"name"`
@@ -12,4 +14,28 @@ type JoinClusterRequest struct {
 type RemoveNodeRequest struct {
 	Name  string `json:"name"`
 	Force bool   `json:"force"`
+	// Drain cordons the node and evicts its pods before removing it. Evictions honor PodDisruptionBudgets.
+	// If Force is set, the node is removed even if draining fails.
+	Drain bool `json:"drain,omitempty"`
+	// DrainTimeout is how long to wait for the pods to be evicted. Zero waits until the request is cancelled.
+	DrainTimeout time.Duration `json:"drain-timeout,omitempty"`
+	// GracePeriod overrides the termination grace period in seconds of the evicted pods. Nil uses the grace period of each pod.
+	GracePeriod *int `json:"grace-period,omitempty"`
+	// IgnoreDaemonSets skips pods that are managed by a DaemonSet. If false, draining fails if there are any.
+	IgnoreDaemonSets bool `json:"ignore-daemonsets,omitempty"`
+}
+
+// NodeMaintenanceRequest is used to request a node to enter or exit maintenance.
+type NodeMaintenanceRequest struct {
+	Name string `json:"name"`
+	// Maintenance is true to enter maintenance and false to exit it.
+	// Entering maintenance cordons and drains the node, and demotes control plane nodes from k8s-dqlite voters.
+	// Exiting maintenance reverts these changes.
+	Maintenance bool `json:"maintenance"`
+	// DrainTimeout is how long to wait for the pods to be evicted. Zero waits until the request is cancelled.
+	DrainTimeout time.Duration `json:"drain-timeout,omitempty"`
+	// GracePeriod overrides the termination grace period in seconds of the evicted pods. Nil uses the grace period of each pod.
+	GracePeriod *int `json:"grace-period,omitempty"`
+	// IgnoreDaemonSets skips pods that are managed by a DaemonSet. If false, draining fails if there are any.
+	IgnoreDaemonSets bool `json:"ignore-daemonsets,omitempty"`
 }
@@ -0,0 +1,84 @@
+package v1
+
+import "time"
+
+// DatastoreMember is a member of the k8s-dqlite cluster.
+type DatastoreMember struct {
+	// ID is the dqlite ID of the member.
+	ID uint64 `json:"id" yaml:"id"`
+	// Name is the name of the control plane node of the member, empty if it is unknown.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Address is the k8s-dqlite address of the member.
+	Address string `json:"address" yaml:"address"`
+	// Role is the role of the member in the k8s-dqlite cluster.
+	Role DatastoreRole `json:"role" yaml:"role"`
+	// Leader is true for the leader of the k8s-dqlite cluster.
+	Leader bool `json:"leader" yaml:"leader"`
+	// Pinned is true if the role of the member was set manually. Pinned roles are not changed when the voters are rebalanced.
+	Pinned bool `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+}
+
+// GetDatastoreMembersRequest is the request for "GET 1.0/k8sd/datastore/members".
+type GetDatastoreMembersRequest struct{}
+
+// GetDatastoreMembersResponse is the response for "GET 1.0/k8sd/datastore/members".
+type GetDatastoreMembersResponse struct {
+	Members []DatastoreMember `json:"members"`
+}
+
+// SetDatastoreRoleRequest is the request for "POST 1.0/k8sd/datastore/role".
+type SetDatastoreRoleRequest struct {
+	// Name is the name of the control plane node.
+	Name string `json:"name"`
+	// Role is the new role of the node in the k8s-dqlite cluster. It must be voter, stand-by or spare.
+	Role DatastoreRole `json:"role"`
+}
+
+// ResetDatastoreRoleRequest is the request for "DELETE 1.0/k8sd/datastore/role".
+type ResetDatastoreRoleRequest struct {
+	// Name is the name of the control plane node whose role is no longer pinned.
+	Name string `json:"name"`
+}
+
+// TransferDatastoreLeadershipRequest is the request for "POST 1.0/k8sd/datastore/transfer-leadership".
+type TransferDatastoreLeadershipRequest struct {
+	// Name is the name of the control plane node that becomes the leader. It must be a voter.
+	// If empty, k8s-dqlite picks a voter.
+	Name string `json:"name,omitempty"`
+}
+
+// DatastoreMemberHealth is the health of a member of the k8s-dqlite cluster.
+type DatastoreMemberHealth struct {
+	DatastoreMember `yaml:",inline"`
+	// Reachable is true if the member answered a request.
+	Reachable bool `json:"reachable" yaml:"reachable"`
+	// Latency is the round-trip time of the request, if the member is reachable.
+	Latency time.Duration `json:"latency,omitempty" yaml:"latency,omitempty"`
+	// LeaderAddress is the address of the leader that the member knows of.
+	// Members that know of a different leader than the rest of the cluster may be partitioned.
+	LeaderAddress string `json:"leader-address,omitempty" yaml:"leader-address,omitempty"`
+	// LogIndex is the index of the last entry in the raft log of the member, 0 if it is not known.
+	LogIndex uint64 `json:"log-index,omitempty" yaml:"log-index,omitempty"`
+	// LogIndexLag is how many entries the raft log of the member is behind the log of the leader.
+	// It is only set if the log indexes of both the member and the leader are known.
+	LogIndexLag *uint64 `json:"log-index-lag,omitempty" yaml:"log-index-lag,omitempty"`
+	// Error is the reason the member is not reachable.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// GetDatastoreHealthRequest is the request for "GET 1.0/k8sd/datastore/health".
+type GetDatastoreHealthRequest struct{}
+
+// GetDatastoreHealthResponse is the response for "GET 1.0/k8sd/datastore/health".
+type GetDatastoreHealthResponse struct {
+	Members []DatastoreMemberHealth `json:"members"`
+}
+
+// GetDatastoreLogIndexRequest is the request for "GET 1.0/k8sd/datastore/log-index".
+type GetDatastoreLogIndexRequest struct{}
+
+// GetDatastoreLogIndexResponse is the response for "GET 1.0/k8sd/datastore/log-index".
+type GetDatastoreLogIndexResponse struct {
+	// LogIndex is the index of the last entry in the raft log of the k8s-dqlite member on the node.
+	LogIndex uint64 `json:"log-index"`
+}
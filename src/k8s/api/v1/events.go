@@ -0,0 +1,56 @@
+package v1
+
+import "time"
+
+// EventType is the kind of an event.
+type EventType string
+
+const (
+	// EventNodeJoined is published when a node joins the cluster.
+	EventNodeJoined EventType = "node-joined"
+	// EventNodeRemoved is published when a node is removed from the cluster.
+	EventNodeRemoved EventType = "node-removed"
+	// EventConfigUpdated is published when the cluster configuration is updated.
+	EventConfigUpdated EventType = "config-updated"
+	// EventFeatureReconciled is published when a feature is applied successfully.
+	EventFeatureReconciled EventType = "feature-reconciled"
+	// EventFeatureFailed is published when a feature fails to apply. The feature is retried until it succeeds.
+	EventFeatureFailed EventType = "feature-failed"
+	// EventDatastoreRoleChanged is published when the datastore role of a control plane node changes.
+	EventDatastoreRoleChanged EventType = "datastore-role-changed"
+	// EventNodeMaintenance is published when a node enters or exits maintenance.
+	EventNodeMaintenance EventType = "node-maintenance"
+	// EventDatastoreVotersRebalanced is published when a k8s-dqlite member is promoted or demoted to keep the voters spread across zones.
+	EventDatastoreVotersRebalanced EventType = "datastore-voters-rebalanced"
+	// EventCertificateReissued is published when a certificate of a control plane service is re-issued.
+	EventCertificateReissued EventType = "certificate-reissued"
+)
+
+// Event is a cluster operation observed by the k8sd of a node.
+type Event struct {
+	// ID increases with every event of the node. IDs restart from 1 when k8sd is restarted.
+	ID uint64 `json:"id" yaml:"id"`
+	// Time is the time the event was published.
+	Time time.Time `json:"time" yaml:"time"`
+	// Node is the name of the node that published the event.
+	Node string `json:"node" yaml:"node"`
+	// Type is the kind of the event.
+	Type EventType `json:"type" yaml:"type"`
+	// Message is a human readable description of the event.
+	Message string `json:"message" yaml:"message"`
+	// Details are the attributes of the event, e.g. the name of the feature.
+	Details map[string]string `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// GetEventsRequest is used to list or follow the events of a node with "GET 1.0/k8sd/events".
+type GetEventsRequest struct {
+	// Since only returns the events with an ID greater than Since.
+	Since uint64 `json:"since,omitempty"`
+	// Follow streams new events as server-sent events until the request is cancelled.
+	Follow bool `json:"follow,omitempty"`
+}
+
+// GetEventsResponse is the response for "GET 1.0/k8sd/events" without follow.
+type GetEventsResponse struct {
+	Events []Event `json:"events"`
+}
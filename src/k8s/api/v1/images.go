@@ -0,0 +1,10 @@
+package v1
+
+// GetClusterImagesRequest is the request for "GET 1.0/k8sd/cluster/images".
+type GetClusterImagesRequest struct{}
+
+// GetClusterImagesResponse is the response for "GET 1.0/k8sd/cluster/images".
+type GetClusterImagesResponse struct {
+	// Images is the list of images that are needed by the current cluster configuration.
+	Images []string `json:"images"`
+}
@@ -9,6 +9,9 @@ import (
 type ControlPlaneNodeJoinConfig struct {
 	ExtraSANS []string `json:"extra-sans,omitempty" yaml:"extra-sans,omitempty"`
 
+	// Zone is the failure domain of the node. It is set as the "topology.kubernetes.io/zone" label of the node.
+	Zone *string `json:"zone,omitempty" yaml:"zone,omitempty"`
+
 	// Seed certificates for external CA
 	FrontProxyClientCert            *string `json:"front-proxy-client-crt,omitempty" yaml:"front-proxy-client-crt,omitempty"`
 	FrontProxyClientKey             *string `json:"front-proxy-client-key,omitempty" yaml:"front-proxy-client-key,omitempty"`
@@ -36,6 +39,7 @@ type WorkerNodeJoinConfig struct {
 	KubeProxyClientKey  *string `json:"kube-proxy-client-key,omitempty" yaml:"kube-proxy-client-key,omitempty"`
 }
 
+func (c *ControlPlaneNodeJoinConfig) GetZone() string { return getField(c.Zone) }
 func (c *ControlPlaneNodeJoinConfig) GetFrontProxyClientCert() string {
 	return getField(c.FrontProxyClientCert)
 }
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -40,11 +41,33 @@ type NodeStatus struct {
 	// DatastoreRole is the role that the node has within the datastore cluster.
 	// Only applicable for control-plane nodes, empty for workers.
 	DatastoreRole DatastoreRole `json:"datastore-role,omitempty"`
+	// KubernetesNode is the status of the matching Kubernetes node.
+	// Only set in the detailed cluster status, nil if the node is not registered in Kubernetes.
+	KubernetesNode *KubernetesNodeStatus `json:"kubernetes-node,omitempty"`
+	// Services is the state of the k8s services on the node, e.g. "kubelet": "active".
+	// Only set in the local node status and in the detailed cluster status for control-plane nodes.
+	Services map[string]string `json:"services,omitempty"`
+}
+
+// KubernetesNodeStatus holds information about a Kubernetes node.
+type KubernetesNodeStatus struct {
+	// Ready is true if the node has the Ready condition.
+	Ready bool `json:"ready"`
+	// KubeletVersion is the version of the kubelet running on the node.
+	KubeletVersion string `json:"kubelet-version,omitempty"`
+	// InternalIP is the internal IP address of the node.
+	InternalIP string `json:"internal-ip,omitempty"`
 }
 
 type Datastore struct {
 	Type    string   `json:"type,omitempty"`
 	Servers []string `json:"servers,omitempty" yaml:"servers,omitempty"`
+	// Voters is the number of voters that k8s-dqlite keeps.
+	Voters int `json:"voters,omitempty" yaml:"voters,omitempty"`
+	// VoterZones are the zones of the k8s-dqlite voters, one entry per voter. Voters without a zone are not included.
+	VoterZones []string `json:"voter-zones,omitempty" yaml:"voter-zones,omitempty"`
+	// VotersBalanced is true if the k8s-dqlite voters are spread across zones as evenly as possible. It is nil if unknown.
+	VotersBalanced *bool `json:"voters-balanced,omitempty" yaml:"voters-balanced,omitempty"`
 }
 
 // ClusterStatus holds information about the cluster, e.g. its current members
@@ -124,6 +147,21 @@ func (c ClusterStatus) datastoreToString() string {
 		result.WriteString("  spare-nodes: none\n")
 	}
 
+	// Voter placement for k8s-dqlite
+	if c.Datastore.Voters > 0 {
+		result.WriteString(fmt.Sprintf("  voters: %d\n", c.Datastore.Voters))
+		if len(c.Datastore.VoterZones) > 0 {
+			result.WriteString(fmt.Sprintf("  voter-zones: %s\n", strings.Join(c.Datastore.VoterZones, ", ")))
+		} else {
+			result.WriteString("  voter-zones: none\n")
+		}
+		if balanced := c.Datastore.VotersBalanced; balanced != nil && *balanced {
+			result.WriteString("  voter-placement: balanced\n")
+		} else if balanced != nil {
+			result.WriteString("  voter-placement: rebalancing\n")
+		}
+	}
+
 	return result.String()
 }
 
@@ -162,3 +200,56 @@ func (c ClusterStatus) String() string {
 }
 
 // TICS +COV_GO_SUPPRESSED_ERROR
+
+// TableHeaders returns the column headers for the cluster members table.
+// The wide table includes the Kubernetes node status and the service health of each member.
+func (c ClusterStatus) TableHeaders(wide bool) []string {
+	headers := []string{"NAME", "ADDRESS", "CLUSTER-ROLE", "DATASTORE-ROLE"}
+	if wide {
+		headers = append(headers, "STATUS", "VERSION", "INTERNAL-IP", "SERVICES")
+	}
+	return headers
+}
+
+// TableRows returns a row for each member of the cluster.
+func (c ClusterStatus) TableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(c.Members))
+	for _, member := range c.Members {
+		row := []string{member.Name, member.Address, string(member.ClusterRole), string(member.DatastoreRole)}
+		if wide {
+			status, version, internalIP := "Unknown", "", ""
+			if node := member.KubernetesNode; node != nil {
+				status, version, internalIP = "NotReady", node.KubeletVersion, node.InternalIP
+				if node.Ready {
+					status = "Ready"
+				}
+			}
+			row = append(row, status, version, internalIP, member.servicesSummary())
+		}
+		for i := range row {
+			if row[i] == "" {
+				row[i] = "-"
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// servicesSummary returns "ok" if all services are active, or the list of services that are not.
+func (n NodeStatus) servicesSummary() string {
+	if len(n.Services) == 0 {
+		return ""
+	}
+	var failing []string
+	for service, state := range n.Services {
+		if state != "active" {
+			failing = append(failing, fmt.Sprintf("%s(%s)", service, state))
+		}
+	}
+	if len(failing) == 0 {
+		return "ok"
+	}
+	sort.Strings(failing)
+	return strings.Join(failing, ",")
+}
@@ -89,6 +89,37 @@ network:
   enabled: true
 dns:
   enabled: true
+`,
+		},
+		{
+			name: "Voter placement",
+			clusterStatus: apiv1.ClusterStatus{
+				Ready: true,
+				Members: []apiv1.NodeStatus{
+					{Name: "node1", DatastoreRole: apiv1.DatastoreRoleVoter, Address: "192.168.0.1"},
+					{Name: "node2", DatastoreRole: apiv1.DatastoreRoleVoter, Address: "192.168.0.2"},
+					{Name: "node3", DatastoreRole: apiv1.DatastoreRoleStandBy, Address: "192.168.0.3"},
+				},
+				Datastore: apiv1.Datastore{
+					Type:           "k8s-dqlite",
+					Voters:         3,
+					VoterZones:     []string{"zone-a", "zone-b"},
+					VotersBalanced: utils.Pointer(false),
+				},
+			},
+			expectedOutput: `status: ready
+high-availability: no
+datastore:
+  type: k8s-dqlite
+  voter-nodes:
+    - 192.168.0.1
+    - 192.168.0.2
+  standby-nodes:
+    - 192.168.0.3
+  spare-nodes: none
+  voters: 3
+  voter-zones: zone-a, zone-b
+  voter-placement: rebalancing
 `,
 		},
 		{
@@ -142,3 +173,47 @@ datastore:
 		})
 	}
 }
+
+func TestTableRows(t *testing.T) {
+	g := NewWithT(t)
+
+	status := apiv1.ClusterStatus{
+		Members: []apiv1.NodeStatus{
+			{
+				Name:           "node1",
+				Address:        "192.168.0.1:6400",
+				ClusterRole:    apiv1.ClusterRoleControlPlane,
+				DatastoreRole:  apiv1.DatastoreRoleVoter,
+				KubernetesNode: &apiv1.KubernetesNodeStatus{Ready: true, KubeletVersion: "v1.30.0", InternalIP: "192.168.0.1"},
+				Services:       map[string]string{"kubelet": "active", "containerd": "active"},
+			},
+			{
+				Name:           "node2",
+				Address:        "192.168.0.2:6400",
+				ClusterRole:    apiv1.ClusterRoleControlPlane,
+				DatastoreRole:  apiv1.DatastoreRoleStandBy,
+				KubernetesNode: &apiv1.KubernetesNodeStatus{Ready: false, KubeletVersion: "v1.30.0", InternalIP: "192.168.0.2"},
+				Services:       map[string]string{"kubelet": "inactive", "containerd": "active", "kube-proxy": "unknown"},
+			},
+			{
+				Name:        "worker1",
+				Address:     "192.168.0.3",
+				ClusterRole: apiv1.ClusterRoleWorker,
+			},
+		},
+	}
+
+	g.Expect(status.TableHeaders(false)).To(Equal([]string{"NAME", "ADDRESS", "CLUSTER-ROLE", "DATASTORE-ROLE"}))
+	g.Expect(status.TableRows(false)).To(Equal([][]string{
+		{"node1", "192.168.0.1:6400", "control-plane", "voter"},
+		{"node2", "192.168.0.2:6400", "control-plane", "stand-by"},
+		{"worker1", "192.168.0.3", "worker", "-"},
+	}))
+
+	g.Expect(status.TableHeaders(true)).To(HaveLen(8))
+	g.Expect(status.TableRows(true)).To(Equal([][]string{
+		{"node1", "192.168.0.1:6400", "control-plane", "voter", "Ready", "v1.30.0", "192.168.0.1", "ok"},
+		{"node2", "192.168.0.2:6400", "control-plane", "stand-by", "NotReady", "v1.30.0", "192.168.0.2", "kube-proxy(unknown),kubelet(inactive)"},
+		{"worker1", "192.168.0.3", "worker", "-", "Unknown", "-", "-", "-"},
+	}))
+}
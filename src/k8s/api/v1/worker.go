@@ -15,6 +15,10 @@ type WorkerNodeInfoResponse struct {
 	ClientCACert string `json:"client-ca,omitempty"`
 	// APIServers is a list of kube-apiserver endpoints of the cluster.
 	APIServers []string `json:"apiServers"`
+	// ControlPlaneEndpoint is the host:port of the control plane endpoint. If set, the worker node uses it instead of APIServers.
+	ControlPlaneEndpoint string `json:"controlPlaneEndpoint,omitempty"`
+	// APIServerPort is the secure port of kube-apiserver in the cluster.
+	APIServerPort int `json:"apiServerPort,omitempty"`
 	// KubeletClientCert is the certificate to use in kubelet to authenticate with kube-apiserver.
 	KubeletClientCert string `json:"kubeletClientCert"`
 	// KubeletClientKey is the private key to use in kubelet to authenticate with kube-apiserver.
@@ -37,6 +41,12 @@ type WorkerNodeInfoResponse struct {
 	KubeletCert string `json:"kubeletCrt,omitempty"`
 	// KubeletKey is the private key to use for kubelet TLS. It will be empty if the cluster is not using self-signed certificates.
 	KubeletKey string `json:"kubeletKey,omitempty"`
+	// ContainerdRegistries is the list of registries to configure in containerd.
+	ContainerdRegistries []ContainerdRegistryConfig `json:"containerdRegistries,omitempty"`
+	// ContainerdRuntimeHandlers is the list of additional runtime handlers to configure in containerd.
+	ContainerdRuntimeHandlers []ContainerdRuntimeHandlerConfig `json:"containerdRuntimeHandlers,omitempty"`
+	// ImageRegistry overrides the registry of the images used by the node.
+	ImageRegistry string `json:"imageRegistry,omitempty"`
 	// K8sdPublicKey is the public key that can be used to validate authenticity of cluster messages.
 	K8sdPublicKey string `json:"k8sdPublicKey,omitempty"`
 }
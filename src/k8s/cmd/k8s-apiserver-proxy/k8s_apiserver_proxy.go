@@ -4,6 +4,7 @@ import (
 	"time"
 
 	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/proxy"
 	"github.com/spf13/cobra"
 )
@@ -14,31 +15,57 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		endpointsConfigFile        string
 		refreshEndpointsInterval   time.Duration
 		refreshEndpointsKubeconfig string
+		healthCheckKubeconfig      string
+		healthCheck                proxy.HealthCheckOptions
+		strategy                   string
+		drainTimeout               time.Duration
+		statusAddress              string
+		logDebug                   bool
+		logFormat                  string
 	}
 
 	cmd := &cobra.Command{
 		Use:   "k8s-apiserver-proxy",
 		Short: "Local API server proxy used in worker nodes. Forwards requests to active kube-apiserver instances",
 		Run: func(cmd *cobra.Command, args []string) {
-			var refreshCh <-chan time.Time
-			if opts.refreshEndpointsInterval == 0 {
-				cmd.Println("Will not auto-refresh list of control plane endpoints")
-			} else {
-				if opts.refreshEndpointsInterval < 15*time.Second {
-					cmd.PrintErrf("Refresh interval %v is less than minimum of 15s. Using the minimum 15s instead.\n", opts.refreshEndpointsInterval)
-					opts.refreshEndpointsInterval = 15 * time.Second
+			logger, err := log.New(env.Stderr, log.Options{Debug: opts.logDebug, Verbose: true, Format: opts.logFormat})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to initialize logger: %v", err)
+				env.Exit(1)
+				return
+			}
+
+			strategy := proxy.Strategy(opts.strategy)
+			if strategy != proxy.StrategyRoundRobin && strategy != proxy.StrategyLeastConnections {
+				cmd.PrintErrf("Error: Invalid strategy %q, must be one of %s or %s.\n", opts.strategy, proxy.StrategyRoundRobin, proxy.StrategyLeastConnections)
+				env.Exit(1)
+				return
+			}
+
+			if opts.listenAddress == "" {
+				if opts.listenAddress, err = proxy.ListenAddressFromKubeconfig(opts.refreshEndpointsKubeconfig); err != nil {
+					logger.Warn("Failed to read the kube-apiserver port from the kubeconfig, listening on the default port", "kubeconfig", opts.refreshEndpointsKubeconfig, "error", err)
+					opts.listenAddress = ":6443"
 				}
-				refreshCh = time.NewTicker(opts.refreshEndpointsInterval).C
+			}
+
+			if opts.refreshEndpointsInterval == 0 {
+				cmd.Println("Will not watch list of control plane endpoints")
 			}
 
 			p := &proxy.APIServerProxy{
-				ListenAddress:       opts.listenAddress,
-				EndpointsConfigFile: opts.endpointsConfigFile,
-				KubeconfigFile:      opts.refreshEndpointsKubeconfig,
-				RefreshCh:           refreshCh,
+				ListenAddress:             opts.listenAddress,
+				EndpointsConfigFile:       opts.endpointsConfigFile,
+				KubeconfigFile:            opts.refreshEndpointsKubeconfig,
+				RefreshInterval:           opts.refreshEndpointsInterval,
+				HealthCheckKubeconfigFile: opts.healthCheckKubeconfig,
+				HealthCheck:               opts.healthCheck,
+				Strategy:                  strategy,
+				DrainTimeout:              opts.drainTimeout,
+				StatusAddress:             opts.statusAddress,
 			}
 
-			if err := p.Run(cmd.Context()); err != nil {
+			if err := p.Run(log.NewContext(cmd.Context(), logger)); err != nil {
 				cmd.PrintErrf("Proxy failed with error: %v", err)
 				env.Exit(1)
 				return
@@ -51,10 +78,20 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd.SetOut(env.Stdout)
 	cmd.SetErr(env.Stderr)
 
-	cmd.Flags().StringVar(&opts.listenAddress, "listen", ":6443", "listen address")
+	cmd.Flags().StringVar(&opts.listenAddress, "listen", "", "listen address. defaults to the port of the server in --kubeconfig, which is the kube-apiserver port of the cluster")
 	cmd.Flags().StringVar(&opts.endpointsConfigFile, "endpoints", "/etc/kubernetes/k8s-apiserver-proxy.json", "configuration file with known kube-apiserver endpoints")
-	cmd.Flags().StringVar(&opts.refreshEndpointsKubeconfig, "kubeconfig", "/etc/kubernetes/kubelet.conf", "kubeconfig file to use for updating list of known kube-apiserver endpoints")
-	cmd.Flags().DurationVar(&opts.refreshEndpointsInterval, "refresh-interval", 30*time.Second, "interval between checking for new kube-apiserver endpoints. set to 0 to disable")
+	cmd.Flags().StringVar(&opts.refreshEndpointsKubeconfig, "kubeconfig", "/etc/kubernetes/proxy.conf", "kubeconfig file to use for watching the list of known kube-apiserver endpoints. must allow to list and watch endpointslices")
+	cmd.Flags().DurationVar(&opts.refreshEndpointsInterval, "refresh-interval", 30*time.Second, "interval between full resyncs of the watched kube-apiserver endpoints. set to 0 to disable watching for new endpoints")
+	cmd.Flags().StringVar(&opts.healthCheckKubeconfig, "health-check-kubeconfig", "", "kubeconfig file with the CA and client certificate to use for the kube-apiserver endpoint readiness checks. defaults to --kubeconfig")
+	cmd.Flags().DurationVar(&opts.healthCheck.Interval, "health-check-interval", 5*time.Second, "interval between checking the readiness of each kube-apiserver endpoint. set to 0 to only check that endpoints accept connections")
+	cmd.Flags().DurationVar(&opts.healthCheck.Timeout, "health-check-timeout", 3*time.Second, "max time to wait for a kube-apiserver endpoint readiness check")
+	cmd.Flags().IntVar(&opts.healthCheck.HealthyThreshold, "healthy-threshold", 2, "number of consecutive successful readiness checks before an endpoint receives new connections")
+	cmd.Flags().IntVar(&opts.healthCheck.UnhealthyThreshold, "unhealthy-threshold", 3, "number of consecutive failed readiness checks before an endpoint stops receiving new connections")
+	cmd.Flags().StringVar(&opts.strategy, "strategy", string(proxy.StrategyRoundRobin), "how to pick the kube-apiserver endpoint for new connections, round-robin or least-connections")
+	cmd.Flags().DurationVar(&opts.drainTimeout, "drain-timeout", 5*time.Minute, "how long connections to removed kube-apiserver endpoints stay open. set to 0 to keep them open until closed")
+	cmd.Flags().StringVar(&opts.statusAddress, "status-address", "", "listen address for the JSON status (/status) and Prometheus metrics (/metrics) endpoints, e.g. \"127.0.0.1:6444\". empty to disable")
+	cmd.Flags().BoolVarP(&opts.logDebug, "debug", "d", false, "show all debug messages")
+	cmd.Flags().StringVar(&opts.logFormat, "log-format", log.FormatText, "format of the log messages, text or json")
 
 	return cmd
 }
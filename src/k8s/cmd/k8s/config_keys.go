@@ -0,0 +1,212 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+)
+
+// configKey is a dotted path in the cluster configuration, e.g. "load-balancer.cidrs".
+type configKey struct {
+	// name is the dotted path of the key.
+	name string
+	// index is the field index sequence of the key in apiv1.UserFacingClusterConfig, see reflect.Value.FieldByIndex.
+	index []int
+	// section is true for keys that group other keys, e.g. "dns".
+	section bool
+	// list is true for keys that hold a list of values.
+	list bool
+}
+
+// hiddenConfigKeys are not shown by "k8s get" and are not offered for shell completion.
+// They can still be configured with "k8s set" and "k8s unset".
+var hiddenConfigKeys = []string{"metrics-server", "cloud-provider"}
+
+// configKeys are all the keys of the cluster configuration, sorted by name.
+// The keys are generated from the json tags of apiv1.UserFacingClusterConfig, so new options are picked up automatically.
+var configKeys = listConfigKeys(reflect.TypeOf(apiv1.UserFacingClusterConfig{}), "", nil)
+
+func listConfigKeys(t reflect.Type, prefix string, index []int) []configKey {
+	var keys []configKey
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := configKey{name: prefix + name, index: append(append([]int{}, index...), i)}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			key.section = true
+			keys = append(keys, key)
+			keys = append(keys, listConfigKeys(fieldType, key.name+".", key.index)...)
+		case reflect.Slice:
+			key.list = true
+			keys = append(keys, key)
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+	return keys
+}
+
+// lookupConfigKey returns the key with the given name.
+func lookupConfigKey(name string) (configKey, bool) {
+	for _, key := range configKeys {
+		if key.name == name {
+			return key, true
+		}
+	}
+	return configKey{}, false
+}
+
+// hidden is true if the key is or belongs to one of the hiddenConfigKeys.
+func (k configKey) hidden() bool {
+	for _, hidden := range hiddenConfigKeys {
+		if k.name == hidden || strings.HasPrefix(k.name, hidden+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the value of the key in config. Unset values are returned as the zero value of their type.
+func (k configKey) get(config apiv1.UserFacingClusterConfig) any {
+	v := reflect.ValueOf(config).FieldByIndex(k.index)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Zero(v.Type().Elem()).Interface()
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// field returns the settable field of the key in config.
+func (k configKey) field(config *apiv1.UserFacingClusterConfig) reflect.Value {
+	return reflect.ValueOf(config).Elem().FieldByIndex(k.index)
+}
+
+// defaultClusterConfig returns the configuration of a new cluster with default values.
+func defaultClusterConfig() apiv1.UserFacingClusterConfig {
+	var config types.ClusterConfig
+	config.SetDefaults()
+	return config.ToUserFacing()
+}
+
+// resetConfigKey sets the key in config to its default value. Lists without a default are set to an empty list.
+// Other keys without a default are set to nil, they must be cleared with apiv1.UpdateClusterConfigRequest.Unset.
+// Empty strings and zero numbers are not considered defaults, as they only mean that the option is not set.
+// resetConfigKey returns true if the key must be cleared.
+func resetConfigKey(config *apiv1.UserFacingClusterConfig, name string) (bool, error) {
+	key, ok := lookupConfigKey(name)
+	if !ok || key.section {
+		return false, fmt.Errorf("unknown option key %q", name)
+	}
+
+	defaults := defaultClusterConfig()
+	value := key.field(&defaults)
+	switch {
+	case value.IsNil() && key.list:
+		value = reflect.New(value.Type().Elem())
+		value.Elem().Set(reflect.MakeSlice(value.Type().Elem(), 0, 0))
+	case !value.IsNil() && value.Elem().Kind() != reflect.Bool && value.Elem().IsZero():
+		value = reflect.Zero(value.Type())
+	}
+	key.field(config).Set(value)
+	return value.IsNil(), nil
+}
+
+// updateConfigList adds ("+") or removes ("-") the values of the list key in config.
+// The values are parsed like "k8s set <key>=<values>". The list is modified starting from the value in config
+// if it was already changed by an earlier argument, or from the value in current otherwise.
+func updateConfigList(config *apiv1.UserFacingClusterConfig, current apiv1.UserFacingClusterConfig, name string, op byte, values string) error {
+	key, ok := lookupConfigKey(name)
+	if !ok || key.section {
+		return fmt.Errorf("unknown option key %q", name)
+	}
+	if !key.list {
+		return fmt.Errorf("option %q is not a list", name)
+	}
+
+	var parsed apiv1.UserFacingClusterConfig
+	if err := updateConfigMapstructure(&parsed, fmt.Sprintf("%s=%s", name, values)); err != nil {
+		return err
+	}
+	items := key.field(&parsed).Elem()
+
+	result := key.field(config)
+	if result.IsNil() {
+		result = key.field(&current)
+	}
+	list := reflect.MakeSlice(items.Type(), 0, 0)
+	if !result.IsNil() {
+		list = reflect.AppendSlice(list, result.Elem())
+	}
+
+	switch op {
+	case '+':
+		list = reflect.AppendSlice(list, items)
+	case '-':
+		remaining := reflect.MakeSlice(items.Type(), 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			removed := false
+			for j := 0; j < items.Len(); j++ {
+				if reflect.DeepEqual(list.Index(i).Interface(), items.Index(j).Interface()) {
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				remaining = reflect.Append(remaining, list.Index(i))
+			}
+		}
+		list = remaining
+	default:
+		return fmt.Errorf("unknown list operation %q", op)
+	}
+
+	value := reflect.New(list.Type())
+	value.Elem().Set(list)
+	key.field(config).Set(value)
+	return nil
+}
+
+// parseConfigArg splits a "key=value", "key+=value" or "key-=value" argument.
+// op is 0 for "key=value", or the "+" or "-" list operation.
+func parseConfigArg(arg string) (key string, op byte, value string, err error) {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok {
+		return "", 0, "", fmt.Errorf("option not in <key>=<value> format")
+	}
+	if strings.HasSuffix(key, "+") || strings.HasSuffix(key, "-") {
+		op = key[len(key)-1]
+		key = key[:len(key)-1]
+	}
+	return key, op, value, nil
+}
+
+// completeConfigKeys returns the visible keys that start with toComplete.
+// Sections are only included if includeSections is true. suffix is appended to each key.
+func completeConfigKeys(toComplete string, includeSections bool, suffix string) []string {
+	var completions []string
+	for _, key := range configKeys {
+		if key.hidden() || (key.section && !includeSections) {
+			continue
+		}
+		if strings.HasPrefix(key.name, toComplete) {
+			completions = append(completions, key.name+suffix)
+		}
+	}
+	return completions
+}
@@ -0,0 +1,221 @@
+package k8s
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	var settable, sections []string
+	for _, key := range configKeys {
+		if key.section {
+			sections = append(sections, key.name)
+		} else {
+			settable = append(settable, key.name)
+		}
+	}
+
+	g.Expect(sections).To(ConsistOf("containerd", "control-plane-endpoint", "dns", "gateway", "ingress", "load-balancer", "local-storage", "metrics-server", "network", "node-local-dns"))
+	g.Expect(settable).To(ConsistOf(
+		"cloud-provider",
+		"containerd.registries",
+		"containerd.runtime-handlers",
+		"control-plane-endpoint.address",
+		"control-plane-endpoint.use-for-workers",
+		"dns.cache-ttl",
+		"dns.cluster-domain",
+		"dns.enabled",
+		"dns.extra-plugins",
+		"dns.extra-servers",
+		"dns.hosts",
+		"dns.service-ip",
+		"dns.stub-zones",
+		"dns.upstream-nameservers",
+		"gateway.enabled",
+		"node-local-dns.enabled",
+		"node-local-dns.local-ip",
+		"image-registry",
+		"ingress.default-tls-secret",
+		"ingress.enable-proxy-protocol",
+		"ingress.enabled",
+		"load-balancer.bgp-local-asn",
+		"load-balancer.bgp-mode",
+		"load-balancer.bgp-peer-address",
+		"load-balancer.bgp-peer-asn",
+		"load-balancer.bgp-peer-port",
+		"load-balancer.cidrs",
+		"load-balancer.enabled",
+		"load-balancer.l2-interfaces",
+		"load-balancer.l2-mode",
+		"local-storage.default",
+		"local-storage.enabled",
+		"local-storage.local-path",
+		"local-storage.reclaim-policy",
+		"metrics-server.enabled",
+		"network.enabled",
+	))
+
+	key, ok := lookupConfigKey("metrics-server.enabled")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(key.hidden()).To(BeTrue())
+
+	g.Expect(completeConfigKeys("load-balancer.bgp-peer", false, "=")).To(Equal([]string{"load-balancer.bgp-peer-address=", "load-balancer.bgp-peer-asn=", "load-balancer.bgp-peer-port="}))
+	g.Expect(completeConfigKeys("m", true, "")).To(BeEmpty())
+	g.Expect(completeConfigKeys("dn", true, "")).To(ContainElements("dns", "dns.enabled"))
+	g.Expect(completeConfigKeys("dn", false, "")).ToNot(ContainElement("dns"))
+}
+
+func TestConfigKeyGet(t *testing.T) {
+	config := apiv1.UserFacingClusterConfig{
+		DNS:           apiv1.DNSConfig{Enabled: utils.Pointer(true), ClusterDomain: utils.Pointer("cluster.local")},
+		LoadBalancer:  apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{"10.0.0.0/24"})},
+		ImageRegistry: utils.Pointer("registry.internal:5000"),
+	}
+
+	for _, tc := range []struct {
+		key    string
+		expect any
+	}{
+		{key: "dns", expect: config.DNS},
+		{key: "dns.enabled", expect: true},
+		{key: "dns.cluster-domain", expect: "cluster.local"},
+		{key: "dns.service-ip", expect: ""},
+		{key: "dns.cache-ttl", expect: 0},
+		{key: "load-balancer.cidrs", expect: []string{"10.0.0.0/24"}},
+		{key: "load-balancer.l2-interfaces", expect: []string(nil)},
+		{key: "image-registry", expect: "registry.internal:5000"},
+		{key: "ingress.enabled", expect: false},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			g := NewWithT(t)
+			key, ok := lookupConfigKey(tc.key)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(key.get(config)).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestUpdateConfigList(t *testing.T) {
+	current := apiv1.UserFacingClusterConfig{
+		LoadBalancer: apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{"10.0.0.0/24", "10.0.1.0/24"})},
+		DNS: apiv1.DNSConfig{
+			StubZones: utils.Pointer([]apiv1.DNSStubZoneConfig{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10"}}}),
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		args      []string
+		expectErr bool
+		assertion func(g Gomega, config apiv1.UserFacingClusterConfig)
+	}{
+		{
+			name: "Append",
+			args: []string{"load-balancer.cidrs+=10.0.2.0/24"},
+			assertion: func(g Gomega, config apiv1.UserFacingClusterConfig) {
+				g.Expect(config.LoadBalancer.CIDRs).To(Equal(utils.Pointer([]string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"})))
+			},
+		},
+		{
+			name: "AppendToUnset",
+			args: []string{"load-balancer.l2-interfaces+=eth0,eth1"},
+			assertion: func(g Gomega, config apiv1.UserFacingClusterConfig) {
+				g.Expect(config.LoadBalancer.L2Interfaces).To(Equal(utils.Pointer([]string{"eth0", "eth1"})))
+			},
+		},
+		{
+			name: "Remove",
+			args: []string{"load-balancer.cidrs-=10.0.0.0/24"},
+			assertion: func(g Gomega, config apiv1.UserFacingClusterConfig) {
+				g.Expect(config.LoadBalancer.CIDRs).To(Equal(utils.Pointer([]string{"10.0.1.0/24"})))
+			},
+		},
+		{
+			name: "RemoveAll",
+			args: []string{"load-balancer.cidrs-=10.0.0.0/24,10.0.1.0/24"},
+			assertion: func(g Gomega, config apiv1.UserFacingClusterConfig) {
+				g.Expect(config.LoadBalancer.CIDRs).To(Equal(utils.Pointer([]string{})))
+			},
+		},
+		{
+			name: "RemoveStruct",
+			args: []string{`dns.stub-zones-=[{"zone": "corp.internal", "nameservers": ["10.0.0.10"]}]`},
+			assertion: func(g Gomega, config apiv1.UserFacingClusterConfig) {
+				g.Expect(config.DNS.StubZones).To(Equal(utils.Pointer([]apiv1.DNSStubZoneConfig{})))
+			},
+		},
+		{
+			name: "Chained",
+			args: []string{"load-balancer.cidrs=10.0.5.0/24", "load-balancer.cidrs+=10.0.6.0/24", "load-balancer.cidrs-=10.0.5.0/24"},
+			assertion: func(g Gomega, config apiv1.UserFacingClusterConfig) {
+				g.Expect(config.LoadBalancer.CIDRs).To(Equal(utils.Pointer([]string{"10.0.6.0/24"})))
+			},
+		},
+		{
+			name:      "NotAList",
+			args:      []string{"dns.enabled+=true"},
+			expectErr: true,
+		},
+		{
+			name:      "UnknownKey",
+			args:      []string{"dns.unknown+=true"},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var config apiv1.UserFacingClusterConfig
+			var err error
+			for _, arg := range tc.args {
+				if err = updateConfig(&config, current, arg); err != nil {
+					break
+				}
+			}
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			tc.assertion(g, config)
+		})
+	}
+}
+
+func TestResetConfigKey(t *testing.T) {
+	g := NewWithT(t)
+
+	config := apiv1.UserFacingClusterConfig{
+		DNS:          apiv1.DNSConfig{ServiceIP: utils.Pointer("10.152.183.10")},
+		LoadBalancer: apiv1.LoadBalancerConfig{BGPLocalASN: utils.Pointer(64512)},
+	}
+	var unset []string
+	for _, key := range []string{"dns.cache-ttl", "dns.service-ip", "local-storage.local-path", "load-balancer.cidrs", "load-balancer.bgp-local-asn", "dns.stub-zones", "metrics-server.enabled"} {
+		clear, err := resetConfigKey(&config, key)
+		g.Expect(err).To(BeNil())
+		if clear {
+			unset = append(unset, key)
+		}
+	}
+
+	g.Expect(config.DNS.CacheTTL).To(Equal(utils.Pointer(30)))
+	g.Expect(config.LocalStorage.LocalPath).To(Equal(utils.Pointer("/var/snap/k8s/common/rawfile-storage")))
+	g.Expect(config.LoadBalancer.CIDRs).To(Equal(utils.Pointer([]string{})))
+	g.Expect(config.DNS.StubZones).To(Equal(utils.Pointer([]apiv1.DNSStubZoneConfig{})))
+	g.Expect(config.MetricsServer.Enabled).To(Equal(utils.Pointer(true)))
+
+	// options without a default are cleared
+	g.Expect(config.DNS.ServiceIP).To(BeNil())
+	g.Expect(config.LoadBalancer.BGPLocalASN).To(BeNil())
+	g.Expect(unset).To(Equal([]string{"dns.service-ip", "load-balancer.bgp-local-asn"}))
+
+	_, err := resetConfigKey(&config, "dns")
+	g.Expect(err).To(HaveOccurred())
+	_, err = resetConfigKey(&config, "unknown")
+	g.Expect(err).To(HaveOccurred())
+}
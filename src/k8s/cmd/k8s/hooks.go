@@ -28,7 +28,7 @@ func hookInitializeFormatter(env cmdutil.ExecutionEnvironment, format *string) f
 		var err error
 		outputFormatter, err = cmdutil.NewFormatter(*format, cmd.OutOrStdout())
 		if err != nil {
-			cmd.PrintErrf("Error: Unknown --output-format %q. It must be one of %q (default), %q or %q.", *format, "plain", "json", "yaml")
+			cmd.PrintErrf("Error: Unknown --output-format %q. It must be one of %q (default), %q, %q, %q or %q.", *format, "plain", "json", "yaml", "table", "wide")
 			env.Exit(1)
 			return
 		}
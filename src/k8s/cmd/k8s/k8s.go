@@ -8,7 +8,7 @@ import (
 )
 
 var (
-	featureList = []string{"network", "dns", "gateway", "ingress", "local-storage", "load-balancer"}
+	featureList = []string{"network", "dns", "node-local-dns", "gateway", "ingress", "local-storage", "load-balancer"}
 
 	outputFormatter cmdutil.Formatter
 )
@@ -61,6 +61,8 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		newStatusCmd(env),
 		newKubeConfigCmd(env),
 		newKubectlCmd(env),
+		newInspectCmd(env),
+		newEventsCmd(env),
 	)
 
 	// Clustering
@@ -71,6 +73,8 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		newGetJoinTokenCmd(env),
 		newJoinClusterCmd(env),
 		newRemoveNodeCmd(env),
+		newNodeCmd(env),
+		newDatastoreCmd(env),
 	)
 
 	// Management
@@ -81,6 +85,10 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		newDisableCmd(env),
 		newSetCmd(env),
 		newGetCmd(env),
+		newUnsetCmd(env),
+		newApplyConfigCmd(env),
+		newCheckConfigCmd(env),
+		newImagesCmd(env),
 	)
 
 	// hidden commands
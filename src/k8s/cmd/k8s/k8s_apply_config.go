@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sclient "github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+type ApplyConfigResult struct {
+	Changes []apiv1.ClusterConfigChange `json:"changes" yaml:"changes"`
+	Applied bool                        `json:"applied" yaml:"applied"`
+}
+
+func (r ApplyConfigResult) String() string {
+	if len(r.Changes) == 0 {
+		return "The cluster configuration is up to date."
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString("Planned changes:\n")
+	for _, change := range r.Changes {
+		fmt.Fprintf(buf, "  %s: %s -> %s\n", change.Key, formatConfigValue(change.Old), formatConfigValue(change.New))
+	}
+	if r.Applied {
+		buf.WriteString("Configuration applied.")
+	} else {
+		buf.WriteString("Dry run, no changes were applied.")
+	}
+	return buf.String()
+}
+
+func newApplyConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		configFile   string
+		prune        bool
+		dryRun       bool
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:   "apply-config",
+		Short: "Apply a declarative cluster configuration file",
+		Long: `Apply the desired cluster configuration from a YAML file, using the same keys as "k8s get".
+Features that are not enabled in the file are disabled. Other options that are not in the file keep their current value, or are reset to their default value with --prune.
+The changes are printed and applied in a single update.
+Registry credentials are compared and printed redacted, so a changed password, token or client key alone is not applied. Use "k8s set" to change them. The update is rejected if the cluster configuration was changed while the changes were computed.`,
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.configFile == "" {
+				cmd.PrintErrln("Error: A configuration file must be specified with --file.")
+				env.Exit(1)
+				return
+			}
+
+			b, err := readConfigFile(env, opts.configFile)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to read cluster configuration from %q.\n\nThe error was: %v\n", opts.configFile, err)
+				env.Exit(1)
+				return
+			}
+			var desired apiv1.UserFacingClusterConfig
+			if err := yaml.UnmarshalStrict(b, &desired); err != nil {
+				cmd.PrintErrf("Error: Failed to parse cluster configuration from %q.\n\nThe error was: %v\n", opts.configFile, err)
+				env.Exit(1)
+				return
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			current, revision, err := client.GetClusterConfig(ctx, apiv1.GetClusterConfigRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to get the current cluster configuration.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			config, unset, changes, err := planConfig(current, desired, opts.prune)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to compute the cluster configuration changes.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			result := ApplyConfigResult{Changes: changes}
+			if len(changes) == 0 || opts.dryRun {
+				outputFormatter.Print(result)
+				return
+			}
+
+			request := apiv1.UpdateClusterConfigRequest{
+				Config:           config,
+				Unset:            unset,
+				ExpectedRevision: &revision,
+			}
+			if err := client.UpdateClusterConfig(ctx, request); err != nil {
+				if errors.Is(err, k8sclient.ErrClusterConfigConflict) {
+					cmd.PrintErrf("Error: The cluster configuration was changed while the changes were computed. Run the command again to compute the changes from the latest configuration.\n\nThe error was: %v\n", err)
+				} else {
+					cmd.PrintErrf("Error: Failed to apply requested cluster configuration changes.\n\nThe error was: %v\n", err)
+				}
+				env.Exit(1)
+				return
+			}
+
+			result.Applied = true
+			outputFormatter.Print(result)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.configFile, "file", "f", "", "path to the YAML file containing the desired cluster configuration. Use '-' to read from stdin.")
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "reset options that are not in the file to their default value")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "print the changes without applying them")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+// isFeatureKey is true for the "<feature>.enabled" keys.
+func isFeatureKey(key configKey) bool {
+	return strings.Count(key.name, ".") == 1 && strings.HasSuffix(key.name, ".enabled")
+}
+
+// planConfig computes the update that turns the current cluster configuration into the desired one.
+// Enabled features that are not enabled in desired are disabled. Options that are not set in desired keep their current value,
+// or are reset to their default value if prune is true. Hidden options are only changed if they are set in desired.
+// Registry credentials are redacted in current, so they are compared and returned in the changes redacted too.
+// planConfig returns the update, which only contains the changed options, the options that must be cleared, and the changes to the current configuration.
+func planConfig(current apiv1.UserFacingClusterConfig, desired apiv1.UserFacingClusterConfig, prune bool) (apiv1.UserFacingClusterConfig, []string, []apiv1.ClusterConfigChange, error) {
+	target := current
+	for _, key := range configKeys {
+		if key.section {
+			continue
+		}
+		switch value := key.field(&desired); {
+		case !value.IsNil():
+			key.field(&target).Set(value)
+		case key.hidden():
+		case isFeatureKey(key):
+			if key.get(current) == true {
+				key.field(&target).Set(reflect.ValueOf(utils.Pointer(false)))
+			}
+		case prune && !key.field(&current).IsNil():
+			if _, err := resetConfigKey(&target, key.name); err != nil {
+				return apiv1.UserFacingClusterConfig{}, nil, nil, fmt.Errorf("failed to reset %q: %w", key.name, err)
+			}
+		}
+	}
+
+	redactedTarget := target
+	redactedTarget.Containerd.Registries = redactRegistries(target.Containerd.Registries)
+
+	var update apiv1.UserFacingClusterConfig
+	var unset []string
+	for _, key := range configKeys {
+		if key.section {
+			continue
+		}
+		if value := key.field(&redactedTarget); !reflect.DeepEqual(key.field(&current).Interface(), value.Interface()) {
+			if value.IsNil() {
+				unset = append(unset, key.name)
+			} else {
+				key.field(&update).Set(key.field(&target))
+			}
+		}
+	}
+
+	changes, err := types.DiffUserFacingClusterConfig(current, redactedTarget)
+	if err != nil {
+		return apiv1.UserFacingClusterConfig{}, nil, nil, fmt.Errorf("failed to compare configurations: %w", err)
+	}
+	return update, unset, changes, nil
+}
+
+// redactRegistries returns a copy of registries with the credentials replaced by types.RedactedValue, like k8sd returns them.
+func redactRegistries(registries *[]apiv1.ContainerdRegistryConfig) *[]apiv1.ContainerdRegistryConfig {
+	if registries == nil {
+		return nil
+	}
+	redacted := make([]apiv1.ContainerdRegistryConfig, 0, len(*registries))
+	for _, r := range *registries {
+		for _, v := range []*string{&r.Password, &r.Token, &r.ClientKey} {
+			if *v != "" {
+				*v = types.RedactedValue
+			}
+		}
+		redacted = append(redacted, r)
+	}
+	return &redacted
+}
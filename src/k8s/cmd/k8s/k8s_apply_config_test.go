@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestPlanConfig(t *testing.T) {
+	current := apiv1.UserFacingClusterConfig{
+		Network:       apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+		DNS:           apiv1.DNSConfig{Enabled: utils.Pointer(true), ClusterDomain: utils.Pointer("cluster.local"), UpstreamNameservers: utils.Pointer([]string{"8.8.8.8"})},
+		Gateway:       apiv1.GatewayConfig{Enabled: utils.Pointer(true)},
+		LoadBalancer:  apiv1.LoadBalancerConfig{Enabled: utils.Pointer(false), CIDRs: utils.Pointer([]string{"10.0.0.0/24"}), BGPLocalASN: utils.Pointer(64512)},
+		MetricsServer: apiv1.MetricsServerConfig{Enabled: utils.Pointer(true)},
+	}
+
+	for _, tc := range []struct {
+		name           string
+		desired        apiv1.UserFacingClusterConfig
+		prune          bool
+		expectedUpdate apiv1.UserFacingClusterConfig
+		expectedUnset  []string
+		expectedKeys   []string
+	}{
+		{
+			name: "UpToDate",
+			desired: apiv1.UserFacingClusterConfig{
+				Network: apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+				DNS:     apiv1.DNSConfig{Enabled: utils.Pointer(true)},
+				Gateway: apiv1.GatewayConfig{Enabled: utils.Pointer(true)},
+			},
+			expectedKeys: []string{},
+		},
+		{
+			name: "DisableAbsentFeatures",
+			desired: apiv1.UserFacingClusterConfig{
+				Network: apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+				DNS:     apiv1.DNSConfig{Enabled: utils.Pointer(true), ClusterDomain: utils.Pointer("cluster.test")},
+			},
+			expectedUpdate: apiv1.UserFacingClusterConfig{
+				DNS:     apiv1.DNSConfig{ClusterDomain: utils.Pointer("cluster.test")},
+				Gateway: apiv1.GatewayConfig{Enabled: utils.Pointer(false)},
+			},
+			expectedKeys: []string{"dns.cluster-domain", "gateway.enabled"},
+		},
+		{
+			name: "Prune",
+			desired: apiv1.UserFacingClusterConfig{
+				Network: apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+				DNS:     apiv1.DNSConfig{Enabled: utils.Pointer(true)},
+				Gateway: apiv1.GatewayConfig{Enabled: utils.Pointer(true)},
+			},
+			prune: true,
+			expectedUpdate: apiv1.UserFacingClusterConfig{
+				DNS:          apiv1.DNSConfig{UpstreamNameservers: utils.Pointer([]string{"/etc/resolv.conf"})},
+				LoadBalancer: apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{})},
+			},
+			expectedUnset: []string{"load-balancer.bgp-local-asn"},
+			expectedKeys:  []string{"dns.upstream-nameservers", "load-balancer.bgp-local-asn", "load-balancer.cidrs"},
+		},
+		{
+			name: "HiddenOptionsAreApplied",
+			desired: apiv1.UserFacingClusterConfig{
+				Network:       apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+				DNS:           apiv1.DNSConfig{Enabled: utils.Pointer(true)},
+				Gateway:       apiv1.GatewayConfig{Enabled: utils.Pointer(true)},
+				MetricsServer: apiv1.MetricsServerConfig{Enabled: utils.Pointer(false)},
+			},
+			expectedUpdate: apiv1.UserFacingClusterConfig{
+				MetricsServer: apiv1.MetricsServerConfig{Enabled: utils.Pointer(false)},
+			},
+			expectedKeys: []string{"metrics-server.enabled"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			update, unset, changes, err := planConfig(current, tc.desired, tc.prune)
+			g.Expect(err).To(BeNil())
+			g.Expect(update).To(Equal(tc.expectedUpdate))
+			g.Expect(unset).To(Equal(tc.expectedUnset))
+
+			keys := []string{}
+			for _, change := range changes {
+				keys = append(keys, change.Key)
+			}
+			g.Expect(keys).To(Equal(tc.expectedKeys))
+		})
+	}
+}
+
+func TestPlanConfigRegistryCredentials(t *testing.T) {
+	registry := apiv1.ContainerdRegistryConfig{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Username: "user", Password: "secret"}
+	desired := apiv1.UserFacingClusterConfig{
+		Network:    apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+		Containerd: apiv1.ContainerdConfig{Registries: utils.Pointer([]apiv1.ContainerdRegistryConfig{registry})},
+	}
+
+	t.Run("SameFileTwice", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := apiv1.UserFacingClusterConfig{Network: apiv1.NetworkConfig{Enabled: utils.Pointer(true)}}
+		update, _, changes, err := planConfig(current, desired, false)
+		g.Expect(err).To(BeNil())
+		g.Expect(update.Containerd.GetRegistries()).To(Equal([]apiv1.ContainerdRegistryConfig{registry}))
+		g.Expect(changes).To(HaveLen(1))
+		g.Expect(formatConfigValue(changes[0].New)).ToNot(ContainSubstring("secret"))
+
+		// k8sd returns the applied configuration with the credentials redacted.
+		current.Containerd.Registries = redactRegistries(update.Containerd.Registries)
+		update, unset, changes, err := planConfig(current, desired, false)
+		g.Expect(err).To(BeNil())
+		g.Expect(update).To(Equal(apiv1.UserFacingClusterConfig{}))
+		g.Expect(unset).To(BeEmpty())
+		g.Expect(changes).To(BeEmpty())
+	})
+
+	t.Run("OtherRegistryChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := apiv1.UserFacingClusterConfig{
+			Network:    apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+			Containerd: apiv1.ContainerdConfig{Registries: redactRegistries(desired.Containerd.Registries)},
+		}
+		(*current.Containerd.Registries)[0].URLs = []string{"https://old-mirror.internal"}
+		update, _, changes, err := planConfig(current, desired, false)
+		g.Expect(err).To(BeNil())
+		g.Expect(update.Containerd.GetRegistries()).To(Equal([]apiv1.ContainerdRegistryConfig{registry}))
+		g.Expect(changes).To(HaveLen(1))
+		g.Expect(formatConfigValue(changes[0].Old)).To(ContainSubstring(types.RedactedValue))
+		g.Expect(formatConfigValue(changes[0].New)).To(ContainSubstring(types.RedactedValue))
+		g.Expect(formatConfigValue(changes[0].New)).ToNot(ContainSubstring("secret"))
+	})
+}
+
+func TestApplyConfigCmd(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		args            []string
+		config          string
+		conflicts       int
+		expectedRequest *apiv1.UpdateClusterConfigRequest
+		expectedCode    int
+		expectedStdout  string
+		expectedStderr  string
+	}{
+		{
+			name:   "Apply",
+			config: "network:\n  enabled: true\ndns:\n  enabled: true\n",
+			expectedRequest: &apiv1.UpdateClusterConfigRequest{
+				Config:           apiv1.UserFacingClusterConfig{Gateway: apiv1.GatewayConfig{Enabled: utils.Pointer(false)}},
+				ExpectedRevision: utils.Pointer(7),
+			},
+			expectedStdout: "Planned changes:\n  gateway.enabled: true -> false\nConfiguration applied.",
+		},
+		{
+			name:           "DryRun",
+			args:           []string{"--dry-run"},
+			config:         "network:\n  enabled: true\ndns:\n  enabled: true\n",
+			expectedStdout: "Dry run, no changes were applied.",
+		},
+		{
+			name:           "UpToDate",
+			config:         "network:\n  enabled: true\ndns:\n  enabled: true\ngateway:\n  enabled: true\n",
+			expectedStdout: "The cluster configuration is up to date.",
+		},
+		{
+			name:           "Conflict",
+			config:         "network:\n  enabled: true\n",
+			conflicts:      1,
+			expectedCode:   1,
+			expectedStderr: "Run the command again",
+		},
+		{
+			name:           "UnknownKeys",
+			config:         "network:\n  unknown: true\n",
+			expectedCode:   1,
+			expectedStderr: "Error: Failed to parse cluster configuration",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &mock.Client{UpdateClusterConfigConflicts: tc.conflicts}
+			mockClient.GetClusterConfigReturn.Config = apiv1.UserFacingClusterConfig{
+				Network: apiv1.NetworkConfig{Enabled: utils.Pointer(true)},
+				DNS:     apiv1.DNSConfig{Enabled: utils.Pointer(true)},
+				Gateway: apiv1.GatewayConfig{Enabled: utils.Pointer(true)},
+			}
+			mockClient.GetClusterConfigReturn.Revision = 7
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdin:  strings.NewReader(tc.config),
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Client: func(ctx context.Context) (client.Client, error) {
+					return mockClient, nil
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := NewRootCmd(env)
+			cmd.SetArgs(append([]string{"apply-config", "--file", "-"}, tc.args...))
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tc.expectedCode))
+			g.Expect(stdout.String()).To(ContainSubstring(tc.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tc.expectedStderr))
+			if tc.expectedRequest != nil {
+				g.Expect(mockClient.UpdateClusterConfigCalledWith).To(Equal(*tc.expectedRequest))
+			} else if tc.conflicts == 0 {
+				g.Expect(mockClient.UpdateClusterConfigCalls).To(Equal(0))
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	apiv1 "github.com/canonical/k8s/api/v1"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/canonical/k8s/pkg/config"
+	"github.com/canonical/k8s/pkg/k8sd/preflight"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/spf13/cobra"
@@ -22,7 +23,8 @@ import (
 )
 
 type BootstrapResult struct {
-	Node apiv1.NodeStatus `json:"node" yaml:"node"`
+	Node      apiv1.NodeStatus   `json:"node" yaml:"node"`
+	Preflight []preflight.Result `json:"preflight,omitempty" yaml:"preflight,omitempty"`
 }
 
 func (b BootstrapResult) String() string {
@@ -41,6 +43,8 @@ func newBootstrapCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		address      string
 		outputFormat string
 		timeout      time.Duration
+
+		ignorePreflightErrors []string
 	}
 	cmd := &cobra.Command{
 		Use:    "bootstrap",
@@ -122,6 +126,28 @@ func newBootstrapCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				}
 			}
 
+			preflightOpts := preflight.Options{
+				ControlPlane: true,
+				Ports:        []int{6443, 10250},
+				Network:      bootstrapConfig.ClusterConfig.Network.GetEnabled(),
+			}
+			if v := bootstrapConfig.SecurePort; v != nil {
+				preflightOpts.Ports[0] = *v
+			}
+			switch bootstrapConfig.GetDatastoreType() {
+			case "", "k8s-dqlite":
+				if v := bootstrapConfig.K8sDqlitePort; v != nil {
+					preflightOpts.Ports = append(preflightOpts.Ports, *v)
+				} else {
+					preflightOpts.Ports = append(preflightOpts.Ports, 9000)
+				}
+			}
+			preflightResults, ok := runPreflightChecks(cmd, env, preflightOpts, opts.ignorePreflightErrors, opts.outputFormat)
+			if !ok {
+				env.Exit(1)
+				return
+			}
+
 			cmd.PrintErrln("Bootstrapping the cluster. This may take a few seconds, please wait.")
 
 			request := apiv1.PostClusterBootstrapRequest{
@@ -140,7 +166,7 @@ func newBootstrapCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				return
 			}
 
-			outputFormatter.Print(BootstrapResult{Node: node})
+			outputFormatter.Print(BootstrapResult{Node: node, Preflight: preflightResults})
 		},
 	}
 
@@ -150,24 +176,31 @@ func newBootstrapCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd.Flags().StringVar(&opts.address, "address", "", "microcluster address, defaults to the node IP address")
 	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	cmd.Flags().StringSliceVar(&opts.ignorePreflightErrors, "ignore-preflight-errors", nil, "list of preflight checks whose errors are ignored, or 'all' to ignore all of them")
 
 	return cmd
 }
 
-func getConfigFromYaml(env cmdutil.ExecutionEnvironment, filePath string) (apiv1.BootstrapConfig, error) {
-	var b []byte
-	var err error
-
+// readConfigFile reads a configuration file. filePath "-" reads from stdin.
+func readConfigFile(env cmdutil.ExecutionEnvironment, filePath string) ([]byte, error) {
 	if filePath == "-" {
-		b, err = io.ReadAll(env.Stdin)
+		b, err := io.ReadAll(env.Stdin)
 		if err != nil {
-			return apiv1.BootstrapConfig{}, fmt.Errorf("failed to read config from stdin: %w", err)
-		}
-	} else {
-		b, err = os.ReadFile(filePath)
-		if err != nil {
-			return apiv1.BootstrapConfig{}, fmt.Errorf("failed to read file: %w", err)
+			return nil, fmt.Errorf("failed to read config from stdin: %w", err)
 		}
+		return b, nil
+	}
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return b, nil
+}
+
+func getConfigFromYaml(env cmdutil.ExecutionEnvironment, filePath string) (apiv1.BootstrapConfig, error) {
+	b, err := readConfigFile(env, filePath)
+	if err != nil {
+		return apiv1.BootstrapConfig{}, err
 	}
 
 	var config apiv1.BootstrapConfig
@@ -194,6 +227,8 @@ func getConfigInteractively(stdin io.Reader, stdout io.Writer, stderr io.Writer)
 			config.ClusterConfig.Network.Enabled = utils.Pointer(true)
 		case "dns":
 			config.ClusterConfig.DNS.Enabled = utils.Pointer(true)
+		case "node-local-dns":
+			config.ClusterConfig.NodeLocalDNS.Enabled = utils.Pointer(true)
 		case "ingress":
 			config.ClusterConfig.Ingress.Enabled = utils.Pointer(true)
 		case "load-balancer":
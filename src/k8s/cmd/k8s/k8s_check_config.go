@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type CheckConfigIssue struct {
+	Field   string `json:"field" yaml:"field"`
+	Message string `json:"message" yaml:"message"`
+}
+
+type CheckConfigResult struct {
+	Errors   []CheckConfigIssue `json:"errors" yaml:"errors"`
+	Warnings []CheckConfigIssue `json:"warnings" yaml:"warnings"`
+}
+
+func (c CheckConfigResult) String() string {
+	buf := &bytes.Buffer{}
+	for _, issue := range c.Errors {
+		buf.WriteString(fmt.Sprintf("error: %s: %s\n", issue.Field, issue.Message))
+	}
+	for _, issue := range c.Warnings {
+		buf.WriteString(fmt.Sprintf("warning: %s: %s\n", issue.Field, issue.Message))
+	}
+	buf.WriteString(fmt.Sprintf("Found %d error(s) and %d warning(s).", len(c.Errors), len(c.Warnings)))
+	return buf.String()
+}
+
+func newCheckConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		configFile   string
+		outputFormat string
+	}
+	cmd := &cobra.Command{
+		Use:    "check-config",
+		Short:  "Validate a bootstrap configuration file",
+		Long:   "Validate a bootstrap configuration file and report all errors and warnings. The k8sd service does not need to be running.",
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.configFile == "" {
+				cmd.PrintErrln("Error: A configuration file must be specified with --file.")
+				env.Exit(1)
+				return
+			}
+
+			bootstrapConfig, err := getConfigFromYaml(env, opts.configFile)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to read bootstrap configuration from %q.\n\nThe error was: %v\n", opts.configFile, err)
+				env.Exit(1)
+				return
+			}
+
+			config, err := types.ClusterConfigFromBootstrapConfig(bootstrapConfig)
+			if err != nil {
+				cmd.PrintErrf("Error: Invalid bootstrap configuration.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+			config.SetDefaults()
+
+			hostNetworks, err := utils.GetHostNetworks()
+			if err != nil {
+				cmd.PrintErrf("Warning: Failed to list the host networks, skipping overlap checks with the host.\n\nThe error was: %v\n", err)
+			}
+
+			validation := config.ValidateAll(types.ValidateOptions{HostNetworks: hostNetworks})
+			result := CheckConfigResult{
+				Errors:   make([]CheckConfigIssue, 0, len(validation.Errors)),
+				Warnings: make([]CheckConfigIssue, 0, len(validation.Warnings)),
+			}
+			for _, issue := range validation.Errors {
+				result.Errors = append(result.Errors, CheckConfigIssue{Field: issue.Field, Message: issue.Message})
+			}
+			for _, issue := range validation.Warnings {
+				result.Warnings = append(result.Warnings, CheckConfigIssue{Field: issue.Field, Message: issue.Message})
+			}
+
+			outputFormatter.Print(result)
+			if len(result.Errors) > 0 {
+				env.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.configFile, "file", "f", "", "path to the YAML file containing the cluster bootstrap configuration. Use '-' to read from stdin.")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+
+	return cmd
+}
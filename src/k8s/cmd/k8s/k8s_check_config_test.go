@@ -0,0 +1,92 @@
+package k8s_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckConfigCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         string
+		outputFormat   string
+		expectedCode   int
+		expectedStdout []string
+		expectedStderr string
+	}{
+		{
+			name:           "Valid",
+			config:         "cluster-config:\n  network:\n    enabled: true\n  dns:\n    enabled: true\n",
+			expectedStdout: []string{"Found 0 error(s)"},
+		},
+		{
+			name: "MultipleErrors",
+			config: `pod-cidr: 10.1.0.0/16
+service-cidr: 10.1.200.0/24
+cluster-config:
+  dns:
+    enabled: true
+    cluster-domain: cluster.local
+    service-ip: 10.152.183.10
+  gateway:
+    enabled: true
+`,
+			expectedCode: 1,
+			expectedStdout: []string{
+				"error: service-cidr: ",
+				"error: dns.service-ip: ",
+				"error: gateway.enabled: ",
+				"Found 3 error(s)",
+			},
+		},
+		{
+			name:           "JSON",
+			config:         "service-cidr: 10.152.183.0/24\ncluster-config:\n  dns:\n    enabled: true\n    service-ip: 10.152.183.1\n",
+			outputFormat:   "json",
+			expectedCode:   1,
+			expectedStdout: []string{`"field": "dns.service-ip"`},
+		},
+		{
+			name:           "UnknownKeys",
+			config:         "cluster-config:\n  unknown: true\n",
+			expectedCode:   1,
+			expectedStderr: "Error: Failed to read bootstrap configuration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdin:  strings.NewReader(tt.config),
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 1000 },
+				Exit:   func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			args := []string{"check-config", "-f", "-"}
+			if tt.outputFormat != "" {
+				args = append(args, "--output-format", tt.outputFormat)
+			}
+			cmd.SetArgs(args)
+			cmd.Execute()
+
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+		})
+	}
+}
@@ -51,6 +51,12 @@ func newKubeConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 			cmd.Println(config)
 		},
 	}
+	cmd.AddCommand(
+		newConfigHistoryCmd(env),
+		newConfigDiffCmd(env),
+		newConfigRollbackCmd(env),
+	)
+
 	cmd.Flags().StringVar(&opts.server, "server", "", "custom cluster server address")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
 	return cmd
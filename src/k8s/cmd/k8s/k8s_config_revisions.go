@@ -0,0 +1,216 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+type ConfigHistoryResult struct {
+	Revisions []apiv1.ClusterConfigRevision `json:"revisions" yaml:"revisions"`
+}
+
+func (r ConfigHistoryResult) String() string {
+	if len(r.Revisions) == 0 {
+		return "The cluster configuration has not been changed yet."
+	}
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tCREATED\tIDENTITY\tCHANGES")
+	for _, revision := range r.Revisions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", revision.Revision, revision.CreatedAt.Local().Format(time.RFC3339), revision.Identity, len(revision.Changes))
+	}
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+type ConfigDiffResult struct {
+	Revision int                         `json:"revision" yaml:"revision"`
+	Changes  []apiv1.ClusterConfigChange `json:"changes" yaml:"changes"`
+}
+
+func (r ConfigDiffResult) String() string {
+	if len(r.Changes) == 0 {
+		return fmt.Sprintf("Revision %d did not change any options.", r.Revision)
+	}
+	buf := &bytes.Buffer{}
+	for _, change := range r.Changes {
+		fmt.Fprintf(buf, "%s: %s -> %s\n", change.Key, formatConfigValue(change.Old), formatConfigValue(change.New))
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// formatConfigValue formats an option value as JSON, or as "<unset>" for nil values.
+// HTML characters are not escaped, so that redacted credentials are printed as "<redacted>".
+func formatConfigValue(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+type ConfigRollbackResult struct {
+	Revision int `json:"revision" yaml:"revision"`
+}
+
+func (r ConfigRollbackResult) String() string {
+	return fmt.Sprintf("Configuration rolled back to revision %d.", r.Revision)
+}
+
+// parseRevision parses a revision argument.
+func parseRevision(arg string) (int, error) {
+	revision, err := strconv.Atoi(arg)
+	if err != nil || revision < 1 {
+		return 0, fmt.Errorf("revision %q is not a positive number", arg)
+	}
+	return revision, nil
+}
+
+func newConfigHistoryCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "history",
+		Short:  "List the revisions of the cluster configuration",
+		Long:   "List the revisions of the cluster configuration. Revision 1 is the bootstrap configuration. The latest 100 revisions are kept. Registry credentials are not recorded in revisions.",
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			revisions, err := client.GetClusterConfigRevisions(ctx, apiv1.GetClusterConfigRevisionsRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to retrieve the cluster configuration revisions.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(ConfigHistoryResult{Revisions: revisions})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+func newConfigDiffCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "diff <revision>",
+		Short:  "Show the changes of a cluster configuration revision",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			revision, err := parseRevision(args[0])
+			if err != nil {
+				cmd.PrintErrf("Error: Invalid revision.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			revisions, err := client.GetClusterConfigRevisions(ctx, apiv1.GetClusterConfigRevisionsRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to retrieve the cluster configuration revisions.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			for _, r := range revisions {
+				if r.Revision == revision {
+					outputFormatter.Print(ConfigDiffResult{Revision: r.Revision, Changes: r.Changes})
+					return
+				}
+			}
+
+			cmd.PrintErrf("Error: Revision %d does not exist. Use \"k8s config history\" to list the revisions.\n", revision)
+			env.Exit(1)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+func newConfigRollbackCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "rollback <revision>",
+		Short:  "Restore the cluster configuration of a previous revision",
+		Long:   "Restore the cluster configuration as it was after the given revision. The rollback is applied like \"k8s set\", so changes that are not allowed are rejected. Options that were not set in the revision are unset. Registry credentials are not recorded in revisions, so the current credentials are kept. The rollback fails if a registry that has credentials in the revision no longer exists.",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			revision, err := parseRevision(args[0])
+			if err != nil {
+				cmd.PrintErrf("Error: Invalid revision.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if err := client.RollbackClusterConfig(ctx, apiv1.RollbackClusterConfigRequest{Revision: revision}); err != nil {
+				cmd.PrintErrf("Error: Failed to roll back the cluster configuration to revision %d.\n\nThe error was: %v\n", revision, err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(ConfigRollbackResult{Revision: revision})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
@@ -0,0 +1,110 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigRevisionsCmd(t *testing.T) {
+	revisions := []apiv1.ClusterConfigRevision{
+		{
+			Revision:  1,
+			CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			Identity:  "node1 (local)",
+			Changes:   []apiv1.ClusterConfigChange{{Key: "dns.enabled", Old: false, New: true}},
+		},
+		{
+			Revision:  2,
+			CreatedAt: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+			Identity:  "node1 (local)",
+			Changes: []apiv1.ClusterConfigChange{
+				{Key: "image-registry", New: "registry.internal:5000"},
+				{Key: "load-balancer.cidrs", Old: []any{"10.0.0.0/24"}, New: []any{"10.0.0.0/24", "10.0.1.0/24"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedCode     int
+		expectedStdout   []string
+		expectedStderr   string
+		expectedRollback int
+	}{
+		{
+			name:           "History",
+			args:           []string{"config", "history"},
+			expectedStdout: []string{"REVISION", "IDENTITY", "node1 (local)"},
+		},
+		{
+			name: "Diff",
+			args: []string{"config", "diff", "2"},
+			expectedStdout: []string{
+				`image-registry: <unset> -> "registry.internal:5000"`,
+				`load-balancer.cidrs: ["10.0.0.0/24"] -> ["10.0.0.0/24","10.0.1.0/24"]`,
+			},
+		},
+		{
+			name:           "DiffJSON",
+			args:           []string{"config", "diff", "1", "--output-format", "json"},
+			expectedStdout: []string{`"key": "dns.enabled"`},
+		},
+		{
+			name:           "DiffUnknownRevision",
+			args:           []string{"config", "diff", "5"},
+			expectedCode:   1,
+			expectedStderr: "Revision 5 does not exist",
+		},
+		{
+			name:           "DiffInvalidRevision",
+			args:           []string{"config", "diff", "latest"},
+			expectedCode:   1,
+			expectedStderr: "Invalid revision",
+		},
+		{
+			name:             "Rollback",
+			args:             []string{"config", "rollback", "1"},
+			expectedStdout:   []string{"Configuration rolled back to revision 1."},
+			expectedRollback: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockClient := &mock.Client{}
+			mockClient.GetClusterConfigRevisionsReturn.Revisions = revisions
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Exit:   func(rc int) { returnCode = rc },
+				Client: func(ctx context.Context) (client.Client, error) { return mockClient, nil },
+			}
+			cmd := k8s.NewRootCmd(env)
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(mockClient.RollbackClusterConfigCalledWith.Revision).To(Equal(tt.expectedRollback))
+		})
+	}
+}
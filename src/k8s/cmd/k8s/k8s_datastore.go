@@ -0,0 +1,409 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// DatastoreMembersResult is the list of members of the k8s-dqlite cluster.
+type DatastoreMembersResult struct {
+	Members []apiv1.DatastoreMember `json:"members" yaml:"members"`
+}
+
+func (r DatastoreMembersResult) String() string {
+	b := &strings.Builder{}
+	for i, member := range r.Members {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%d: %s (%s) %s", member.ID, datastoreMemberName(member), member.Address, member.Role)
+		if member.Leader {
+			b.WriteString(", leader")
+		}
+		if member.Pinned {
+			b.WriteString(", pinned")
+		}
+	}
+	return b.String()
+}
+
+func (r DatastoreMembersResult) TableHeaders(wide bool) []string {
+	return []string{"ID", "NAME", "ADDRESS", "ROLE", "LEADER", "PINNED"}
+}
+
+func (r DatastoreMembersResult) TableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(r.Members))
+	for _, member := range r.Members {
+		rows = append(rows, []string{strconv.FormatUint(member.ID, 10), datastoreMemberName(member), member.Address, string(member.Role), strconv.FormatBool(member.Leader), strconv.FormatBool(member.Pinned)})
+	}
+	return rows
+}
+
+// DatastoreRoleResult is the new role of a node in the k8s-dqlite cluster.
+type DatastoreRoleResult struct {
+	Name string              `json:"name" yaml:"name"`
+	Role apiv1.DatastoreRole `json:"role" yaml:"role"`
+}
+
+func (r DatastoreRoleResult) String() string {
+	return fmt.Sprintf("Node %s is now a datastore %s.", r.Name, r.Role)
+}
+
+// DatastoreResetRoleResult is the outcome of resetting the role of a node in the k8s-dqlite cluster.
+type DatastoreResetRoleResult struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+func (r DatastoreResetRoleResult) String() string {
+	return fmt.Sprintf("The datastore role of node %s is no longer pinned.", r.Name)
+}
+
+// DatastoreLeadershipResult is the outcome of a leadership transfer in the k8s-dqlite cluster.
+type DatastoreLeadershipResult struct {
+	Leader string `json:"leader,omitempty" yaml:"leader,omitempty"`
+}
+
+func (r DatastoreLeadershipResult) String() string {
+	if r.Leader == "" {
+		return "Datastore leadership was transferred."
+	}
+	return fmt.Sprintf("Datastore leadership was transferred to %s.", r.Leader)
+}
+
+// DatastoreHealthResult is the health of the members of the k8s-dqlite cluster.
+type DatastoreHealthResult struct {
+	Members []apiv1.DatastoreMemberHealth `json:"members" yaml:"members"`
+}
+
+func (r DatastoreHealthResult) String() string {
+	b := &strings.Builder{}
+	for i, member := range r.Members {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%s (%s): ", datastoreMemberName(member.DatastoreMember), member.Address)
+		if !member.Reachable {
+			fmt.Fprintf(b, "unreachable (%s)", member.Error)
+			continue
+		}
+		fmt.Fprintf(b, "reachable in %v", member.Latency.Round(time.Millisecond))
+		if member.LeaderAddress != "" {
+			fmt.Fprintf(b, ", leader %s", member.LeaderAddress)
+		} else {
+			b.WriteString(", no leader")
+		}
+		if member.LogIndex != 0 {
+			fmt.Fprintf(b, ", log index %d", member.LogIndex)
+			if member.LogIndexLag != nil {
+				fmt.Fprintf(b, " (%d behind the leader)", *member.LogIndexLag)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (r DatastoreHealthResult) TableHeaders(wide bool) []string {
+	headers := []string{"NAME", "ADDRESS", "ROLE", "REACHABLE", "LATENCY", "LEADER", "LOG INDEX", "LAG"}
+	if wide {
+		headers = append(headers, "ERROR")
+	}
+	return headers
+}
+
+func (r DatastoreHealthResult) TableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(r.Members))
+	for _, member := range r.Members {
+		var latency string
+		if member.Reachable {
+			latency = member.Latency.Round(time.Millisecond).String()
+		}
+		var logIndex, lag string
+		if member.LogIndex != 0 {
+			logIndex = strconv.FormatUint(member.LogIndex, 10)
+		}
+		if member.LogIndexLag != nil {
+			lag = strconv.FormatUint(*member.LogIndexLag, 10)
+		}
+		row := []string{datastoreMemberName(member.DatastoreMember), member.Address, string(member.Role), strconv.FormatBool(member.Reachable), latency, member.LeaderAddress, logIndex, lag}
+		if wide {
+			row = append(row, member.Error)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// datastoreMemberName returns the node name of a k8s-dqlite member, or "unknown" for members that are not cluster nodes.
+func datastoreMemberName(member apiv1.DatastoreMember) string {
+	if member.Name == "" {
+		return "unknown"
+	}
+	return member.Name
+}
+
+func newDatastoreCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "datastore",
+		Short: "Manage the k8s-dqlite datastore of the cluster",
+		Long:  "Inspect and manage the members of the k8s-dqlite datastore. These commands are not available for clusters that use an external datastore.",
+	}
+	cmd.AddCommand(
+		newDatastoreMembersCmd(env),
+		newDatastoreRoleCmd(env, true),
+		newDatastoreRoleCmd(env, false),
+		newDatastoreResetRoleCmd(env),
+		newDatastoreTransferLeadershipCmd(env),
+		newDatastoreHealthCmd(env),
+	)
+	return cmd
+}
+
+func newDatastoreMembersCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "members",
+		Short:  "List the members of the datastore",
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			members, err := client.GetDatastoreMembers(ctx, apiv1.GetDatastoreMembersRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to retrieve the members of the datastore.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(DatastoreMembersResult{Members: members})
+		},
+	}
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json, yaml, table or wide")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+// newDatastoreRoleCmd creates the command to promote a node in the datastore, or to demote it if promote is false.
+func newDatastoreRoleCmd(env cmdutil.ExecutionEnvironment, promote bool) *cobra.Command {
+	var opts struct {
+		role         string
+		outputFormat string
+		timeout      time.Duration
+	}
+	roles := []apiv1.DatastoreRole{apiv1.DatastoreRoleVoter, apiv1.DatastoreRoleStandBy}
+	cmd := &cobra.Command{
+		Use:    "promote <node-name>",
+		Short:  "Promote a control plane node in the datastore",
+		Long:   "Promote a control plane node to a voter or stand-by of the datastore. Voters take part in the Raft quorum, stand-by nodes replicate the database without voting.\nThe role is pinned, so k8sd does not change it when it rebalances the voters. Use \"k8s datastore reset-role\" to let k8sd manage it again.",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			role := apiv1.DatastoreRole(opts.role)
+			if !slices.Contains(roles, role) {
+				cmd.PrintErrf("Error: Invalid role %q. Must be one of %s or %s.\n", opts.role, roles[0], roles[1])
+				env.Exit(1)
+				return
+			}
+
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			name := args[0]
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if err := client.SetDatastoreRole(ctx, apiv1.SetDatastoreRoleRequest{Name: name, Role: role}); err != nil {
+				cmd.PrintErrf("Error: Failed to assign the %s role to node %q.\n\nThe error was: %v\n", role, name, err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(DatastoreRoleResult{Name: name, Role: role})
+		},
+	}
+
+	defaultRole := apiv1.DatastoreRoleVoter
+	if !promote {
+		roles = []apiv1.DatastoreRole{apiv1.DatastoreRoleStandBy, apiv1.DatastoreRoleSpare}
+		defaultRole = apiv1.DatastoreRoleStandBy
+		cmd.Use = "demote <node-name>"
+		cmd.Short = "Demote a control plane node in the datastore"
+		cmd.Long = "Demote a control plane node to a stand-by or spare of the datastore. If the node is the leader, leadership is transferred to another voter first. The last voter of the datastore cannot be demoted.\nThe role is pinned, so k8sd does not change it when it rebalances the voters. Use \"k8s datastore reset-role\" to let k8sd manage it again."
+	}
+	cmd.Flags().StringVar(&opts.role, "role", string(defaultRole), fmt.Sprintf("the new role of the node, one of %s or %s", roles[0], roles[1]))
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+func newDatastoreResetRoleCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "reset-role <node-name>",
+		Short:  "Let k8sd manage the datastore role of a node again",
+		Long:   "Unpin the datastore role of a control plane node that was set with \"k8s datastore promote\" or \"k8s datastore demote\". k8sd may then change the role of the node when it rebalances the voters.",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			name := args[0]
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if err := client.ResetDatastoreRole(ctx, apiv1.ResetDatastoreRoleRequest{Name: name}); err != nil {
+				cmd.PrintErrf("Error: Failed to reset the datastore role of node %q.\n\nThe error was: %v\n", name, err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(DatastoreResetRoleResult{Name: name})
+		},
+	}
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+func newDatastoreTransferLeadershipCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "transfer-leadership [<node-name>]",
+		Short:  "Transfer the leadership of the datastore",
+		Long:   "Transfer the leadership of the datastore to a voter. If no node is given, the datastore picks a voter.",
+		Args:   cmdutil.MaximumNArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if err := client.TransferDatastoreLeadership(ctx, apiv1.TransferDatastoreLeadershipRequest{Name: name}); err != nil {
+				cmd.PrintErrf("Error: Failed to transfer the leadership of the datastore.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(DatastoreLeadershipResult{Leader: name})
+		},
+	}
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+func newDatastoreHealthCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "health",
+		Short:  "Check the health of the datastore members",
+		Long:   "Check whether each member of the datastore is reachable, how long it takes to answer and which leader it knows of. Members that know of a different leader than the rest may be partitioned. The command fails if any member is unreachable.\nIf the datastore has lost quorum, the members known to the local node are checked. The index of the last entry in the log of each member, and how far it is behind the leader, are read by k8sd on each control plane node and are omitted for nodes that cannot be reached.",
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			members, err := client.GetDatastoreHealth(ctx, apiv1.GetDatastoreHealthRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to check the health of the datastore.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(DatastoreHealthResult{Members: members})
+
+			for _, member := range members {
+				if !member.Reachable {
+					env.Exit(1)
+					return
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json, yaml, table or wide")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
@@ -0,0 +1,176 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestDatastoreCmd(t *testing.T) {
+	members := []apiv1.DatastoreMember{
+		{ID: 1, Name: "n1", Address: "10.0.0.1:9000", Role: apiv1.DatastoreRoleVoter, Leader: true},
+		{ID: 2, Name: "n2", Address: "10.0.0.2:9000", Role: apiv1.DatastoreRoleStandBy, Pinned: true},
+	}
+	health := []apiv1.DatastoreMemberHealth{
+		{DatastoreMember: members[0], Reachable: true, Latency: 2 * time.Millisecond, LeaderAddress: "10.0.0.1:9000"},
+		{DatastoreMember: members[1], Error: "connection refused"},
+	}
+	healthWithLogIndex := []apiv1.DatastoreMemberHealth{
+		{DatastoreMember: members[0], Reachable: true, Latency: 2 * time.Millisecond, LeaderAddress: "10.0.0.1:9000", LogIndex: 120, LogIndexLag: utils.Pointer[uint64](0)},
+		{DatastoreMember: members[1], Reachable: true, Latency: 3 * time.Millisecond, LeaderAddress: "10.0.0.1:9000", LogIndex: 100, LogIndexLag: utils.Pointer[uint64](20)},
+	}
+
+	tests := []struct {
+		name                      string
+		args                      []string
+		members                   []apiv1.DatastoreMember
+		health                    []apiv1.DatastoreMemberHealth
+		err                       error
+		expectedCode              int
+		expectedStdout            string
+		expectedStderr            string
+		expectedRoleRequest       apiv1.SetDatastoreRoleRequest
+		expectedLeadershipRequest *apiv1.TransferDatastoreLeadershipRequest
+		expectedResetRoleRequest  *apiv1.ResetDatastoreRoleRequest
+	}{
+		{
+			name:           "Members",
+			args:           []string{"datastore", "members"},
+			members:        members,
+			expectedStdout: "1: n1 (10.0.0.1:9000) voter, leader\n2: n2 (10.0.0.2:9000) stand-by, pinned\n",
+		},
+		{
+			name:           "MembersTable",
+			args:           []string{"datastore", "members", "--output-format", "table"},
+			members:        members,
+			expectedStdout: "ID   NAME   ADDRESS         ROLE       LEADER   PINNED\n1    n1     10.0.0.1:9000   voter      true     false\n",
+		},
+		{
+			name:           "MembersFail",
+			args:           []string{"datastore", "members"},
+			err:            fmt.Errorf("external datastore"),
+			expectedCode:   1,
+			expectedStderr: "external datastore",
+		},
+		{
+			name:                "Promote",
+			args:                []string{"datastore", "promote", "n2"},
+			expectedStdout:      "Node n2 is now a datastore voter.",
+			expectedRoleRequest: apiv1.SetDatastoreRoleRequest{Name: "n2", Role: apiv1.DatastoreRoleVoter},
+		},
+		{
+			name:                "PromoteStandBy",
+			args:                []string{"datastore", "promote", "n2", "--role", "stand-by"},
+			expectedStdout:      "Node n2 is now a datastore stand-by.",
+			expectedRoleRequest: apiv1.SetDatastoreRoleRequest{Name: "n2", Role: apiv1.DatastoreRoleStandBy},
+		},
+		{
+			name:           "PromoteInvalidRole",
+			args:           []string{"datastore", "promote", "n2", "--role", "spare"},
+			expectedCode:   1,
+			expectedStderr: `Invalid role "spare"`,
+		},
+		{
+			name:                "Demote",
+			args:                []string{"datastore", "demote", "n1"},
+			expectedStdout:      "Node n1 is now a datastore stand-by.",
+			expectedRoleRequest: apiv1.SetDatastoreRoleRequest{Name: "n1", Role: apiv1.DatastoreRoleStandBy},
+		},
+		{
+			name:                "DemoteSpareFails",
+			args:                []string{"datastore", "demote", "n1", "--role", "spare"},
+			err:                 fmt.Errorf("cannot demote the last voter"),
+			expectedCode:        1,
+			expectedStderr:      "cannot demote the last voter",
+			expectedRoleRequest: apiv1.SetDatastoreRoleRequest{Name: "n1", Role: apiv1.DatastoreRoleSpare},
+		},
+		{
+			name:                     "ResetRole",
+			args:                     []string{"datastore", "reset-role", "n2"},
+			expectedStdout:           "The datastore role of node n2 is no longer pinned.",
+			expectedResetRoleRequest: &apiv1.ResetDatastoreRoleRequest{Name: "n2"},
+		},
+		{
+			name:         "ResetRoleMissingNode",
+			args:         []string{"datastore", "reset-role"},
+			expectedCode: 1,
+		},
+		{
+			name:                      "TransferLeadership",
+			args:                      []string{"datastore", "transfer-leadership"},
+			expectedStdout:            "Datastore leadership was transferred.",
+			expectedLeadershipRequest: &apiv1.TransferDatastoreLeadershipRequest{},
+		},
+		{
+			name:                      "TransferLeadershipToNode",
+			args:                      []string{"datastore", "transfer-leadership", "n2"},
+			expectedStdout:            "Datastore leadership was transferred to n2.",
+			expectedLeadershipRequest: &apiv1.TransferDatastoreLeadershipRequest{Name: "n2"},
+		},
+		{
+			name:         "TransferLeadershipTooManyArgs",
+			args:         []string{"datastore", "transfer-leadership", "n1", "n2"},
+			expectedCode: 1,
+		},
+		{
+			name:           "Health",
+			args:           []string{"datastore", "health"},
+			health:         health[:1],
+			expectedStdout: "n1 (10.0.0.1:9000): reachable in 2ms, leader 10.0.0.1:9000",
+		},
+		{
+			name:           "HealthLogIndex",
+			args:           []string{"datastore", "health"},
+			health:         healthWithLogIndex,
+			expectedStdout: "n1 (10.0.0.1:9000): reachable in 2ms, leader 10.0.0.1:9000, log index 120 (0 behind the leader)\nn2 (10.0.0.2:9000): reachable in 3ms, leader 10.0.0.1:9000, log index 100 (20 behind the leader)",
+		},
+		{
+			name:           "HealthUnreachable",
+			args:           []string{"datastore", "health"},
+			health:         health,
+			expectedCode:   1,
+			expectedStdout: "n2 (10.0.0.2:9000): unreachable (connection refused)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockClient := &mock.Client{SetDatastoreRoleErr: tt.err}
+			mockClient.GetDatastoreMembersReturn.Members = tt.members
+			mockClient.GetDatastoreMembersReturn.Err = tt.err
+			mockClient.GetDatastoreHealthReturn.Members = tt.health
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Exit:   func(rc int) { returnCode = rc },
+				Client: func(ctx context.Context) (client.Client, error) { return mockClient, nil },
+			}
+			cmd := k8s.NewRootCmd(env)
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(mockClient.SetDatastoreRoleCalledWith).To(Equal(tt.expectedRoleRequest))
+			g.Expect(mockClient.TransferDatastoreLeadershipCalledWith).To(Equal(tt.expectedLeadershipRequest))
+			g.Expect(mockClient.ResetDatastoreRoleCalledWith).To(Equal(tt.expectedResetRoleRequest))
+		})
+	}
+}
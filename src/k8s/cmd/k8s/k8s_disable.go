@@ -29,7 +29,7 @@ func newDisableCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:    "disable <feature> ...",
 		Short:  "Disable core cluster features",
-		Long:   fmt.Sprintf("Disable one of %s.", strings.Join(featureList, ", ")),
+		Long:   fmt.Sprintf("Disable one of %s. Pods keep using node-local-dns until they are restarted, so its DNS cache is only removed once the pods created while it was enabled are restarted, for example with \"k8s kubectl rollout restart\".", strings.Join(featureList, ", ")),
 		Args:   cmdutil.MinimumNArgs(env, 1),
 		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -50,6 +50,10 @@ func newDisableCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 					config.DNS = api.DNSConfig{
 						Enabled: utils.Pointer(false),
 					}
+				case "node-local-dns":
+					config.NodeLocalDNS = api.NodeLocalDNSConfig{
+						Enabled: utils.Pointer(false),
+					}
 				case "gateway":
 					config.Gateway = api.GatewayConfig{
 						Enabled: utils.Pointer(false),
@@ -50,6 +50,10 @@ func newEnableCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 					config.DNS = api.DNSConfig{
 						Enabled: utils.Pointer(true),
 					}
+				case "node-local-dns":
+					config.NodeLocalDNS = api.NodeLocalDNSConfig{
+						Enabled: utils.Pointer(true),
+					}
 				case "gateway":
 					config.Gateway = api.GatewayConfig{
 						Enabled: utils.Pointer(true),
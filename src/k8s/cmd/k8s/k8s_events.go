@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+type EventResult struct {
+	apiv1.Event `yaml:",inline"`
+}
+
+func (r EventResult) String() string {
+	return fmt.Sprintf("%s %s %s %s", r.Time.Format(time.RFC3339), r.Node, r.Type, r.Message)
+}
+
+type EventsResult struct {
+	Events []apiv1.Event `json:"events" yaml:"events"`
+}
+
+func (r EventsResult) String() string {
+	if len(r.Events) == 0 {
+		return "No events."
+	}
+	lines := make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		lines = append(lines, EventResult{Event: event}.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newEventsCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		follow       bool
+		since        uint64
+		types        []string
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "List the cluster operations observed by this node",
+		Long: `List the recent cluster operations observed by the k8sd service of this node, e.g. nodes joining or leaving, configuration updates and feature reconciliation.
+Events are kept in memory by the k8sd service of each node and are not shared between nodes, e.g. a configuration update is only listed on the node that received it. Run the command on each node to follow the whole cluster.
+Use --follow to wait for new events. Events are numbered, use --since to only show the events after a number. Numbers restart when the k8sd service is restarted.`,
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			matches := func(event apiv1.Event) bool {
+				return len(opts.types) == 0 || slices.Contains(opts.types, string(event.Type))
+			}
+			request := apiv1.GetEventsRequest{Since: opts.since, Follow: opts.follow}
+
+			if !opts.follow {
+				ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+				cobra.OnFinalize(cancel)
+
+				events, err := client.GetEvents(ctx, request)
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to retrieve the events.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+				result := EventsResult{Events: []apiv1.Event{}}
+				for _, event := range events {
+					if matches(event) {
+						result.Events = append(result.Events, event)
+					}
+				}
+				outputFormatter.Print(result)
+				return
+			}
+
+			lastID := opts.since
+			if err := client.WatchEvents(cmd.Context(), request, func(event apiv1.Event) error {
+				lastID = event.ID
+				if matches(event) {
+					outputFormatter.Print(EventResult{Event: event})
+				}
+				return nil
+			}); err != nil && cmd.Context().Err() == nil {
+				cmd.PrintErrf("Error: Failed to follow the events. Use --since %d to resume.\n\nThe error was: %v\n", lastID, err)
+				env.Exit(1)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "wait for new events until interrupted")
+	cmd.Flags().Uint64Var(&opts.since, "since", 0, "only show the events after this event number")
+	cmd.Flags().StringSliceVar(&opts.types, "type", nil, "only show the events of these types, e.g. feature-reconciled")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute, ignored with --follow")
+	return cmd
+}
@@ -0,0 +1,102 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestEventsCmd(t *testing.T) {
+	events := []apiv1.Event{
+		{ID: 4, Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Node: "node1", Type: apiv1.EventConfigUpdated, Message: "Cluster configuration updated to revision 2 by node1 (local)"},
+		{ID: 5, Time: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC), Node: "node1", Type: apiv1.EventFeatureReconciled, Message: "Reconciled dns"},
+	}
+
+	for _, tc := range []struct {
+		name           string
+		args           []string
+		watchErr       error
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+		expectedSince  uint64
+	}{
+		{
+			name:           "List",
+			args:           []string{"--since", "3"},
+			expectedStdout: "2024-01-01T00:00:00Z node1 config-updated Cluster configuration updated to revision 2 by node1 (local)\n2024-01-01T00:00:01Z node1 feature-reconciled Reconciled dns\n",
+			expectedSince:  3,
+		},
+		{
+			name:           "ListJSON",
+			args:           []string{"--output-format", "json"},
+			expectedStdout: `"type": "feature-reconciled"`,
+		},
+		{
+			name:           "ListType",
+			args:           []string{"--type", "feature-reconciled"},
+			expectedStdout: "2024-01-01T00:00:01Z node1 feature-reconciled Reconciled dns\n",
+		},
+		{
+			name:           "Follow",
+			args:           []string{"--follow", "--type", "config-updated"},
+			expectedStdout: "2024-01-01T00:00:00Z node1 config-updated Cluster configuration updated to revision 2 by node1 (local)\n",
+		},
+		{
+			name:           "FollowJSON",
+			args:           []string{"--follow", "--output-format", "json"},
+			expectedStdout: `"id": 5,`,
+		},
+		{
+			name:           "FollowClosed",
+			args:           []string{"--follow", "--since", "3"},
+			watchErr:       errors.New("event stream was closed"),
+			expectedCode:   1,
+			expectedStderr: "Use --since 5 to resume.",
+			expectedSince:  3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &mock.Client{}
+			mockClient.GetEventsReturn.Events = events
+			mockClient.WatchEventsReturn.Events = events
+			mockClient.WatchEventsReturn.Err = tc.watchErr
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Client: func(ctx context.Context) (client.Client, error) {
+					return mockClient, nil
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+
+			// --follow runs until the command is interrupted
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			cmd := k8s.NewRootCmd(env)
+			cmd.SetArgs(append([]string{"events"}, tc.args...))
+			cmd.ExecuteContext(ctx)
+
+			g.Expect(returnCode).To(Equal(tc.expectedCode))
+			g.Expect(stdout.String()).To(ContainSubstring(tc.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tc.expectedStderr))
+			g.Expect(mockClient.GetEventsCalledWith.Since + mockClient.WatchEventsCalledWith.Since).To(Equal(tc.expectedSince))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+type ListImagesResult struct {
+	Images []string `json:"images" yaml:"images"`
+}
+
+func (l ListImagesResult) String() string {
+	return strings.Join(l.Images, "\n")
+}
+
+func newImagesCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "Manage the container images of the cluster",
+	}
+
+	cmd.AddCommand(newListImagesCmd(env), newImportImagesCmd(env))
+	return cmd
+}
+
+func newListImagesCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "list",
+		Short:  "List the images that are needed by the current cluster configuration",
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			images, err := client.GetClusterImages(ctx, apiv1.GetClusterImagesRequest{})
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to retrieve the cluster images.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(ListImagesResult{Images: images})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	return cmd
+}
+
+func newImportImagesCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "import <archive.tar> ...",
+		Short:  "Import images from OCI archives into the containerd of the local node",
+		Args:   cmdutil.MinimumNArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env)),
+		Run: func(cmd *cobra.Command, args []string) {
+			binary, err := exec.LookPath("ctr")
+			if err != nil {
+				cmd.PrintErrln("Error: ctr binary not found")
+				env.Exit(1)
+				return
+			}
+
+			for _, archive := range args {
+				if _, err := os.Stat(archive); err != nil {
+					cmd.PrintErrf("Error: Failed to access image archive %s.\n\nThe error was: %v\n", archive, err)
+					env.Exit(1)
+					return
+				}
+
+				// images must be imported in the "k8s.io" namespace to be used by the kubelet
+				command := exec.CommandContext(cmd.Context(), binary, "images", "import", archive)
+				command.Env = cmdutil.EnvironWithDefaults(
+					env.Environ,
+					"CONTAINERD_NAMESPACE", "k8s.io",
+					"CONTAINERD_ADDRESS", path.Join(env.Snap.ContainerdSocketDir(), "containerd.sock"),
+				)
+				command.Stdout = cmd.OutOrStdout()
+				command.Stderr = cmd.ErrOrStderr()
+				if err := command.Run(); err != nil {
+					cmd.PrintErrf("Error: Failed to import image archive %s.\n\nThe error was: %v\n", archive, err)
+					env.Exit(1)
+					return
+				}
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestImagesListCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		images         []string
+		err            error
+		outputFormat   string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name:           "plain",
+			images:         []string{"ghcr.io/canonical/k8s-snap/pause:3.10", "ghcr.io/canonical/coredns:1.11.1-ck4"},
+			expectedStdout: "ghcr.io/canonical/k8s-snap/pause:3.10\nghcr.io/canonical/coredns:1.11.1-ck4\n",
+		},
+		{
+			name:           "json",
+			images:         []string{"ghcr.io/canonical/k8s-snap/pause:3.10"},
+			outputFormat:   "json",
+			expectedStdout: `"images": [`,
+		},
+		{
+			name:           "error",
+			err:            fmt.Errorf("some error"),
+			expectedCode:   1,
+			expectedStderr: "Error: Failed to retrieve the cluster images",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &mock.Client{}
+			mockClient.GetClusterImagesReturn.Images = tt.images
+			mockClient.GetClusterImagesReturn.Err = tt.err
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Client: func(ctx context.Context) (client.Client, error) {
+					return mockClient, nil
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := k8s.NewRootCmd(env)
+
+			args := []string{"images", "list"}
+			if tt.outputFormat != "" {
+				args = append(args, "--output-format", tt.outputFormat)
+			}
+			cmd.SetArgs(args)
+			cmd.Execute()
+
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+		})
+	}
+}
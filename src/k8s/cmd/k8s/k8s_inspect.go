@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/inspect"
+	"github.com/spf13/cobra"
+)
+
+type InspectResult struct {
+	ReportFile string                  `json:"report-file" yaml:"report-file"`
+	Errors     []inspect.ManifestError `json:"errors" yaml:"errors"`
+}
+
+func (r InspectResult) String() string {
+	buf := &bytes.Buffer{}
+	for _, e := range r.Errors {
+		buf.WriteString(fmt.Sprintf("Warning: Failed to collect %s: %s\n", e.Collector, e.Error))
+	}
+	buf.WriteString(fmt.Sprintf("The inspection report is at %s", r.ReportFile))
+	return buf.String()
+}
+
+func newInspectCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFile   string
+		journalLines int
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "inspect",
+		Short:  "Collect diagnostics of the local node into a report",
+		Long:   "Collect service arguments, service logs, cluster status, datastore members, feature status, certificate expiry and node resources into a tarball. Private keys, tokens and passwords are redacted.",
+		Args:   cmdutil.ExactArgs(env, 0),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+
+			if opts.outputFile == "" {
+				opts.outputFile = fmt.Sprintf("%s-%s.tar.gz", inspect.RootDir, time.Now().Format("20060102_150405"))
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			inspectOpts := inspect.Options{Snap: env.Snap, JournalLines: opts.journalLines}
+			if client, err := env.Client(ctx); err != nil {
+				cmd.PrintErrf("Warning: Failed to create a k8sd client, the cluster status will not be collected.\n\nThe error was: %v\n", err)
+			} else {
+				inspectOpts.Client = client
+			}
+
+			f, err := os.OpenFile(opts.outputFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create the report file %q.\n\nThe error was: %v\n", opts.outputFile, err)
+				env.Exit(1)
+				return
+			}
+			defer f.Close()
+
+			cmd.PrintErrln("Collecting the inspection report. This may take a few seconds, please wait.")
+			manifest, err := inspect.Collect(ctx, f, inspectOpts)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to collect the inspection report.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(InspectResult{ReportFile: opts.outputFile, Errors: manifest.Errors})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFile, "output-file", "", "path of the report tarball, defaults to inspection-report-<timestamp>.tar.gz in the current directory")
+	cmd.Flags().IntVar(&opts.journalLines, "num-journal-entries", 100000, "the number of journal entries to collect for each service")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Minute, "the max time to wait for the command to execute")
+	return cmd
+}
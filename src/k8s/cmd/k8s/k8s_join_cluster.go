@@ -10,12 +10,15 @@ import (
 	apiv1 "github.com/canonical/k8s/api/v1"
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/canonical/k8s/pkg/config"
+	"github.com/canonical/k8s/pkg/k8sd/preflight"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/spf13/cobra"
 )
 
 type JoinClusterResult struct {
-	Name string `json:"name" yaml:"name"`
+	Name      string             `json:"name" yaml:"name"`
+	Preflight []preflight.Result `json:"preflight,omitempty" yaml:"preflight,omitempty"`
 }
 
 func (b JoinClusterResult) String() string {
@@ -29,6 +32,8 @@ func newJoinClusterCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		configFile   string
 		outputFormat string
 		timeout      time.Duration
+
+		ignorePreflightErrors []string
 	}
 	cmd := &cobra.Command{
 		Use:    "join-cluster <join-token>",
@@ -96,6 +101,12 @@ func newJoinClusterCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				joinClusterConfig = string(b)
 			}
 
+			preflightResults, ok := runPreflightChecks(cmd, env, joinPreflightOptions(token), opts.ignorePreflightErrors, opts.outputFormat)
+			if !ok {
+				env.Exit(1)
+				return
+			}
+
 			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
 			cobra.OnFinalize(cancel)
 
@@ -106,7 +117,7 @@ func newJoinClusterCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				return
 			}
 
-			outputFormatter.Print(JoinClusterResult{Name: opts.name})
+			outputFormatter.Print(JoinClusterResult{Name: opts.name, Preflight: preflightResults})
 		},
 	}
 	cmd.Flags().StringVar(&opts.name, "name", "", "node name, defaults to hostname")
@@ -114,5 +125,29 @@ func newJoinClusterCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	cmd.Flags().StringVar(&opts.configFile, "file", "", "path to the YAML file containing your custom cluster join configuration. Use '-' to read from stdin.")
 	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	cmd.Flags().StringSliceVar(&opts.ignorePreflightErrors, "ignore-preflight-errors", nil, "list of preflight checks whose errors are ignored, or 'all' to ignore all of them")
 	return cmd
 }
+
+// joinPreflightOptions returns the preflight options for joining a cluster with the given token.
+// Worker nodes run the apiserver proxy and the kubelet, control plane nodes also run the datastore.
+// Tokens created by older versions do not include the cluster ports, so the default ports are checked.
+func joinPreflightOptions(token string) preflight.Options {
+	ports := &types.JoinTokenPorts{APIServer: 6443, K8sDqlite: 9000}
+	opts := preflight.Options{ControlPlane: true, Network: true}
+	if workerToken := (&types.InternalWorkerNodeToken{}); workerToken.Decode(token) == nil {
+		opts.ControlPlane = false
+		if workerToken.Ports != nil {
+			ports = workerToken.Ports
+		}
+		ports.K8sDqlite = 0
+	} else if tokenPorts, err := types.ControlPlaneTokenPorts(token); err == nil && tokenPorts != nil {
+		ports = tokenPorts
+	}
+
+	opts.Ports = []int{ports.APIServer, 10250}
+	if ports.K8sDqlite != 0 {
+		opts.Ports = append(opts.Ports, ports.K8sDqlite)
+	}
+	return opts
+}
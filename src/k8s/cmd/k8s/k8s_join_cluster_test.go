@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/preflight"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	. "github.com/onsi/gomega"
+)
+
+func TestJoinPreflightOptions(t *testing.T) {
+	mustEncode := func(token *types.InternalWorkerNodeToken) string {
+		s, err := token.Encode()
+		if err != nil {
+			t.Fatalf("failed to encode worker token: %v", err)
+		}
+		return s
+	}
+	controlPlaneToken := base64.StdEncoding.EncodeToString([]byte(`{"name":"node2","secret":"secret"}`))
+	controlPlaneTokenWithPorts, err := types.WithControlPlaneTokenPorts(controlPlaneToken, types.JoinTokenPorts{APIServer: 16443, K8sDqlite: 19000})
+	if err != nil {
+		t.Fatalf("failed to add ports to control plane token: %v", err)
+	}
+	controlPlaneTokenExternal, err := types.WithControlPlaneTokenPorts(controlPlaneToken, types.JoinTokenPorts{APIServer: 6443})
+	if err != nil {
+		t.Fatalf("failed to add ports to control plane token: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		token      string
+		expectOpts preflight.Options
+	}{
+		{
+			name:       "ControlPlaneDefaultPorts",
+			token:      controlPlaneToken,
+			expectOpts: preflight.Options{ControlPlane: true, Network: true, Ports: []int{6443, 10250, 9000}},
+		},
+		{
+			name:       "ControlPlane",
+			token:      controlPlaneTokenWithPorts,
+			expectOpts: preflight.Options{ControlPlane: true, Network: true, Ports: []int{16443, 10250, 19000}},
+		},
+		{
+			name:       "ControlPlaneExternalDatastore",
+			token:      controlPlaneTokenExternal,
+			expectOpts: preflight.Options{ControlPlane: true, Network: true, Ports: []int{6443, 10250}},
+		},
+		{
+			name:       "WorkerDefaultPorts",
+			token:      mustEncode(&types.InternalWorkerNodeToken{Secret: "secret"}),
+			expectOpts: preflight.Options{Network: true, Ports: []int{6443, 10250}},
+		},
+		{
+			name:       "Worker",
+			token:      mustEncode(&types.InternalWorkerNodeToken{Secret: "secret", Ports: &types.JoinTokenPorts{APIServer: 16443}}),
+			expectOpts: preflight.Options{Network: true, Ports: []int{16443, 10250}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(joinPreflightOptions(tc.token)).To(Equal(tc.expectOpts))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+type NodeMaintenanceResult struct {
+	Name        string `json:"name" yaml:"name"`
+	Maintenance bool   `json:"maintenance" yaml:"maintenance"`
+}
+
+func (r NodeMaintenanceResult) String() string {
+	if r.Maintenance {
+		return fmt.Sprintf("Node %s is in maintenance.", r.Name)
+	}
+	return fmt.Sprintf("Node %s is no longer in maintenance.", r.Name)
+}
+
+func newNodeCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Manage the nodes of the cluster",
+	}
+
+	maintenanceCmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Put nodes in maintenance or take them out of it",
+		Long:  "Put nodes in maintenance, e.g. to patch or reboot the host, or take them out of it. The services of a node are not restarted to apply configuration changes while it is in maintenance.",
+	}
+	maintenanceCmd.AddCommand(newNodeMaintenanceCmd(env, true), newNodeMaintenanceCmd(env, false))
+
+	cmd.AddCommand(maintenanceCmd)
+	return cmd
+}
+
+// newNodeMaintenanceCmd creates the command to enter maintenance, or to exit it if enter is false.
+func newNodeMaintenanceCmd(env cmdutil.ExecutionEnvironment, enter bool) *cobra.Command {
+	var opts struct {
+		drainTimeout     time.Duration
+		gracePeriod      int
+		ignoreDaemonSets bool
+		outputFormat     string
+		timeout          time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "enter <node-name>",
+		Short:  "Put a node in maintenance",
+		Long:   "Put a node in maintenance. The node is cordoned and its pods are evicted. Control plane nodes are demoted from k8s-dqlite voters after another node is promoted to take their place, and stay voters if no node can replace them. If the command fails, the changes are reverted.",
+		Args:   cmdutil.ExactArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.timeout < minTimeout {
+				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
+				opts.timeout = minTimeout
+			}
+			if enter && opts.timeout <= opts.drainTimeout {
+				timeout := opts.drainTimeout + minTimeout
+				cmd.PrintErrf("Timeout %v is not longer than the drain timeout %v. Using %v instead.\n", opts.timeout, opts.drainTimeout, timeout)
+				opts.timeout = timeout
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			name := args[0]
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			request := apiv1.NodeMaintenanceRequest{Name: name, Maintenance: enter}
+			if enter {
+				cmd.PrintErrf("Putting %q in maintenance. This may take a few seconds, please wait.\n", name)
+				request.DrainTimeout = opts.drainTimeout
+				request.IgnoreDaemonSets = opts.ignoreDaemonSets
+				if opts.gracePeriod >= 0 {
+					request.GracePeriod = &opts.gracePeriod
+				}
+			}
+			if err := client.NodeMaintenance(ctx, request); err != nil {
+				if enter {
+					cmd.PrintErrf("Error: Failed to put node %q in maintenance. Run \"k8s node maintenance exit %s\" to revert the changes.\n\nThe error was: %v\n", name, name, err)
+				} else {
+					cmd.PrintErrf("Error: Failed to take node %q out of maintenance.\n\nThe error was: %v\n", name, err)
+				}
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(NodeMaintenanceResult{Name: name, Maintenance: enter})
+		},
+	}
+
+	if enter {
+		cmd.Flags().DurationVar(&opts.drainTimeout, "drain-timeout", 60*time.Second, "the max time to wait for the pods of the node to be evicted")
+		cmd.Flags().IntVar(&opts.gracePeriod, "grace-period", -1, "termination grace period in seconds of the evicted pods. set to -1 to use the grace period of each pod")
+		cmd.Flags().BoolVar(&opts.ignoreDaemonSets, "ignore-daemonsets", true, "skip pods managed by DaemonSets when draining the node. if false, draining fails if there are any")
+		cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute, including draining the node")
+	} else {
+		cmd.Use = "exit <node-name>"
+		cmd.Short = "Take a node out of maintenance"
+		cmd.Long = "Take a node out of maintenance. Control plane nodes that were demoted from k8s-dqlite voters are promoted again, and the node is uncordoned."
+		cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	}
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	return cmd
+}
@@ -0,0 +1,94 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestNodeMaintenanceCmd(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		err             error
+		expectedCode    int
+		expectedStdout  string
+		expectedStderr  string
+		expectedRequest apiv1.NodeMaintenanceRequest
+	}{
+		{
+			name:            "Enter",
+			args:            []string{"node", "maintenance", "enter", "n1"},
+			expectedStdout:  "Node n1 is in maintenance.",
+			expectedRequest: apiv1.NodeMaintenanceRequest{Name: "n1", Maintenance: true, DrainTimeout: 60 * time.Second, IgnoreDaemonSets: true},
+		},
+		{
+			name:            "EnterDrainOptions",
+			args:            []string{"node", "maintenance", "enter", "n1", "--drain-timeout", "5m", "--grace-period", "10", "--ignore-daemonsets=false"},
+			expectedStdout:  "Node n1 is in maintenance.",
+			expectedStderr:  "Using 5m3s instead",
+			expectedRequest: apiv1.NodeMaintenanceRequest{Name: "n1", Maintenance: true, DrainTimeout: 5 * time.Minute, GracePeriod: utils.Pointer(10)},
+		},
+		{
+			name:            "EnterFails",
+			args:            []string{"node", "maintenance", "enter", "n1"},
+			err:             fmt.Errorf("failed to drain node"),
+			expectedCode:    1,
+			expectedStderr:  `Run "k8s node maintenance exit n1" to revert the changes`,
+			expectedRequest: apiv1.NodeMaintenanceRequest{Name: "n1", Maintenance: true, DrainTimeout: 60 * time.Second, IgnoreDaemonSets: true},
+		},
+		{
+			name:            "Exit",
+			args:            []string{"node", "maintenance", "exit", "n1"},
+			expectedStdout:  "Node n1 is no longer in maintenance.",
+			expectedRequest: apiv1.NodeMaintenanceRequest{Name: "n1"},
+		},
+		{
+			name:            "ExitJSON",
+			args:            []string{"node", "maintenance", "exit", "n1", "--output-format", "json"},
+			expectedStdout:  `"maintenance": false`,
+			expectedRequest: apiv1.NodeMaintenanceRequest{Name: "n1"},
+		},
+		{
+			name:         "MissingName",
+			args:         []string{"node", "maintenance", "enter"},
+			expectedCode: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockClient := &mock.Client{NodeMaintenanceErr: tt.err}
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Exit:   func(rc int) { returnCode = rc },
+				Client: func(ctx context.Context) (client.Client, error) { return mockClient, nil },
+			}
+			cmd := k8s.NewRootCmd(env)
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tt.expectedCode))
+			g.Expect(stdout.String()).To(ContainSubstring(tt.expectedStdout))
+			g.Expect(stderr.String()).To(ContainSubstring(tt.expectedStderr))
+			g.Expect(mockClient.NodeMaintenanceCalledWith).To(Equal(tt.expectedRequest))
+		})
+	}
+}
@@ -20,9 +20,13 @@ func (r RemoveNodeResult) String() string {
 
 func newRemoveNodeCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
-		force        bool
-		outputFormat string
-		timeout      time.Duration
+		force            bool
+		drain            bool
+		drainTimeout     time.Duration
+		gracePeriod      int
+		ignoreDaemonSets bool
+		outputFormat     string
+		timeout          time.Duration
 	}
 	cmd := &cobra.Command{
 		Use:    "remove-node <node-name>",
@@ -34,6 +38,11 @@ func newRemoveNodeCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
 				opts.timeout = minTimeout
 			}
+			if opts.drain && opts.timeout <= opts.drainTimeout {
+				timeout := opts.drainTimeout + minTimeout
+				cmd.PrintErrf("Timeout %v is not longer than the drain timeout %v. Using %v instead.\n", opts.timeout, opts.drainTimeout, timeout)
+				opts.timeout = timeout
+			}
 
 			client, err := env.Client(cmd.Context())
 			if err != nil {
@@ -48,7 +57,17 @@ func newRemoveNodeCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 			cobra.OnFinalize(cancel)
 
 			cmd.PrintErrf("Removing %q from the Kubernetes cluster. This may take a few seconds, please wait.\n", name)
-			if err := client.RemoveNode(ctx, apiv1.RemoveNodeRequest{Name: name, Force: opts.force}); err != nil {
+			request := apiv1.RemoveNodeRequest{
+				Name:             name,
+				Force:            opts.force,
+				Drain:            opts.drain,
+				DrainTimeout:     opts.drainTimeout,
+				IgnoreDaemonSets: opts.ignoreDaemonSets,
+			}
+			if opts.gracePeriod >= 0 {
+				request.GracePeriod = &opts.gracePeriod
+			}
+			if err := client.RemoveNode(ctx, request); err != nil {
 				cmd.PrintErrf("Error: Failed to remove node %q from the cluster.\n\nThe error was: %v\n", name, err)
 				env.Exit(1)
 				return
@@ -58,9 +77,13 @@ func newRemoveNodeCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().BoolVar(&opts.force, "force", false, "forcibly remove the cluster member")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "forcibly remove the cluster member, even if draining the node fails")
+	cmd.Flags().BoolVar(&opts.drain, "drain", true, "cordon the node and evict its pods before removing it. evictions honor PodDisruptionBudgets")
+	cmd.Flags().DurationVar(&opts.drainTimeout, "drain-timeout", 60*time.Second, "the max time to wait for the pods of the node to be evicted")
+	cmd.Flags().IntVar(&opts.gracePeriod, "grace-period", -1, "termination grace period in seconds of the evicted pods. set to -1 to use the grace period of each pod")
+	cmd.Flags().BoolVar(&opts.ignoreDaemonSets, "ignore-daemonsets", true, "skip pods managed by DaemonSets when draining the node. if false, draining fails if there are any")
 	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
-	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute, including draining the node")
 
 	return cmd
 }
@@ -2,12 +2,15 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
 	cmdutil "github.com/canonical/k8s/cmd/util"
+	k8sclient "github.com/canonical/k8s/pkg/k8s/client"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
@@ -21,24 +24,31 @@ func (s SetResult) String() string {
 	return "Configuration updated."
 }
 
+// maxSetAttempts is the number of times "k8s set" applies list operations if the cluster configuration is changed concurrently.
+const maxSetAttempts = 5
+
 func newSetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
 		outputFormat string
 		timeout      time.Duration
 	}
 	cmd := &cobra.Command{
-		Use:    "set <feature.key=value> ...",
-		Short:  "Set cluster configuration",
-		Long:   fmt.Sprintf("Configure one of %s.\nUse `k8s get` to explore configuration options.", strings.Join(featureList, ", ")),
+		Use:   "set <feature.key=value> ...",
+		Short: "Set cluster configuration",
+		Long: fmt.Sprintf(`Configure one of %s.
+Use "<key>+=<value>" to add items to a list and "<key>-=<value>" to remove items from a list, e.g. "load-balancer.cidrs+=10.0.0.0/24".
+Use "k8s get" to explore configuration options and "k8s unset" to revert options to their default value.`, strings.Join(featureList, ", ")),
 		Args:   cmdutil.MinimumNArgs(env, 1),
 		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeConfigKeys(toComplete, false, "="), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			config := apiv1.UserFacingClusterConfig{}
-
 			for _, arg := range args {
-				if err := updateConfigMapstructure(&config, arg); err != nil {
+				if _, _, _, err := parseConfigArg(arg); err != nil {
 					cmd.PrintErrf("Error: Invalid option %q.\n\nThe error was: %v\n", arg, err)
 					env.Exit(1)
+					return
 				}
 			}
 
@@ -49,20 +59,46 @@ func newSetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				return
 			}
 
-			request := apiv1.UpdateClusterConfigRequest{
-				Config: config,
-			}
-
 			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
 			cobra.OnFinalize(cancel)
 
-			if err := client.UpdateClusterConfig(ctx, request); err != nil {
-				cmd.PrintErrf("Error: Failed to apply requested cluster configuration changes.\n\nThe error was: %v\n", err)
-				env.Exit(1)
+			// list operations are applied to the current configuration of the cluster. The update is rejected if the
+			// configuration was changed in the meantime, so the changes are computed again from a fresh read.
+			// Plain "key=value" options do not depend on the current configuration and are applied unconditionally.
+			readCurrent := slices.ContainsFunc(args, isConfigListArg)
+			for attempt := 1; ; attempt++ {
+				var current apiv1.UserFacingClusterConfig
+				var request apiv1.UpdateClusterConfigRequest
+				if readCurrent {
+					var revision int
+					if current, revision, err = client.GetClusterConfig(ctx, apiv1.GetClusterConfigRequest{}); err != nil {
+						cmd.PrintErrf("Error: Failed to get the current cluster configuration.\n\nThe error was: %v\n", err)
+						env.Exit(1)
+						return
+					}
+					request.ExpectedRevision = &revision
+				}
+
+				for _, arg := range args {
+					if err := updateConfig(&request.Config, current, arg); err != nil {
+						cmd.PrintErrf("Error: Invalid option %q.\n\nThe error was: %v\n", arg, err)
+						env.Exit(1)
+						return
+					}
+				}
+
+				if err := client.UpdateClusterConfig(ctx, request); err != nil {
+					if errors.Is(err, k8sclient.ErrClusterConfigConflict) && attempt < maxSetAttempts {
+						continue
+					}
+					cmd.PrintErrf("Error: Failed to apply requested cluster configuration changes.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+
+				outputFormatter.Print(SetResult{ClusterConfig: request.Config})
 				return
 			}
-
-			outputFormatter.Print(SetResult{ClusterConfig: config})
 		},
 	}
 
@@ -72,31 +108,23 @@ func newSetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	return cmd
 }
 
-var knownSetKeys = map[string]struct{}{
-	"cloud-provider":                 {},
-	"dns.cluster-domain":             {},
-	"dns.enabled":                    {},
-	"dns.service-ip":                 {},
-	"dns.upstream-nameservers":       {},
-	"gateway.enabled":                {},
-	"ingress.default-tls-secret":     {},
-	"ingress.enable-proxy-protocol":  {},
-	"ingress.enabled":                {},
-	"load-balancer.bgp-local-asn":    {},
-	"load-balancer.bgp-mode":         {},
-	"load-balancer.bgp-peer-address": {},
-	"load-balancer.bgp-peer-asn":     {},
-	"load-balancer.bgp-peer-port":    {},
-	"load-balancer.cidrs":            {},
-	"load-balancer.enabled":          {},
-	"load-balancer.l2-interfaces":    {},
-	"load-balancer.l2-mode":          {},
-	"local-storage.default":          {},
-	"local-storage.enabled":          {},
-	"local-storage.local-path":       {},
-	"local-storage.reclaim-policy":   {},
-	"metrics-server.enabled":         {},
-	"network.enabled":                {},
+// updateConfig applies a "key=value", "key+=value" or "key-=value" argument to config.
+// current is the configuration of the cluster, it is only used for list operations.
+func updateConfig(config *apiv1.UserFacingClusterConfig, current apiv1.UserFacingClusterConfig, arg string) error {
+	key, op, value, err := parseConfigArg(arg)
+	if err != nil {
+		return err
+	}
+	if op == 0 {
+		return updateConfigMapstructure(config, arg)
+	}
+	return updateConfigList(config, current, key, op, value)
+}
+
+// isConfigListArg is true for "key+=value" and "key-=value" arguments.
+func isConfigListArg(arg string) bool {
+	_, op, _, err := parseConfigArg(arg)
+	return err == nil && op != 0
 }
 
 func updateConfigMapstructure(config *apiv1.UserFacingClusterConfig, arg string) error {
@@ -106,6 +134,7 @@ func updateConfigMapstructure(config *apiv1.UserFacingClusterConfig, arg string)
 		ErrorUnused:      true,
 		Result:           config,
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			utils.YAMLToStructSliceHookFunc,
 			utils.YAMLToStringSliceHookFunc,
 			utils.StringToFieldsSliceHookFunc(','),
 		),
@@ -121,7 +150,7 @@ func updateConfigMapstructure(config *apiv1.UserFacingClusterConfig, arg string)
 	key := parts[0]
 	value := parts[1]
 
-	if _, ok := knownSetKeys[key]; !ok {
+	if k, ok := lookupConfigKey(key); !ok || k.section {
 		return fmt.Errorf("unknown option key %q", key)
 	}
 
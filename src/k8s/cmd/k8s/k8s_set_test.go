@@ -1,10 +1,15 @@
 package k8s
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
 	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
@@ -109,10 +114,89 @@ func generateMapstructureTestCasesInt(keyName string, fieldName string) []mapstr
 	}
 }
 
+func generateMapstructureTestCasesRegistries(keyName string, fieldName string) []mapstructureTestCase {
+	return []mapstructureTestCase{
+		{
+			val:        fmt.Sprintf("%s=", keyName),
+			assertions: []types.GomegaMatcher{HaveField(fieldName, utils.Pointer([]apiv1.ContainerdRegistryConfig{}))},
+		},
+		{
+			val:        fmt.Sprintf("%s=[]", keyName),
+			assertions: []types.GomegaMatcher{HaveField(fieldName, utils.Pointer([]apiv1.ContainerdRegistryConfig{}))},
+		},
+		{
+			val: fmt.Sprintf(`%s=[{host: docker.io, urls: ["https://mirror.internal"], capabilities: [pull, resolve], skip-verify: true}]`, keyName),
+			assertions: []types.GomegaMatcher{HaveField(fieldName, utils.Pointer([]apiv1.ContainerdRegistryConfig{
+				{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Capabilities: []string{"pull", "resolve"}, SkipVerify: true},
+			}))},
+		},
+		{
+			val:       fmt.Sprintf("%s=[{host: docker.io, unknown: value}]", keyName),
+			expectErr: true,
+		},
+		{
+			val:       fmt.Sprintf("%s=docker.io", keyName),
+			expectErr: true,
+		},
+	}
+}
+
+func generateMapstructureTestCasesRuntimeHandlers(keyName string, fieldName string) []mapstructureTestCase {
+	return []mapstructureTestCase{
+		{
+			val:        fmt.Sprintf("%s=", keyName),
+			assertions: []types.GomegaMatcher{HaveField(fieldName, utils.Pointer([]apiv1.ContainerdRuntimeHandlerConfig{}))},
+		},
+		{
+			val: fmt.Sprintf(`%s=[{name: gvisor, runtime-type: io.containerd.runsc.v1, options: {TypeUrl: io.containerd.runsc.v1.options}, node-selector: {sandbox: gvisor}}]`, keyName),
+			assertions: []types.GomegaMatcher{HaveField(fieldName, utils.Pointer([]apiv1.ContainerdRuntimeHandlerConfig{
+				{
+					Name:         "gvisor",
+					RuntimeType:  "io.containerd.runsc.v1",
+					Options:      map[string]any{"TypeUrl": "io.containerd.runsc.v1.options"},
+					NodeSelector: map[string]string{"sandbox": "gvisor"},
+				},
+			}))},
+		},
+		{
+			val:       fmt.Sprintf("%s=gvisor", keyName),
+			expectErr: true,
+		},
+	}
+}
+
+func generateMapstructureTestCasesDNS() []mapstructureTestCase {
+	return []mapstructureTestCase{
+		{
+			val:        "dns.stub-zones=[{zone: corp.internal, nameservers: [10.0.0.10, 10.0.0.11]}]",
+			assertions: []types.GomegaMatcher{HaveField("DNS.StubZones", utils.Pointer([]apiv1.DNSStubZoneConfig{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10", "10.0.0.11"}}}))},
+		},
+		{
+			val:        "dns.hosts=[{ip: 10.0.0.20, hostnames: [git.corp.internal, git]}]",
+			assertions: []types.GomegaMatcher{HaveField("DNS.Hosts", utils.Pointer([]apiv1.DNSHostConfig{{IP: "10.0.0.20", Hostnames: []string{"git.corp.internal", "git"}}}))},
+		},
+		{
+			val:        `dns.extra-plugins=[{name: log}, {name: rewrite, parameters: "name old.internal new.internal"}]`,
+			assertions: []types.GomegaMatcher{HaveField("DNS.ExtraPlugins", utils.Pointer([]apiv1.DNSPluginConfig{{Name: "log"}, {Name: "rewrite", Parameters: "name old.internal new.internal"}}))},
+		},
+		{
+			val: `dns.extra-servers=[{zones: [example.com], port: 5353, plugins: [{name: file, parameters: /etc/coredns/example.db}]}]`,
+			assertions: []types.GomegaMatcher{HaveField("DNS.ExtraServers", utils.Pointer([]apiv1.DNSServerConfig{
+				{Zones: []string{"example.com"}, Port: 5353, Plugins: []apiv1.DNSPluginConfig{{Name: "file", Parameters: "/etc/coredns/example.db"}}},
+			}))},
+		},
+		{
+			val:       "dns.stub-zones=corp.internal",
+			expectErr: true,
+		},
+	}
+}
+
 func Test_updateConfigMapstructure(t *testing.T) {
 	for _, tcs := range [][]mapstructureTestCase{
 		generateMapstructureTestCasesBool("dns.enabled", "DNS.Enabled"),
 		generateMapstructureTestCasesBool("gateway.enabled", "Gateway.Enabled"),
+		generateMapstructureTestCasesBool("control-plane-endpoint.use-for-workers", "ControlPlaneEndpoint.UseForWorkers"),
 		generateMapstructureTestCasesBool("ingress.enable-proxy-protocol", "Ingress.EnableProxyProtocol"),
 		generateMapstructureTestCasesBool("ingress.enabled", "Ingress.Enabled"),
 		generateMapstructureTestCasesBool("load-balancer.bgp-mode", "LoadBalancer.BGPMode"),
@@ -123,10 +207,14 @@ func Test_updateConfigMapstructure(t *testing.T) {
 		generateMapstructureTestCasesBool("local-storage.enabled", "LocalStorage.Enabled"),
 		generateMapstructureTestCasesBool("metrics-server.enabled", "MetricsServer.Enabled"),
 		generateMapstructureTestCasesBool("network.enabled", "Network.Enabled"),
+		generateMapstructureTestCasesBool("node-local-dns.enabled", "NodeLocalDNS.Enabled"),
 
 		generateMapstructureTestCasesString("cloud-provider", "CloudProvider"),
 		generateMapstructureTestCasesString("dns.cluster-domain", "DNS.ClusterDomain"),
 		generateMapstructureTestCasesString("dns.service-ip", "DNS.ServiceIP"),
+		generateMapstructureTestCasesString("image-registry", "ImageRegistry"),
+		generateMapstructureTestCasesString("control-plane-endpoint.address", "ControlPlaneEndpoint.Address"),
+		generateMapstructureTestCasesString("node-local-dns.local-ip", "NodeLocalDNS.LocalIP"),
 		generateMapstructureTestCasesString("ingress.default-tls-secret", "Ingress.DefaultTLSSecret"),
 		generateMapstructureTestCasesString("load-balancer.bgp-peer-address", "LoadBalancer.BGPPeerAddress"),
 		generateMapstructureTestCasesString("local-storage.local-path", "LocalStorage.LocalPath"),
@@ -136,6 +224,11 @@ func Test_updateConfigMapstructure(t *testing.T) {
 		generateMapstructureTestCasesStringSlice("load-balancer.cidrs", "LoadBalancer.CIDRs"),
 		generateMapstructureTestCasesStringSlice("load-balancer.l2-interfaces", "LoadBalancer.L2Interfaces"),
 
+		generateMapstructureTestCasesRegistries("containerd.registries", "Containerd.Registries"),
+		generateMapstructureTestCasesRuntimeHandlers("containerd.runtime-handlers", "Containerd.RuntimeHandlers"),
+		generateMapstructureTestCasesDNS(),
+
+		generateMapstructureTestCasesInt("dns.cache-ttl", "DNS.CacheTTL"),
 		generateMapstructureTestCasesInt("load-balancer.bgp-local-asn", "LoadBalancer.BGPLocalASN"),
 		generateMapstructureTestCasesInt("load-balancer.bgp-peer-asn", "LoadBalancer.BGPPeerASN"),
 		generateMapstructureTestCasesInt("load-balancer.bgp-peer-port", "LoadBalancer.BGPPeerPort"),
@@ -156,3 +249,88 @@ func Test_updateConfigMapstructure(t *testing.T) {
 		}
 	}
 }
+
+func TestSetCmd(t *testing.T) {
+	current := apiv1.UserFacingClusterConfig{
+		LoadBalancer: apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{"10.0.0.0/24"})},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		args            []string
+		conflicts       int
+		expectedRequest apiv1.UpdateClusterConfigRequest
+		expectedCalls   int
+		expectedCode    int
+		expectedStderr  string
+	}{
+		{
+			name: "PlainSetIsUnconditional",
+			args: []string{"dns.enabled=true"},
+			// conflicts are only reported for requests with an expected revision
+			conflicts: 1,
+			expectedRequest: apiv1.UpdateClusterConfigRequest{
+				Config: apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name: "ListOperationExpectsRevision",
+			args: []string{"load-balancer.cidrs+=10.0.1.0/24"},
+			expectedRequest: apiv1.UpdateClusterConfigRequest{
+				Config:           apiv1.UserFacingClusterConfig{LoadBalancer: apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{"10.0.0.0/24", "10.0.1.0/24"})}},
+				ExpectedRevision: utils.Pointer(3),
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:      "ListOperationRetriesOnConflict",
+			args:      []string{"load-balancer.cidrs-=10.0.0.0/24"},
+			conflicts: 2,
+			expectedRequest: apiv1.UpdateClusterConfigRequest{
+				Config:           apiv1.UserFacingClusterConfig{LoadBalancer: apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{})}},
+				ExpectedRevision: utils.Pointer(3),
+			},
+			expectedCalls: 3,
+		},
+		{
+			name:           "ListOperationGivesUp",
+			args:           []string{"load-balancer.cidrs+=10.0.1.0/24"},
+			conflicts:      maxSetAttempts,
+			expectedCalls:  maxSetAttempts,
+			expectedCode:   1,
+			expectedStderr: "changed concurrently",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			mockClient := &mock.Client{UpdateClusterConfigConflicts: tc.conflicts}
+			mockClient.GetClusterConfigReturn.Config = current
+			mockClient.GetClusterConfigReturn.Revision = 3
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: stderr,
+				Getuid: func() int { return 0 },
+				Client: func(ctx context.Context) (client.Client, error) {
+					return mockClient, nil
+				},
+				Exit: func(rc int) { returnCode = rc },
+			}
+			cmd := NewRootCmd(env)
+			cmd.SetArgs(append([]string{"set"}, tc.args...))
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(tc.expectedCode))
+			g.Expect(stderr.String()).To(ContainSubstring(tc.expectedStderr))
+			g.Expect(mockClient.UpdateClusterConfigCalls).To(Equal(tc.expectedCalls))
+			if tc.expectedCode == 0 {
+				g.Expect(stdout.String()).To(ContainSubstring("Configuration updated."))
+				g.Expect(mockClient.UpdateClusterConfigCalledWith).To(Equal(tc.expectedRequest))
+			}
+		})
+	}
+}
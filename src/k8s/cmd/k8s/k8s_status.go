@@ -2,29 +2,151 @@ package k8s
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
 	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/proxy"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils/control"
 	"github.com/spf13/cobra"
 )
 
+// WorkerStatusResult is the status of a worker node.
+type WorkerStatusResult struct {
+	APIServerProxy apiv1.APIServerProxyStatus `json:"apiserver-proxy" yaml:"apiserver-proxy"`
+}
+
+func (r WorkerStatusResult) String() string {
+	b := &strings.Builder{}
+	b.WriteString("role: worker\n")
+	b.WriteString("apiserver-proxy:\n")
+	fmt.Fprintf(b, "  listen-address: %s\n", r.APIServerProxy.ListenAddress)
+	fmt.Fprintf(b, "  strategy: %s\n", r.APIServerProxy.Strategy)
+	b.WriteString("  endpoints:")
+	if len(r.APIServerProxy.Endpoints) == 0 {
+		b.WriteString(" none")
+	}
+	for _, endpoint := range r.APIServerProxy.Endpoints {
+		state := "active"
+		if !endpoint.Active {
+			state = "inactive"
+		}
+		fmt.Fprintf(b, "\n    - %s (%s, %d connections)", endpoint.Address, state, endpoint.Connections)
+	}
+	return b.String()
+}
+
+func (r WorkerStatusResult) TableHeaders(wide bool) []string {
+	headers := []string{"ENDPOINT", "STATE", "CONNECTIONS"}
+	if wide {
+		headers = append(headers, "CONNECTIONS-TOTAL", "DIAL-FAILURES")
+	}
+	return headers
+}
+
+func (r WorkerStatusResult) TableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(r.APIServerProxy.Endpoints))
+	for _, endpoint := range r.APIServerProxy.Endpoints {
+		state := "active"
+		if !endpoint.Active {
+			state = "inactive"
+		}
+		row := []string{endpoint.Address, state, strconv.Itoa(endpoint.Connections)}
+		if wide {
+			row = append(row, strconv.FormatUint(endpoint.ConnectionsTotal, 10), strconv.FormatUint(endpoint.DialFailuresTotal, 10))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// workerStatus retrieves the status of the API server proxy at statusAddress.
+// If waitReady is set, workerStatus waits until the proxy has an active kube-apiserver endpoint.
+func workerStatus(ctx context.Context, statusAddress string, waitReady bool) (apiv1.APIServerProxyStatus, error) {
+	status, err := proxy.GetStatus(ctx, statusAddress)
+	if !waitReady || (err == nil && hasActiveEndpoint(status)) {
+		return status, err
+	}
+
+	if waitErr := control.WaitUntilReady(ctx, func() (bool, error) {
+		status, err = proxy.GetStatus(ctx, statusAddress)
+		// the proxy may still be starting
+		return err == nil && hasActiveEndpoint(status), nil
+	}); waitErr != nil {
+		if err != nil {
+			return apiv1.APIServerProxyStatus{}, err
+		}
+		return apiv1.APIServerProxyStatus{}, fmt.Errorf("no active kube-apiserver endpoint: %w", waitErr)
+	}
+	return status, nil
+}
+
+func hasActiveEndpoint(status apiv1.APIServerProxyStatus) bool {
+	for _, endpoint := range status.Endpoints {
+		if endpoint.Active {
+			return true
+		}
+	}
+	return false
+}
+
 func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 	var opts struct {
 		waitReady    bool
+		wide         bool
 		outputFormat string
 		timeout      time.Duration
 	}
 	cmd := &cobra.Command{
-		Use:    "status",
-		Short:  "Retrieve the current status of the cluster",
-		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		Use:   "status",
+		Short: "Retrieve the current status of the cluster",
+		Long:  "Retrieve the current status of the cluster. Use --wide to list every node with its Kubernetes node status and the health of its services.\nOn worker nodes, show the kube-apiserver endpoints known to the local API server proxy and their connections.",
+		PreRun: chainPreRunHooks(
+			hookRequireRoot(env),
+			func(cmd *cobra.Command, args []string) {
+				if opts.wide && opts.outputFormat == "plain" {
+					opts.outputFormat = "wide"
+				}
+			},
+			hookInitializeFormatter(env, &opts.outputFormat),
+		),
 		Run: func(cmd *cobra.Command, args []string) {
 			if opts.timeout < minTimeout {
 				cmd.PrintErrf("Timeout %v is less than minimum of %v. Using the minimum %v instead.\n", opts.timeout, minTimeout, minTimeout)
 				opts.timeout = minTimeout
 			}
 
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			isWorker, err := snaputil.IsWorker(env.Snap)
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to check if this is worker-only node.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			if isWorker {
+				statusAddress, err := snaputil.GetServiceArgument(env.Snap, "k8s-apiserver-proxy", "--status-address")
+				if err != nil || statusAddress == "" {
+					cmd.PrintErrln("Error: The status endpoint of the API server proxy is not enabled on this worker node.")
+					env.Exit(1)
+					return
+				}
+				status, err := workerStatus(ctx, statusAddress, opts.waitReady)
+				if err != nil {
+					cmd.PrintErrf("Error: Failed to retrieve the API server proxy status. Make sure that the k8s-apiserver-proxy service is running.\n\nThe error was: %v\n", err)
+					env.Exit(1)
+					return
+				}
+				outputFormatter.Print(WorkerStatusResult{APIServerProxy: status})
+				return
+			}
+
 			client, err := env.Client(cmd.Context())
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
@@ -32,16 +154,14 @@ func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				return
 			}
 
-			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
-			cobra.OnFinalize(cancel)
-
 			if !client.IsBootstrapped(ctx) {
 				cmd.PrintErrln("Error: The node is not part of a Kubernetes cluster. You can bootstrap a new cluster with:\n\n  sudo k8s bootstrap")
 				env.Exit(1)
 				return
 			}
 
-			status, err := client.ClusterStatus(ctx, opts.waitReady)
+			request := apiv1.GetClusterStatusRequest{Detailed: opts.wide || opts.outputFormat == "wide"}
+			status, err := client.ClusterStatus(ctx, request, opts.waitReady)
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to retrieve the cluster status.\n\nThe error was: %v\n", err)
 				env.Exit(1)
@@ -55,8 +175,9 @@ func newStatusCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().BoolVar(&opts.waitReady, "wait-ready", false, "wait until at least one cluster node is ready")
-	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().BoolVar(&opts.waitReady, "wait-ready", false, "wait until at least one cluster node is ready. on worker nodes, wait until the API server proxy has an active kube-apiserver endpoint")
+	cmd.Flags().BoolVar(&opts.wide, "wide", false, "include the Kubernetes node status and the service health of every node")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json, yaml, table or wide")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
 	return cmd
 }
@@ -0,0 +1,207 @@
+package k8s_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/cmd/k8s"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	. "github.com/onsi/gomega"
+)
+
+func TestStatusCmd(t *testing.T) {
+	status := apiv1.ClusterStatus{
+		Ready: true,
+		Members: []apiv1.NodeStatus{
+			{
+				Name:           "node1",
+				Address:        "192.168.0.1:6400",
+				ClusterRole:    apiv1.ClusterRoleControlPlane,
+				DatastoreRole:  apiv1.DatastoreRoleVoter,
+				KubernetesNode: &apiv1.KubernetesNodeStatus{Ready: true, KubeletVersion: "v1.30.0", InternalIP: "192.168.0.1"},
+				Services:       map[string]string{"kubelet": "active"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedDetailed bool
+		expectedStdout   []string
+		unexpectedStdout []string
+	}{
+		{
+			name:             "Plain",
+			args:             []string{"status"},
+			expectedStdout:   []string{"status: ready"},
+			unexpectedStdout: []string{"NAME"},
+		},
+		{
+			name:             "Table",
+			args:             []string{"status", "--output-format", "table"},
+			expectedStdout:   []string{"NAME", "DATASTORE-ROLE", "node1", "voter"},
+			unexpectedStdout: []string{"KUBELET-VERSION", "v1.30.0"},
+		},
+		{
+			name:             "Wide",
+			args:             []string{"status", "--wide"},
+			expectedDetailed: true,
+			expectedStdout:   []string{"NAME", "STATUS", "node1", "Ready", "v1.30.0", "192.168.0.1", "ok"},
+		},
+		{
+			name:             "WideJSON",
+			args:             []string{"status", "--wide", "--output-format", "json"},
+			expectedDetailed: true,
+			expectedStdout:   []string{`"kubelet-version": "v1.30.0"`, `"kubelet": "active"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockClient := &mock.Client{IsBootstrappedReturn: true, ClusterStatusReturn: status}
+			stdout := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: &bytes.Buffer{},
+				Getuid: func() int { return 0 },
+				Exit:   func(rc int) { returnCode = rc },
+				Client: func(ctx context.Context) (client.Client, error) { return mockClient, nil },
+				Snap:   &snapmock.Snap{Mock: snapmock.Mock{LockFilesDir: t.TempDir()}},
+			}
+			cmd := k8s.NewRootCmd(env)
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(0))
+			g.Expect(mockClient.ClusterStatusCalledWith.Detailed).To(Equal(tt.expectedDetailed))
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+			for _, unexpected := range tt.unexpectedStdout {
+				g.Expect(stdout.String()).ToNot(ContainSubstring(unexpected))
+			}
+		})
+	}
+}
+
+func TestStatusCmdWorker(t *testing.T) {
+	status := apiv1.APIServerProxyStatus{
+		ListenAddress: "127.0.0.1:6443",
+		Strategy:      "least-connections",
+		Endpoints: []apiv1.APIServerProxyEndpoint{
+			{Address: "10.0.0.1:6443", Active: true, Connections: 3, ConnectionsTotal: 10},
+			{Address: "10.0.0.2:6443", Active: false, DialFailuresTotal: 2},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(status)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedStdout []string
+	}{
+		{
+			name:           "Plain",
+			args:           []string{"status"},
+			expectedStdout: []string{"role: worker", "strategy: least-connections", "10.0.0.1:6443 (active, 3 connections)", "10.0.0.2:6443 (inactive, 0 connections)"},
+		},
+		{
+			name:           "Wide",
+			args:           []string{"status", "--wide"},
+			expectedStdout: []string{"ENDPOINT", "DIAL-FAILURES", "10.0.0.1:6443", "10"},
+		},
+		{
+			name:           "JSON",
+			args:           []string{"status", "--output-format", "json"},
+			expectedStdout: []string{`"apiserver-proxy"`, `"dial-failures-total": 2`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			snap := &snapmock.Snap{Mock: snapmock.Mock{LockFilesDir: dir, ServiceArgumentsDir: dir}}
+			g.Expect(snaputil.MarkAsWorkerNode(snap, true)).To(Succeed())
+			g.Expect(os.WriteFile(path.Join(dir, "k8s-apiserver-proxy"), []byte("--status-address="+strings.TrimPrefix(server.URL, "http://")+"\n"), 0600)).To(Succeed())
+
+			mockClient := &mock.Client{}
+			stdout := &bytes.Buffer{}
+			var returnCode int
+			env := cmdutil.ExecutionEnvironment{
+				Stdout: stdout,
+				Stderr: &bytes.Buffer{},
+				Getuid: func() int { return 0 },
+				Exit:   func(rc int) { returnCode = rc },
+				Client: func(ctx context.Context) (client.Client, error) { return mockClient, nil },
+				Snap:   snap,
+			}
+			cmd := k8s.NewRootCmd(env)
+			cmd.SetArgs(tt.args)
+			cmd.Execute()
+
+			g.Expect(returnCode).To(Equal(0))
+			for _, expected := range tt.expectedStdout {
+				g.Expect(stdout.String()).To(ContainSubstring(expected))
+			}
+		})
+	}
+}
+
+func TestStatusCmdWorkerWaitReady(t *testing.T) {
+	g := NewWithT(t)
+
+	// the first status has no active endpoints
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		active := requests.Add(1) > 1
+		json.NewEncoder(w).Encode(apiv1.APIServerProxyStatus{
+			Endpoints: []apiv1.APIServerProxyEndpoint{{Address: "10.0.0.1:6443", Active: active}},
+		})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	snap := &snapmock.Snap{Mock: snapmock.Mock{LockFilesDir: dir, ServiceArgumentsDir: dir}}
+	g.Expect(snaputil.MarkAsWorkerNode(snap, true)).To(Succeed())
+	g.Expect(os.WriteFile(path.Join(dir, "k8s-apiserver-proxy"), []byte("--status-address="+strings.TrimPrefix(server.URL, "http://")+"\n"), 0600)).To(Succeed())
+
+	stdout := &bytes.Buffer{}
+	var returnCode int
+	env := cmdutil.ExecutionEnvironment{
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+		Getuid: func() int { return 0 },
+		Exit:   func(rc int) { returnCode = rc },
+		Client: func(ctx context.Context) (client.Client, error) { return &mock.Client{}, nil },
+		Snap:   snap,
+	}
+	cmd := k8s.NewRootCmd(env)
+	cmd.SetArgs([]string{"status", "--wait-ready"})
+	cmd.Execute()
+
+	g.Expect(returnCode).To(Equal(0))
+	g.Expect(requests.Load()).To(BeNumerically(">", 1))
+	g.Expect(stdout.String()).To(ContainSubstring("10.0.0.1:6443 (active, 0 connections)"))
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+func newUnsetCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
+	var opts struct {
+		outputFormat string
+		timeout      time.Duration
+	}
+	cmd := &cobra.Command{
+		Use:    "unset <feature.key> ...",
+		Short:  "Revert cluster configuration to the defaults",
+		Long:   "Revert cluster configuration options to their default value. Options without a default value are cleared.\nUse `k8s get` to explore configuration options.",
+		Args:   cmdutil.MinimumNArgs(env, 1),
+		PreRun: chainPreRunHooks(hookRequireRoot(env), hookInitializeFormatter(env, &opts.outputFormat)),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeConfigKeys(toComplete, false, ""), cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			config := apiv1.UserFacingClusterConfig{}
+			var unset []string
+
+			for _, arg := range args {
+				clear, err := resetConfigKey(&config, arg)
+				if err != nil {
+					cmd.PrintErrf("Error: Invalid option %q.\n\nThe error was: %v\n", arg, err)
+					env.Exit(1)
+					return
+				}
+				if clear {
+					unset = append(unset, arg)
+				}
+			}
+
+			client, err := env.Client(cmd.Context())
+			if err != nil {
+				cmd.PrintErrf("Error: Failed to create a k8sd client. Make sure that the k8sd service is running.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			cobra.OnFinalize(cancel)
+
+			if err := client.UpdateClusterConfig(ctx, apiv1.UpdateClusterConfigRequest{Config: config, Unset: unset}); err != nil {
+				cmd.PrintErrf("Error: Failed to apply requested cluster configuration changes.\n\nThe error was: %v\n", err)
+				env.Exit(1)
+				return
+			}
+
+			outputFormatter.Print(SetResult{ClusterConfig: config})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "plain", "set the output format to one of plain, json or yaml")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 90*time.Second, "the max time to wait for the command to execute")
+
+	return cmd
+}
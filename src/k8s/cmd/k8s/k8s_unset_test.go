@@ -0,0 +1,39 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/k8s/client/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestUnsetCmd(t *testing.T) {
+	g := NewWithT(t)
+
+	mockClient := &mock.Client{}
+	var returnCode int
+	env := cmdutil.ExecutionEnvironment{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+		Getuid: func() int { return 0 },
+		Client: func(ctx context.Context) (client.Client, error) {
+			return mockClient, nil
+		},
+		Exit: func(rc int) { returnCode = rc },
+	}
+	cmd := NewRootCmd(env)
+	cmd.SetArgs([]string{"unset", "dns.cache-ttl", "load-balancer.bgp-local-asn", "dns.service-ip"})
+	cmd.Execute()
+
+	g.Expect(returnCode).To(Equal(0))
+	g.Expect(mockClient.UpdateClusterConfigCalledWith).To(Equal(apiv1.UpdateClusterConfigRequest{
+		Config: apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{CacheTTL: utils.Pointer(30)}},
+		Unset:  []string{"load-balancer.bgp-local-asn", "dns.service-ip"},
+	}))
+}
@@ -53,7 +53,7 @@ func newXSnapdConfigCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 					env.Exit(1)
 					return
 				}
-				config, err := client.GetClusterConfig(cmd.Context(), apiv1.GetClusterConfigRequest{})
+				config, _, err := client.GetClusterConfig(cmd.Context(), apiv1.GetClusterConfigRequest{})
 				if err != nil {
 					cmd.PrintErrf("Error: failed to retrieve cluster configuration: %v\n", err)
 					env.Exit(1)
@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"strings"
+
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	"github.com/canonical/k8s/pkg/k8sd/preflight"
+	"github.com/spf13/cobra"
+)
+
+// PreflightResult is the report of the preflight checks.
+type PreflightResult struct {
+	Preflight []preflight.Result `json:"preflight" yaml:"preflight"`
+}
+
+func (r PreflightResult) String() string {
+	lines := make([]string, 0, len(r.Preflight))
+	for _, result := range r.Preflight {
+		lines = append(lines, result.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runPreflightChecks runs the preflight checks on the local node and reports the results.
+// With plain output, the report is printed to stderr, so that it does not mix with the command output.
+// With other output formats, the report is printed with the output formatter if the checks fail. Otherwise, the results
+// are returned so that the command can include them in its output.
+// runPreflightChecks returns false if any of the checks failed and was not ignored.
+func runPreflightChecks(cmd *cobra.Command, env cmdutil.ExecutionEnvironment, opts preflight.Options, ignore []string, outputFormat string) ([]preflight.Result, bool) {
+	plain := outputFormat == "" || outputFormat == "plain"
+	if plain {
+		cmd.PrintErrln("Running preflight checks.")
+	}
+
+	results := preflight.Run(cmd.Context(), env.Snap, opts)
+	if plain {
+		cmd.PrintErrln(PreflightResult{Preflight: results}.String())
+	}
+
+	if failures := preflight.Failures(results, ignore); len(failures) > 0 {
+		if !plain {
+			outputFormatter.Print(PreflightResult{Preflight: results})
+		}
+		names := make([]string, 0, len(failures))
+		for _, failure := range failures {
+			names = append(names, failure.Name)
+		}
+		cmd.PrintErrf("Error: Preflight checks failed. To continue anyway, use --ignore-preflight-errors=%s\n", strings.Join(names, ","))
+		return nil, false
+	}
+	if plain {
+		return nil, true
+	}
+	return results, true
+}
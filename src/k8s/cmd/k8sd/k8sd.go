@@ -3,12 +3,14 @@ package k8sd
 import (
 	cmdutil "github.com/canonical/k8s/cmd/util"
 	"github.com/canonical/k8s/pkg/k8sd/app"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/spf13/cobra"
 )
 
 var rootCmdOpts struct {
 	logDebug     bool
 	logVerbose   bool
+	logFormat    string
 	stateDir     string
 	pprofAddress string
 }
@@ -24,6 +26,8 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 				StateDir:     rootCmdOpts.stateDir,
 				Snap:         env.Snap,
 				PprofAddress: rootCmdOpts.pprofAddress,
+				LogFormat:    rootCmdOpts.logFormat,
+				LogOutput:    env.Stderr,
 			})
 			if err != nil {
 				cmd.PrintErrf("Error: Failed to initialize k8sd: %v", err)
@@ -45,6 +49,7 @@ func NewRootCmd(env cmdutil.ExecutionEnvironment) *cobra.Command {
 
 	cmd.PersistentFlags().BoolVarP(&rootCmdOpts.logDebug, "debug", "d", false, "Show all debug messages")
 	cmd.PersistentFlags().BoolVarP(&rootCmdOpts.logVerbose, "verbose", "v", true, "Show all information messages")
+	cmd.PersistentFlags().StringVar(&rootCmdOpts.logFormat, "log-format", log.FormatText, "Format of the log messages, text or json")
 	cmd.PersistentFlags().StringVar(&rootCmdOpts.stateDir, "state-dir", "", "Directory with the dqlite datastore")
 	cmd.PersistentFlags().StringVar(&rootCmdOpts.pprofAddress, "pprof-address", "", "Listen address for pprof endpoints, e.g. \"127.0.0.1:4217\"")
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strings"
+	"text/tabwriter"
 
 	"gopkg.in/yaml.v2"
 )
@@ -14,8 +16,17 @@ type Formatter interface {
 	Print(any)
 }
 
+// Table is implemented by data that can be printed as a table.
+type Table interface {
+	// TableHeaders returns the column headers. wide is true if additional columns are requested.
+	TableHeaders(wide bool) []string
+	// TableRows returns the rows of the table. Each row has one cell per header.
+	TableRows(wide bool) [][]string
+}
+
 // New creates a new formatter based on passed type
-// Can be "plain", "json", "yaml".
+// Can be "plain", "json", "yaml", "table", "wide".
+// The table formatters fall back to plain output for data that does not implement Table.
 func NewFormatter(formatterType string, writer io.Writer) (Formatter, error) {
 	switch formatterType {
 	case "", "plain":
@@ -24,6 +35,10 @@ func NewFormatter(formatterType string, writer io.Writer) (Formatter, error) {
 		return jsonFormatter{writer: writer}, nil
 	case "yaml":
 		return yamlFormatter{writer: writer}, nil
+	case "table":
+		return tableFormatter{writer: writer}, nil
+	case "wide":
+		return tableFormatter{writer: writer, wide: true}, nil
 	default:
 		return nil, fmt.Errorf("unknown formatter type %q", formatterType)
 	}
@@ -60,3 +75,25 @@ func (y yamlFormatter) Print(data any) {
 		log.Printf("Failed to format YAML output: %v", err)
 	}
 }
+
+type tableFormatter struct {
+	writer io.Writer
+	wide   bool
+}
+
+func (t tableFormatter) Print(data any) {
+	table, ok := data.(Table)
+	if !ok {
+		plainFormatter{writer: t.writer}.Print(data)
+		return
+	}
+
+	w := tabwriter.NewWriter(t.writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(table.TableHeaders(t.wide), "\t"))
+	for _, row := range table.TableRows(t.wide) {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		log.Printf("Failed to format table output: %v", err)
+	}
+}
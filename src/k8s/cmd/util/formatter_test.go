@@ -0,0 +1,78 @@
+package cmdutil_test
+
+import (
+	"bytes"
+	"testing"
+
+	cmdutil "github.com/canonical/k8s/cmd/util"
+	. "github.com/onsi/gomega"
+)
+
+type testTable struct{}
+
+func (testTable) String() string { return "plain" }
+
+func (testTable) TableHeaders(wide bool) []string {
+	if wide {
+		return []string{"NAME", "ADDRESS", "EXTRA"}
+	}
+	return []string{"NAME", "ADDRESS"}
+}
+
+func (testTable) TableRows(wide bool) [][]string {
+	if wide {
+		return [][]string{{"node1", "10.0.0.1", "x"}, {"long-node-name", "10.0.0.2", "y"}}
+	}
+	return [][]string{{"node1", "10.0.0.1"}, {"long-node-name", "10.0.0.2"}}
+}
+
+func TestFormatter(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		formatterType  string
+		data           any
+		expectedOutput string
+	}{
+		{
+			name:           "Plain",
+			formatterType:  "plain",
+			data:           testTable{},
+			expectedOutput: "plain\n",
+		},
+		{
+			name:           "Table",
+			formatterType:  "table",
+			data:           testTable{},
+			expectedOutput: "NAME             ADDRESS\nnode1            10.0.0.1\nlong-node-name   10.0.0.2\n",
+		},
+		{
+			name:           "Wide",
+			formatterType:  "wide",
+			data:           testTable{},
+			expectedOutput: "NAME             ADDRESS    EXTRA\nnode1            10.0.0.1   x\nlong-node-name   10.0.0.2   y\n",
+		},
+		{
+			name:           "TableFallbackToPlain",
+			formatterType:  "table",
+			data:           "not a table",
+			expectedOutput: "not a table\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			b := &bytes.Buffer{}
+			formatter, err := cmdutil.NewFormatter(tc.formatterType, b)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			formatter.Print(tc.data)
+			g.Expect(b.String()).To(Equal(tc.expectedOutput))
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := cmdutil.NewFormatter("unknown", &bytes.Buffer{})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -24,6 +24,10 @@ type Client struct {
 	// clientGetter dynamically creates a dqlite client. This is because the dqlite client
 	// must dynamically connect to the leader node of the cluster.
 	clientGetter func(context.Context) (*client.Client, error)
+	// nodeClientGetter creates a dqlite client connected to the node with the given address.
+	nodeClientGetter func(context.Context, string) (*client.Client, error)
+	// localMembersGetter returns the nodes of the cluster known to the local node, without connecting to the leader.
+	localMembersGetter func(context.Context) ([]client.NodeInfo, error)
 }
 
 // NewClient creates a new client connected to the leader of the dqlite cluster.
@@ -57,5 +61,19 @@ func NewClient(ctx context.Context, opts ClientOpts) (*Client, error) {
 			}
 			return c, nil
 		},
+		nodeClientGetter: func(ctx context.Context, address string) (*client.Client, error) {
+			c, err := client.New(ctx, address, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to dqlite node %s: %w", address, err)
+			}
+			return c, nil
+		},
+		localMembersGetter: func(ctx context.Context) ([]client.NodeInfo, error) {
+			store, err := client.NewYamlNodeStore(opts.ClusterYAML)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open node store from %q: %w", opts.ClusterYAML, err)
+			}
+			return store.Get(ctx)
+		},
 	}, nil
 }
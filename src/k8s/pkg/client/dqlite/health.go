@@ -0,0 +1,73 @@
+package dqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HealthTimeout is how long each node is given to answer the health check before it is considered unreachable.
+const HealthTimeout = 5 * time.Second
+
+// MemberHealth is the health of a node in the dqlite cluster, as observed by connecting to it.
+type MemberHealth struct {
+	NodeInfo
+	// Reachable is true if the node accepted a connection and answered a request.
+	Reachable bool
+	// Latency is the round-trip time of the request, if the node is reachable.
+	Latency time.Duration
+	// LeaderAddress is the address of the leader that the node knows of, empty if it knows of none.
+	// Nodes that know of a different leader than the rest of the cluster may be partitioned.
+	LeaderAddress string
+	// Error is the reason the node is not reachable.
+	Error string
+}
+
+// CheckHealth connects to each node of the cluster and reports whether it is reachable.
+// Each node is given up to timeout to respond.
+// The nodes are retrieved from the leader. If there is no leader, e.g. because the cluster lost quorum, the nodes
+// known to the local node are checked instead.
+func (c *Client) CheckHealth(ctx context.Context, timeout time.Duration) ([]MemberHealth, error) {
+	members, err := c.ListMembers(ctx)
+	if err != nil {
+		var localErr error
+		if members, localErr = c.localMembersGetter(ctx); localErr != nil {
+			return nil, fmt.Errorf("failed to retrieve cluster nodes: %w", errors.Join(err, localErr))
+		}
+	}
+
+	result := make([]MemberHealth, len(members))
+	for i, member := range members {
+		result[i] = c.checkMemberHealth(ctx, member, timeout)
+	}
+	return result, nil
+}
+
+func (c *Client) checkMemberHealth(ctx context.Context, member NodeInfo, timeout time.Duration) MemberHealth {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	health := MemberHealth{NodeInfo: member}
+
+	start := time.Now()
+	client, err := c.nodeClientGetter(ctx, member.Address)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	defer client.Close()
+
+	leader, err := client.Leader(ctx)
+	if err != nil {
+		health.Error = fmt.Sprintf("failed to query node: %v", err)
+		return health
+	}
+
+	health.Reachable = true
+	health.Latency = time.Since(start)
+	if leader != nil {
+		health.LeaderAddress = leader.Address
+	}
+	return health
+}
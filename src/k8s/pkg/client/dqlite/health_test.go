@@ -0,0 +1,34 @@
+package dqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/canonical/go-dqlite/client"
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckHealthWithoutLeader(t *testing.T) {
+	g := NewWithT(t)
+
+	c := &Client{
+		clientGetter: func(ctx context.Context) (*client.Client, error) {
+			return nil, errors.New("no available dqlite leader server found")
+		},
+		nodeClientGetter: func(ctx context.Context, address string) (*client.Client, error) {
+			return nil, errors.New("connection refused")
+		},
+		localMembersGetter: func(ctx context.Context) ([]client.NodeInfo, error) {
+			return []client.NodeInfo{{ID: 1, Address: "10.0.0.1:9000", Role: client.Voter}}, nil
+		},
+	}
+
+	members, err := c.CheckHealth(context.Background(), time.Second)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(members).To(HaveLen(1))
+	g.Expect(members[0].Address).To(Equal("10.0.0.1:9000"))
+	g.Expect(members[0].Reachable).To(BeFalse())
+	g.Expect(members[0].Error).To(ContainSubstring("connection refused"))
+}
@@ -13,3 +13,13 @@ func (c *Client) ListMembers(ctx context.Context) ([]NodeInfo, error) {
 	defer client.Close()
 	return client.Cluster(ctx)
 }
+
+// Leader returns the current leader of the dqlite cluster.
+func (c *Client) Leader(ctx context.Context) (*NodeInfo, error) {
+	client, err := c.clientGetter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dqlite client: %w", err)
+	}
+	defer client.Close()
+	return client.Leader(ctx)
+}
@@ -0,0 +1,165 @@
+package dqlite
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LastLogIndex returns the index of the last entry in the raft log of the dqlite node with the given data directory.
+// The index is read from the names of the closed segment and snapshot files, which include the indexes they contain,
+// and from the number of entries in the open segments, which follow the last closed segment. If there is no closed
+// segment, the open segments are assumed to follow the latest snapshot. LastLogIndex returns 0 if the log is empty.
+func LastLogIndex(dataDir string) (uint64, error) {
+	var index uint64
+	var err error
+	// segments are closed and removed while the node is running, so the files are listed again if one disappears
+	for attempt := 0; attempt < 3; attempt++ {
+		if index, err = lastLogIndex(dataDir); !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+	}
+	return index, err
+}
+
+func lastLogIndex(dataDir string) (uint64, error) {
+	files, err := os.ReadDir(dataDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list data directory: %w", err)
+	}
+
+	var closedIndex, snapshotIndex uint64
+	var openSegments []uint64
+	for _, file := range files {
+		name := file.Name()
+		switch {
+		case strings.HasPrefix(name, "open-"):
+			// open-<counter>
+			counter, err := strconv.ParseUint(strings.TrimPrefix(name, "open-"), 10, 64)
+			if err != nil {
+				continue
+			}
+			openSegments = append(openSegments, counter)
+		case strings.HasPrefix(name, "snapshot-") && !strings.HasSuffix(name, ".meta"):
+			// snapshot-<term>-<index>-<timestamp>
+			parts := strings.Split(name, "-")
+			if len(parts) != 4 {
+				continue
+			}
+			if index, err := strconv.ParseUint(parts[2], 10, 64); err == nil {
+				snapshotIndex = max(snapshotIndex, index)
+			}
+		default:
+			// <first index>-<last index> of closed segments
+			first, last, ok := strings.Cut(name, "-")
+			if !ok || len(first) != 16 || len(last) != 16 {
+				continue
+			}
+			if index, err := strconv.ParseUint(last, 10, 64); err == nil {
+				closedIndex = max(closedIndex, index)
+			}
+		}
+	}
+
+	lastIndex := closedIndex
+	if lastIndex == 0 {
+		lastIndex = snapshotIndex
+	}
+	// open segments hold the entries that follow the closed segments, in the order of their counter
+	sort.Slice(openSegments, func(i, j int) bool { return openSegments[i] < openSegments[j] })
+	for _, counter := range openSegments {
+		n, err := countSegmentEntries(filepath.Join(dataDir, fmt.Sprintf("open-%d", counter)))
+		if err != nil {
+			return 0, fmt.Errorf("failed to read open segment %d: %w", counter, err)
+		}
+		lastIndex += n
+	}
+	return max(lastIndex, snapshotIndex), nil
+}
+
+// countSegmentEntries returns the number of entries in a raft segment file.
+// A segment starts with the format version, followed by batches of entries. Each batch has two checksums, the number
+// of entries, a 16 byte header per entry with the size of its data, and the data of the entries, padded to 8 bytes.
+// Open segments are allocated ahead of time, so the batches end at the first one without entries.
+func countSegmentEntries(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	r := &segmentReader{b: b}
+	if format, err := r.uint64(); errors.Is(err, io.ErrUnexpectedEOF) || err == nil && format == 0 {
+		// the segment was allocated, but nothing was written to it yet
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	} else if format != 1 {
+		return 0, fmt.Errorf("unsupported segment format %d", format)
+	}
+
+	var count uint64
+	for {
+		// checksums
+		if err := r.skip(8); err != nil {
+			break
+		}
+		n, err := r.uint64()
+		if err != nil || n == 0 {
+			break
+		}
+		var dataSize uint64
+		for i := uint64(0); i < n; i++ {
+			// term, type and padding
+			if err := r.skip(12); err != nil {
+				return count, nil
+			}
+			size, err := r.uint32()
+			if err != nil {
+				return count, nil
+			}
+			dataSize += (uint64(size) + 7) / 8 * 8
+		}
+		if err := r.skip(dataSize); err != nil {
+			// the batch is still being written
+			break
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// segmentReader reads the little endian fields of a raft segment.
+type segmentReader struct {
+	b      []byte
+	offset uint64
+}
+
+func (r *segmentReader) skip(n uint64) error {
+	if uint64(len(r.b))-r.offset < n {
+		return io.ErrUnexpectedEOF
+	}
+	r.offset += n
+	return nil
+}
+
+func (r *segmentReader) uint64() (uint64, error) {
+	start := r.offset
+	if err := r.skip(8); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(r.b[start:r.offset]), nil
+}
+
+func (r *segmentReader) uint32() (uint32, error) {
+	start := r.offset
+	if err := r.skip(4); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(r.b[start:r.offset]), nil
+}
@@ -0,0 +1,107 @@
+package dqlite_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	. "github.com/onsi/gomega"
+)
+
+// segment encodes a raft segment with one batch per list of entry sizes, followed by the given number of zero bytes.
+func segment(batches [][]uint32, padding int) []byte {
+	b := binary.LittleEndian.AppendUint64(nil, 1)
+	for _, sizes := range batches {
+		// checksums
+		b = binary.LittleEndian.AppendUint64(b, 0xdeadbeef)
+		b = binary.LittleEndian.AppendUint64(b, uint64(len(sizes)))
+		var data []byte
+		for _, size := range sizes {
+			// term, type and padding
+			b = binary.LittleEndian.AppendUint64(b, 1)
+			b = binary.LittleEndian.AppendUint32(b, 1)
+			b = binary.LittleEndian.AppendUint32(b, size)
+			data = append(data, make([]byte, (size+7)/8*8)...)
+		}
+		b = append(b, data...)
+	}
+	return append(b, make([]byte, padding)...)
+}
+
+func TestLastLogIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		files       map[string][]byte
+		expectIndex uint64
+	}{
+		{
+			name:  "Empty",
+			files: map[string][]byte{"cluster.yaml": nil, "info.yaml": nil},
+		},
+		{
+			name: "ClosedSegments",
+			files: map[string][]byte{
+				"0000000000000001-0000000000000010": nil,
+				"0000000000000011-0000000000000042": nil,
+			},
+			expectIndex: 42,
+		},
+		{
+			name: "OpenSegments",
+			files: map[string][]byte{
+				"0000000000000001-0000000000000010": nil,
+				"open-2":                            segment([][]uint32{{3, 8}, {17}}, 64),
+				"open-3":                            segment([][]uint32{{1}}, 0),
+				// allocated, but not written yet
+				"open-4": make([]byte, 64),
+			},
+			expectIndex: 14,
+		},
+		{
+			name: "PartialBatch",
+			files: map[string][]byte{
+				"0000000000000001-0000000000000010": nil,
+				"open-1":                            segment([][]uint32{{3}, {64}}, 0)[:100],
+			},
+			expectIndex: 11,
+		},
+		{
+			name: "Snapshot",
+			files: map[string][]byte{
+				"snapshot-2-100-1700000000":      nil,
+				"snapshot-2-100-1700000000.meta": nil,
+				"open-7":                         segment([][]uint32{{8, 8}}, 0),
+			},
+			expectIndex: 102,
+		},
+		{
+			name: "SnapshotAfterClosedSegments",
+			files: map[string][]byte{
+				"0000000000000001-0000000000000010": nil,
+				"snapshot-2-12-1700000000":          nil,
+				"open-1":                            segment([][]uint32{{8, 8, 8}}, 0),
+			},
+			expectIndex: 13,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			dir := t.TempDir()
+			for name, data := range tc.files {
+				g.Expect(os.WriteFile(filepath.Join(dir, name), data, 0600)).To(Succeed())
+			}
+
+			index, err := dqlite.LastLogIndex(dir)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(index).To(Equal(tc.expectIndex))
+		})
+	}
+
+	t.Run("MissingDirectory", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := dqlite.LastLogIndex(filepath.Join(t.TempDir(), "missing"))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
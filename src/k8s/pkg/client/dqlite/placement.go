@@ -0,0 +1,140 @@
+package dqlite
+
+import "sort"
+
+// Voters is the number of voters that k8s-dqlite keeps. It is the default of the go-dqlite app that k8s-dqlite runs,
+// which demotes any extra voters.
+const Voters = 3
+
+// PlacementNode is a node of the dqlite cluster that is considered for voter placement.
+type PlacementNode struct {
+	NodeInfo
+	// FailureDomain is the failure domain of the node, e.g. its zone. Nodes without a failure domain share the empty one.
+	FailureDomain string
+	// Available is false for nodes that must not be voters, e.g. because they are unreachable or in maintenance.
+	// Voters that are not available but not lost either, e.g. in maintenance, stay voters if no other node can replace them.
+	Available bool
+	// Lost is true for nodes that are unreachable. Lost voters are demoted to spare, as they cannot replicate the database.
+	Lost bool
+	// Pinned is true for nodes whose role must not be changed, e.g. because it was set manually.
+	// Pinned voters that are available count towards the voters.
+	Pinned bool
+}
+
+// RoleChange is a new role for a node of the dqlite cluster.
+type RoleChange struct {
+	NodeInfo
+	Role NodeRole
+}
+
+// PlanVoters returns the role changes that keep the given number of voters in the cluster, spread as evenly as possible across failure domains.
+// Only available nodes become voters. If there are fewer available nodes than voters, all of them are voters, and voters
+// that are not available but not lost keep their role to make up the difference, as fewer voters tolerate fewer failures.
+// Voters that are lost are demoted to spare, other voters that are no longer needed to stand-by.
+// Stand-by nodes are promoted before spare nodes, as they already replicate the database.
+// Promotions come before demotions, so that applying the changes in order does not leave the cluster with fewer voters than needed.
+// The roles of pinned nodes are never changed, so there may be more or fewer voters than requested.
+func PlanVoters(nodes []PlacementNode, voters int) []RoleChange {
+	nodes = append([]PlacementNode(nil), nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	var available int
+	selected := make(map[uint64]bool, len(nodes))
+	perDomain := make(map[string]int)
+	for _, node := range nodes {
+		if !node.Available || node.Pinned && node.Role != Voter {
+			continue
+		}
+		available++
+		if node.Role == Voter {
+			selected[node.ID] = true
+			perDomain[node.FailureDomain]++
+		}
+	}
+
+	target := min(voters, available)
+	if target == 0 {
+		// never demote all voters
+		return nil
+	}
+
+	// promote returns the best node to become a voter, demote the best voter to be replaced.
+	promote := func() *PlacementNode {
+		var best *PlacementNode
+		for i, node := range nodes {
+			if !node.Available || node.Pinned || selected[node.ID] {
+				continue
+			}
+			if best == nil || perDomain[node.FailureDomain] < perDomain[best.FailureDomain] ||
+				perDomain[node.FailureDomain] == perDomain[best.FailureDomain] && node.Role == StandBy && best.Role != StandBy {
+				best = &nodes[i]
+			}
+		}
+		return best
+	}
+	demote := func() *PlacementNode {
+		var best *PlacementNode
+		for i, node := range nodes {
+			if !selected[node.ID] || node.Pinned {
+				continue
+			}
+			if best == nil || perDomain[node.FailureDomain] >= perDomain[best.FailureDomain] {
+				best = &nodes[i]
+			}
+		}
+		return best
+	}
+
+	for len(selected) > target {
+		node := demote()
+		if node == nil {
+			// only pinned voters are left
+			break
+		}
+		delete(selected, node.ID)
+		perDomain[node.FailureDomain]--
+	}
+	for len(selected) < target {
+		node := promote()
+		if node == nil {
+			break
+		}
+		selected[node.ID] = true
+		perDomain[node.FailureDomain]++
+	}
+	// swap voters from crowded failure domains with nodes from sparse ones, until no swap improves the spread
+	for {
+		in, out := promote(), demote()
+		if in == nil || out == nil || perDomain[in.FailureDomain]+1 >= perDomain[out.FailureDomain] {
+			break
+		}
+		delete(selected, out.ID)
+		perDomain[out.FailureDomain]--
+		selected[in.ID] = true
+		perDomain[in.FailureDomain]++
+	}
+
+	// voters that are not lost stay voters if no available node can replace them
+	for _, node := range nodes {
+		if len(selected) >= voters {
+			break
+		}
+		if !node.Available && !node.Lost && !node.Pinned && node.Role == Voter {
+			selected[node.ID] = true
+		}
+	}
+
+	var promotions, demotions []RoleChange
+	for _, node := range nodes {
+		switch {
+		case node.Pinned:
+		case selected[node.ID] && node.Role != Voter:
+			promotions = append(promotions, RoleChange{NodeInfo: node.NodeInfo, Role: Voter})
+		case !selected[node.ID] && node.Role == Voter && node.Lost:
+			demotions = append(demotions, RoleChange{NodeInfo: node.NodeInfo, Role: Spare})
+		case !selected[node.ID] && node.Role == Voter:
+			demotions = append(demotions, RoleChange{NodeInfo: node.NodeInfo, Role: StandBy})
+		}
+	}
+	return append(promotions, demotions...)
+}
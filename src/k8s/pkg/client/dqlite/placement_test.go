@@ -0,0 +1,188 @@
+package dqlite_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	. "github.com/onsi/gomega"
+)
+
+func TestPlanVoters(t *testing.T) {
+	// nodes that are not available are lost
+	node := func(id uint64, role dqlite.NodeRole, domain string, available bool) dqlite.PlacementNode {
+		return dqlite.PlacementNode{NodeInfo: dqlite.NodeInfo{ID: id, Role: role}, FailureDomain: domain, Available: available, Lost: !available}
+	}
+	inMaintenance := func(node dqlite.PlacementNode) dqlite.PlacementNode {
+		node.Available, node.Lost = false, false
+		return node
+	}
+	pinned := func(node dqlite.PlacementNode) dqlite.PlacementNode {
+		node.Pinned = true
+		return node
+	}
+	// change only holds the ID of the node, as the changes also include the current role
+	type change struct {
+		id   uint64
+		role dqlite.NodeRole
+	}
+
+	for _, tc := range []struct {
+		name    string
+		nodes   []dqlite.PlacementNode
+		voters  int
+		changes []change
+	}{
+		{
+			name: "Balanced",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				node(3, dqlite.Voter, "c", true),
+				node(4, dqlite.StandBy, "a", true),
+			},
+			voters: 3,
+		},
+		{
+			name: "SpreadAcrossDomains",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "a", true),
+				node(3, dqlite.Voter, "a", true),
+				node(4, dqlite.StandBy, "b", true),
+				node(5, dqlite.Spare, "c", true),
+			},
+			voters:  3,
+			changes: []change{{4, dqlite.Voter}, {5, dqlite.Voter}, {2, dqlite.StandBy}, {3, dqlite.StandBy}},
+		},
+		{
+			name: "ReplaceLostVoter",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				node(3, dqlite.Voter, "c", false),
+				node(4, dqlite.Spare, "c", true),
+				node(5, dqlite.StandBy, "a", true),
+			},
+			voters:  3,
+			changes: []change{{4, dqlite.Voter}, {3, dqlite.Spare}},
+		},
+		{
+			name: "VoterInMaintenance",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				inMaintenance(node(3, dqlite.Voter, "c", true)),
+				node(4, dqlite.Spare, "c", true),
+			},
+			voters: 3,
+			// nodes in maintenance are reachable, so they keep replicating the database
+			changes: []change{{4, dqlite.Voter}, {3, dqlite.StandBy}},
+		},
+		{
+			name: "VoterInMaintenanceWithoutReplacement",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				inMaintenance(node(3, dqlite.Voter, "c", true)),
+				inMaintenance(node(4, dqlite.StandBy, "c", true)),
+			},
+			voters: 3,
+		},
+		{
+			name: "PreferStandBy",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "", true),
+				node(2, dqlite.Voter, "", true),
+				node(3, dqlite.Spare, "", true),
+				node(4, dqlite.StandBy, "", true),
+			},
+			voters:  3,
+			changes: []change{{4, dqlite.Voter}},
+		},
+		{
+			name: "FiveVoters",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				node(3, dqlite.Voter, "c", true),
+				node(4, dqlite.StandBy, "a", true),
+				node(5, dqlite.StandBy, "b", true),
+				node(6, dqlite.StandBy, "b", true),
+			},
+			voters:  5,
+			changes: []change{{4, dqlite.Voter}, {5, dqlite.Voter}},
+		},
+		{
+			name: "DemoteExtraVoter",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				node(3, dqlite.Voter, "c", true),
+				node(4, dqlite.Voter, "a", true),
+			},
+			voters:  3,
+			changes: []change{{4, dqlite.StandBy}},
+		},
+		{
+			name: "FewerNodesThanVoters",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Spare, "b", true),
+			},
+			voters:  3,
+			changes: []change{{2, dqlite.Voter}},
+		},
+		{
+			name: "PinnedStandBy",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				node(3, dqlite.Voter, "c", false),
+				pinned(node(4, dqlite.StandBy, "c", true)),
+				node(5, dqlite.Spare, "a", true),
+			},
+			voters:  3,
+			changes: []change{{5, dqlite.Voter}, {3, dqlite.Spare}},
+		},
+		{
+			name: "PinnedLostVoter",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", true),
+				node(2, dqlite.Voter, "b", true),
+				pinned(node(3, dqlite.Voter, "c", false)),
+				node(4, dqlite.Spare, "c", true),
+			},
+			voters:  3,
+			changes: []change{{4, dqlite.Voter}},
+		},
+		{
+			name: "PinnedExtraVoters",
+			nodes: []dqlite.PlacementNode{
+				pinned(node(1, dqlite.Voter, "a", true)),
+				pinned(node(2, dqlite.Voter, "a", true)),
+				node(3, dqlite.Voter, "a", true),
+				node(4, dqlite.Voter, "b", true),
+				node(5, dqlite.StandBy, "c", true),
+			},
+			voters:  3,
+			changes: []change{{3, dqlite.StandBy}},
+		},
+		{
+			name: "NoAvailableNodes",
+			nodes: []dqlite.PlacementNode{
+				node(1, dqlite.Voter, "a", false),
+				node(2, dqlite.Voter, "b", false),
+			},
+			voters: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var changes []change
+			for _, c := range dqlite.PlanVoters(tc.nodes, tc.voters) {
+				changes = append(changes, change{id: c.ID, role: c.Role})
+			}
+			g.Expect(changes).To(Equal(tc.changes))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package dqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/canonical/k8s/pkg/utils/control"
+)
+
+// AssignRole assigns a role to the node with the given address.
+// A voter cannot be demoted if it is the last voter of the cluster. If it is the leader, leadership is transferred to another voter first.
+func (c *Client) AssignRole(ctx context.Context, address string, role NodeRole) error {
+	client, err := c.clientGetter(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create dqlite client: %w", err)
+	}
+	defer func() { client.Close() }()
+
+	members, err := client.Cluster(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve cluster nodes: %w", err)
+	}
+
+	var member, otherVoter *NodeInfo
+	for _, m := range members {
+		switch {
+		case m.Address == address:
+			member = &m
+		case m.Role == Voter:
+			otherVoter = &m
+		}
+	}
+
+	if member == nil {
+		return fmt.Errorf("cluster does not have a node with address %v", address)
+	}
+	if member.Role == role {
+		return nil
+	}
+
+	if member.Role == Voter {
+		if otherVoter == nil {
+			return fmt.Errorf("cannot demote the last voter of the cluster")
+		}
+
+		leader, err := client.Leader(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve dqlite leader: %w", err)
+		}
+		if leader != nil && leader.ID == member.ID {
+			if err := client.Transfer(ctx, otherVoter.ID); err != nil {
+				return fmt.Errorf("failed to transfer leadership to %d: %w", otherVoter.ID, err)
+			}
+			// Recreate client to point to the new leader.
+			client.Close()
+			client, err = c.clientGetter(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create dqlite client: %w", err)
+			}
+		}
+	}
+
+	// Retry as the leadership transfer might still be in progress.
+	return control.RetryFor(ctx, 10, 5*time.Second, func() error {
+		if err := client.Assign(ctx, member.ID, role); err != nil {
+			return fmt.Errorf("failed to assign %s role to %d: %w", role, member.ID, err)
+		}
+		return nil
+	})
+}
+
+// ErrNoReplacement is returned by DemoteVoter if there is no stand-by or spare node to promote in place of the voter.
+var ErrNoReplacement = errors.New("no stand-by or spare node can replace the voter")
+
+// DemoteVoter assigns the stand-by role to the voter node with the given address, so that it no longer participates in the Raft quorum.
+// A stand-by or spare node is promoted to voter first, so that the cluster keeps the same number of voters.
+// DemoteVoter refuses to demote the node with ErrNoReplacement if there is no such node, as fewer voters tolerate fewer failures.
+// DemoteVoter returns false if the node is not a voter.
+func (c *Client) DemoteVoter(ctx context.Context, address string) (bool, error) {
+	members, err := c.ListMembers(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve cluster nodes: %w", err)
+	}
+
+	var memberToDemote, replacement *NodeInfo
+	for _, member := range members {
+		switch {
+		case member.Address == address:
+			memberToDemote = &member
+		case member.Role == StandBy:
+			// prefer stand-by nodes, as they already replicate the database
+			replacement = &member
+		case member.Role == Spare && replacement == nil:
+			replacement = &member
+		}
+	}
+
+	if memberToDemote == nil {
+		return false, fmt.Errorf("cluster does not have a node with address %v", address)
+	}
+	if memberToDemote.Role != Voter {
+		return false, nil
+	}
+
+	if replacement == nil {
+		return false, fmt.Errorf("cannot demote %v: %w", address, ErrNoReplacement)
+	}
+	if err := c.AssignRole(ctx, replacement.Address, Voter); err != nil {
+		return false, fmt.Errorf("failed to promote replacement voter: %w", err)
+	}
+	if err := c.AssignRole(ctx, address, StandBy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TransferLeadership transfers the leadership of the cluster to the voter node with the given address.
+// If address is empty, dqlite picks a voter.
+func (c *Client) TransferLeadership(ctx context.Context, address string) error {
+	client, err := c.clientGetter(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create dqlite client: %w", err)
+	}
+	defer client.Close()
+
+	var id uint64
+	if address != "" {
+		members, err := client.Cluster(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve cluster nodes: %w", err)
+		}
+		for _, member := range members {
+			if member.Address != address {
+				continue
+			}
+			if member.Role != Voter {
+				return fmt.Errorf("cannot transfer leadership to %v, as it is a %s node", address, member.Role)
+			}
+			id = member.ID
+		}
+		if id == 0 {
+			return fmt.Errorf("cluster does not have a node with address %v", address)
+		}
+	}
+
+	if err := client.Transfer(ctx, id); err != nil {
+		return fmt.Errorf("failed to transfer leadership: %w", err)
+	}
+	return nil
+}
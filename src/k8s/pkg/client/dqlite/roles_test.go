@@ -0,0 +1,179 @@
+package dqlite_test
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	. "github.com/onsi/gomega"
+)
+
+func TestDemoteVoter(t *testing.T) {
+	t.Run("LastVoter", func(t *testing.T) {
+		withDqliteCluster(t, 2, func(ctx context.Context, dirs []string) {
+			g := NewWithT(t)
+			client, err := dqlite.NewClient(ctx, dqlite.ClientOpts{
+				ClusterYAML: path.Join(dirs[0], "cluster.yaml"),
+			})
+			g.Expect(err).To(BeNil())
+
+			members, err := client.ListMembers(ctx)
+			g.Expect(err).To(BeNil())
+			g.Expect(members).To(HaveLen(2))
+
+			voter, spare := members[0], members[1]
+			if voter.Role != dqlite.Voter {
+				voter, spare = spare, voter
+			}
+
+			// The spare node is promoted and takes over leadership.
+			demoted, err := client.DemoteVoter(ctx, voter.Address)
+			g.Expect(err).To(BeNil())
+			g.Expect(demoted).To(BeTrue())
+
+			members, err = client.ListMembers(ctx)
+			g.Expect(err).To(BeNil())
+			for _, member := range members {
+				switch member.Address {
+				case voter.Address:
+					g.Expect(member.Role).To(Equal(dqlite.StandBy))
+				case spare.Address:
+					g.Expect(member.Role).To(Equal(dqlite.Voter))
+				}
+			}
+
+			// The node is not a voter anymore.
+			demoted, err = client.DemoteVoter(ctx, voter.Address)
+			g.Expect(err).To(BeNil())
+			g.Expect(demoted).To(BeFalse())
+
+			g.Expect(client.AssignRole(ctx, voter.Address, dqlite.Voter)).To(Succeed())
+
+			members, err = client.ListMembers(ctx)
+			g.Expect(err).To(BeNil())
+			for _, member := range members {
+				g.Expect(member.Role).To(Equal(dqlite.Voter))
+			}
+		})
+	})
+
+	t.Run("OnlyNode", func(t *testing.T) {
+		withDqliteCluster(t, 1, func(ctx context.Context, dirs []string) {
+			g := NewWithT(t)
+			client, err := dqlite.NewClient(ctx, dqlite.ClientOpts{
+				ClusterYAML: path.Join(dirs[0], "cluster.yaml"),
+			})
+			g.Expect(err).To(BeNil())
+
+			members, err := client.ListMembers(ctx)
+			g.Expect(err).To(BeNil())
+			g.Expect(members).To(HaveLen(1))
+
+			// there is no node to replace the voter
+			_, err = client.DemoteVoter(ctx, members[0].Address)
+			g.Expect(err).To(MatchError(dqlite.ErrNoReplacement))
+		})
+	})
+}
+
+func TestMaintenanceWithVoterPlacement(t *testing.T) {
+	withDqliteCluster(t, 3, func(ctx context.Context, dirs []string) {
+		g := NewWithT(t)
+		client, err := dqlite.NewClient(ctx, dqlite.ClientOpts{
+			ClusterYAML: path.Join(dirs[0], "cluster.yaml"),
+		})
+		g.Expect(err).To(BeNil())
+
+		members, err := client.ListMembers(ctx)
+		g.Expect(err).To(BeNil())
+		g.Expect(members).To(HaveLen(3))
+
+		// the voter controller keeps the nodes of the cluster as voters
+		for _, member := range members {
+			g.Expect(client.AssignRole(ctx, member.Address, dqlite.Voter)).To(Succeed())
+		}
+		node := members[0]
+
+		// entering maintenance records that the node is a voter before it is drained
+		members, err = client.ListMembers(ctx)
+		g.Expect(err).To(BeNil())
+		var datastoreVoter bool
+		for _, member := range members {
+			if member.Address == node.Address {
+				datastoreVoter = member.Role == dqlite.Voter
+			}
+		}
+		g.Expect(datastoreVoter).To(BeTrue())
+
+		// the voter controller keeps the node as voter while it is drained, as no other node can replace it
+		placement := make([]dqlite.PlacementNode, 0, len(members))
+		for _, member := range members {
+			placement = append(placement, dqlite.PlacementNode{NodeInfo: member, Available: member.Address != node.Address})
+		}
+		g.Expect(dqlite.PlanVoters(placement, dqlite.Voters)).To(BeEmpty())
+
+		// after the drain, the node is not demoted either
+		_, err = client.DemoteVoter(ctx, node.Address)
+		g.Expect(err).To(MatchError(dqlite.ErrNoReplacement))
+	})
+}
+
+func TestAssignRole(t *testing.T) {
+	withDqliteCluster(t, 3, func(ctx context.Context, dirs []string) {
+		g := NewWithT(t)
+		client, err := dqlite.NewClient(ctx, dqlite.ClientOpts{
+			ClusterYAML: path.Join(dirs[0], "cluster.yaml"),
+		})
+		g.Expect(err).To(BeNil())
+
+		members, err := client.ListMembers(ctx)
+		g.Expect(err).To(BeNil())
+		g.Expect(members).To(HaveLen(3))
+
+		// promote all nodes to voters
+		for _, member := range members {
+			g.Expect(client.AssignRole(ctx, member.Address, dqlite.Voter)).To(Succeed())
+		}
+
+		leader, err := client.Leader(ctx)
+		g.Expect(err).To(BeNil())
+
+		// demoting the leader transfers leadership first
+		g.Expect(client.AssignRole(ctx, leader.Address, dqlite.Spare)).To(Succeed())
+		newLeader, err := client.Leader(ctx)
+		g.Expect(err).To(BeNil())
+		g.Expect(newLeader.Address).ToNot(Equal(leader.Address))
+
+		// leadership can only be transferred to voters
+		g.Expect(client.TransferLeadership(ctx, leader.Address)).ToNot(Succeed())
+		g.Expect(client.TransferLeadership(ctx, "127.0.0.1:1")).ToNot(Succeed())
+
+		var otherVoter string
+		for _, member := range members {
+			if member.Address != leader.Address && member.Address != newLeader.Address {
+				otherVoter = member.Address
+			}
+		}
+		g.Expect(client.TransferLeadership(ctx, otherVoter)).To(Succeed())
+		g.Eventually(func() string {
+			leader, _ := client.Leader(ctx)
+			if leader == nil {
+				return ""
+			}
+			return leader.Address
+		}).Should(Equal(otherVoter))
+
+		// the last voter cannot be demoted
+		g.Expect(client.AssignRole(ctx, newLeader.Address, dqlite.StandBy)).To(Succeed())
+		g.Expect(client.AssignRole(ctx, otherVoter, dqlite.StandBy)).ToNot(Succeed())
+
+		health, err := client.CheckHealth(ctx, time.Second)
+		g.Expect(err).To(BeNil())
+		g.Expect(health).To(HaveLen(3))
+		for _, member := range health {
+			g.Expect(member.Reachable).To(BeTrue())
+		}
+	})
+}
@@ -15,3 +15,6 @@ var StandBy = client.StandBy
 
 // Spare is the role for nodes that do not participate in quroum and do not replicate the database.
 var Spare = client.Spare
+
+// NodeRole is the role of a node in the dqlite cluster.
+type NodeRole = client.NodeRole
@@ -5,9 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"path"
 
+	"github.com/canonical/k8s/pkg/log"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/storage/driver"
@@ -31,10 +31,12 @@ func NewClient(manifestsBaseDir string, restClientGetter func(string) genericcli
 	}
 }
 
-func (h *client) newActionConfiguration(namespace string) (*action.Configuration, error) {
+func (h *client) newActionConfiguration(ctx context.Context, chart string, namespace string) (*action.Configuration, error) {
 	actionConfig := new(action.Configuration)
 
-	if err := actionConfig.Init(h.restClientGetter(namespace), namespace, "", log.Printf); err != nil {
+	logger := log.FromContext(ctx).With("chart", chart, "namespace", namespace)
+	debugLog := func(format string, v ...any) { logger.Debug(fmt.Sprintf(format, v...)) }
+	if err := actionConfig.Init(h.restClientGetter(namespace), namespace, "", debugLog); err != nil {
 		return nil, fmt.Errorf("failed to initialize: %w", err)
 	}
 	return actionConfig, nil
@@ -42,7 +44,7 @@ func (h *client) newActionConfiguration(namespace string) (*action.Configuration
 
 // Apply implements the Client interface.
 func (h *client) Apply(ctx context.Context, c InstallableChart, desired State, values map[string]any) (bool, error) {
-	cfg, err := h.newActionConfiguration(c.Namespace)
+	cfg, err := h.newActionConfiguration(ctx, c.Name, c.Namespace)
 	if err != nil {
 		return false, fmt.Errorf("failed to create action configuration: %w", err)
 	}
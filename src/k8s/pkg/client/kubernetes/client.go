@@ -5,11 +5,15 @@ import (
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 )
 
 // Client is a wrapper around the kubernetes.Interface.
 type Client struct {
 	kubernetes.Interface
+
+	// Metadata reads objects without their contents, e.g. the labels of secrets without their data.
+	Metadata metadata.Interface
 }
 
 func NewClient(restClientGetter genericclioptions.RESTClientGetter) (*Client, error) {
@@ -21,5 +25,9 @@ func NewClient(restClientGetter genericclioptions.RESTClientGetter) (*Client, er
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
 	}
-	return &Client{clientset}, nil
+	metadataClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes metadata client: %w", err)
+	}
+	return &Client{Interface: clientset, Metadata: metadataClient}, nil
 }
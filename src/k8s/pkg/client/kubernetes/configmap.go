@@ -3,8 +3,8 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"log"
 
+	"github.com/canonical/k8s/pkg/log"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	applyv1 "k8s.io/client-go/applyconfigurations/core/v1"
@@ -30,7 +30,7 @@ func (c *Client) WatchConfigMap(ctx context.Context, namespace string, name stri
 			}
 
 			if err := reconcile(configMap); err != nil {
-				log.Println(fmt.Errorf("failed to reconcile configmap, namespace: %s name: %s: %w", namespace, name, err))
+				log.FromContext(ctx).Error("Failed to reconcile configmap", "namespace", namespace, "name", name, "error", err)
 			}
 		}
 	}
@@ -0,0 +1,27 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsDaemonSetReady returns true if the latest revision of the given DaemonSet is rolled out and available on all scheduled nodes.
+// IsDaemonSetReady returns false if the DaemonSet does not exist.
+func (c *Client) IsDaemonSetReady(ctx context.Context, name, namespace string) (bool, error) {
+	daemonset, err := c.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get daemonset %s in namespace %s: %w", name, namespace, err)
+	}
+
+	status := daemonset.Status
+	if status.ObservedGeneration < daemonset.Generation || status.DesiredNumberScheduled == 0 {
+		return false, nil
+	}
+	return status.UpdatedNumberScheduled == status.DesiredNumberScheduled && status.NumberAvailable == status.DesiredNumberScheduled, nil
+}
@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsDaemonSetReady(t *testing.T) {
+	daemonset := func(generation int64, status appsv1.DaemonSetStatus) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "namespace", Generation: generation},
+			Status:     status,
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		objects     []runtime.Object
+		expectReady bool
+	}{
+		{
+			name: "missing",
+		},
+		{
+			name:    "not scheduled",
+			objects: []runtime.Object{daemonset(1, appsv1.DaemonSetStatus{ObservedGeneration: 1})},
+		},
+		{
+			name: "not observed",
+			objects: []runtime.Object{daemonset(2, appsv1.DaemonSetStatus{
+				ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3,
+			})},
+		},
+		{
+			name: "rolling out",
+			objects: []runtime.Object{daemonset(1, appsv1.DaemonSetStatus{
+				ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 2, NumberAvailable: 3,
+			})},
+		},
+		{
+			name: "unavailable",
+			objects: []runtime.Object{daemonset(1, appsv1.DaemonSetStatus{
+				ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2,
+			})},
+		},
+		{
+			name: "ready",
+			objects: []runtime.Object{daemonset(1, appsv1.DaemonSetStatus{
+				ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3,
+			})},
+			expectReady: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := &Client{Interface: fake.NewSimpleClientset(tc.objects...)}
+
+			ready, err := client.IsDaemonSetReady(context.Background(), "test", "namespace")
+			g.Expect(err).To(BeNil())
+			g.Expect(ready).To(Equal(tc.expectReady))
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/canonical/k8s/pkg/log"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// DrainOptions configures how pods are evicted from a node.
+type DrainOptions struct {
+	// GracePeriodSeconds overrides the termination grace period of the evicted pods. Nil uses the grace period of each pod.
+	GracePeriodSeconds *int64
+	// IgnoreDaemonSets skips pods that are managed by a DaemonSet. If false, the drain fails if there are any.
+	IgnoreDaemonSets bool
+	// Timeout is how long to wait for all pods to be evicted. Zero waits until the context is cancelled.
+	Timeout time.Duration
+	// RetryInterval is how long to wait before retrying an eviction that is blocked by a PodDisruptionBudget,
+	// and between checks of whether the evicted pods are gone. Defaults to 5 seconds.
+	RetryInterval time.Duration
+}
+
+// CordonNode marks a node as unschedulable.
+// CordonNode will retry if there is a conflict on the resource.
+func (c *Client) CordonNode(ctx context.Context, nodeName string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		node, err := c.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+		if node.Spec.Unschedulable {
+			return nil
+		}
+		node.Spec.Unschedulable = true
+		if _, err := c.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update node: %w", err)
+		}
+		return nil
+	})
+}
+
+// UncordonNode marks a node as schedulable.
+// UncordonNode will retry if there is a conflict on the resource.
+func (c *Client) UncordonNode(ctx context.Context, nodeName string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		node, err := c.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+		if !node.Spec.Unschedulable {
+			return nil
+		}
+		node.Spec.Unschedulable = false
+		if _, err := c.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update node: %w", err)
+		}
+		return nil
+	})
+}
+
+// DrainNode cordons a node and evicts its pods, honoring PodDisruptionBudgets.
+// Mirror pods and pods that have already terminated are not evicted.
+// DrainNode returns after all evicted pods are gone, or fails if the timeout is reached.
+// If the node is not Ready, its kubelet cannot confirm that pods terminated, so evicted pods count as gone once they are
+// marked for deletion.
+// DrainNode does nothing if the node does not exist.
+func (c *Client) DrainNode(ctx context.Context, nodeName string, opts DrainOptions) error {
+	if opts.RetryInterval == 0 {
+		opts.RetryInterval = 5 * time.Second
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	logger := log.FromContext(ctx).With("node", nodeName)
+
+	if err := c.CordonNode(ctx, nodeName); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Node does not exist, nothing to drain")
+			return nil
+		}
+		return fmt.Errorf("failed to cordon node: %w", err)
+	}
+
+	pods, err := c.podsToEvict(ctx, nodeName, opts.IgnoreDaemonSets)
+	if err != nil {
+		return err
+	}
+
+	remaining := make(map[types.UID]v1.Pod, len(pods))
+	for _, pod := range pods {
+		remaining[pod.UID] = pod
+	}
+
+	evicted := make(map[types.UID]struct{}, len(pods))
+	for {
+		nodeReady, err := c.isNodeReady(ctx, nodeName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.Info("Node was deleted, nothing left to drain")
+				return nil
+			}
+			return err
+		}
+
+		for uid, pod := range remaining {
+			if _, ok := evicted[uid]; !ok {
+				err := c.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+					ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+					DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds},
+				})
+				switch {
+				case err == nil, apierrors.IsNotFound(err):
+					logger.Info("Evicted pod", "namespace", pod.Namespace, "pod", pod.Name)
+					evicted[uid] = struct{}{}
+				case apierrors.IsTooManyRequests(err):
+					// blocked by a PodDisruptionBudget, retry later
+					logger.Info("Pod eviction blocked, will retry", "namespace", pod.Namespace, "pod", pod.Name, "error", err)
+					continue
+				default:
+					return fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+				}
+			}
+
+			current, err := c.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err), err == nil && current.UID != uid:
+				delete(remaining, uid)
+			case err == nil && !nodeReady && current.DeletionTimestamp != nil:
+				logger.Info("Node is not ready, not waiting for evicted pod to terminate", "namespace", pod.Namespace, "pod", pod.Name)
+				delete(remaining, uid)
+			case err != nil:
+				return fmt.Errorf("failed to get pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pods to be evicted: %s", podNames(remaining))
+		case <-time.After(opts.RetryInterval):
+		}
+	}
+}
+
+// isNodeReady returns true if the node has the Ready condition.
+func (c *Client) isNodeReady(ctx context.Context, nodeName string) (bool, error) {
+	node, err := c.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get node: %w", err)
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// podsToEvict returns the pods of the node that must be evicted to drain it.
+func (c *Client) podsToEvict(ctx context.Context, nodeName string, ignoreDaemonSets bool) ([]v1.Pod, error) {
+	podList, err := c.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var pods []v1.Pod
+	var daemonSetPods []string
+	for _, pod := range podList.Items {
+		switch {
+		case pod.Spec.NodeName != nodeName:
+		case pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed:
+		case pod.Annotations[v1.MirrorPodAnnotationKey] != "":
+			// static pods are managed by the kubelet and cannot be evicted
+		case isDaemonSetPod(pod):
+			daemonSetPods = append(daemonSetPods, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		default:
+			pods = append(pods, pod)
+		}
+	}
+
+	if len(daemonSetPods) > 0 && !ignoreDaemonSets {
+		return nil, fmt.Errorf("cannot evict pods managed by DaemonSets, ignore them to continue: %s", strings.Join(daemonSetPods, ", "))
+	}
+	return pods, nil
+}
+
+func isDaemonSetPod(pod v1.Pod) bool {
+	controller := metav1.GetControllerOf(&pod)
+	return controller != nil && controller.Kind == "DaemonSet"
+}
+
+func podNames(pods map[types.UID]v1.Pod) string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,192 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newDrainTestClient(objects ...runtime.Object) (*Client, *fake.Clientset, *[]string) {
+	clientset := fake.NewSimpleClientset(objects...)
+	var evicted []string
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		evicted = append(evicted, eviction.Namespace+"/"+eviction.Name)
+		return true, nil, clientset.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+	})
+	return &Client{Interface: clientset}, clientset, &evicted
+}
+
+func drainTestPod(name string, nodeName string, mutate ...func(*v1.Pod)) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	for _, f := range mutate {
+		f(pod)
+	}
+	return pod
+}
+
+func TestDrainNode(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}},
+	}
+	daemonSetPod := func(pod *v1.Pod) {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: utils.Pointer(true)}}
+	}
+
+	t.Run("EvictPods", func(t *testing.T) {
+		g := NewWithT(t)
+		client, _, evicted := newDrainTestClient(
+			node.DeepCopy(),
+			drainTestPod("p1", "n1"),
+			drainTestPod("p2", "n1"),
+			drainTestPod("other", "n2"),
+			drainTestPod("done", "n1", func(p *v1.Pod) { p.Status.Phase = v1.PodSucceeded }),
+			drainTestPod("static", "n1", func(p *v1.Pod) { p.Annotations = map[string]string{v1.MirrorPodAnnotationKey: "x"} }),
+		)
+
+		g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{RetryInterval: time.Millisecond})).To(Succeed())
+		g.Expect(*evicted).To(ConsistOf("default/p1", "default/p2"))
+
+		result, err := client.CoreV1().Nodes().Get(context.Background(), "n1", metav1.GetOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Spec.Unschedulable).To(BeTrue())
+	})
+
+	t.Run("DaemonSets", func(t *testing.T) {
+		g := NewWithT(t)
+		client, _, _ := newDrainTestClient(node.DeepCopy(), drainTestPod("p1", "n1", daemonSetPod))
+
+		g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{RetryInterval: time.Millisecond})).To(MatchError(ContainSubstring("default/p1")))
+	})
+
+	t.Run("IgnoreDaemonSets", func(t *testing.T) {
+		g := NewWithT(t)
+		client, _, evicted := newDrainTestClient(node.DeepCopy(), drainTestPod("p1", "n1", daemonSetPod), drainTestPod("p2", "n1"))
+
+		g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{IgnoreDaemonSets: true, RetryInterval: time.Millisecond})).To(Succeed())
+		g.Expect(*evicted).To(ConsistOf("default/p2"))
+	})
+
+	t.Run("PodDisruptionBudget", func(t *testing.T) {
+		g := NewWithT(t)
+		client, clientset, evicted := newDrainTestClient(node.DeepCopy(), drainTestPod("p1", "n1"))
+
+		// the first eviction is blocked by a PodDisruptionBudget
+		var blocked bool
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" || blocked {
+				return false, nil, nil
+			}
+			blocked = true
+			return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+		})
+
+		g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{RetryInterval: time.Millisecond})).To(Succeed())
+		g.Expect(blocked).To(BeTrue())
+		g.Expect(*evicted).To(ConsistOf("default/p1"))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		g := NewWithT(t)
+		client, clientset, _ := newDrainTestClient(node.DeepCopy(), drainTestPod("p1", "n1"))
+
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+		})
+
+		err := client.DrainNode(context.Background(), "n1", DrainOptions{Timeout: 50 * time.Millisecond, RetryInterval: time.Millisecond})
+		g.Expect(err).To(MatchError(ContainSubstring("default/p1")))
+	})
+
+	t.Run("TerminatingPods", func(t *testing.T) {
+		// evicted pods are marked for deletion, but never terminate
+		terminate := func(clientset *fake.Clientset) {
+			clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "eviction" {
+					return false, nil, nil
+				}
+				pod, err := clientset.Tracker().Get(v1.SchemeGroupVersion.WithResource("pods"), "default", "p1")
+				if err != nil {
+					return true, nil, err
+				}
+				pod.(*v1.Pod).DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				return true, nil, clientset.Tracker().Update(v1.SchemeGroupVersion.WithResource("pods"), pod, "default")
+			})
+		}
+
+		t.Run("Ready", func(t *testing.T) {
+			g := NewWithT(t)
+			client, clientset, _ := newDrainTestClient(node.DeepCopy(), drainTestPod("p1", "n1"))
+			terminate(clientset)
+
+			err := client.DrainNode(context.Background(), "n1", DrainOptions{Timeout: 50 * time.Millisecond, RetryInterval: time.Millisecond})
+			g.Expect(err).To(MatchError(ContainSubstring("default/p1")))
+		})
+
+		t.Run("NotReady", func(t *testing.T) {
+			g := NewWithT(t)
+			notReady := node.DeepCopy()
+			notReady.Status.Conditions[0].Status = v1.ConditionUnknown
+			client, clientset, _ := newDrainTestClient(notReady, drainTestPod("p1", "n1"))
+			terminate(clientset)
+
+			g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{Timeout: time.Second, RetryInterval: time.Millisecond})).To(Succeed())
+		})
+	})
+
+	t.Run("GracePeriod", func(t *testing.T) {
+		g := NewWithT(t)
+		client, clientset, _ := newDrainTestClient(node.DeepCopy(), drainTestPod("p1", "n1"))
+
+		var gracePeriod *int64
+		clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() == "eviction" {
+				gracePeriod = action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction).DeleteOptions.GracePeriodSeconds
+			}
+			return false, nil, nil
+		})
+
+		g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{GracePeriodSeconds: utils.Pointer[int64](10), RetryInterval: time.Millisecond})).To(Succeed())
+		g.Expect(gracePeriod).To(Equal(utils.Pointer[int64](10)))
+	})
+
+	t.Run("NodeNotFound", func(t *testing.T) {
+		g := NewWithT(t)
+		client, _, _ := newDrainTestClient()
+
+		g.Expect(client.DrainNode(context.Background(), "n1", DrainOptions{})).To(Succeed())
+	})
+}
+
+func TestUncordonNode(t *testing.T) {
+	g := NewWithT(t)
+	client, _, _ := newDrainTestClient(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}, Spec: v1.NodeSpec{Unschedulable: true}})
+
+	g.Expect(client.UncordonNode(context.Background(), "n1")).To(Succeed())
+
+	node, err := client.CoreV1().Nodes().Get(context.Background(), "n1", metav1.GetOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(node.Spec.Unschedulable).To(BeFalse())
+}
@@ -11,8 +11,9 @@ import (
 )
 
 // GetKubeAPIServerEndpoints retrieves the known kube-apiserver endpoints of the cluster.
+// GetKubeAPIServerEndpoints uses defaultPort for endpoints that do not list an "https" port.
 // GetKubeAPIServerEndpoints returns an error if the list of endpoints is empty.
-func (c *Client) GetKubeAPIServerEndpoints(ctx context.Context) ([]string, error) {
+func (c *Client) GetKubeAPIServerEndpoints(ctx context.Context, defaultPort int) ([]string, error) {
 	var endpoints *v1.Endpoints
 	var err error
 	err = retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
@@ -31,7 +32,7 @@ func (c *Client) GetKubeAPIServerEndpoints(ctx context.Context) ([]string, error
 
 	addresses := make([]string, 0, len(endpoints.Subsets))
 	for _, subset := range endpoints.Subsets {
-		portNumber := 6443
+		portNumber := defaultPort
 		for _, port := range subset.Ports {
 			if port.Name == "https" {
 				portNumber = int(port.Port)
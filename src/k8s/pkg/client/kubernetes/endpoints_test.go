@@ -15,6 +15,7 @@ func TestGetKubeAPIServerEndpoints(t *testing.T) {
 	tests := []struct {
 		name              string
 		objects           []runtime.Object
+		defaultPort       int
 		expectedAddresses []string
 		expectError       bool
 	}{
@@ -82,6 +83,19 @@ func TestGetKubeAPIServerEndpoints(t *testing.T) {
 			},
 			expectedAddresses: []string{"1.1.1.1:6443", "2.2.2.2:6443", "3.3.3.3:10000"},
 		},
+		{
+			name: "default port",
+			objects: []runtime.Object{
+				&corev1.Endpoints{
+					ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"},
+					Subsets: []corev1.EndpointSubset{
+						{Addresses: []corev1.EndpointAddress{{IP: "1.1.1.1"}}},
+					},
+				},
+			},
+			defaultPort:       16443,
+			expectedAddresses: []string{"1.1.1.1:16443"},
+		},
 		{
 			name: "sort",
 			objects: []runtime.Object{
@@ -101,10 +115,13 @@ func TestGetKubeAPIServerEndpoints(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
 
+			if tc.defaultPort == 0 {
+				tc.defaultPort = 6443
+			}
 			clientset := fake.NewSimpleClientset(tc.objects...)
 			client := &Client{Interface: clientset}
 
-			servers, err := client.GetKubeAPIServerEndpoints(context.Background())
+			servers, err := client.GetKubeAPIServerEndpoints(context.Background(), tc.defaultPort)
 			if tc.expectError {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(servers).To(BeEmpty())
@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// MaintenanceAnnotation marks a node that is in maintenance.
+// The k8sd database is the source of truth, the annotation lets worker nodes, which do not have access to it, know about the maintenance.
+const MaintenanceAnnotation = "k8sd.io/maintenance"
+
+// SetNodeMaintenance adds or removes the maintenance annotation of a node.
+// SetNodeMaintenance will retry if there is a conflict on the resource.
+func (c *Client) SetNodeMaintenance(ctx context.Context, nodeName string, inMaintenance bool) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		node, err := c.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+		if _, ok := node.Annotations[MaintenanceAnnotation]; ok == inMaintenance {
+			return nil
+		}
+		if inMaintenance {
+			if node.Annotations == nil {
+				node.Annotations = make(map[string]string)
+			}
+			node.Annotations[MaintenanceAnnotation] = "true"
+		} else {
+			delete(node.Annotations, MaintenanceAnnotation)
+		}
+		if _, err := c.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update node: %w", err)
+		}
+		return nil
+	})
+}
+
+// IsNodeInMaintenance returns true if the node has the maintenance annotation.
+func (c *Client) IsNodeInMaintenance(ctx context.Context, nodeName string) (bool, error) {
+	node, err := c.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get node: %w", err)
+	}
+	_, ok := node.Annotations[MaintenanceAnnotation]
+	return ok, nil
+}
@@ -0,0 +1,33 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeMaintenance(t *testing.T) {
+	g := NewWithT(t)
+	client := &Client{Interface: fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}})}
+
+	inMaintenance, err := client.IsNodeInMaintenance(context.Background(), "n1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMaintenance).To(BeFalse())
+
+	g.Expect(client.SetNodeMaintenance(context.Background(), "n1", true)).To(Succeed())
+	inMaintenance, err = client.IsNodeInMaintenance(context.Background(), "n1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMaintenance).To(BeTrue())
+
+	g.Expect(client.SetNodeMaintenance(context.Background(), "n1", false)).To(Succeed())
+	inMaintenance, err = client.IsNodeInMaintenance(context.Background(), "n1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMaintenance).To(BeFalse())
+
+	_, err = client.IsNodeInMaintenance(context.Background(), "n2")
+	g.Expect(err).To(HaveOccurred())
+}
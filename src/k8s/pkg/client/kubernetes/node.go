@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 )
@@ -19,3 +20,17 @@ func (c *Client) DeleteNode(ctx context.Context, nodeName string) error {
 		return nil
 	})
 }
+
+// GetNodeZones returns the zone of each node, as set by its "topology.kubernetes.io/zone" label.
+// Nodes without the label are not included.
+func (c *Client) GetNodeZones(ctx context.Context) (map[string]string, error) {
+	nodes, err := c.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: corev1.LabelTopologyZone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	zones := make(map[string]string, len(nodes.Items))
+	for _, node := range nodes.Items {
+		zones[node.Name] = node.Labels[corev1.LabelTopologyZone]
+	}
+	return zones, nil
+}
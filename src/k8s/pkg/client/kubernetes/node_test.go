@@ -44,3 +44,18 @@ func TestDeleteNode(t *testing.T) {
 		g.Expect(err).To(gomega.MatchError(fmt.Errorf("failed to delete node: %w", expectedErr)))
 	})
 }
+
+func TestGetNodeZones(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	clientset := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{"topology.kubernetes.io/zone": "a"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n2", Labels: map[string]string{"topology.kubernetes.io/zone": "b"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n3"}},
+	)
+	client := &Client{Interface: clientset}
+
+	zones, err := client.GetNodeZones(context.Background())
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(zones).To(gomega.Equal(map[string]string{"n1": "a", "n2": "b"}))
+}
@@ -0,0 +1,96 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	nodev1 "k8s.io/api/node/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// runtimeClassManagedByLabel is set on all RuntimeClass objects that are managed by k8sd.
+// RuntimeClass objects without the label have been created by the user and are never modified.
+const runtimeClassManagedByLabel = "app.kubernetes.io/managed-by"
+
+// RuntimeClass describes a RuntimeClass object that is managed by k8sd.
+type RuntimeClass struct {
+	// Name is the name of the RuntimeClass object.
+	Name string
+	// Handler is the name of the containerd runtime handler.
+	Handler string
+	// NodeSelector restricts scheduling of pods using the RuntimeClass to matching nodes.
+	NodeSelector map[string]string
+}
+
+// UpdateRuntimeClasses ensures the given RuntimeClass objects exist in the cluster.
+// RuntimeClass objects that were previously created by k8sd but are not in the list are removed.
+// UpdateRuntimeClasses will retry if there is a conflict on the resources.
+func (c *Client) UpdateRuntimeClasses(ctx context.Context, runtimeClasses []RuntimeClass) error {
+	managed := make(map[string]struct{}, len(runtimeClasses))
+	for _, runtimeClass := range runtimeClasses {
+		if err := c.updateRuntimeClass(ctx, runtimeClass); err != nil {
+			return fmt.Errorf("failed to update RuntimeClass %s: %w", runtimeClass.Name, err)
+		}
+		managed[runtimeClass.Name] = struct{}{}
+	}
+
+	existing, err := c.NodeV1().RuntimeClasses().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=k8sd", runtimeClassManagedByLabel)})
+	if err != nil {
+		return fmt.Errorf("failed to list RuntimeClasses: %w", err)
+	}
+	for _, runtimeClass := range existing.Items {
+		if _, ok := managed[runtimeClass.Name]; ok {
+			continue
+		}
+		if err := c.NodeV1().RuntimeClasses().Delete(ctx, runtimeClass.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete RuntimeClass %s: %w", runtimeClass.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) updateRuntimeClass(ctx context.Context, runtimeClass RuntimeClass) error {
+	var scheduling *nodev1.Scheduling
+	if len(runtimeClass.NodeSelector) > 0 {
+		scheduling = &nodev1.Scheduling{NodeSelector: runtimeClass.NodeSelector}
+	}
+
+	desired := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   runtimeClass.Name,
+			Labels: map[string]string{runtimeClassManagedByLabel: "k8sd"},
+		},
+		Handler:    runtimeClass.Handler,
+		Scheduling: scheduling,
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current, err := c.NodeV1().RuntimeClasses().Get(ctx, runtimeClass.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = c.NodeV1().RuntimeClasses().Create(ctx, desired, metav1.CreateOptions{})
+			return err
+		case err != nil:
+			return err
+		case current.Labels[runtimeClassManagedByLabel] != "k8sd":
+			return fmt.Errorf("RuntimeClass already exists and is not managed by k8sd")
+		case current.Handler != runtimeClass.Handler:
+			// the handler of a RuntimeClass is immutable, so the object has to be re-created
+			if err := c.NodeV1().RuntimeClasses().Delete(ctx, runtimeClass.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			_, err = c.NodeV1().RuntimeClasses().Create(ctx, desired, metav1.CreateOptions{})
+			return err
+		case reflect.DeepEqual(current.Scheduling, scheduling):
+			return nil
+		}
+
+		current.Scheduling = scheduling
+		_, err = c.NodeV1().RuntimeClasses().Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,76 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	nodev1 "k8s.io/api/node/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUpdateRuntimeClasses(t *testing.T) {
+	ctx := context.Background()
+
+	clientset := fake.NewSimpleClientset(
+		&nodev1.RuntimeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "user-class"},
+			Handler:    "user-handler",
+		},
+		&nodev1.RuntimeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "stale", Labels: map[string]string{"app.kubernetes.io/managed-by": "k8sd"}},
+			Handler:    "stale",
+		},
+	)
+	client := &Client{Interface: clientset}
+
+	t.Run("Create", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(client.UpdateRuntimeClasses(ctx, []RuntimeClass{
+			{Name: "gvisor", Handler: "gvisor", NodeSelector: map[string]string{"sandbox": "gvisor"}},
+			{Name: "kata", Handler: "kata"},
+		})).To(Succeed())
+
+		gvisor, err := clientset.NodeV1().RuntimeClasses().Get(ctx, "gvisor", metav1.GetOptions{})
+		g.Expect(err).To(BeNil())
+		g.Expect(gvisor.Handler).To(Equal("gvisor"))
+		g.Expect(gvisor.Scheduling).To(Equal(&nodev1.Scheduling{NodeSelector: map[string]string{"sandbox": "gvisor"}}))
+
+		kata, err := clientset.NodeV1().RuntimeClasses().Get(ctx, "kata", metav1.GetOptions{})
+		g.Expect(err).To(BeNil())
+		g.Expect(kata.Scheduling).To(BeNil())
+
+		_, err = clientset.NodeV1().RuntimeClasses().Get(ctx, "stale", metav1.GetOptions{})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(client.UpdateRuntimeClasses(ctx, []RuntimeClass{
+			{Name: "gvisor", Handler: "runsc"},
+		})).To(Succeed())
+
+		gvisor, err := clientset.NodeV1().RuntimeClasses().Get(ctx, "gvisor", metav1.GetOptions{})
+		g.Expect(err).To(BeNil())
+		g.Expect(gvisor.Handler).To(Equal("runsc"))
+		g.Expect(gvisor.Scheduling).To(BeNil())
+
+		_, err = clientset.NodeV1().RuntimeClasses().Get(ctx, "kata", metav1.GetOptions{})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("UserManaged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(client.UpdateRuntimeClasses(ctx, []RuntimeClass{
+			{Name: "user-class", Handler: "gvisor"},
+		})).ToNot(Succeed())
+
+		userClass, err := clientset.NodeV1().RuntimeClasses().Get(ctx, "user-class", metav1.GetOptions{})
+		g.Expect(err).To(BeNil())
+		g.Expect(userClass.Handler).To(Equal("user-handler"))
+	})
+}
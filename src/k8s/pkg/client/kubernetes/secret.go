@@ -0,0 +1,47 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyv1 "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+// GetSecretData returns the data of a secret.
+// GetSecretData returns nil data and no error if the secret does not exist.
+func (c *Client) GetSecretData(ctx context.Context, namespace string, name string) (map[string][]byte, error) {
+	secret, err := c.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get secret, namespace: %s name: %s: %w", namespace, name, err)
+	}
+	return secret.Data, nil
+}
+
+// UpdateSecret creates or updates an opaque secret with the given data.
+func (c *Client) UpdateSecret(ctx context.Context, namespace string, name string, data map[string][]byte) (*v1.Secret, error) {
+	opts := applyv1.Secret(name, namespace).WithType(v1.SecretTypeOpaque).WithData(data)
+	secret, err := c.CoreV1().Secrets(namespace).Apply(ctx, opts, metav1.ApplyOptions{FieldManager: "ck-k8s-client"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret, namespace: %s name: %s: %w", namespace, name, err)
+	}
+	return secret, nil
+}
+
+// ListSecretsMetadata returns the metadata of the secrets that match the label selector, without their data.
+// ListSecretsMetadata lists the secrets of all namespaces if namespace is empty.
+func (c *Client) ListSecretsMetadata(ctx context.Context, namespace string, labelSelector string) ([]metav1.PartialObjectMetadata, error) {
+	if c.Metadata == nil {
+		return nil, fmt.Errorf("metadata client is not configured")
+	}
+	list, err := c.Metadata.Resource(v1.SchemeGroupVersion.WithResource("secrets")).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secret metadata, namespace: %s selector: %s: %w", namespace, labelSelector, err)
+	}
+	return list.Items, nil
+}
@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func TestSecret(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NotFound", func(t *testing.T) {
+		g := NewWithT(t)
+		client := &Client{Interface: fake.NewSimpleClientset()}
+
+		data, err := client.GetSecretData(ctx, "kube-system", "test-secret")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data).To(BeNil())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		g := NewWithT(t)
+		client := &Client{Interface: fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "kube-system"},
+			Data:       map[string][]byte{"existing-key": []byte("old-value")},
+		})}
+
+		updateData := map[string][]byte{"existing-key": []byte("change-value")}
+		secret, err := client.UpdateSecret(ctx, "kube-system", "test-secret", updateData)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(secret.Data).To(Equal(updateData))
+
+		data, err := client.GetSecretData(ctx, "kube-system", "test-secret")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data).To(Equal(updateData))
+	})
+
+	t.Run("ListMetadata", func(t *testing.T) {
+		g := NewWithT(t)
+		scheme := metadatafake.NewTestScheme()
+		g.Expect(metav1.AddMetaToScheme(scheme)).To(Succeed())
+		secretMetadata := func(namespace, name string, labels map[string]string) *metav1.PartialObjectMetadata {
+			return &metav1.PartialObjectMetadata{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			}
+		}
+		client := &Client{Metadata: metadatafake.NewSimpleMetadataClient(scheme,
+			secretMetadata("kube-system", "release", map[string]string{"owner": "helm"}),
+			secretMetadata("default", "release", map[string]string{"owner": "helm"}),
+			secretMetadata("kube-system", "other", nil),
+		)}
+
+		secrets, err := client.ListSecretsMetadata(ctx, "", "owner=helm")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(secrets).To(HaveLen(2))
+
+		secrets, err = client.ListSecretsMetadata(ctx, "kube-system", "owner=helm")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(secrets).To(HaveLen(1))
+		g.Expect(secrets[0].Namespace).To(Equal("kube-system"))
+	})
+}
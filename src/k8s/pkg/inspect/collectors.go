@@ -0,0 +1,313 @@
+package inspect
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+var (
+	// controlPlaneServices are the snap services that run on control plane nodes.
+	controlPlaneServices = []string{"containerd", "k8sd", "k8s-dqlite", "kube-apiserver", "kube-controller-manager", "kube-proxy", "kube-scheduler", "kubelet"}
+	// workerServices are the snap services that run on worker nodes.
+	workerServices = []string{"containerd", "k8sd", "k8s-apiserver-proxy", "kube-proxy", "kubelet"}
+
+	// runCommand runs a command and returns its combined output. It is replaced in tests.
+	runCommand = func(ctx context.Context, command ...string) ([]byte, error) {
+		return exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+	}
+)
+
+// collectNodeStatus adds the k8sd status of the local node and records the role of the node.
+func collectNodeStatus(ctx context.Context, b *bundle, opts Options) error {
+	if opts.Client == nil {
+		return fmt.Errorf("k8sd is not available")
+	}
+	status, err := opts.Client.LocalNodeStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local node status: %w", err)
+	}
+	if status.ClusterRole != "" {
+		b.manifest.ClusterRole = status.ClusterRole
+	}
+	return b.addJSON("k8sd/node-status.json", status)
+}
+
+// collectClusterStatus adds the k8sd cluster status, including the cluster configuration.
+func collectClusterStatus(ctx context.Context, b *bundle, opts Options) error {
+	if opts.Client == nil || b.manifest.ClusterRole != apiv1.ClusterRoleControlPlane {
+		return nil
+	}
+	status, err := opts.Client.ClusterStatus(ctx, apiv1.GetClusterStatusRequest{Detailed: true}, false)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster status: %w", err)
+	}
+	return b.addJSON("k8sd/cluster-status.json", status)
+}
+
+// datastoreMember is a member of the k8s-dqlite cluster.
+type datastoreMember struct {
+	ID      uint64 `json:"id"`
+	Address string `json:"address"`
+	Role    string `json:"role"`
+}
+
+// collectDatastoreMembers adds the members of the k8s-dqlite cluster.
+func collectDatastoreMembers(ctx context.Context, b *bundle, opts Options) error {
+	if exists, _ := utils.FileExists(opts.Snap.K8sDqliteStateDir(), "cluster.yaml"); !exists {
+		return nil
+	}
+	client, err := opts.Snap.K8sDqliteClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+	}
+	nodes, err := client.ListMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list k8s-dqlite members: %w", err)
+	}
+	members := make([]datastoreMember, 0, len(nodes))
+	for _, node := range nodes {
+		members = append(members, datastoreMember{ID: node.ID, Address: node.Address, Role: node.Role.String()})
+	}
+	return b.addJSON("datastore/members.json", members)
+}
+
+// helmRelease is the status of a Helm release.
+type helmRelease struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Revision  int    `json:"revision"`
+	Status    string `json:"status"`
+}
+
+// collectHelmReleases adds the status of the Helm releases of the cluster features.
+// Only the metadata of the Helm release secrets is retrieved, as the releases contain the chart values.
+func collectHelmReleases(ctx context.Context, b *bundle, opts Options) error {
+	if b.manifest.ClusterRole != apiv1.ClusterRoleControlPlane {
+		return nil
+	}
+	client, err := opts.Snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	secrets, err := client.ListSecretsMetadata(ctx, "", "owner=helm")
+	if err != nil {
+		return fmt.Errorf("failed to list helm release secrets: %w", err)
+	}
+
+	latest := make(map[string]helmRelease)
+	for _, secret := range secrets {
+		revision, _ := strconv.Atoi(secret.Labels["version"])
+		release := helmRelease{
+			Namespace: secret.Namespace,
+			Name:      secret.Labels["name"],
+			Revision:  revision,
+			Status:    secret.Labels["status"],
+		}
+		key := release.Namespace + "/" + release.Name
+		if existing, ok := latest[key]; !ok || existing.Revision < release.Revision {
+			latest[key] = release
+		}
+	}
+
+	releases := make([]helmRelease, 0, len(latest))
+	for _, release := range latest {
+		releases = append(releases, release)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		return releases[i].Name < releases[j].Name
+	})
+	return b.addJSON("features/helm-releases.json", releases)
+}
+
+// collectArgs adds the arguments files of the services and their extra configuration files.
+func collectArgs(ctx context.Context, b *bundle, opts Options) error {
+	for _, dir := range []struct{ path, prefix string }{
+		{path: opts.Snap.ServiceArgumentsDir(), prefix: "args"},
+		{path: opts.Snap.ServiceExtraConfigDir(), prefix: "args/conf.d"},
+	} {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to list %s: %w", dir.path, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir.path, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+			}
+			if err := b.add(filepath.Join(dir.prefix, entry.Name()), data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectServices adds the systemd status and the journal logs of the services of the node.
+func collectServices(ctx context.Context, b *bundle, opts Options) error {
+	services := controlPlaneServices
+	if b.manifest.ClusterRole == apiv1.ClusterRoleWorker {
+		services = workerServices
+	}
+
+	var failed []string
+	for _, service := range services {
+		unit := fmt.Sprintf("snap.k8s.%s", service)
+
+		// systemctl status exits with a non-zero code for inactive services, the output is still useful.
+		status, _ := runCommand(ctx, "systemctl", "status", "--no-pager", unit)
+		if err := b.add(fmt.Sprintf("services/%s/status.log", service), status); err != nil {
+			return err
+		}
+
+		journal, err := runCommand(ctx, "journalctl", "--no-pager", "-n", strconv.Itoa(opts.JournalLines), "-u", unit)
+		if err != nil {
+			failed = append(failed, service)
+		}
+		if err := b.add(fmt.Sprintf("services/%s/journal.log", service), journal); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to collect journal logs for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// certificateInfo is the expiry information of a certificate.
+type certificateInfo struct {
+	Path          string    `json:"path"`
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	NotBefore     time.Time `json:"not-before"`
+	NotAfter      time.Time `json:"not-after"`
+	ExpiresInDays int       `json:"expires-in-days"`
+}
+
+// collectCertificates adds the expiry dates of the certificates of the node.
+func collectCertificates(ctx context.Context, b *bundle, opts Options) error {
+	certificates := []certificateInfo{}
+	now := time.Now()
+	for _, dir := range []string{opts.Snap.KubernetesPKIDir(), opts.Snap.K8sDqliteStateDir()} {
+		if err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(p, ".crt") {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", p, err)
+			}
+			block, _ := pem.Decode(data)
+			if block == nil {
+				return fmt.Errorf("%s is not a PEM encoded certificate", p)
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", p, err)
+			}
+			certificates = append(certificates, certificateInfo{
+				Path:          p,
+				Subject:       cert.Subject.String(),
+				Issuer:        cert.Issuer.String(),
+				NotBefore:     cert.NotBefore.UTC(),
+				NotAfter:      cert.NotAfter.UTC(),
+				ExpiresInDays: int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+			})
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to collect certificates from %s: %w", dir, err)
+		}
+	}
+	return b.addJSON("certificates/expiry.json", certificates)
+}
+
+// nodeResources are the resources of the node.
+type nodeResources struct {
+	Kernel               string    `json:"kernel"`
+	Architecture         string    `json:"architecture"`
+	CPUs                 int       `json:"cpus"`
+	LoadAverage          []float64 `json:"load-average"`
+	MemoryTotalBytes     uint64    `json:"memory-total-bytes"`
+	MemoryAvailableBytes uint64    `json:"memory-available-bytes"`
+	DiskPath             string    `json:"disk-path"`
+	DiskTotalBytes       uint64    `json:"disk-total-bytes"`
+	DiskFreeBytes        uint64    `json:"disk-free-bytes"`
+}
+
+// collectNodeResources adds the CPU, memory and disk resources of the node.
+func collectNodeResources(ctx context.Context, b *bundle, opts Options) error {
+	resources := nodeResources{
+		Architecture: runtime.GOARCH,
+		CPUs:         runtime.NumCPU(),
+		LoadAverage:  []float64{},
+	}
+
+	if release, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		resources.Kernel = strings.TrimSpace(string(release))
+	}
+
+	var sysinfo syscall.Sysinfo_t
+	if err := syscall.Sysinfo(&sysinfo); err == nil {
+		for _, load := range sysinfo.Loads {
+			// load averages are fixed point numbers with 16 bits of fraction, see sysinfo(2).
+			resources.LoadAverage = append(resources.LoadAverage, math.Round(float64(load)/65536*100)/100)
+		}
+	}
+
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 2 {
+				continue
+			}
+			kb, _ := strconv.ParseUint(fields[1], 10, 64)
+			switch fields[0] {
+			case "MemTotal:":
+				resources.MemoryTotalBytes = kb * 1024
+			case "MemAvailable:":
+				resources.MemoryAvailableBytes = kb * 1024
+			}
+		}
+		f.Close()
+	}
+
+	resources.DiskPath = opts.Snap.ContainerdRootDir()
+	var statfs syscall.Statfs_t
+	if err := syscall.Statfs(resources.DiskPath, &statfs); err == nil {
+		resources.DiskTotalBytes = statfs.Blocks * uint64(statfs.Bsize)
+		resources.DiskFreeBytes = statfs.Bavail * uint64(statfs.Bsize)
+	}
+
+	return b.addJSON("node/resources.json", resources)
+}
@@ -0,0 +1,170 @@
+// Package inspect collects diagnostic information about a node into a support bundle.
+package inspect
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8s/client"
+	"github.com/canonical/k8s/pkg/snap"
+)
+
+// FormatVersion is the version of the report layout. It is increased on incompatible changes,
+// e.g. when a file is moved or the schema of a JSON file changes.
+const FormatVersion = 1
+
+// RootDir is the top-level directory of the report tarball.
+const RootDir = "inspection-report"
+
+// ManifestFile is the name of the report manifest, relative to RootDir.
+const ManifestFile = "manifest.json"
+
+// Manifest describes the contents of a report.
+type Manifest struct {
+	// FormatVersion is the version of the report layout.
+	FormatVersion int `json:"format-version"`
+	// CreatedAt is the time the report was created.
+	CreatedAt time.Time `json:"created-at"`
+	// Hostname is the hostname of the node.
+	Hostname string `json:"hostname"`
+	// ClusterRole is the role of the node in the cluster.
+	ClusterRole apiv1.ClusterRole `json:"cluster-role"`
+	// Files are the files in the report, relative to RootDir.
+	Files []ManifestEntry `json:"files"`
+	// Errors are the problems that were encountered while collecting the report.
+	Errors []ManifestError `json:"errors"`
+}
+
+// ManifestEntry is a single file in a report.
+type ManifestEntry struct {
+	// Path is the path of the file, relative to RootDir.
+	Path string `json:"path"`
+	// Collector is the name of the collector that produced the file.
+	Collector string `json:"collector"`
+}
+
+// ManifestError is a problem that was encountered while collecting a report.
+type ManifestError struct {
+	// Collector is the name of the collector that failed.
+	Collector string `json:"collector"`
+	// Error is the error message.
+	Error string `json:"error"`
+}
+
+// Options configure the collection of a report.
+type Options struct {
+	// Snap is the snap of the node.
+	Snap snap.Snap
+	// Client is the k8sd client of the node. If nil, the k8sd status is not collected.
+	Client client.Client
+	// JournalLines is the number of journal lines to collect for each service.
+	JournalLines int
+}
+
+// collector adds a part of the report to the bundle.
+type collector struct {
+	name    string
+	collect func(ctx context.Context, b *bundle, opts Options) error
+}
+
+// collectors are run in order. Collectors that need the cluster role must run after "node-status".
+var collectors = []collector{
+	{name: "node-status", collect: collectNodeStatus},
+	{name: "cluster-status", collect: collectClusterStatus},
+	{name: "datastore", collect: collectDatastoreMembers},
+	{name: "helm-releases", collect: collectHelmReleases},
+	{name: "args", collect: collectArgs},
+	{name: "services", collect: collectServices},
+	{name: "certificates", collect: collectCertificates},
+	{name: "node-resources", collect: collectNodeResources},
+}
+
+// Collect writes a gzip compressed tarball with the report to w.
+// Collect returns the manifest of the report. Failures of individual collectors are recorded in the manifest
+// and do not fail the report. All files are redacted before they are added to the report.
+func Collect(ctx context.Context, w io.Writer, opts Options) (Manifest, error) {
+	hostname, _ := os.Hostname()
+
+	gw := gzip.NewWriter(w)
+	b := &bundle{
+		tw: tar.NewWriter(gw),
+		manifest: Manifest{
+			FormatVersion: FormatVersion,
+			CreatedAt:     time.Now().UTC(),
+			Hostname:      hostname,
+			ClusterRole:   apiv1.ClusterRoleUnknown,
+			Files:         []ManifestEntry{},
+			Errors:        []ManifestError{},
+		},
+	}
+
+	for _, c := range collectors {
+		b.collector = c.name
+		if err := c.collect(ctx, b, opts); err != nil {
+			b.manifest.Errors = append(b.manifest.Errors, ManifestError{Collector: c.name, Error: err.Error()})
+		}
+	}
+
+	manifest, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := b.write(ManifestFile, manifest); err != nil {
+		return Manifest{}, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := b.tw.Close(); err != nil {
+		return Manifest{}, fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return Manifest{}, fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+	return b.manifest, nil
+}
+
+// bundle is the report that is being collected.
+type bundle struct {
+	tw        *tar.Writer
+	manifest  Manifest
+	collector string
+}
+
+// add redacts data and adds it to the report as name.
+func (b *bundle) add(name string, data []byte) error {
+	if err := b.write(name, Redact(data)); err != nil {
+		return fmt.Errorf("failed to add %s to report: %w", name, err)
+	}
+	b.manifest.Files = append(b.manifest.Files, ManifestEntry{Path: name, Collector: b.collector})
+	return nil
+}
+
+// addJSON adds v to the report as name.
+func (b *bundle) addJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return b.add(name, append(data, '\n'))
+}
+
+func (b *bundle) write(name string, data []byte) error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:    path.Join(RootDir, name),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: b.manifest.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+	return nil
+}
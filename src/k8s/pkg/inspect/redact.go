@@ -0,0 +1,70 @@
+package inspect
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// privateKeyRegexp matches PEM encoded private keys.
+	privateKeyRegexp = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+	// keyValueRegexp matches "key=value", "--key=value", "key: value", "key = \"value\"" and "\"key\": \"value\"".
+	keyValueRegexp = regexp.MustCompile(`(?i)(["']?)([a-z0-9_.-]+)(["']?[ \t]*[:=][ \t]*)("[^"\n]*"|'[^'\n]*'|[^\s,}\]"']+)`)
+
+	// authorizationHeaderRegexp matches unquoted HTTP authorization headers, e.g. "Authorization: Bearer <token>".
+	// The whole header value is matched, as it contains the authentication scheme and the credentials.
+	authorizationHeaderRegexp = regexp.MustCompile(`(?i)\b((?:proxy-)?authorization[ \t]*:[ \t]*)([^\s"'][^"'\r\n]*)`)
+
+	// sensitiveKeys are substrings of keys whose values must not be included in the report.
+	sensitiveKeys = []string{"password", "passwd", "secret", "token", "key-data"}
+	// sensitiveExactKeys are keys whose values must not be included in the report.
+	sensitiveExactKeys = []string{"auth", "identitytoken", "authorization", "proxy-authorization"}
+	// pathSuffixes are suffixes of keys whose values are file paths, which are safe to include.
+	pathSuffixes = []string{"-file", "_file", "-dir", "_dir", "-path", "_path"}
+)
+
+// Redacted is the replacement for sensitive values in the report.
+const Redacted = "<redacted>"
+
+// Redact replaces private keys, authorization headers and the values of sensitive keys (passwords, tokens, secrets) in b.
+func Redact(b []byte) []byte {
+	b = privateKeyRegexp.ReplaceAll(b, []byte(Redacted))
+	b = authorizationHeaderRegexp.ReplaceAll(b, []byte("${1}"+Redacted))
+
+	return keyValueRegexp.ReplaceAllFunc(b, func(match []byte) []byte {
+		parts := keyValueRegexp.FindSubmatch(match)
+		key, value := string(parts[2]), string(parts[4])
+		if !isSensitiveKey(key) || value == "true" || value == "false" || value == `""` {
+			return match
+		}
+
+		redacted := Redacted
+		if strings.HasPrefix(value, `"`) {
+			redacted = `"` + Redacted + `"`
+		} else if strings.HasPrefix(value, `'`) {
+			redacted = `'` + Redacted + `'`
+		}
+		return []byte(string(parts[1]) + key + string(parts[3]) + redacted)
+	})
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.TrimLeft(key, "-"))
+	for _, suffix := range pathSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return false
+		}
+	}
+	for _, exact := range sensitiveExactKeys {
+		if key == exact {
+			return true
+		}
+	}
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -32,10 +32,10 @@ func (c *k8sdClient) Bootstrap(ctx context.Context, request apiv1.PostClusterBoo
 }
 
 // ClusterStatus returns the current status of the cluster.
-func (c *k8sdClient) ClusterStatus(ctx context.Context, waitReady bool) (apiv1.ClusterStatus, error) {
+func (c *k8sdClient) ClusterStatus(ctx context.Context, request apiv1.GetClusterStatusRequest, waitReady bool) (apiv1.ClusterStatus, error) {
 	var response apiv1.GetClusterStatusResponse
 	err := control.WaitUntilReady(ctx, func() (bool, error) {
-		if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "cluster"), nil, &response); err != nil {
+		if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "cluster"), request, &response); err != nil {
 			return false, fmt.Errorf("failed to GET /k8sd/cluster: %w", err)
 		}
 		return !waitReady || response.ClusterStatus.Ready, nil
@@ -2,26 +2,50 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
 	api "github.com/canonical/lxd/shared/api"
 )
 
+// ErrClusterConfigConflict is returned when the cluster configuration was changed since the expected revision.
+var ErrClusterConfigConflict = errors.New("cluster configuration was changed concurrently")
+
 func (c *k8sdClient) UpdateClusterConfig(ctx context.Context, request apiv1.UpdateClusterConfigRequest) error {
 	var response apiv1.UpdateClusterConfigResponse
 	if err := c.mc.Query(ctx, "PUT", api.NewURL().Path("k8sd", "cluster", "config"), request, &response); err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return fmt.Errorf("failed to PUT /k8sd/cluster/config: %w: %v", ErrClusterConfigConflict, err)
+		}
 		return fmt.Errorf("failed to PUT /k8sd/cluster/config: %w", err)
 	}
 	return nil
 }
 
-func (c *k8sdClient) GetClusterConfig(ctx context.Context, request apiv1.GetClusterConfigRequest) (apiv1.UserFacingClusterConfig, error) {
+func (c *k8sdClient) GetClusterConfig(ctx context.Context, request apiv1.GetClusterConfigRequest) (apiv1.UserFacingClusterConfig, int, error) {
 	var response apiv1.GetClusterConfigResponse
 
 	if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "cluster", "config"), nil, &response); err != nil {
-		return apiv1.UserFacingClusterConfig{}, fmt.Errorf("failed to GET /k8sd/cluster/config: %w", err)
+		return apiv1.UserFacingClusterConfig{}, 0, fmt.Errorf("failed to GET /k8sd/cluster/config: %w", err)
+	}
+
+	return response.Config, response.Revision, nil
+}
+
+func (c *k8sdClient) GetClusterConfigRevisions(ctx context.Context, request apiv1.GetClusterConfigRevisionsRequest) ([]apiv1.ClusterConfigRevision, error) {
+	var response apiv1.GetClusterConfigRevisionsResponse
+	if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "cluster", "config", "revisions"), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to GET /k8sd/cluster/config/revisions: %w", err)
 	}
+	return response.Revisions, nil
+}
 
-	return response.Config, nil
+func (c *k8sdClient) RollbackClusterConfig(ctx context.Context, request apiv1.RollbackClusterConfigRequest) error {
+	var response apiv1.RollbackClusterConfigResponse
+	if err := c.mc.Query(ctx, "POST", api.NewURL().Path("k8sd", "cluster", "config", "rollback"), request, &response); err != nil {
+		return fmt.Errorf("failed to POST /k8sd/cluster/config/rollback: %w", err)
+	}
+	return nil
 }
@@ -34,6 +34,13 @@ func (c *k8sdClient) RemoveNode(ctx context.Context, request apiv1.RemoveNodeReq
 	return nil
 }
 
+func (c *k8sdClient) NodeMaintenance(ctx context.Context, request apiv1.NodeMaintenanceRequest) error {
+	if err := c.mc.Query(ctx, "POST", api.NewURL().Path("k8sd", "cluster", "maintenance"), request, nil); err != nil {
+		return fmt.Errorf("failed to POST /k8sd/cluster/maintenance: %w", err)
+	}
+	return nil
+}
+
 func (c *k8sdClient) ResetNode(ctx context.Context, name string, force bool) error {
 	if err := c.mc.ResetClusterMember(ctx, name, force); err != nil {
 		return fmt.Errorf("failed to ResetClusterMember: %w", err)
@@ -46,7 +53,7 @@ func (c *k8sdClient) ResetNode(ctx context.Context, name string, force bool) err
 // dqlite node is properly setup yet.
 func (c *k8sdClient) WaitForDqliteNodeToBeReady(ctx context.Context, nodeName string) error {
 	return control.WaitUntilReady(ctx, func() (bool, error) {
-		clusterStatus, err := c.ClusterStatus(ctx, false)
+		clusterStatus, err := c.ClusterStatus(ctx, apiv1.GetClusterStatusRequest{}, false)
 		if err != nil {
 			return false, fmt.Errorf("failed to get the cluster status: %w", err)
 		}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func (c *k8sdClient) GetDatastoreMembers(ctx context.Context, request apiv1.GetDatastoreMembersRequest) ([]apiv1.DatastoreMember, error) {
+	var response apiv1.GetDatastoreMembersResponse
+	if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "datastore", "members"), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to GET /k8sd/datastore/members: %w", err)
+	}
+	return response.Members, nil
+}
+
+func (c *k8sdClient) SetDatastoreRole(ctx context.Context, request apiv1.SetDatastoreRoleRequest) error {
+	if err := c.mc.Query(ctx, "POST", api.NewURL().Path("k8sd", "datastore", "role"), request, nil); err != nil {
+		return fmt.Errorf("failed to POST /k8sd/datastore/role: %w", err)
+	}
+	return nil
+}
+
+func (c *k8sdClient) ResetDatastoreRole(ctx context.Context, request apiv1.ResetDatastoreRoleRequest) error {
+	if err := c.mc.Query(ctx, "DELETE", api.NewURL().Path("k8sd", "datastore", "role"), request, nil); err != nil {
+		return fmt.Errorf("failed to DELETE /k8sd/datastore/role: %w", err)
+	}
+	return nil
+}
+
+func (c *k8sdClient) TransferDatastoreLeadership(ctx context.Context, request apiv1.TransferDatastoreLeadershipRequest) error {
+	if err := c.mc.Query(ctx, "POST", api.NewURL().Path("k8sd", "datastore", "transfer-leadership"), request, nil); err != nil {
+		return fmt.Errorf("failed to POST /k8sd/datastore/transfer-leadership: %w", err)
+	}
+	return nil
+}
+
+func (c *k8sdClient) GetDatastoreHealth(ctx context.Context, request apiv1.GetDatastoreHealthRequest) ([]apiv1.DatastoreMemberHealth, error) {
+	var response apiv1.GetDatastoreHealthResponse
+	if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "datastore", "health"), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to GET /k8sd/datastore/health: %w", err)
+	}
+	return response.Members, nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	api "github.com/canonical/lxd/shared/api"
+)
+
+func (c *k8sdClient) GetEvents(ctx context.Context, request apiv1.GetEventsRequest) ([]apiv1.Event, error) {
+	var response apiv1.GetEventsResponse
+	path := api.NewURL().Path("k8sd", "events").WithQuery("since", strconv.FormatUint(request.Since, 10))
+	if err := c.mc.Query(ctx, "GET", path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to GET /k8sd/events: %w", err)
+	}
+	return response.Events, nil
+}
+
+func (c *k8sdClient) WatchEvents(ctx context.Context, request apiv1.GetEventsRequest, handler func(apiv1.Event) error) error {
+	// the event stream is not a JSON response, so it is read from the HTTP client of the local microcluster client
+	u := c.mc.URL()
+	u.URL.Path = "/1.0/k8sd/events"
+	u = *u.WithQuery("since", strconv.FormatUint(request.Since, 10)).WithQuery("follow", "true")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.mc.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to GET /k8sd/events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse api.Response
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return fmt.Errorf("failed to GET /k8sd/events: %s", resp.Status)
+		}
+		return fmt.Errorf("failed to GET /k8sd/events: %w", api.StatusErrorf(resp.StatusCode, errorResponse.Error))
+	}
+
+	reader := events.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("event stream was closed: %w", err)
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+}
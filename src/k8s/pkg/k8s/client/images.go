@@ -0,0 +1,18 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func (c *k8sdClient) GetClusterImages(ctx context.Context, request apiv1.GetClusterImagesRequest) ([]string, error) {
+	var response apiv1.GetClusterImagesResponse
+
+	if err := c.mc.Query(ctx, "GET", api.NewURL().Path("k8sd", "cluster", "images"), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to GET /k8sd/cluster/images: %w", err)
+	}
+	return response.Images, nil
+}
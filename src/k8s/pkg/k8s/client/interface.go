@@ -15,7 +15,7 @@ type Client interface {
 	// CleanupNode performs cleanup operations for a specific node in the cluster.
 	CleanupNode(ctx context.Context, nodeName string)
 	// ClusterStatus retrieves the current status of the Kubernetes cluster.
-	ClusterStatus(ctx context.Context, waitReady bool) (apiv1.ClusterStatus, error)
+	ClusterStatus(ctx context.Context, request apiv1.GetClusterStatusRequest, waitReady bool) (apiv1.ClusterStatus, error)
 	// LocalNodeStatus retrieves the current status of the local node.
 	LocalNodeStatus(ctx context.Context) (apiv1.NodeStatus, error)
 	// GetJoinToken generates a token for a new node to join the cluster.
@@ -30,10 +30,34 @@ type Client interface {
 	KubeConfig(ctx context.Context, request apiv1.GetKubeConfigRequest) (string, error)
 	// RemoveNode removes a node from the cluster.
 	RemoveNode(ctx context.Context, request apiv1.RemoveNodeRequest) error
+	// NodeMaintenance puts a node in maintenance or takes it out of maintenance.
+	NodeMaintenance(ctx context.Context, request apiv1.NodeMaintenanceRequest) error
 	// UpdateClusterConfig updates configuration of the cluster.
+	// UpdateClusterConfig returns ErrClusterConfigConflict if the request has an expected revision that is not the latest.
 	UpdateClusterConfig(ctx context.Context, request apiv1.UpdateClusterConfigRequest) error
-	// GetClusterConfig retrieves configuration of the cluster.
-	GetClusterConfig(ctx context.Context, request apiv1.GetClusterConfigRequest) (apiv1.UserFacingClusterConfig, error)
+	// GetClusterConfig retrieves configuration of the cluster and its latest revision.
+	GetClusterConfig(ctx context.Context, request apiv1.GetClusterConfigRequest) (apiv1.UserFacingClusterConfig, int, error)
+	// GetClusterConfigRevisions retrieves the revisions of the cluster configuration, oldest first.
+	GetClusterConfigRevisions(ctx context.Context, request apiv1.GetClusterConfigRevisionsRequest) ([]apiv1.ClusterConfigRevision, error)
+	// RollbackClusterConfig restores the cluster configuration of a previous revision.
+	RollbackClusterConfig(ctx context.Context, request apiv1.RollbackClusterConfigRequest) error
+	// GetEvents retrieves the recent events of the node.
+	GetEvents(ctx context.Context, request apiv1.GetEventsRequest) ([]apiv1.Event, error)
+	// WatchEvents streams the events of the node to handler until ctx is cancelled, the stream is closed or handler returns an error.
+	// The events of the node that were published after request.Since are sent first.
+	WatchEvents(ctx context.Context, request apiv1.GetEventsRequest, handler func(apiv1.Event) error) error
+	// GetClusterImages retrieves the list of images that are needed by the cluster.
+	GetClusterImages(ctx context.Context, request apiv1.GetClusterImagesRequest) ([]string, error)
+	// GetDatastoreMembers retrieves the members of the k8s-dqlite cluster.
+	GetDatastoreMembers(ctx context.Context, request apiv1.GetDatastoreMembersRequest) ([]apiv1.DatastoreMember, error)
+	// SetDatastoreRole assigns a role in the k8s-dqlite cluster to a control plane node.
+	SetDatastoreRole(ctx context.Context, request apiv1.SetDatastoreRoleRequest) error
+	// ResetDatastoreRole lets k8sd change the role of a control plane node in the k8s-dqlite cluster again.
+	ResetDatastoreRole(ctx context.Context, request apiv1.ResetDatastoreRoleRequest) error
+	// TransferDatastoreLeadership transfers the leadership of the k8s-dqlite cluster to a voter.
+	TransferDatastoreLeadership(ctx context.Context, request apiv1.TransferDatastoreLeadershipRequest) error
+	// GetDatastoreHealth checks whether the members of the k8s-dqlite cluster are reachable.
+	GetDatastoreHealth(ctx context.Context, request apiv1.GetDatastoreHealthRequest) ([]apiv1.DatastoreMemberHealth, error)
 }
 
 var _ Client = &k8sdClient{}
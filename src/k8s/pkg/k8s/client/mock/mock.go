@@ -20,12 +20,13 @@ type Client struct {
 		Ctx      context.Context
 		NodeName string
 	}
-	ClusterStatusReturn    apiv1.ClusterStatus
-	ClusterStatusErr       error
-	NodeStatusReturn       apiv1.NodeStatus
-	NodeStatusErr          error
-	GetJoinTokenCalledWith apiv1.GetJoinTokenRequest
-	GetJoinTokenReturn     struct {
+	ClusterStatusCalledWith apiv1.GetClusterStatusRequest
+	ClusterStatusReturn     apiv1.ClusterStatus
+	ClusterStatusErr        error
+	NodeStatusReturn        apiv1.NodeStatus
+	NodeStatusErr           error
+	GetJoinTokenCalledWith  apiv1.GetJoinTokenRequest
+	GetJoinTokenReturn      struct {
 		Token string
 		Err   error
 	}
@@ -42,13 +43,56 @@ type Client struct {
 	KubeConfigErr              error
 	RemoveNodeCalledWith       apiv1.RemoveNodeRequest
 	RemoveNodeErr              error
+	NodeMaintenanceCalledWith  apiv1.NodeMaintenanceRequest
+	NodeMaintenanceErr         error
 	GetClusterConfigCalledWith apiv1.GetClusterConfigRequest
 	GetClusterConfigReturn     struct {
-		Config apiv1.UserFacingClusterConfig
-		Err    error
+		Config   apiv1.UserFacingClusterConfig
+		Revision int
+		Err      error
 	}
 	UpdateClusterConfigCalledWith apiv1.UpdateClusterConfigRequest
 	UpdateClusterConfigErr        error
+	// UpdateClusterConfigConflicts is the number of UpdateClusterConfig calls with an expected revision
+	// that fail with client.ErrClusterConfigConflict before UpdateClusterConfigErr is returned.
+	UpdateClusterConfigConflicts    int
+	UpdateClusterConfigCalls        int
+	GetClusterConfigRevisionsReturn struct {
+		Revisions []apiv1.ClusterConfigRevision
+		Err       error
+	}
+	RollbackClusterConfigCalledWith apiv1.RollbackClusterConfigRequest
+	RollbackClusterConfigErr        error
+	GetEventsCalledWith             apiv1.GetEventsRequest
+	GetEventsReturn                 struct {
+		Events []apiv1.Event
+		Err    error
+	}
+	// WatchEventsReturn are sent to the handler of WatchEvents. WatchEvents then blocks until ctx is cancelled.
+	// If Err is set, WatchEvents returns Err instead of blocking.
+	WatchEventsCalledWith apiv1.GetEventsRequest
+	WatchEventsReturn     struct {
+		Events []apiv1.Event
+		Err    error
+	}
+	GetClusterImagesReturn struct {
+		Images []string
+		Err    error
+	}
+	GetDatastoreMembersReturn struct {
+		Members []apiv1.DatastoreMember
+		Err     error
+	}
+	SetDatastoreRoleCalledWith            apiv1.SetDatastoreRoleRequest
+	SetDatastoreRoleErr                   error
+	ResetDatastoreRoleCalledWith          *apiv1.ResetDatastoreRoleRequest
+	ResetDatastoreRoleErr                 error
+	TransferDatastoreLeadershipCalledWith *apiv1.TransferDatastoreLeadershipRequest
+	TransferDatastoreLeadershipErr        error
+	GetDatastoreHealthReturn              struct {
+		Members []apiv1.DatastoreMemberHealth
+		Err     error
+	}
 }
 
 func (c *Client) Bootstrap(ctx context.Context, request apiv1.PostClusterBootstrapRequest) (apiv1.NodeStatus, error) {
@@ -66,7 +110,8 @@ func (c *Client) CleanupNode(ctx context.Context, nodeName string) {
 	c.CleanupNodeCalledWith.NodeName = nodeName
 }
 
-func (c *Client) ClusterStatus(ctx context.Context, waitReady bool) (apiv1.ClusterStatus, error) {
+func (c *Client) ClusterStatus(ctx context.Context, request apiv1.GetClusterStatusRequest, waitReady bool) (apiv1.ClusterStatus, error) {
+	c.ClusterStatusCalledWith = request
 	return c.ClusterStatusReturn, c.ClusterStatusErr
 }
 
@@ -103,14 +148,79 @@ func (c *Client) RemoveNode(ctx context.Context, request apiv1.RemoveNodeRequest
 	return c.RemoveNodeErr
 }
 
+func (c *Client) NodeMaintenance(ctx context.Context, request apiv1.NodeMaintenanceRequest) error {
+	c.NodeMaintenanceCalledWith = request
+	return c.NodeMaintenanceErr
+}
+
 func (c *Client) UpdateClusterConfig(ctx context.Context, request apiv1.UpdateClusterConfigRequest) error {
 	c.UpdateClusterConfigCalledWith = request
+	c.UpdateClusterConfigCalls++
+	if request.ExpectedRevision != nil && c.UpdateClusterConfigConflicts > 0 {
+		c.UpdateClusterConfigConflicts--
+		return client.ErrClusterConfigConflict
+	}
 	return c.UpdateClusterConfigErr
 }
 
-func (c *Client) GetClusterConfig(ctx context.Context, request apiv1.GetClusterConfigRequest) (apiv1.UserFacingClusterConfig, error) {
+func (c *Client) GetClusterConfig(ctx context.Context, request apiv1.GetClusterConfigRequest) (apiv1.UserFacingClusterConfig, int, error) {
 	c.GetClusterConfigCalledWith = request
-	return c.GetClusterConfigReturn.Config, c.GetClusterConfigReturn.Err
+	return c.GetClusterConfigReturn.Config, c.GetClusterConfigReturn.Revision, c.GetClusterConfigReturn.Err
+}
+
+func (c *Client) GetClusterConfigRevisions(ctx context.Context, request apiv1.GetClusterConfigRevisionsRequest) ([]apiv1.ClusterConfigRevision, error) {
+	return c.GetClusterConfigRevisionsReturn.Revisions, c.GetClusterConfigRevisionsReturn.Err
+}
+
+func (c *Client) RollbackClusterConfig(ctx context.Context, request apiv1.RollbackClusterConfigRequest) error {
+	c.RollbackClusterConfigCalledWith = request
+	return c.RollbackClusterConfigErr
+}
+
+func (c *Client) GetEvents(ctx context.Context, request apiv1.GetEventsRequest) ([]apiv1.Event, error) {
+	c.GetEventsCalledWith = request
+	return c.GetEventsReturn.Events, c.GetEventsReturn.Err
+}
+
+func (c *Client) WatchEvents(ctx context.Context, request apiv1.GetEventsRequest, handler func(apiv1.Event) error) error {
+	c.WatchEventsCalledWith = request
+	for _, event := range c.WatchEventsReturn.Events {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	if c.WatchEventsReturn.Err != nil {
+		return c.WatchEventsReturn.Err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *Client) GetClusterImages(ctx context.Context, request apiv1.GetClusterImagesRequest) ([]string, error) {
+	return c.GetClusterImagesReturn.Images, c.GetClusterImagesReturn.Err
+}
+
+func (c *Client) GetDatastoreMembers(ctx context.Context, request apiv1.GetDatastoreMembersRequest) ([]apiv1.DatastoreMember, error) {
+	return c.GetDatastoreMembersReturn.Members, c.GetDatastoreMembersReturn.Err
+}
+
+func (c *Client) SetDatastoreRole(ctx context.Context, request apiv1.SetDatastoreRoleRequest) error {
+	c.SetDatastoreRoleCalledWith = request
+	return c.SetDatastoreRoleErr
+}
+
+func (c *Client) ResetDatastoreRole(ctx context.Context, request apiv1.ResetDatastoreRoleRequest) error {
+	c.ResetDatastoreRoleCalledWith = &request
+	return c.ResetDatastoreRoleErr
+}
+
+func (c *Client) TransferDatastoreLeadership(ctx context.Context, request apiv1.TransferDatastoreLeadershipRequest) error {
+	c.TransferDatastoreLeadershipCalledWith = &request
+	return c.TransferDatastoreLeadershipErr
+}
+
+func (c *Client) GetDatastoreHealth(ctx context.Context, request apiv1.GetDatastoreHealthRequest) ([]apiv1.DatastoreMemberHealth, error) {
+	return c.GetDatastoreHealthReturn.Members, c.GetDatastoreHealthReturn.Err
 }
 
 var _ client.Client = &Client{}
@@ -2,11 +2,14 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/api/impl"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/state"
 )
@@ -17,6 +20,12 @@ func (e *Endpoints) getClusterStatus(s *state.State, r *http.Request) response.R
 		return response.Unavailable(fmt.Errorf("daemon not yet initialized"))
 	}
 
+	// the request body is optional, older clients do not send one.
+	var req apiv1.GetClusterStatusRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
 	members, err := impl.GetClusterMembers(s.Context, s)
 	if err != nil {
 		return response.InternalError(fmt.Errorf("failed to get cluster members: %w", err))
@@ -36,15 +45,30 @@ func (e *Endpoints) getClusterStatus(s *state.State, r *http.Request) response.R
 		return response.InternalError(fmt.Errorf("failed to check if cluster has ready nodes: %w", err))
 	}
 
+	if req.Detailed {
+		members, err = impl.GetDetailedClusterMembers(s.Context, s, e.provider.Snap(), client, members)
+		if err != nil {
+			return response.InternalError(fmt.Errorf("failed to get detailed cluster members: %w", err))
+		}
+	}
+
+	datastore := apiv1.Datastore{
+		Type:    config.Datastore.GetType(),
+		Servers: config.Datastore.GetExternalServers(),
+	}
+	if datastore.Type == "k8s-dqlite" {
+		// the voter placement is informational, the status is still useful without it
+		if err := datastoreVoters(s.Context, s, e.provider.Snap(), client, config, &datastore); err != nil {
+			log.FromContext(s.Context).Warn("Failed to retrieve k8s-dqlite voter placement", "error", err)
+		}
+	}
+
 	result := apiv1.GetClusterStatusResponse{
 		ClusterStatus: apiv1.ClusterStatus{
-			Ready:   ready,
-			Members: members,
-			Config:  config.ToUserFacing(),
-			Datastore: apiv1.Datastore{
-				Type:    config.Datastore.GetType(),
-				Servers: config.Datastore.GetExternalServers(),
-			},
+			Ready:     ready,
+			Members:   members,
+			Config:    config.Redacted().ToUserFacing(),
+			Datastore: datastore,
 		},
 	}
 
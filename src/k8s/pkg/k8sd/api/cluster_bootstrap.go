@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/state"
@@ -16,6 +18,24 @@ func (e *Endpoints) postClusterBootstrap(s *state.State, r *http.Request) respon
 		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
 	}
 
+	// Validate the cluster configuration before touching the node
+	cfg, err := types.ClusterConfigFromBootstrapConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("invalid bootstrap config: %w", err))
+	}
+	cfg.SetDefaults()
+	hostNetworks, err := utils.GetHostNetworks()
+	if err != nil {
+		log.FromContext(r.Context()).Warn("Failed to list host networks, skipping host overlap checks", "error", err)
+	}
+	validation := cfg.ValidateAll(types.ValidateOptions{HostNetworks: hostNetworks})
+	if err := validation.Err(); err != nil {
+		return response.BadRequest(fmt.Errorf("invalid cluster configuration: %w", err))
+	}
+	for _, warning := range validation.Warnings {
+		log.FromContext(r.Context()).Warn("Cluster configuration warning", "warning", warning)
+	}
+
 	//Convert Bootstrap config to map
 	config, err := req.Config.ToMicrocluster()
 	if err != nil {
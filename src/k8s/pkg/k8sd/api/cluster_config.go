@@ -3,18 +3,26 @@ package api
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	api "github.com/canonical/k8s/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/database"
-	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/state"
 )
 
+// errStaleClusterConfigRevision is returned when an update expects a revision of the cluster configuration that is no longer the latest.
+var errStaleClusterConfigRevision = errors.New("cluster configuration revision is stale")
+
+// errInvalidClusterConfig is returned when an update cannot be applied to the current cluster configuration.
+var errInvalidClusterConfig = errors.New("invalid cluster configuration")
+
 func (e *Endpoints) putClusterConfig(s *state.State, r *http.Request) response.Response {
 	var req api.UpdateClusterConfigRequest
 
@@ -22,6 +30,12 @@ func (e *Endpoints) putClusterConfig(s *state.State, r *http.Request) response.R
 		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
 	}
 
+	return e.updateClusterConfig(s, r, req, requestIdentity(s, r))
+}
+
+// updateClusterConfig merges the requested changes into the cluster configuration and records a new revision.
+// identity describes who requested the change, it is stored with the revision.
+func (e *Endpoints) updateClusterConfig(s *state.State, r *http.Request, req api.UpdateClusterConfigRequest, identity string) response.Response {
 	requestedConfig, err := types.ClusterConfigFromUserFacing(req.Config)
 	if err != nil {
 		return response.BadRequest(fmt.Errorf("invalid configuration: %w", err))
@@ -29,38 +43,95 @@ func (e *Endpoints) putClusterConfig(s *state.State, r *http.Request) response.R
 	if requestedConfig.Datastore, err = types.DatastoreConfigFromUserFacing(req.Datastore); err != nil {
 		return response.BadRequest(fmt.Errorf("failed to parse datastore config: %w", err))
 	}
+	unsetConfig, err := types.ClusterConfigUnsetMask(req.Unset)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("invalid options to unset: %w", err))
+	}
 
+	var mergedConfig types.ClusterConfig
+	var revision int
 	if err := s.Database.Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
-		if _, err := database.SetClusterConfig(ctx, tx, requestedConfig); err != nil {
+		if req.ExpectedRevision != nil {
+			latestRevision, err := database.GetLatestClusterConfigRevision(ctx, tx)
+			if err != nil {
+				return fmt.Errorf("failed to get latest cluster configuration revision: %w", err)
+			}
+			if latestRevision != *req.ExpectedRevision {
+				return fmt.Errorf("expected revision %d but latest revision is %d: %w", *req.ExpectedRevision, latestRevision, errStaleClusterConfigRevision)
+			}
+		}
+		previousConfig, err := database.GetClusterConfig(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to get current cluster configuration: %w", err)
+		}
+		// clients that read the redacted configuration send the redacted credentials back
+		if requestedConfig.Containerd, err = requestedConfig.Containerd.RestoreRedacted(previousConfig.Containerd); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidClusterConfig, err)
+		}
+		if mergedConfig, err = database.UpdateClusterConfig(ctx, tx, requestedConfig, unsetConfig); err != nil {
 			return fmt.Errorf("failed to update cluster configuration: %w", err)
 		}
+		if revision, err = database.AddClusterConfigRevision(ctx, tx, identity, previousConfig, mergedConfig); err != nil {
+			return fmt.Errorf("failed to record cluster configuration revision: %w", err)
+		}
 		return nil
 	}); err != nil {
+		if errors.Is(err, errStaleClusterConfigRevision) {
+			return response.Conflict(fmt.Errorf("cluster configuration was changed concurrently: %w", err))
+		}
+		if errors.Is(err, errInvalidClusterConfig) {
+			return response.BadRequest(err)
+		}
 		return response.InternalError(fmt.Errorf("database transaction to update cluster configuration failed: %w", err))
 	}
 
+	e.provider.Events().Publish(api.EventConfigUpdated, fmt.Sprintf("Cluster configuration updated to revision %d by %s", revision, identity), map[string]string{
+		"revision": strconv.Itoa(revision), "identity": identity,
+	})
+
+	// errors are rejected by SetClusterConfig, only the warnings are left to report
+	for _, warning := range mergedConfig.ValidateAll(types.ValidateOptions{}).Warnings {
+		log.FromContext(r.Context()).Warn("Cluster configuration warning", "warning", warning)
+	}
+
+	// features that deploy images must be re-applied if the image registry changes
+	imageRegistryChanged := requestedConfig.Containerd.ImageRegistry != nil || unsetConfig.Containerd.ImageRegistry != nil
+	changed := func(requested, unset interface{ Empty() bool }) bool { return !requested.Empty() || !unset.Empty() }
+
 	e.provider.NotifyUpdateNodeConfigController()
 	e.provider.NotifyFeatureController(
-		!requestedConfig.Network.Empty(),
-		!requestedConfig.Gateway.Empty(),
-		!requestedConfig.Ingress.Empty(),
-		!requestedConfig.LoadBalancer.Empty(),
-		!requestedConfig.LocalStorage.Empty(),
-		!requestedConfig.MetricsServer.Empty(),
-		!requestedConfig.DNS.Empty() || !requestedConfig.Kubelet.Empty(),
+		changed(requestedConfig.Network, unsetConfig.Network) || imageRegistryChanged,
+		changed(requestedConfig.Gateway, unsetConfig.Gateway),
+		changed(requestedConfig.Ingress, unsetConfig.Ingress),
+		changed(requestedConfig.LoadBalancer, unsetConfig.LoadBalancer),
+		changed(requestedConfig.LocalStorage, unsetConfig.LocalStorage) || imageRegistryChanged,
+		changed(requestedConfig.MetricsServer, unsetConfig.MetricsServer) || imageRegistryChanged,
+		changed(requestedConfig.DNS, unsetConfig.DNS) || changed(requestedConfig.Kubelet, unsetConfig.Kubelet) || imageRegistryChanged,
+		changed(requestedConfig.NodeLocalDNS, unsetConfig.NodeLocalDNS) || changed(requestedConfig.Kubelet, unsetConfig.Kubelet) || imageRegistryChanged,
 	)
 
-	return response.SyncResponse(true, &api.UpdateClusterConfigResponse{})
+	return response.SyncResponse(true, &api.UpdateClusterConfigResponse{Revision: revision})
 }
 
 func (e *Endpoints) getClusterConfig(s *state.State, r *http.Request) response.Response {
-	config, err := databaseutil.GetClusterConfig(r.Context(), s)
-	if err != nil {
+	var config types.ClusterConfig
+	var revision int
+	if err := s.Database.Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if config, err = database.GetClusterConfig(ctx, tx); err != nil {
+			return fmt.Errorf("failed to get cluster configuration: %w", err)
+		}
+		if revision, err = database.GetLatestClusterConfigRevision(ctx, tx); err != nil {
+			return fmt.Errorf("failed to get latest cluster configuration revision: %w", err)
+		}
+		return nil
+	}); err != nil {
 		return response.InternalError(fmt.Errorf("failed to retrieve cluster configuration: %w", err))
 	}
 
 	result := api.GetClusterConfigResponse{
-		Config: config.ToUserFacing(),
+		Config:   config.Redacted().ToUserFacing(),
+		Revision: revision,
 	}
 	return response.SyncResponse(true, &result)
 }
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/state"
+)
+
+func (e *Endpoints) getClusterConfigRevisions(s *state.State, r *http.Request) response.Response {
+	var revisions []apiv1.ClusterConfigRevision
+	if err := s.Database.Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if revisions, err = database.ListClusterConfigRevisions(ctx, tx); err != nil {
+			return fmt.Errorf("failed to list cluster configuration revisions: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction failed: %w", err))
+	}
+
+	return response.SyncResponse(true, &apiv1.GetClusterConfigRevisionsResponse{Revisions: revisions})
+}
+
+func (e *Endpoints) postClusterConfigRollback(s *state.State, r *http.Request) response.Response {
+	var req apiv1.RollbackClusterConfigRequest
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to decode request: %w", err))
+	}
+
+	var revision apiv1.ClusterConfigRevision
+	var current types.ClusterConfig
+	var latestRevision int
+	if err := s.Database.Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if revision, err = database.GetClusterConfigRevision(ctx, tx, req.Revision); err != nil {
+			return err
+		}
+		if current, err = database.GetClusterConfig(ctx, tx); err != nil {
+			return fmt.Errorf("failed to get current cluster configuration: %w", err)
+		}
+		if latestRevision, err = database.GetLatestClusterConfigRevision(ctx, tx); err != nil {
+			return fmt.Errorf("failed to get latest cluster configuration revision: %w", err)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return response.NotFound(fmt.Errorf("cluster configuration revision %d does not exist", req.Revision))
+		}
+		return response.InternalError(fmt.Errorf("failed to retrieve cluster configuration revision %d: %w", req.Revision, err))
+	}
+
+	// the revision is applied like any other update, so changes that are not allowed are rejected by the merge.
+	// options that were set since the revision are unset, and the update fails if the configuration changes meanwhile.
+	identity := fmt.Sprintf("%s (rollback to revision %d)", requestIdentity(s, r), req.Revision)
+	return e.updateClusterConfig(s, r, apiv1.UpdateClusterConfigRequest{
+		Config:           revision.Config,
+		Unset:            types.ClusterConfigUnsetKeys(current.ToUserFacing(), revision.Config),
+		ExpectedRevision: &latestRevision,
+	}, identity)
+}
+
+// requestIdentity describes the origin of a request.
+// Requests over the local unix socket are attributed to the local node, other requests to their client certificate.
+func requestIdentity(s *state.State, r *http.Request) string {
+	if r.RemoteAddr == "@" {
+		return fmt.Sprintf("%s (local)", s.Name())
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return fmt.Sprintf("%s (%s)", r.TLS.PeerCertificates[0].Subject.CommonName, r.RemoteAddr)
+	}
+	return r.RemoteAddr
+}
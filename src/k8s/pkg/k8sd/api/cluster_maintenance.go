@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+	nodeutil "github.com/canonical/k8s/pkg/utils/node"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/state"
+)
+
+func (e *Endpoints) postClusterMaintenance(s *state.State, r *http.Request) response.Response {
+	snap := e.provider.Snap()
+
+	req := apiv1.NodeMaintenanceRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
+	controlPlaneNode, err := nodeutil.GetControlPlaneNode(r.Context(), s, req.Name)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to check if node is control-plane: %w", err))
+	}
+	isWorker, err := databaseutil.IsWorkerNode(r.Context(), s, req.Name)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to check if node is worker: %w", err))
+	}
+	if !isWorker && controlPlaneNode == nil {
+		return NodeUnavailable(fmt.Errorf("node %q is not part of the cluster", req.Name))
+	}
+
+	// the k8s-dqlite address of control plane nodes, empty if the node is not a member of the k8s-dqlite cluster
+	var datastoreAddress string
+	if controlPlaneNode != nil {
+		cfg, err := databaseutil.GetClusterConfig(r.Context(), s)
+		if err != nil {
+			return response.InternalError(fmt.Errorf("failed to retrieve cluster configuration: %w", err))
+		}
+		if cfg.Datastore.GetType() == "k8s-dqlite" {
+			if datastoreAddress, err = k8sDqliteAddress(controlPlaneNode.Address, cfg.Datastore.GetK8sDqlitePort()); err != nil {
+				return response.InternalError(err)
+			}
+		}
+	}
+
+	// datastoreVoter is true if the node was a k8s-dqlite voter when it entered maintenance
+	var inMaintenance, datastoreVoter bool
+	if err := s.Database.Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		inMaintenance, datastoreVoter, err = database.GetNodeMaintenance(ctx, tx, req.Name)
+		return err
+	}); err != nil {
+		return response.InternalError(fmt.Errorf("database transaction to get node maintenance failed: %w", err))
+	}
+
+	if req.Maintenance {
+		if err := enterNodeMaintenance(r.Context(), s, snap, req, datastoreAddress, datastoreVoter, inMaintenance); err != nil {
+			if inMaintenance {
+				return response.InternalError(fmt.Errorf("failed to put node %q in maintenance again, it stays in maintenance: %w", req.Name, err))
+			}
+			return response.InternalError(fmt.Errorf("failed to put node %q in maintenance: %w", req.Name, err))
+		}
+		e.provider.Events().Publish(apiv1.EventNodeMaintenance, fmt.Sprintf("Node %s entered maintenance", req.Name), map[string]string{
+			"node": req.Name, "maintenance": "true",
+		})
+	} else {
+		if err := exitNodeMaintenance(r.Context(), s, snap, req.Name, datastoreAddress, datastoreVoter); err != nil {
+			return response.InternalError(fmt.Errorf("failed to take node %q out of maintenance: %w", req.Name, err))
+		}
+		e.provider.Events().Publish(apiv1.EventNodeMaintenance, fmt.Sprintf("Node %s exited maintenance", req.Name), map[string]string{
+			"node": req.Name, "maintenance": "false",
+		})
+	}
+
+	return response.SyncResponse(true, nil)
+}
+
+// enterNodeMaintenance marks the node as in maintenance, drains it and demotes it from k8s-dqlite voter.
+// The node stays a voter if no other node can replace it.
+// datastoreVoter is true if the node was a voter when it entered maintenance before, in case it is put in maintenance again.
+// If entering maintenance fails, a node that was not in maintenance before is taken out of maintenance again.
+func enterNodeMaintenance(ctx context.Context, s *state.State, snap snap.Snap, req apiv1.NodeMaintenanceRequest, datastoreAddress string, datastoreVoter bool, inMaintenance bool) (rerr error) {
+	// the voter controller demotes nodes once they are marked, so whether the node is a voter is recorded when it is marked
+	if datastoreAddress != "" && !datastoreVoter {
+		client, err := snap.K8sDqliteClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+		}
+		members, err := client.ListMembers(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list k8s-dqlite members: %w", err)
+		}
+		for _, member := range members {
+			if member.Address == datastoreAddress && member.Role == dqlite.Voter {
+				datastoreVoter = true
+			}
+		}
+	}
+
+	// mark the node first, so that its services are not restarted while it is drained
+	if err := setNodeMaintenance(ctx, s, req.Name, datastoreVoter); err != nil {
+		return err
+	}
+	if !inMaintenance {
+		defer func() {
+			if rerr == nil {
+				return
+			}
+			if err := exitNodeMaintenance(ctx, s, snap, req.Name, datastoreAddress, datastoreVoter); err != nil {
+				rerr = fmt.Errorf("%w; failed to revert the changes, run \"k8s node maintenance exit %s\" to take the node out of maintenance: %v", rerr, req.Name, err)
+			}
+		}()
+	}
+
+	c, err := snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create k8s client: %w", err)
+	}
+	if err := c.SetNodeMaintenance(ctx, req.Name, true); err != nil {
+		return fmt.Errorf("failed to annotate k8s node: %w", err)
+	}
+
+	if err := drainNode(ctx, snap, req.Name, req.DrainTimeout, req.GracePeriod, req.IgnoreDaemonSets); err != nil {
+		return fmt.Errorf("failed to drain node: %w", err)
+	}
+
+	if datastoreAddress != "" {
+		client, err := snap.K8sDqliteClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+		}
+		// the node is not a voter anymore if the voter controller demoted it while it was drained
+		if _, err := client.DemoteVoter(ctx, datastoreAddress); errors.Is(err, dqlite.ErrNoReplacement) {
+			log.FromContext(ctx).Info("Keeping node in maintenance as k8s-dqlite voter, as no other node can replace it", "node", req.Name)
+		} else if err != nil {
+			return fmt.Errorf("failed to demote k8s-dqlite node %s: %w", datastoreAddress, err)
+		}
+	}
+
+	return nil
+}
+
+// exitNodeMaintenance promotes the node to k8s-dqlite voter if it was demoted, uncordons it and marks it as no longer in maintenance.
+func exitNodeMaintenance(ctx context.Context, s *state.State, snap snap.Snap, name string, datastoreAddress string, datastoreVoter bool) error {
+	if datastoreVoter && datastoreAddress != "" {
+		client, err := snap.K8sDqliteClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+		}
+		if err := client.AssignRole(ctx, datastoreAddress, dqlite.Voter); err != nil {
+			return fmt.Errorf("failed to promote k8s-dqlite node %s: %w", datastoreAddress, err)
+		}
+	}
+
+	c, err := snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create k8s client: %w", err)
+	}
+	if err := c.UncordonNode(ctx, name); err != nil {
+		return fmt.Errorf("failed to uncordon k8s node: %w", err)
+	}
+	if err := c.SetNodeMaintenance(ctx, name, false); err != nil {
+		return fmt.Errorf("failed to annotate k8s node: %w", err)
+	}
+
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return database.DeleteNodeMaintenance(ctx, tx, name)
+	}); err != nil {
+		return fmt.Errorf("database transaction to delete node maintenance failed: %w", err)
+	}
+	return nil
+}
+
+func setNodeMaintenance(ctx context.Context, s *state.State, name string, datastoreVoter bool) error {
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return database.SetNodeMaintenance(ctx, tx, name, datastoreVoter)
+	}); err != nil {
+		return fmt.Errorf("database transaction to set node maintenance failed: %w", err)
+	}
+	return nil
+}
@@ -1,11 +1,16 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/utils"
 	nodeutil "github.com/canonical/k8s/pkg/utils/node"
 	"github.com/canonical/lxd/lxd/response"
@@ -24,6 +29,24 @@ func (e *Endpoints) postClusterRemove(s *state.State, r *http.Request) response.
 	if err != nil {
 		return response.InternalError(fmt.Errorf("failed to check if node is control-plane: %w", err))
 	}
+	isWorker, err := databaseutil.IsWorkerNode(r.Context(), s, req.Name)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to check if node is worker: %w", err))
+	}
+	if !isWorker && !isControlPlane {
+		return NodeUnavailable(fmt.Errorf("node %q is not part of the cluster", req.Name))
+	}
+
+	// Evict the pods before the node is removed from the cluster and the datastore.
+	if req.Drain {
+		if err := drainNode(r.Context(), snap, req.Name, req.DrainTimeout, req.GracePeriod, req.IgnoreDaemonSets); err != nil {
+			if !req.Force {
+				return response.InternalError(fmt.Errorf("failed to drain node %q: %w", req.Name, err))
+			}
+			log.FromContext(r.Context()).Warn("Failed to drain node, removing it anyway", "node", req.Name, "error", err)
+		}
+	}
+
 	if isControlPlane {
 		// Remove control plane via microcluster API.
 		// The postRemove hook will take care of cleaning up kubernetes.
@@ -34,12 +57,17 @@ func (e *Endpoints) postClusterRemove(s *state.State, r *http.Request) response.
 		if err := c.DeleteClusterMember(r.Context(), req.Name, req.Force); err != nil {
 			return response.InternalError(fmt.Errorf("failed to delete cluster member %s: %w", req.Name, err))
 		}
-	}
 
-	isWorker, err := databaseutil.IsWorkerNode(r.Context(), s, req.Name)
-	if err != nil {
-		return response.InternalError(fmt.Errorf("failed to check if node is worker: %w", err))
+		// The preRemove hook does not run if the node is unreachable.
+		// The node is no longer a cluster member, so failing to clean up is not an error.
+		if err := databaseutil.DeleteNodeMaintenanceEntry(r.Context(), s, req.Name); err != nil {
+			log.FromContext(r.Context()).Warn("Failed to remove node maintenance entry", "node", req.Name, "error", err)
+		}
+		if err := databaseutil.SetDatastoreRolePinned(r.Context(), s, req.Name, false); err != nil {
+			log.FromContext(r.Context()).Warn("Failed to unpin datastore role", "node", req.Name, "error", err)
+		}
 	}
+
 	if isWorker {
 		// For worker nodes, we need to manually clean up the kubernetes node and db entry.
 		c, err := snap.KubernetesClient("")
@@ -54,10 +82,35 @@ func (e *Endpoints) postClusterRemove(s *state.State, r *http.Request) response.
 		if err := databaseutil.DeleteWorkerNodeEntry(r.Context(), s, req.Name); err != nil {
 			return response.InternalError(fmt.Errorf("failed to remove worker entry %q: %w", req.Name, err))
 		}
-	}
 
-	if !isWorker && !isControlPlane {
-		return NodeUnavailable(fmt.Errorf("node %q is not part of the cluster", req.Name))
+		if err := databaseutil.DeleteNodeMaintenanceEntry(r.Context(), s, req.Name); err != nil {
+			return response.InternalError(fmt.Errorf("failed to remove node maintenance entry %q: %w", req.Name, err))
+		}
+
+		e.provider.Events().Publish(apiv1.EventNodeRemoved, fmt.Sprintf("Worker node %s was removed from the cluster", req.Name), map[string]string{
+			"node": req.Name, "role": string(apiv1.ClusterRoleWorker),
+		})
 	}
+
 	return response.SyncResponse(true, nil)
 }
+
+// drainNode cordons the node and evicts its pods.
+func drainNode(ctx context.Context, snap snap.Snap, name string, timeout time.Duration, gracePeriod *int, ignoreDaemonSets bool) error {
+	c, err := snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create k8s client: %w", err)
+	}
+
+	opts := kubernetes.DrainOptions{
+		IgnoreDaemonSets: ignoreDaemonSets,
+		Timeout:          timeout,
+	}
+	if gracePeriod != nil {
+		opts.GracePeriodSeconds = utils.Pointer(int64(*gracePeriod))
+	}
+	if err := c.DrainNode(ctx, name, opts); err != nil {
+		return fmt.Errorf("failed to drain k8s node: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	apiv1 "github.com/canonical/k8s/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/database"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
@@ -26,11 +27,21 @@ func (e *Endpoints) postClusterJoinTokens(s *state.State, r *http.Request) respo
 		return response.BadRequest(fmt.Errorf("invalid hostname %q: %w", req.Name, err))
 	}
 
+	config, err := databaseutil.GetClusterConfig(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to get cluster config: %w", err))
+	}
+	// the joining node checks that the ports are free before joining
+	ports := types.JoinTokenPorts{APIServer: config.APIServer.GetSecurePort()}
+	if config.Datastore.GetType() == "k8s-dqlite" {
+		ports.K8sDqlite = config.Datastore.GetK8sDqlitePort()
+	}
+
 	var token string
 	if req.Worker {
-		token, err = getOrCreateWorkerToken(r.Context(), s, hostname)
+		token, err = getOrCreateWorkerToken(r.Context(), s, hostname, ports)
 	} else {
-		token, err = getOrCreateJoinToken(r.Context(), e.provider.MicroCluster(), hostname)
+		token, err = getOrCreateJoinToken(r.Context(), e.provider.MicroCluster(), hostname, ports)
 	}
 	if err != nil {
 		return response.InternalError(fmt.Errorf("failed to create token: %w", err))
@@ -39,7 +50,7 @@ func (e *Endpoints) postClusterJoinTokens(s *state.State, r *http.Request) respo
 	return response.SyncResponse(true, &apiv1.GetJoinTokenResponse{EncodedToken: token})
 }
 
-func getOrCreateJoinToken(ctx context.Context, m *microcluster.MicroCluster, tokenName string) (string, error) {
+func getOrCreateJoinToken(ctx context.Context, m *microcluster.MicroCluster, tokenName string, ports types.JoinTokenPorts) (string, error) {
 	// grab token if it exists and return it
 	records, err := m.ListJoinTokens(ctx)
 	if err != nil {
@@ -47,7 +58,7 @@ func getOrCreateJoinToken(ctx context.Context, m *microcluster.MicroCluster, tok
 	} else {
 		for _, record := range records {
 			if record.Name == tokenName {
-				return record.Token, nil
+				return types.WithControlPlaneTokenPorts(record.Token, ports)
 			}
 		}
 		fmt.Println("No token exists yet. Creating a new token.")
@@ -58,10 +69,10 @@ func getOrCreateJoinToken(ctx context.Context, m *microcluster.MicroCluster, tok
 	if err != nil {
 		return "", fmt.Errorf("failed to generate a new microcluster join token: %w", err)
 	}
-	return token, nil
+	return types.WithControlPlaneTokenPorts(token, ports)
 }
 
-func getOrCreateWorkerToken(ctx context.Context, s *state.State, nodeName string) (string, error) {
+func getOrCreateWorkerToken(ctx context.Context, s *state.State, nodeName string, ports types.JoinTokenPorts) (string, error) {
 	var token string
 	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 		var err error
@@ -89,6 +100,7 @@ func getOrCreateWorkerToken(ctx context.Context, s *state.State, nodeName string
 		Secret:        token,
 		JoinAddresses: addresses,
 		Fingerprint:   utils.CertFingerprint(cert),
+		Ports:         &types.JoinTokenPorts{APIServer: ports.APIServer},
 	}
 
 	token, err = info.Encode()
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+	nodeutil "github.com/canonical/k8s/pkg/utils/node"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/state"
+)
+
+func (e *Endpoints) getDatastoreMembers(s *state.State, r *http.Request) response.Response {
+	client, names, resp := k8sDqliteClient(r.Context(), s, e.provider.Snap())
+	if resp != nil {
+		return resp
+	}
+
+	members, err := client.ListMembers(r.Context())
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to list k8s-dqlite members: %w", err))
+	}
+	leader, err := client.Leader(r.Context())
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to retrieve k8s-dqlite leader: %w", err))
+	}
+	pinned, err := databaseutil.ListPinnedDatastoreRoles(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to list pinned datastore roles: %w", err))
+	}
+
+	result := make([]apiv1.DatastoreMember, 0, len(members))
+	for _, member := range members {
+		result = append(result, datastoreMember(member, names, leader, pinned))
+	}
+	return response.SyncResponse(true, &apiv1.GetDatastoreMembersResponse{Members: result})
+}
+
+func (e *Endpoints) postDatastoreRole(s *state.State, r *http.Request) response.Response {
+	req := apiv1.SetDatastoreRoleRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
+	var role dqlite.NodeRole
+	switch req.Role {
+	case apiv1.DatastoreRoleVoter:
+		role = dqlite.Voter
+	case apiv1.DatastoreRoleStandBy:
+		role = dqlite.StandBy
+	case apiv1.DatastoreRoleSpare:
+		role = dqlite.Spare
+	default:
+		return response.BadRequest(fmt.Errorf("invalid role %q, must be one of %s, %s or %s", req.Role, apiv1.DatastoreRoleVoter, apiv1.DatastoreRoleStandBy, apiv1.DatastoreRoleSpare))
+	}
+
+	client, names, resp := k8sDqliteClient(r.Context(), s, e.provider.Snap())
+	if resp != nil {
+		return resp
+	}
+	address, ok := datastoreAddressOf(names, req.Name)
+	if !ok {
+		return NodeUnavailable(fmt.Errorf("node %q is not a control plane node", req.Name))
+	}
+
+	pinned, err := databaseutil.ListPinnedDatastoreRoles(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to list pinned datastore roles: %w", err))
+	}
+	// pin the role first, so that the voter controller does not revert it
+	if err := databaseutil.SetDatastoreRolePinned(r.Context(), s, req.Name, true); err != nil {
+		return response.InternalError(fmt.Errorf("failed to pin the datastore role of node %q: %w", req.Name, err))
+	}
+	if err := client.AssignRole(r.Context(), address, role); err != nil {
+		// the voter controller manages the role again if it was not pinned before
+		if !slices.Contains(pinned, req.Name) {
+			if unpinErr := databaseutil.SetDatastoreRolePinned(r.Context(), s, req.Name, false); unpinErr != nil {
+				log.FromContext(r.Context()).Error("Failed to unpin the datastore role after the role could not be assigned", "node", req.Name, "error", unpinErr)
+			}
+		}
+		return response.InternalError(fmt.Errorf("failed to assign %s role to node %q: %w", req.Role, req.Name, err))
+	}
+	return response.SyncResponse(true, nil)
+}
+
+func (e *Endpoints) deleteDatastoreRole(s *state.State, r *http.Request) response.Response {
+	req := apiv1.ResetDatastoreRoleRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
+	_, names, resp := k8sDqliteClient(r.Context(), s, e.provider.Snap())
+	if resp != nil {
+		return resp
+	}
+	if _, ok := datastoreAddressOf(names, req.Name); !ok {
+		return NodeUnavailable(fmt.Errorf("node %q is not a control plane node", req.Name))
+	}
+
+	if err := databaseutil.SetDatastoreRolePinned(r.Context(), s, req.Name, false); err != nil {
+		return response.InternalError(fmt.Errorf("failed to unpin the datastore role of node %q: %w", req.Name, err))
+	}
+	return response.SyncResponse(true, nil)
+}
+
+func (e *Endpoints) postDatastoreTransferLeadership(s *state.State, r *http.Request) response.Response {
+	req := apiv1.TransferDatastoreLeadershipRequest{}
+	if err := utils.NewStrictJSONDecoder(r.Body).Decode(&req); err != nil {
+		return response.BadRequest(fmt.Errorf("failed to parse request: %w", err))
+	}
+
+	client, names, resp := k8sDqliteClient(r.Context(), s, e.provider.Snap())
+	if resp != nil {
+		return resp
+	}
+
+	var address string
+	if req.Name != "" {
+		var ok bool
+		if address, ok = datastoreAddressOf(names, req.Name); !ok {
+			return NodeUnavailable(fmt.Errorf("node %q is not a control plane node", req.Name))
+		}
+	}
+
+	if err := client.TransferLeadership(r.Context(), address); err != nil {
+		return response.InternalError(fmt.Errorf("failed to transfer k8s-dqlite leadership: %w", err))
+	}
+	return response.SyncResponse(true, nil)
+}
+
+func (e *Endpoints) getDatastoreHealth(s *state.State, r *http.Request) response.Response {
+	client, names, resp := k8sDqliteClient(r.Context(), s, e.provider.Snap())
+	if resp != nil {
+		return resp
+	}
+
+	members, err := client.CheckHealth(r.Context(), dqlite.HealthTimeout)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to check k8s-dqlite health: %w", err))
+	}
+	// the health is still reported if the cluster has no leader, e.g. because it lost quorum
+	leader, err := client.Leader(r.Context())
+	if err != nil {
+		log.FromContext(r.Context()).Warn("Failed to retrieve k8s-dqlite leader", "error", err)
+		leader = nil
+	}
+	pinned, err := databaseutil.ListPinnedDatastoreRoles(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to list pinned datastore roles: %w", err))
+	}
+
+	logIndexes := datastoreLogIndexes(r.Context(), s, e.provider.Snap(), names)
+	var leaderLogIndex uint64
+	if leader != nil {
+		leaderLogIndex = logIndexes[leader.Address]
+	}
+
+	result := make([]apiv1.DatastoreMemberHealth, 0, len(members))
+	for _, member := range members {
+		health := apiv1.DatastoreMemberHealth{
+			DatastoreMember: datastoreMember(member.NodeInfo, names, leader, pinned),
+			Reachable:       member.Reachable,
+			Latency:         member.Latency,
+			LeaderAddress:   member.LeaderAddress,
+			LogIndex:        logIndexes[member.Address],
+			Error:           member.Error,
+		}
+		if health.LogIndex != 0 && leaderLogIndex != 0 {
+			// the indexes are not read at the same time, so a member may appear ahead of the leader
+			health.LogIndexLag = utils.Pointer(leaderLogIndex - min(health.LogIndex, leaderLogIndex))
+		}
+		result = append(result, health)
+	}
+	return response.SyncResponse(true, &apiv1.GetDatastoreHealthResponse{Members: result})
+}
+
+func (e *Endpoints) getDatastoreLogIndex(s *state.State, r *http.Request) response.Response {
+	index, err := dqlite.LastLogIndex(e.provider.Snap().K8sDqliteStateDir())
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to read the k8s-dqlite log index: %w", err))
+	}
+	return response.SyncResponse(true, &apiv1.GetDatastoreLogIndexResponse{LogIndex: index})
+}
+
+// datastoreLogIndexes returns the last raft log index of the k8s-dqlite members by address.
+// The raft log is not exposed by k8s-dqlite, so the log index of each member is read by k8sd on its control plane node.
+// Failures to reach other control plane nodes are logged and leave their log index unknown.
+func datastoreLogIndexes(ctx context.Context, s *state.State, snap snap.Snap, names map[string]string) map[string]uint64 {
+	logIndexes := make(map[string]uint64, len(names))
+	if address, ok := datastoreAddressOf(names, s.Name()); ok {
+		if index, err := dqlite.LastLogIndex(snap.K8sDqliteStateDir()); err != nil {
+			log.FromContext(ctx).Warn("Failed to read the k8s-dqlite log index", "error", err)
+		} else {
+			logIndexes[address] = index
+		}
+	}
+	if len(names) <= 1 {
+		return logIndexes
+	}
+
+	nodeNames := make(map[string]string, len(names))
+	for name, addrPort := range s.Remotes().Addresses() {
+		nodeNames[addrPort.String()] = name
+	}
+	clients, err := s.Cluster(nil)
+	if err != nil {
+		log.FromContext(ctx).Warn("Failed to get clients for cluster members, the k8s-dqlite log index of remote nodes is unknown", "error", err)
+	}
+	for _, c := range clients {
+		address, ok := datastoreAddressOf(names, nodeNames[c.URL().URL.Host])
+		if !ok {
+			continue
+		}
+		queryCtx, cancel := context.WithTimeout(ctx, dqlite.HealthTimeout)
+		var response apiv1.GetDatastoreLogIndexResponse
+		err := c.Query(queryCtx, "GET", api.NewURL().Path("k8sd", "datastore", "log-index"), nil, &response)
+		cancel()
+		if err != nil {
+			log.FromContext(ctx).Warn("Failed to get k8s-dqlite log index", "node", c.URL().URL.Host, "error", err)
+			continue
+		}
+		logIndexes[address] = response.LogIndex
+	}
+	return logIndexes
+}
+
+// k8sDqliteClient returns a client for the k8s-dqlite cluster, and the names of the control plane nodes by their k8s-dqlite address.
+// k8sDqliteClient returns a response instead if the cluster does not use k8s-dqlite or the client cannot be created.
+func k8sDqliteClient(ctx context.Context, s *state.State, snap snap.Snap) (*dqlite.Client, map[string]string, response.Response) {
+	cfg, err := databaseutil.GetClusterConfig(ctx, s)
+	if err != nil {
+		return nil, nil, response.InternalError(fmt.Errorf("failed to retrieve cluster configuration: %w", err))
+	}
+	if datastoreType := cfg.Datastore.GetType(); datastoreType != "k8s-dqlite" {
+		return nil, nil, response.BadRequest(fmt.Errorf("the cluster uses the %q datastore, not k8s-dqlite", datastoreType))
+	}
+
+	client, names, err := newK8sDqliteClient(ctx, s, snap, cfg.Datastore.GetK8sDqlitePort())
+	if err != nil {
+		return nil, nil, response.InternalError(err)
+	}
+	return client, names, nil
+}
+
+// newK8sDqliteClient returns a client for the k8s-dqlite cluster, and the names of the control plane nodes by their k8s-dqlite address.
+// The names are retrieved from the local truststore, so they do not require a microcluster leader.
+func newK8sDqliteClient(ctx context.Context, s *state.State, snap snap.Snap, port int) (*dqlite.Client, map[string]string, error) {
+	remotes := s.Remotes().Addresses()
+	names := make(map[string]string, len(remotes))
+	for name, addrPort := range remotes {
+		address, err := k8sDqliteAddress(addrPort.String(), port)
+		if err != nil {
+			return nil, nil, err
+		}
+		names[address] = name
+	}
+
+	client, err := snap.K8sDqliteClient(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+	}
+	return client, names, nil
+}
+
+// datastoreVoters fills in the voter placement of a k8s-dqlite datastore.
+// Members are not probed, so voters on unreachable nodes count as placed. Nodes in maintenance are not considered for placement.
+func datastoreVoters(ctx context.Context, s *state.State, snap snap.Snap, kubeClient *kubernetes.Client, cfg types.ClusterConfig, datastore *apiv1.Datastore) error {
+	datastore.Voters = dqlite.Voters
+
+	client, names, err := newK8sDqliteClient(ctx, s, snap, cfg.Datastore.GetK8sDqlitePort())
+	if err != nil {
+		return err
+	}
+	members, err := client.ListMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list k8s-dqlite members: %w", err)
+	}
+	zones, err := kubeClient.GetNodeZones(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve node zones: %w", err)
+	}
+	inMaintenance, err := databaseutil.ListNodesInMaintenance(ctx, s)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes in maintenance: %w", err)
+	}
+	pinned, err := databaseutil.ListPinnedDatastoreRoles(ctx, s)
+	if err != nil {
+		return fmt.Errorf("failed to list pinned datastore roles: %w", err)
+	}
+
+	placement := make([]dqlite.PlacementNode, 0, len(members))
+	for _, member := range members {
+		name, ok := names[member.Address]
+		zone := zones[name]
+		if member.Role == dqlite.Voter && zone != "" {
+			datastore.VoterZones = append(datastore.VoterZones, zone)
+		}
+		placement = append(placement, dqlite.PlacementNode{
+			NodeInfo:      member,
+			FailureDomain: zone,
+			Available:     ok && !slices.Contains(inMaintenance, name),
+			Pinned:        ok && slices.Contains(pinned, name),
+		})
+	}
+	sort.Strings(datastore.VoterZones)
+	datastore.VotersBalanced = utils.Pointer(len(dqlite.PlanVoters(placement, datastore.Voters)) == 0)
+	return nil
+}
+
+// k8sDqliteAddress returns the k8s-dqlite address of a control plane node, given its microcluster address.
+func k8sDqliteAddress(nodeAddress string, port int) (string, error) {
+	host, _, err := net.SplitHostPort(nodeAddress)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse node address %q: %w", nodeAddress, err)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// datastoreAddressOf returns the k8s-dqlite address of the control plane node with the given name.
+func datastoreAddressOf(names map[string]string, name string) (string, bool) {
+	for address, n := range names {
+		if n == name {
+			return address, true
+		}
+	}
+	return "", false
+}
+
+func datastoreMember(member dqlite.NodeInfo, names map[string]string, leader *dqlite.NodeInfo, pinned []string) apiv1.DatastoreMember {
+	name, ok := names[member.Address]
+	return apiv1.DatastoreMember{
+		ID:      member.ID,
+		Name:    name,
+		Address: member.Address,
+		Role:    nodeutil.DatastoreRoleFromString(member.Role.String()),
+		Leader:  leader != nil && leader.ID == member.ID,
+		Pinned:  ok && slices.Contains(pinned, name),
+	}
+}
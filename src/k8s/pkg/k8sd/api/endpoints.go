@@ -52,6 +52,39 @@ func (e *Endpoints) Endpoints() []rest.Endpoint {
 			Path: "k8sd/cluster/remove",
 			Post: rest.EndpointAction{Handler: e.postClusterRemove, AccessHandler: e.restrictWorkers},
 		},
+		// Node maintenance (control-plane and worker nodes)
+		{
+			Name: "ClusterMaintenance",
+			Path: "k8sd/cluster/maintenance",
+			Post: rest.EndpointAction{Handler: e.postClusterMaintenance, AccessHandler: e.restrictWorkers},
+		},
+		// k8s-dqlite membership of the control plane nodes
+		{
+			Name: "DatastoreMembers",
+			Path: "k8sd/datastore/members",
+			Get:  rest.EndpointAction{Handler: e.getDatastoreMembers, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name:   "DatastoreRole",
+			Path:   "k8sd/datastore/role",
+			Post:   rest.EndpointAction{Handler: e.postDatastoreRole, AccessHandler: e.restrictWorkers},
+			Delete: rest.EndpointAction{Handler: e.deleteDatastoreRole, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "DatastoreTransferLeadership",
+			Path: "k8sd/datastore/transfer-leadership",
+			Post: rest.EndpointAction{Handler: e.postDatastoreTransferLeadership, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "DatastoreHealth",
+			Path: "k8sd/datastore/health",
+			Get:  rest.EndpointAction{Handler: e.getDatastoreHealth, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "DatastoreLogIndex",
+			Path: "k8sd/datastore/log-index",
+			Get:  rest.EndpointAction{Handler: e.getDatastoreLogIndex, AccessHandler: e.restrictWorkers},
+		},
 		// Worker nodes
 		{
 			Name: "WorkerInfo",
@@ -76,6 +109,29 @@ func (e *Endpoints) Endpoints() []rest.Endpoint {
 			Put:  rest.EndpointAction{Handler: e.putClusterConfig, AccessHandler: e.restrictWorkers},
 			Get:  rest.EndpointAction{Handler: e.getClusterConfig, AccessHandler: e.restrictWorkers},
 		},
+		// List the revisions of the cluster configuration and restore a previous revision
+		{
+			Name: "ClusterConfigRevisions",
+			Path: "k8sd/cluster/config/revisions",
+			Get:  rest.EndpointAction{Handler: e.getClusterConfigRevisions, AccessHandler: e.restrictWorkers},
+		},
+		{
+			Name: "ClusterConfigRollback",
+			Path: "k8sd/cluster/config/rollback",
+			Post: rest.EndpointAction{Handler: e.postClusterConfigRollback, AccessHandler: e.restrictWorkers},
+		},
+		// List the images that are needed by the cluster (e.g. to prepare air-gapped deployments)
+		{
+			Name: "ClusterImages",
+			Path: "k8sd/cluster/images",
+			Get:  rest.EndpointAction{Handler: e.getClusterImages, AccessHandler: e.restrictWorkers},
+		},
+		// Events of the cluster operations observed by this node, optionally streamed as server-sent events
+		{
+			Name: "Events",
+			Path: "k8sd/events",
+			Get:  rest.EndpointAction{Handler: e.getEvents},
+		},
 		// Kubernetes auth tokens and token review webhook for kube-apiserver
 		{
 			Name:   "KubernetesAuthTokens",
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/state"
+)
+
+// eventsKeepaliveInterval is the interval of keepalive comments on idle event streams.
+const eventsKeepaliveInterval = 15 * time.Second
+
+func (e *Endpoints) getEvents(s *state.State, r *http.Request) response.Response {
+	var req apiv1.GetEventsRequest
+	if since := r.URL.Query().Get("since"); since != "" {
+		var err error
+		if req.Since, err = strconv.ParseUint(since, 10, 64); err != nil {
+			return response.BadRequest(fmt.Errorf("invalid since %q: %w", since, err))
+		}
+	}
+	if follow := r.URL.Query().Get("follow"); follow != "" {
+		var err error
+		if req.Follow, err = strconv.ParseBool(follow); err != nil {
+			return response.BadRequest(fmt.Errorf("invalid follow %q: %w", follow, err))
+		}
+	}
+
+	broker := e.provider.Events()
+	if !req.Follow {
+		result := broker.Events(req.Since)
+		for i := range result {
+			result[i].Node = s.Name()
+		}
+		return response.SyncResponse(true, &apiv1.GetEventsResponse{Events: result})
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		history, ch, cancel := broker.Subscribe(req.Since)
+		defer cancel()
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			return fmt.Errorf("streaming is not supported by the connection")
+		}
+		w.Header().Set("Content-Type", events.ContentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		for _, event := range history {
+			event.Node = s.Name()
+			if err := events.WriteEvent(w, event); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+
+		keepalive := time.NewTicker(eventsKeepaliveInterval)
+		defer keepalive.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return nil
+			case <-s.Context.Done():
+				return nil
+			case <-keepalive.C:
+				if err := events.WriteKeepalive(w); err != nil {
+					return nil
+				}
+			case event, ok := <-ch:
+				if !ok {
+					// the subscriber fell behind, the client resumes from the last event it received
+					return nil
+				}
+				event.Node = s.Name()
+				if err := events.WriteEvent(w, event); err != nil {
+					return nil
+				}
+			}
+			flusher.Flush()
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/k8sd/features"
+	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/microcluster/state"
+)
+
+func (e *Endpoints) getClusterImages(s *state.State, r *http.Request) response.Response {
+	config, err := databaseutil.GetClusterConfig(r.Context(), s)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to retrieve cluster configuration: %w", err))
+	}
+
+	var images []string
+	for _, image := range setup.ContainerdImages() {
+		images = append(images, image.WithRegistry(config.Containerd.GetImageRegistry()).String())
+	}
+	for _, image := range features.Implementation.Images(config) {
+		images = append(images, image.String())
+	}
+
+	return response.SyncResponse(true, &apiv1.GetClusterImagesResponse{Images: images})
+}
@@ -3,13 +3,18 @@ package impl
 import (
 	"context"
 	"fmt"
-	"log"
+	"sort"
 
 	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
 	nodeutil "github.com/canonical/k8s/pkg/utils/node"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/microcluster/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // GetClusterMembers retrieves information about the members of the cluster.
@@ -53,10 +58,11 @@ func GetLocalNodeStatus(ctx context.Context, s *state.State, snap snap.Snap) (ap
 		if err != nil {
 			// The node is likely in a joining or leaving phase where the role is not yet settled.
 			// Use the unknown role but still log this incident for debugging.
-			log.Printf("Failed to check if node is control-plane. This is expected if the node is in a joining/leaving phase. %v", err)
+			log.FromContext(ctx).Debug("Failed to check if node is control-plane. This is expected if the node is in a joining/leaving phase", "node", s.Name(), "error", err)
 			clusterRole = apiv1.ClusterRoleUnknown
 		} else {
 			if node != nil {
+				node.Services = snaputil.ServiceStates(ctx, snap, false)
 				return *node, nil
 			}
 		}
@@ -66,6 +72,76 @@ func GetLocalNodeStatus(ctx context.Context, s *state.State, snap snap.Snap) (ap
 		Name:        s.Name(),
 		Address:     s.Address().Hostname(),
 		ClusterRole: clusterRole,
+		Services:    snaputil.ServiceStates(ctx, snap, isWorker),
 	}, nil
 
 }
+
+// GetDetailedClusterMembers adds the Kubernetes node status and the service health to the cluster members.
+// Kubernetes nodes that are not cluster members are added as worker nodes. The service health of
+// worker nodes is not known, as they are not reachable through the cluster.
+// Failures to reach other control plane nodes are logged and leave their service health empty.
+func GetDetailedClusterMembers(ctx context.Context, s *state.State, snap snap.Snap, client *kubernetes.Client, members []apiv1.NodeStatus) ([]apiv1.NodeStatus, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kubernetes nodes: %w", err)
+	}
+
+	kubernetesNodes := make(map[string]*apiv1.KubernetesNodeStatus, len(nodes.Items))
+	for _, node := range nodes.Items {
+		status := &apiv1.KubernetesNodeStatus{KubeletVersion: node.Status.NodeInfo.KubeletVersion}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				status.Ready = condition.Status == corev1.ConditionTrue
+			}
+		}
+		for _, address := range node.Status.Addresses {
+			if address.Type == corev1.NodeInternalIP {
+				status.InternalIP = address.Address
+				break
+			}
+		}
+		kubernetesNodes[node.Name] = status
+	}
+
+	remoteServices := make(map[string]map[string]string)
+	if len(members) > 1 {
+		clients, err := s.Cluster(nil)
+		if err != nil {
+			log.FromContext(ctx).Warn("Failed to get clients for cluster members, the service health of remote nodes is unknown", "error", err)
+		}
+		for _, c := range clients {
+			var response apiv1.GetNodeStatusResponse
+			if err := c.Query(ctx, "GET", api.NewURL().Path("k8sd", "node"), nil, &response); err != nil {
+				log.FromContext(ctx).Warn("Failed to get node status", "node", c.URL().URL.Host, "error", err)
+				continue
+			}
+			remoteServices[c.URL().URL.Host] = response.NodeStatus.Services
+		}
+	}
+
+	detailed := make([]apiv1.NodeStatus, 0, len(nodes.Items))
+	for _, member := range members {
+		member.KubernetesNode = kubernetesNodes[member.Name]
+		delete(kubernetesNodes, member.Name)
+		if member.Name == s.Name() {
+			member.Services = snaputil.ServiceStates(ctx, snap, false)
+		} else {
+			member.Services = remoteServices[member.Address]
+		}
+		detailed = append(detailed, member)
+	}
+
+	workers := make([]apiv1.NodeStatus, 0, len(kubernetesNodes))
+	for name, node := range kubernetesNodes {
+		workers = append(workers, apiv1.NodeStatus{
+			Name:           name,
+			Address:        node.InternalIP,
+			ClusterRole:    apiv1.ClusterRoleWorker,
+			KubernetesNode: node,
+		})
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].Name < workers[j].Name })
+
+	return append(detailed, workers...), nil
+}
@@ -24,7 +24,10 @@ func (e *Endpoints) getKubeconfig(s *state.State, r *http.Request) response.Resp
 		return response.InternalError(fmt.Errorf("failed to retrieve cluster config: %w", err))
 	}
 	server := req.Server
-	if req.Server == "" {
+	if server == "" {
+		server = config.APIServer.ControlPlaneEndpointAddress()
+	}
+	if server == "" {
 		server = fmt.Sprintf("%s:%d", s.Address().Hostname(), config.APIServer.GetSecurePort())
 	}
 
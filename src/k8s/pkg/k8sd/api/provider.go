@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/canonical/k8s/pkg/k8sd/events"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/microcluster/microcluster"
 )
@@ -10,5 +11,6 @@ type Provider interface {
 	MicroCluster() *microcluster.MicroCluster
 	Snap() snap.Snap
 	NotifyUpdateNodeConfigController()
-	NotifyFeatureController(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns bool)
+	NotifyFeatureController(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns, nodeLocalDNS bool)
+	Events() *events.Broker
 }
@@ -10,7 +10,9 @@ import (
 	apiv1 "github.com/canonical/k8s/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	node_local_dns "github.com/canonical/k8s/pkg/k8sd/features/node-local-dns"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/microcluster/state"
@@ -53,10 +55,14 @@ func (e *Endpoints) postWorkerInfo(s *state.State, r *http.Request) response.Res
 	if err := client.WaitKubernetesEndpointAvailable(s.Context); err != nil {
 		return response.InternalError(fmt.Errorf("kubernetes endpoints not ready yet: %w", err))
 	}
-	servers, err := client.GetKubeAPIServerEndpoints(s.Context)
+	servers, err := client.GetKubeAPIServerEndpoints(s.Context, cfg.APIServer.GetSecurePort())
 	if err != nil {
 		return response.InternalError(fmt.Errorf("failed to retrieve list of known kube-apiserver endpoints: %w", err))
 	}
+	kubelet, err := node_local_dns.NodeKubelet(s.Context, client, cfg)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("failed to check node-local-dns rollout: %w", err))
+	}
 
 	if err := s.Database.Transaction(s.Context, func(ctx context.Context, tx *sql.Tx) error {
 		return database.AddWorkerNode(ctx, tx, workerName)
@@ -71,21 +77,30 @@ func (e *Endpoints) postWorkerInfo(s *state.State, r *http.Request) response.Res
 		return response.InternalError(fmt.Errorf("delete worker node token transaction failed: %w", err))
 	}
 
+	e.provider.Events().Publish(apiv1.EventNodeJoined, fmt.Sprintf("Worker node %s joined the cluster", workerName), map[string]string{
+		"node": workerName, "role": string(apiv1.ClusterRoleWorker),
+	})
+
 	return response.SyncResponse(true, &apiv1.WorkerNodeInfoResponse{
-		CACert:              cfg.Certificates.GetCACert(),
-		ClientCACert:        cfg.Certificates.GetClientCACert(),
-		APIServers:          servers,
-		PodCIDR:             cfg.Network.GetPodCIDR(),
-		ServiceCIDR:         cfg.Network.GetServiceCIDR(),
-		ClusterDomain:       cfg.Kubelet.GetClusterDomain(),
-		ClusterDNS:          cfg.Kubelet.GetClusterDNS(),
-		CloudProvider:       cfg.Kubelet.GetCloudProvider(),
-		KubeletCert:         workerCertificates.KubeletCert,
-		KubeletKey:          workerCertificates.KubeletKey,
-		KubeletClientCert:   workerCertificates.KubeletClientCert,
-		KubeletClientKey:    workerCertificates.KubeletClientKey,
-		KubeProxyClientCert: workerCertificates.KubeProxyClientCert,
-		KubeProxyClientKey:  workerCertificates.KubeProxyClientKey,
-		K8sdPublicKey:       cfg.Certificates.GetK8sdPublicKey(),
+		CACert:                    cfg.Certificates.GetCACert(),
+		ClientCACert:              cfg.Certificates.GetClientCACert(),
+		APIServers:                servers,
+		ControlPlaneEndpoint:      cfg.APIServer.WorkerEndpoint(),
+		APIServerPort:             cfg.APIServer.GetSecurePort(),
+		PodCIDR:                   cfg.Network.GetPodCIDR(),
+		ServiceCIDR:               cfg.Network.GetServiceCIDR(),
+		ClusterDomain:             cfg.Kubelet.GetClusterDomain(),
+		ClusterDNS:                kubelet.GetClusterDNS(),
+		CloudProvider:             cfg.Kubelet.GetCloudProvider(),
+		KubeletCert:               workerCertificates.KubeletCert,
+		KubeletKey:                workerCertificates.KubeletKey,
+		KubeletClientCert:         workerCertificates.KubeletClientCert,
+		KubeletClientKey:          workerCertificates.KubeletClientKey,
+		KubeProxyClientCert:       workerCertificates.KubeProxyClientCert,
+		KubeProxyClientKey:        workerCertificates.KubeProxyClientKey,
+		K8sdPublicKey:             cfg.Certificates.GetK8sdPublicKey(),
+		ContainerdRegistries:      types.ContainerdRegistriesToAPI(cfg.Containerd.GetRegistries()),
+		ContainerdRuntimeHandlers: types.ContainerdRuntimeHandlersToAPI(cfg.Containerd.GetRuntimeHandlers()),
+		ImageRegistry:             cfg.Containerd.GetImageRegistry(),
 	})
 }
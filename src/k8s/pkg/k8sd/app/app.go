@@ -3,15 +3,19 @@ package app
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/canonical/k8s/pkg/k8sd/api"
 	"github.com/canonical/k8s/pkg/k8sd/controllers"
 	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/microcluster/config"
 	"github.com/canonical/microcluster/microcluster"
@@ -30,6 +34,10 @@ type Config struct {
 	Snap snap.Snap
 	// PprofAddress is the address to listen for pprof debug endpoints. Empty to disable.
 	PprofAddress string
+	// LogFormat is the format of the log messages, "text" or "json". Defaults to "text".
+	LogFormat string
+	// LogOutput is where log messages are written. Defaults to stderr.
+	LogOutput io.Writer
 }
 
 // App is the k8sd microcluster instance.
@@ -37,14 +45,21 @@ type App struct {
 	microCluster *microcluster.MicroCluster
 	snap         snap.Snap
 
+	// logger is the structured logger passed to the hooks and controllers.
+	logger *slog.Logger
+
 	// profilingAddress
 	profilingAddress string
 
 	// readyWg is used to denote that the microcluster node is now running
 	readyWg sync.WaitGroup
 
+	// events are the cluster operations observed by this node
+	events *events.Broker
+
 	nodeConfigController         *controllers.NodeConfigurationController
 	controlPlaneConfigController *controllers.ControlPlaneConfigurationController
+	datastoreVoterController     *controllers.DatastoreVoterController
 
 	// updateNodeConfigController
 	triggerUpdateNodeConfigControllerCh chan struct{}
@@ -58,6 +73,7 @@ type App struct {
 	triggerFeatureControllerLocalStorageCh  chan struct{}
 	triggerFeatureControllerMetricsServerCh chan struct{}
 	triggerFeatureControllerDNSCh           chan struct{}
+	triggerFeatureControllerNodeLocalDNSCh  chan struct{}
 	featureController                       *controllers.FeatureController
 }
 
@@ -66,6 +82,14 @@ func New(cfg Config) (*App, error) {
 	if cfg.StateDir == "" {
 		cfg.StateDir = cfg.Snap.K8sdStateDir()
 	}
+	if cfg.LogOutput == nil {
+		cfg.LogOutput = os.Stderr
+	}
+	logger, err := log.New(cfg.LogOutput, log.Options{Debug: cfg.Debug, Verbose: cfg.Verbose, Format: cfg.LogFormat})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+
 	cluster, err := microcluster.App(microcluster.Args{
 		Verbose:  cfg.Verbose,
 		Debug:    cfg.Debug,
@@ -80,6 +104,8 @@ func New(cfg Config) (*App, error) {
 		microCluster:     cluster,
 		snap:             cfg.Snap,
 		profilingAddress: cfg.PprofAddress,
+		logger:           logger,
+		events:           events.NewBroker(1000),
 	}
 	app.readyWg.Add(1)
 
@@ -92,6 +118,14 @@ func New(cfg Config) (*App, error) {
 		cfg.Snap,
 		app.readyWg.Wait,
 		time.NewTicker(10*time.Second).C,
+		app.events,
+	)
+
+	app.datastoreVoterController = controllers.NewDatastoreVoterController(
+		cfg.Snap,
+		app.readyWg.Wait,
+		time.NewTicker(30*time.Second).C,
+		app.events,
 	)
 
 	app.triggerUpdateNodeConfigControllerCh = make(chan struct{}, 1)
@@ -108,6 +142,7 @@ func New(cfg Config) (*App, error) {
 	app.triggerFeatureControllerLocalStorageCh = make(chan struct{}, 1)
 	app.triggerFeatureControllerMetricsServerCh = make(chan struct{}, 1)
 	app.triggerFeatureControllerDNSCh = make(chan struct{}, 1)
+	app.triggerFeatureControllerNodeLocalDNSCh = make(chan struct{}, 1)
 	app.featureController = controllers.NewFeatureController(controllers.FeatureControllerOpts{
 		Snap:                   cfg.Snap,
 		WaitReady:              app.readyWg.Wait,
@@ -116,8 +151,10 @@ func New(cfg Config) (*App, error) {
 		TriggerIngressCh:       app.triggerFeatureControllerIngressCh,
 		TriggerLoadBalancerCh:  app.triggerFeatureControllerLoadBalancerCh,
 		TriggerDNSCh:           app.triggerFeatureControllerDNSCh,
+		TriggerNodeLocalDNSCh:  app.triggerFeatureControllerNodeLocalDNSCh,
 		TriggerLocalStorageCh:  app.triggerFeatureControllerLocalStorageCh,
 		TriggerMetricsServerCh: app.triggerFeatureControllerMetricsServerCh,
+		Events:                 app.events,
 	})
 
 	return app, nil
@@ -148,9 +185,13 @@ func (a *App) Run(ctx context.Context, customHooks *config.Hooks) error {
 		}
 	}
 
+	// API handlers log with the default logger, hooks and controllers with the logger of the context.
+	slog.SetDefault(a.logger)
+	ctx = log.NewContext(ctx, a.logger)
+
 	// start profiling server
 	if a.profilingAddress != "" {
-		log.Printf("Enable pprof endpoint at http://%s", a.profilingAddress)
+		a.logger.Info("Enabling pprof endpoint", "address", a.profilingAddress)
 
 		go func() {
 			mux := http.NewServeMux()
@@ -161,7 +202,7 @@ func (a *App) Run(ctx context.Context, customHooks *config.Hooks) error {
 			mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
 			if err := http.ListenAndServe(a.profilingAddress, mux); err != nil {
-				log.Printf("ERROR: Failed to serve pprof endpoint: %v", err)
+				a.logger.Error("Failed to serve pprof endpoint", "address", a.profilingAddress, "error", err)
 			}
 		}()
 	}
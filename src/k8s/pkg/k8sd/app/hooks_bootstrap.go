@@ -155,10 +155,18 @@ func (a *App) onBootstrapWorkerNode(s *state.State, encodedToken string, joinCon
 	}
 
 	// Kubeconfigs
-	if err := setup.Kubeconfig(path.Join(snap.KubernetesConfigDir(), "kubelet.conf"), "127.0.0.1:6443", certificates.CACert, certificates.KubeletClientCert, certificates.KubeletClientKey); err != nil {
+	// Worker nodes reach kube-apiserver through the local k8s-apiserver-proxy, which listens
+	// on the same port as kube-apiserver on the control plane nodes.
+	apiServerPort := response.APIServerPort
+	if apiServerPort == 0 {
+		// control plane nodes that do not know of the secure port run kube-apiserver on the default port
+		apiServerPort = 6443
+	}
+	localServer := fmt.Sprintf("127.0.0.1:%d", apiServerPort)
+	if err := setup.Kubeconfig(path.Join(snap.KubernetesConfigDir(), "kubelet.conf"), localServer, certificates.CACert, certificates.KubeletClientCert, certificates.KubeletClientKey); err != nil {
 		return fmt.Errorf("failed to generate kubelet kubeconfig: %w", err)
 	}
-	if err := setup.Kubeconfig(path.Join(snap.KubernetesConfigDir(), "proxy.conf"), "127.0.0.1:6443", certificates.CACert, certificates.KubeProxyClientCert, certificates.KubeProxyClientKey); err != nil {
+	if err := setup.Kubeconfig(path.Join(snap.KubernetesConfigDir(), "proxy.conf"), localServer, certificates.CACert, certificates.KubeProxyClientCert, certificates.KubeProxyClientKey); err != nil {
 		return fmt.Errorf("failed to generate kube-proxy kubeconfig: %w", err)
 	}
 
@@ -166,6 +174,7 @@ func (a *App) onBootstrapWorkerNode(s *state.State, encodedToken string, joinCon
 	//
 	// Worker nodes only use a subset of the ClusterConfig struct. At the moment, these are:
 	// - Network.PodCIDR and Network.ClusterCIDR: informative
+	// - APIServer.SecurePort: port of the local k8s-apiserver-proxy
 	// - Certificates.K8sdPublicKey: used to verify the signature of the k8sd-config configmap.
 	//
 	// TODO(neoaggelos): We should be explicit here and try to avoid having worker nodes use
@@ -175,6 +184,9 @@ func (a *App) onBootstrapWorkerNode(s *state.State, encodedToken string, joinCon
 			PodCIDR:     utils.Pointer(response.PodCIDR),
 			ServiceCIDR: utils.Pointer(response.ServiceCIDR),
 		},
+		APIServer: types.APIServer{
+			SecurePort: utils.Pointer(apiServerPort),
+		},
 		Certificates: types.Certificates{
 			K8sdPublicKey: utils.Pointer(response.K8sdPublicKey),
 		},
@@ -189,7 +201,7 @@ func (a *App) onBootstrapWorkerNode(s *state.State, encodedToken string, joinCon
 	}
 
 	// Worker node services
-	if err := setup.Containerd(snap, nil); err != nil {
+	if err := setup.Containerd(snap, types.ContainerdRegistriesFromAPI(response.ContainerdRegistries), types.ContainerdRuntimeHandlersFromAPI(response.ContainerdRuntimeHandlers), response.ImageRegistry); err != nil {
 		return fmt.Errorf("failed to configure containerd: %w", err)
 	}
 	if err := setup.KubeletWorker(snap, s.Name(), nodeIP, response.ClusterDNS, response.ClusterDomain, response.CloudProvider); err != nil {
@@ -198,8 +210,8 @@ func (a *App) onBootstrapWorkerNode(s *state.State, encodedToken string, joinCon
 	if err := setup.KubeProxy(s.Context, snap, s.Name(), response.PodCIDR); err != nil {
 		return fmt.Errorf("failed to configure kube-proxy: %w", err)
 	}
-	if err := setup.K8sAPIServerProxy(snap, response.APIServers); err != nil {
-		return fmt.Errorf("failed to configure kube-proxy: %w", err)
+	if err := setup.K8sAPIServerProxy(snap, response.APIServers, apiServerPort, response.ControlPlaneEndpoint); err != nil {
+		return fmt.Errorf("failed to configure k8s-apiserver-proxy: %w", err)
 	}
 
 	// TODO(berkayoz): remove the lock on cleanup
@@ -226,6 +238,9 @@ func (a *App) onBootstrapControlPlane(s *state.State, bootstrapConfig apiv1.Boot
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("invalid cluster configuration: %w", err)
 	}
+	if err := validateZone(bootstrapConfig.GetZone()); err != nil {
+		return fmt.Errorf("invalid bootstrap config: %w", err)
+	}
 
 	nodeIP := net.ParseIP(s.Address().Hostname())
 	if nodeIP == nil {
@@ -277,7 +292,8 @@ func (a *App) onBootstrapControlPlane(s *state.State, bootstrapConfig apiv1.Boot
 	}
 
 	// Certificates
-	extraIPs, extraNames := utils.SplitIPAndDNSSANs(bootstrapConfig.ExtraSANs)
+	// SplitIPAndDNSSANs skips the control plane endpoint if it is not set
+	extraIPs, extraNames := utils.SplitIPAndDNSSANs(append(bootstrapConfig.ExtraSANs, cfg.APIServer.ControlPlaneEndpointHost()))
 	certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
 		Hostname:                  s.Name(),
 		IPSANs:                    append(append([]net.IP{nodeIP}, serviceIPs...), extraIPs...),
@@ -353,15 +369,20 @@ func (a *App) onBootstrapControlPlane(s *state.State, bootstrapConfig apiv1.Boot
 	}
 
 	// Configure services
-	if err := setupControlPlaneServices(snap, s, cfg, nodeIP); err != nil {
+	if err := setupControlPlaneServices(snap, s, cfg, nodeIP, bootstrapConfig.GetZone()); err != nil {
 		return fmt.Errorf("failed to configure services: %w", err)
 	}
 
 	// Write cluster configuration to dqlite
 	if err := s.Database.Transaction(s.Context, func(ctx context.Context, tx *sql.Tx) error {
-		if _, err := database.SetClusterConfig(ctx, tx, cfg); err != nil {
+		mergedConfig, err := database.SetClusterConfig(ctx, tx, cfg)
+		if err != nil {
 			return fmt.Errorf("failed to write cluster configuration: %w", err)
 		}
+		// the bootstrap configuration is the first revision, so that it can be restored
+		if _, err := database.AddClusterConfigRevision(ctx, tx, fmt.Sprintf("%s (bootstrap)", s.Name()), types.ClusterConfig{}, mergedConfig); err != nil {
+			return fmt.Errorf("failed to record cluster configuration revision: %w", err)
+		}
 		return nil
 	}); err != nil {
 		return fmt.Errorf("database transaction to update cluster configuration failed: %w", err)
@@ -389,6 +410,7 @@ func (a *App) onBootstrapControlPlane(s *state.State, bootstrapConfig apiv1.Boot
 		cfg.LocalStorage.GetEnabled(),
 		cfg.MetricsServer.GetEnabled(),
 		cfg.DNS.GetEnabled(),
+		cfg.NodeLocalDNS.GetEnabled(),
 	)
 	a.NotifyUpdateNodeConfigController()
 	return nil
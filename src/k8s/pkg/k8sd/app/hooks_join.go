@@ -22,6 +22,9 @@ func (a *App) onPostJoin(s *state.State, initConfig map[string]string) error {
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal control plane join config: %w", err)
 	}
+	if err := validateZone(joinConfig.GetZone()); err != nil {
+		return fmt.Errorf("invalid control plane join config: %w", err)
+	}
 
 	cfg, err := databaseutil.GetClusterConfig(s.Context, s)
 	if err != nil {
@@ -75,7 +78,8 @@ func (a *App) onPostJoin(s *state.State, initConfig map[string]string) error {
 	}
 
 	// Certificates
-	extraIPs, extraNames := utils.SplitIPAndDNSSANs(joinConfig.ExtraSANS)
+	// SplitIPAndDNSSANs skips the control plane endpoint if it is not set
+	extraIPs, extraNames := utils.SplitIPAndDNSSANs(append(joinConfig.ExtraSANS, cfg.APIServer.ControlPlaneEndpointHost()))
 	certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
 		Hostname:                  s.Name(),
 		IPSANs:                    append(append([]net.IP{nodeIP}, serviceIPs...), extraIPs...),
@@ -143,7 +147,7 @@ func (a *App) onPostJoin(s *state.State, initConfig map[string]string) error {
 	}
 
 	// Configure services
-	if err := setupControlPlaneServices(snap, s, cfg, nodeIP); err != nil {
+	if err := setupControlPlaneServices(snap, s, cfg, nodeIP, joinConfig.GetZone()); err != nil {
 		return fmt.Errorf("failed to configure services: %w", err)
 	}
 
@@ -197,5 +201,13 @@ func (a *App) onPreRemove(s *state.State, force bool) error {
 		return fmt.Errorf("failed to remove k8s node %q: %w", s.Name(), err)
 	}
 
+	if err := databaseutil.DeleteNodeMaintenanceEntry(s.Context, s, s.Name()); err != nil {
+		return fmt.Errorf("failed to remove node maintenance entry %q: %w", s.Name(), err)
+	}
+
+	if err := databaseutil.SetDatastoreRolePinned(s.Context, s, s.Name(), false); err != nil {
+		return fmt.Errorf("failed to unpin datastore role of %q: %w", s.Name(), err)
+	}
+
 	return nil
 }
@@ -5,7 +5,9 @@ import (
 	"crypto/rsa"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
 	"github.com/canonical/k8s/pkg/k8sd/database"
 	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
@@ -18,6 +20,9 @@ func (a *App) onStart(s *state.State) error {
 	// start a goroutine to mark the node as running
 	go a.markNodeReady(s.Context, s)
 
+	// publish events for changes of the control plane members
+	go a.watchClusterMembers(s.Context, s, 10*time.Second)
+
 	// start node config controller
 	if a.nodeConfigController != nil {
 		go a.nodeConfigController.Run(s.Context, func(ctx context.Context) (*rsa.PublicKey, error) {
@@ -31,6 +36,13 @@ func (a *App) onStart(s *state.State) error {
 				return nil, fmt.Errorf("failed to load RSA key: %w", err)
 			}
 			return key, nil
+		}, func(ctx context.Context) (bool, error) {
+			// worker nodes do not have access to the database, use the annotation of the node instead
+			client, err := a.Snap().KubernetesNodeClient("")
+			if err != nil {
+				return false, fmt.Errorf("failed to create kubernetes client: %w", err)
+			}
+			return client.IsNodeInMaintenance(ctx, s.Name())
 		})
 	}
 
@@ -38,6 +50,17 @@ func (a *App) onStart(s *state.State) error {
 	if a.controlPlaneConfigController != nil {
 		go a.controlPlaneConfigController.Run(s.Context, func(ctx context.Context) (types.ClusterConfig, error) {
 			return databaseutil.GetClusterConfig(ctx, s)
+		}, func(ctx context.Context) (bool, error) {
+			return databaseutil.IsNodeInMaintenance(ctx, s, s.Name())
+		})
+	}
+
+	// start datastore voter controller
+	if a.datastoreVoterController != nil {
+		go a.datastoreVoterController.Run(s.Context, func(ctx context.Context) (types.ClusterConfig, error) {
+			return databaseutil.GetClusterConfig(ctx, s)
+		}, func(ctx context.Context, cfg types.ClusterConfig) (map[string]controllers.DatastoreNode, error) {
+			return getDatastoreNodes(ctx, s, cfg.Datastore.GetK8sDqlitePort())
 		})
 	}
 
@@ -69,9 +92,13 @@ func (a *App) onStart(s *state.State) error {
 
 				// DNS IP has changed, notify node config controller
 				a.NotifyUpdateNodeConfigController()
+				// node-local-dns forwards to the DNS IP
+				utils.MaybeNotify(a.triggerFeatureControllerNodeLocalDNSCh)
 
 				return nil
 			},
+			// node-local-dns is ready, kubelets can switch to it
+			a.NotifyUpdateNodeConfigController,
 		)
 	}
 
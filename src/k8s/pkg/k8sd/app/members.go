@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/api/impl"
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	databaseutil "github.com/canonical/k8s/pkg/k8sd/database/util"
+	"github.com/canonical/k8s/pkg/log"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/microcluster/state"
+)
+
+// watchClusterMembers periodically lists the control plane members and publishes events when members join,
+// are removed or change their datastore role. The first list of members is not published.
+func (a *App) watchClusterMembers(ctx context.Context, s *state.State, interval time.Duration) {
+	a.readyWg.Wait()
+	logger := log.FromContext(ctx).With("component", "member-watcher")
+
+	var previous map[string]apiv1.DatastoreRole
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		// worker nodes are not members of the k8sd cluster
+		if isWorker, err := snaputil.IsWorker(a.snap); err != nil || isWorker {
+			continue
+		}
+
+		members, err := impl.GetClusterMembers(ctx, s)
+		if err != nil {
+			logger.Warn("Failed to list cluster members", "error", err)
+			continue
+		}
+		current := make(map[string]apiv1.DatastoreRole, len(members))
+		for _, member := range members {
+			current[member.Name] = member.DatastoreRole
+		}
+
+		if previous != nil {
+			a.events.PublishMemberChanges(previous, current)
+		}
+		previous = current
+	}
+}
+
+// getDatastoreNodes lists the control plane nodes by their k8s-dqlite address.
+func getDatastoreNodes(ctx context.Context, s *state.State, port int) (map[string]controllers.DatastoreNode, error) {
+	leader, err := s.Leader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get microcluster leader client: %w", err)
+	}
+	members, err := leader.GetClusterMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get microcluster members: %w", err)
+	}
+	inMaintenance, err := databaseutil.ListNodesInMaintenance(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes in maintenance: %w", err)
+	}
+	pinned, err := databaseutil.ListPinnedDatastoreRoles(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pinned datastore roles: %w", err)
+	}
+
+	nodes := make(map[string]controllers.DatastoreNode, len(members))
+	for _, member := range members {
+		address := net.JoinHostPort(member.Address.Addr().String(), strconv.Itoa(port))
+		nodes[address] = controllers.DatastoreNode{
+			Name:          member.Name,
+			Local:         member.Name == s.Name(),
+			InMaintenance: slices.Contains(inMaintenance, member.Name),
+			Pinned:        slices.Contains(pinned, member.Name),
+		}
+	}
+	return nodes, nil
+}
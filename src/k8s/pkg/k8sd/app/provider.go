@@ -2,6 +2,7 @@ package app
 
 import (
 	"github.com/canonical/k8s/pkg/k8sd/api"
+	"github.com/canonical/k8s/pkg/k8sd/events"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/microcluster/microcluster"
@@ -15,11 +16,15 @@ func (a *App) Snap() snap.Snap {
 	return a.snap
 }
 
+func (a *App) Events() *events.Broker {
+	return a.events
+}
+
 func (a *App) NotifyUpdateNodeConfigController() {
 	utils.MaybeNotify(a.triggerUpdateNodeConfigControllerCh)
 }
 
-func (a *App) NotifyFeatureController(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns bool) {
+func (a *App) NotifyFeatureController(network, gateway, ingress, loadBalancer, localStorage, metricsServer, dns, nodeLocalDNS bool) {
 	if network {
 		utils.MaybeNotify(a.triggerFeatureControllerNetworkCh)
 	}
@@ -41,6 +46,9 @@ func (a *App) NotifyFeatureController(network, gateway, ingress, loadBalancer, l
 	if dns {
 		utils.MaybeNotify(a.triggerFeatureControllerDNSCh)
 	}
+	if nodeLocalDNS {
+		utils.MaybeNotify(a.triggerFeatureControllerNodeLocalDNSCh)
+	}
 }
 
 // Ensure App implements api.Provider
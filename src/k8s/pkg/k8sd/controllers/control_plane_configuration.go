@@ -2,15 +2,19 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"time"
 
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/events"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/k8s/pkg/utils/experimental/snapdconfig"
 )
 
@@ -20,24 +24,32 @@ type ControlPlaneConfigurationController struct {
 	snap      snap.Snap
 	waitReady func()
 	triggerCh <-chan time.Time
+	events    *events.Broker
+
+	// noCAKeyEndpoint is the control plane endpoint that was last reported as missing from the kube-apiserver certificate.
+	noCAKeyEndpoint string
 }
 
 // NewControlPlaneConfigurationController creates a new controller.
 // triggerCh is typically a `time.NewTicker(<duration>).C`
-func NewControlPlaneConfigurationController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time) *ControlPlaneConfigurationController {
+func NewControlPlaneConfigurationController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time, events *events.Broker) *ControlPlaneConfigurationController {
 	return &ControlPlaneConfigurationController{
 		snap:      snap,
 		waitReady: waitReady,
 		triggerCh: triggerCh,
+		events:    events,
 	}
 }
 
 // Run starts the controller.
 // Run accepts a context to manage the lifecycle of the controller.
 // Run accepts a function that retrieves the current cluster configuration.
+// Run accepts a function that returns true if the node is in maintenance. Changes are not applied while the node is in maintenance.
 // Run will loop every time the trigger channel is
-func (c *ControlPlaneConfigurationController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error)) {
+func (c *ControlPlaneConfigurationController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), isInMaintenance func(context.Context) (bool, error)) {
 	c.waitReady()
+	ctx = log.WithComponent(ctx, "control-plane-configuration-controller")
+	logger := log.FromContext(ctx)
 
 	for {
 		select {
@@ -47,21 +59,30 @@ func (c *ControlPlaneConfigurationController) Run(ctx context.Context, getCluste
 		}
 
 		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
-			log.Println(fmt.Errorf("failed to check if this is a worker node: %w", err))
+			logger.Error("Failed to check if this is a worker node", "error", err)
 			continue
 		} else if isWorker {
-			log.Println("Stopping control plane controller as this is a worker node")
+			logger.Info("Stopping controller as this is a worker node")
 			return
 		}
 
+		// do not restart the services of a node that is in maintenance, changes are applied after it exits maintenance
+		if inMaintenance, err := isInMaintenance(ctx); err != nil {
+			logger.Error("Failed to check if this node is in maintenance", "error", err)
+			continue
+		} else if inMaintenance {
+			logger.Debug("Skipping reconcile as this node is in maintenance")
+			continue
+		}
+
 		config, err := getClusterConfig(ctx)
 		if err != nil {
-			log.Println(fmt.Errorf("failed to retrieve cluster config: %w", err))
+			logger.Error("Failed to retrieve cluster config", "error", err)
 			continue
 		}
 
 		if err := c.reconcile(ctx, config); err != nil {
-			log.Println(fmt.Errorf("failed to reconcile control plane configuration: %w", err))
+			logger.Error("Failed to reconcile control plane configuration", "error", err)
 		}
 	}
 }
@@ -94,6 +115,12 @@ func (c *ControlPlaneConfigurationController) reconcile(ctx context.Context, con
 		}
 	}
 
+	// kube-apiserver: control plane endpoint certificate SANs
+	if err := c.reconcileAPIServerCertificate(ctx, config); err != nil {
+		// do not block the rest of the configuration
+		log.FromContext(ctx).Error("Failed to reconcile kube-apiserver certificate", "error", err)
+	}
+
 	// kube-controller-manager: cloud-provider
 	if v := config.Kubelet.CloudProvider; v != nil {
 		mustRestart, err := snaputil.UpdateServiceArguments(c.snap, "kube-controller-manager", map[string]string{"--cloud-provider": *v}, nil)
@@ -111,9 +138,42 @@ func (c *ControlPlaneConfigurationController) reconcile(ctx context.Context, con
 	// snapd
 	if meta, _, err := snapdconfig.ParseMeta(ctx, c.snap); err == nil && meta.Orb != "none" {
 		if err := snapdconfig.SetSnapdFromK8sd(ctx, config.ToUserFacing(), c.snap); err != nil {
-			log.Printf("Warning: failed to update snapd configuration: %v", err)
+			log.FromContext(ctx).Warn("Failed to update snapd configuration", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileAPIServerCertificate re-issues the kube-apiserver certificate if it does not include the control plane endpoint.
+// With an external kubernetes CA without a key, the certificate cannot be re-issued, which is reported once per endpoint.
+func (c *ControlPlaneConfigurationController) reconcileAPIServerCertificate(ctx context.Context, config types.ClusterConfig) error {
+	host := config.APIServer.ControlPlaneEndpointHost()
+	if host == "" {
+		return nil
+	}
+
+	ipSANs, dnsSANs := utils.SplitIPAndDNSSANs([]string{host})
+	certificateChanged, err := setup.EnsureAPIServerCertificateSANs(c.snap, config.Certificates.GetCACert(), config.Certificates.GetCAKey(), dnsSANs, ipSANs)
+	if errors.Is(err, pki.ErrNoCAKey) {
+		if c.noCAKeyEndpoint != host {
+			c.noCAKeyEndpoint = host
+			log.FromContext(ctx).Warn("The kube-apiserver certificate does not include the control plane endpoint and cannot be re-issued without the kubernetes CA key, re-issue it manually", "endpoint", host)
 		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to add control plane endpoint to kube-apiserver certificate: %w", err)
 	}
 
+	if certificateChanged {
+		log.FromContext(ctx).Info("Re-issued kube-apiserver certificate for control plane endpoint", "endpoint", host)
+		c.events.Publish(apiv1.EventCertificateReissued, fmt.Sprintf("Re-issued kube-apiserver certificate for control plane endpoint %s", host), map[string]string{
+			"certificate": "kube-apiserver",
+			"endpoint":    host,
+		})
+		if err := c.snap.RestartService(ctx, "kube-apiserver"); err != nil {
+			return fmt.Errorf("failed to restart kube-apiserver to apply configuration: %w", err)
+		}
+	}
 	return nil
 }
@@ -8,7 +8,10 @@ import (
 	"testing"
 	"time"
 
+	apiv1 "github.com/canonical/k8s/api/v1"
 	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap/mock"
@@ -20,13 +23,18 @@ import (
 const channelSendTimeout = 100 * time.Millisecond
 
 type configProvider struct {
-	config types.ClusterConfig
+	config        types.ClusterConfig
+	inMaintenance bool
 }
 
 func (c *configProvider) getConfig(ctx context.Context) (types.ClusterConfig, error) {
 	return c.config, nil
 }
 
+func (c *configProvider) isInMaintenance(ctx context.Context) (bool, error) {
+	return c.inMaintenance, nil
+}
+
 func TestControlPlaneConfigController(t *testing.T) {
 	t.Run("ControlPlane", func(t *testing.T) {
 		dir := t.TempDir()
@@ -49,8 +57,8 @@ func TestControlPlaneConfigController(t *testing.T) {
 		triggerCh := make(chan time.Time)
 		configProvider := &configProvider{}
 
-		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh)
-		go ctrl.Run(ctx, configProvider.getConfig)
+		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh, nil)
+		go ctrl.Run(ctx, configProvider.getConfig, configProvider.isInMaintenance)
 
 		for _, tc := range []struct {
 			name   string
@@ -255,8 +263,8 @@ func TestControlPlaneConfigController(t *testing.T) {
 		triggerCh := make(chan time.Time)
 		configProvider := &configProvider{}
 
-		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh)
-		go ctrl.Run(ctx, configProvider.getConfig)
+		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh, nil)
+		go ctrl.Run(ctx, configProvider.getConfig, configProvider.isInMaintenance)
 
 		// mark as worker node
 		g.Expect(snaputil.MarkAsWorkerNode(s, true)).To(Succeed())
@@ -316,4 +324,158 @@ func TestControlPlaneConfigController(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Maintenance", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s := &mock.Snap{
+			Mock: mock.Mock{
+				EtcdPKIDir:          path.Join(dir, "etcd-pki"),
+				ServiceArgumentsDir: path.Join(dir, "args"),
+				LockFilesDir:        path.Join(dir, "locks"),
+				UID:                 os.Getuid(),
+				GID:                 os.Getgid(),
+			},
+		}
+
+		g := NewWithT(t)
+		g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		triggerCh := make(chan time.Time)
+		configProvider := &configProvider{inMaintenance: true}
+
+		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh, nil)
+		go ctrl.Run(ctx, configProvider.getConfig, configProvider.isInMaintenance)
+
+		configProvider.config = types.ClusterConfig{
+			Kubelet: types.Kubelet{
+				CloudProvider: utils.Pointer("external"),
+			},
+		}
+
+		trigger := func() {
+			select {
+			case triggerCh <- time.Now():
+			case <-time.After(channelSendTimeout):
+				g.Fail("Timed out while attempting to trigger controller reconcile loop")
+			}
+
+			// TODO: this should be changed to call g.Eventually()
+			<-time.After(50 * time.Millisecond)
+		}
+
+		trigger()
+		g.Expect(s.RestartServiceCalledWith).To(BeEmpty())
+		_, err := snaputil.GetServiceArgument(s, "kube-controller-manager", "--cloud-provider")
+		g.Expect(err).To(HaveOccurred())
+
+		// changes are applied after the node exits maintenance
+		configProvider.inMaintenance = false
+		trigger()
+		g.Expect(s.RestartServiceCalledWith).To(ConsistOf("kube-controller-manager"))
+		val, err := snaputil.GetServiceArgument(s, "kube-controller-manager", "--cloud-provider")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(val).To(Equal("external"))
+	})
+	t.Run("ExternalCAWithoutKey", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s := &mock.Snap{
+			Mock: mock.Mock{
+				KubernetesPKIDir:    path.Join(dir, "pki"),
+				ServiceArgumentsDir: path.Join(dir, "args"),
+				UID:                 os.Getuid(),
+				GID:                 os.Getgid(),
+			},
+		}
+
+		g := NewWithT(t)
+		g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+		certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{Hostname: "h1", Years: 1, AllowSelfSignedCA: true})
+		g.Expect(certificates.CompleteCertificates()).To(Succeed())
+		g.Expect(os.WriteFile(path.Join(dir, "pki", "apiserver.crt"), []byte(certificates.APIServerCert), 0600)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		triggerCh := make(chan time.Time)
+		configProvider := &configProvider{config: types.ClusterConfig{
+			APIServer: types.APIServer{ControlPlaneEndpoint: utils.Pointer("10.0.0.100:6443")},
+			// the certificate cannot be re-issued without the CA key
+			Certificates: types.Certificates{CACert: utils.Pointer(certificates.CACert)},
+			Kubelet:      types.Kubelet{CloudProvider: utils.Pointer("external")},
+		}}
+
+		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh, nil)
+		go ctrl.Run(ctx, configProvider.getConfig, configProvider.isInMaintenance)
+
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Fail("Timed out while attempting to trigger controller reconcile loop")
+		}
+
+		// TODO: this should be changed to call g.Eventually()
+		<-time.After(50 * time.Millisecond)
+
+		// the rest of the configuration is still applied
+		g.Expect(s.RestartServiceCalledWith).To(ConsistOf("kube-controller-manager"))
+		g.Expect(snaputil.GetServiceArgument(s, "kube-controller-manager", "--cloud-provider")).To(Equal("external"))
+
+		b, err := os.ReadFile(path.Join(dir, "pki", "apiserver.crt"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(b)).To(Equal(certificates.APIServerCert))
+	})
+	t.Run("ControlPlaneEndpointCertificate", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s := &mock.Snap{
+			Mock: mock.Mock{
+				KubernetesPKIDir:    path.Join(dir, "pki"),
+				ServiceArgumentsDir: path.Join(dir, "args"),
+				UID:                 os.Getuid(),
+				GID:                 os.Getgid(),
+			},
+		}
+
+		g := NewWithT(t)
+		g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+		certificates := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{Hostname: "h1", Years: 1, AllowSelfSignedCA: true})
+		g.Expect(certificates.CompleteCertificates()).To(Succeed())
+		g.Expect(os.WriteFile(path.Join(dir, "pki", "apiserver.crt"), []byte(certificates.APIServerCert), 0600)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		triggerCh := make(chan time.Time)
+		configProvider := &configProvider{config: types.ClusterConfig{
+			APIServer:    types.APIServer{ControlPlaneEndpoint: utils.Pointer("10.0.0.100:6443")},
+			Certificates: types.Certificates{CACert: utils.Pointer(certificates.CACert), CAKey: utils.Pointer(certificates.CAKey)},
+		}}
+		broker := events.NewBroker(10)
+
+		ctrl := controllers.NewControlPlaneConfigurationController(s, func() {}, triggerCh, broker)
+		go ctrl.Run(ctx, configProvider.getConfig, configProvider.isInMaintenance)
+
+		select {
+		case triggerCh <- time.Now():
+		case <-time.After(channelSendTimeout):
+			g.Fail("Timed out while attempting to trigger controller reconcile loop")
+		}
+
+		// the event is published before kube-apiserver is restarted
+		g.Eventually(func() []string { return s.RestartServiceCalledWith }, 5*time.Second).Should(ConsistOf("kube-apiserver"))
+		b, err := os.ReadFile(path.Join(dir, "pki", "apiserver.crt"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(b)).ToNot(Equal(certificates.APIServerCert))
+
+		published := broker.Events(0)
+		g.Expect(published).To(HaveLen(1))
+		g.Expect(published[0].Type).To(Equal(apiv1.EventCertificateReissued))
+		g.Expect(published[0].Details).To(Equal(map[string]string{"certificate": "kube-apiserver", "endpoint": "10.0.0.100"}))
+	})
 }
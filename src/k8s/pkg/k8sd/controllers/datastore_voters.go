@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/canonical/k8s/pkg/snap"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+)
+
+// datastoreLostTimeout is how long a k8s-dqlite member must be unreachable before it is considered lost, so that
+// short network interruptions do not demote voters.
+const datastoreLostTimeout = 90 * time.Second
+
+// DatastoreNode is a control plane node that is a member of the k8s-dqlite cluster.
+type DatastoreNode struct {
+	// Name is the name of the node.
+	Name string
+	// Local is true for the node that runs the controller.
+	Local bool
+	// InMaintenance is true if the node is in maintenance. Nodes in maintenance are not promoted to voters, and voters in
+	// maintenance are demoted to stand-by if another node can replace them.
+	InMaintenance bool
+	// Pinned is true if the role of the node was set manually. The controller does not change pinned roles.
+	Pinned bool
+}
+
+// DatastoreVoterController keeps the configured number of k8s-dqlite voters, spread across the zones of the control plane nodes.
+// Voters that are unreachable for datastoreLostTimeout are replaced by stand-by or spare nodes. Only the k8s-dqlite leader changes the roles of the members.
+// Roles that were set manually with "k8s datastore promote" or "k8s datastore demote" are pinned and left unchanged.
+type DatastoreVoterController struct {
+	snap      snap.Snap
+	waitReady func()
+	triggerCh <-chan time.Time
+	events    *events.Broker
+
+	// unreachableSince is when each unreachable k8s-dqlite member was first found unreachable, by address.
+	unreachableSince map[string]time.Time
+}
+
+// NewDatastoreVoterController creates a new controller.
+// triggerCh is typically a `time.NewTicker(<duration>).C`
+// events receives an event for every role change. It is optional.
+func NewDatastoreVoterController(snap snap.Snap, waitReady func(), triggerCh <-chan time.Time, events *events.Broker) *DatastoreVoterController {
+	return &DatastoreVoterController{
+		snap:      snap,
+		waitReady: waitReady,
+		triggerCh: triggerCh,
+		events:    events,
+
+		unreachableSince: make(map[string]time.Time),
+	}
+}
+
+// Run starts the controller.
+// Run accepts a context to manage the lifecycle of the controller.
+// Run accepts a function that retrieves the current cluster configuration.
+// Run accepts a function that lists the control plane nodes by their k8s-dqlite address.
+// Run will loop every time the trigger channel is triggered.
+func (c *DatastoreVoterController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), getNodes func(context.Context, types.ClusterConfig) (map[string]DatastoreNode, error)) {
+	c.waitReady()
+	ctx = log.WithComponent(ctx, "datastore-voter-controller")
+	logger := log.FromContext(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.triggerCh:
+		}
+
+		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
+			logger.Error("Failed to check if this is a worker node", "error", err)
+			continue
+		} else if isWorker {
+			logger.Info("Stopping controller as this is a worker node")
+			return
+		}
+
+		config, err := getClusterConfig(ctx)
+		if err != nil {
+			logger.Error("Failed to retrieve cluster config", "error", err)
+			continue
+		}
+		if config.Datastore.GetType() != "k8s-dqlite" {
+			logger.Info("Stopping controller as the cluster does not use k8s-dqlite")
+			return
+		}
+
+		nodes, err := getNodes(ctx, config)
+		if err != nil {
+			logger.Error("Failed to list control plane nodes", "error", err)
+			continue
+		}
+
+		if err := c.reconcile(ctx, config, nodes); err != nil {
+			logger.Error("Failed to reconcile k8s-dqlite voters", "error", err)
+		}
+	}
+}
+
+func (c *DatastoreVoterController) reconcile(ctx context.Context, config types.ClusterConfig, nodes map[string]DatastoreNode) error {
+	logger := log.FromContext(ctx)
+
+	client, err := c.snap.K8sDqliteClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create k8s-dqlite client: %w", err)
+	}
+
+	leader, err := client.Leader(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve k8s-dqlite leader: %w", err)
+	}
+	if leader == nil || !nodes[leader.Address].Local {
+		return nil
+	}
+
+	members, err := client.CheckHealth(ctx, dqlite.HealthTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to check k8s-dqlite members: %w", err)
+	}
+	if unreachable := c.trackUnreachable(members, time.Now()); len(unreachable) > 0 {
+		logger.Info("Waiting for unreachable k8s-dqlite members to recover before changing roles", "addresses", unreachable, "timeout", datastoreLostTimeout)
+		return nil
+	}
+
+	// without zones, lost voters are still replaced
+	var zones map[string]string
+	if kubeClient, err := c.snap.KubernetesClient(""); err != nil {
+		logger.Warn("Failed to create Kubernetes client, ignoring the zones of the nodes", "error", err)
+	} else if zones, err = kubeClient.GetNodeZones(ctx); err != nil {
+		logger.Warn("Failed to retrieve the zones of the nodes, ignoring them", "error", err)
+	}
+
+	placement := make([]dqlite.PlacementNode, 0, len(members))
+	for _, member := range members {
+		node, ok := nodes[member.Address]
+		placement = append(placement, dqlite.PlacementNode{
+			NodeInfo:      member.NodeInfo,
+			FailureDomain: zones[node.Name],
+			Available:     ok && member.Reachable && !node.InMaintenance,
+			Lost:          !member.Reachable,
+			Pinned:        ok && node.Pinned,
+		})
+	}
+
+	for _, change := range dqlite.PlanVoters(placement, dqlite.Voters) {
+		name := nodes[change.Address].Name
+		logger.Info("Changing k8s-dqlite role", "node", name, "address", change.Address, "zone", zones[name], "from", change.NodeInfo.Role, "to", change.Role)
+		if err := client.AssignRole(ctx, change.Address, change.Role); err != nil {
+			return fmt.Errorf("failed to assign %s role to %s: %w", change.Role, change.Address, err)
+		}
+		c.events.Publish(apiv1.EventDatastoreVotersRebalanced, fmt.Sprintf("Datastore role of %s changed from %s to %s", name, change.NodeInfo.Role, change.Role), map[string]string{
+			"node": name, "zone": zones[name], "old-role": change.NodeInfo.Role.String(), "new-role": change.Role.String(),
+		})
+	}
+	return nil
+}
+
+// trackUnreachable records since when members are unreachable. It returns the addresses of the members that have been
+// unreachable for less than datastoreLostTimeout, which may still recover.
+func (c *DatastoreVoterController) trackUnreachable(members []dqlite.MemberHealth, now time.Time) []string {
+	unreachableSince := make(map[string]time.Time, len(c.unreachableSince))
+	var recovering []string
+	for _, member := range members {
+		if member.Reachable {
+			continue
+		}
+		since, ok := c.unreachableSince[member.Address]
+		if !ok {
+			since = now
+		}
+		unreachableSince[member.Address] = since
+		if now.Sub(since) < datastoreLostTimeout {
+			recovering = append(recovering, member.Address)
+		}
+	}
+	c.unreachableSince = unreachableSince
+	return recovering
+}
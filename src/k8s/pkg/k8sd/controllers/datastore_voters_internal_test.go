@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/client/dqlite"
+	. "github.com/onsi/gomega"
+)
+
+func TestTrackUnreachable(t *testing.T) {
+	g := NewWithT(t)
+
+	c := NewDatastoreVoterController(nil, func() {}, nil, nil)
+	member := func(address string, reachable bool) dqlite.MemberHealth {
+		return dqlite.MemberHealth{NodeInfo: dqlite.NodeInfo{Address: address}, Reachable: reachable}
+	}
+	start := time.Now()
+
+	// a member that is unreachable may still recover
+	g.Expect(c.trackUnreachable([]dqlite.MemberHealth{member("n1", true), member("n2", false)}, start)).To(Equal([]string{"n2"}))
+	g.Expect(c.trackUnreachable([]dqlite.MemberHealth{member("n1", true), member("n2", false)}, start.Add(datastoreLostTimeout/2))).To(Equal([]string{"n2"}))
+
+	// a member that answers again starts over
+	g.Expect(c.trackUnreachable([]dqlite.MemberHealth{member("n1", true), member("n2", true)}, start.Add(datastoreLostTimeout*3/4))).To(BeEmpty())
+	g.Expect(c.trackUnreachable([]dqlite.MemberHealth{member("n1", true), member("n2", false)}, start.Add(datastoreLostTimeout))).To(Equal([]string{"n2"}))
+
+	// a member is lost after being unreachable for datastoreLostTimeout
+	g.Expect(c.trackUnreachable([]dqlite.MemberHealth{member("n1", true), member("n2", false)}, start.Add(2*datastoreLostTimeout))).To(BeEmpty())
+}
@@ -0,0 +1,72 @@
+package controllers_test
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/k8sd/controllers"
+	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap/mock"
+	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestDatastoreVoterController(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		datastore string
+		worker    bool
+	}{
+		{name: "ExternalDatastore", datastore: "external"},
+		{name: "Worker", datastore: "k8s-dqlite", worker: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			s := &mock.Snap{
+				Mock: mock.Mock{
+					LockFilesDir: path.Join(dir, "locks"),
+				},
+			}
+			g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+			if tc.worker {
+				g.Expect(snaputil.MarkAsWorkerNode(s, true)).To(Succeed())
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			triggerCh := make(chan time.Time)
+			getConfig := func(context.Context) (types.ClusterConfig, error) {
+				return types.ClusterConfig{Datastore: types.Datastore{Type: utils.Pointer(tc.datastore)}}, nil
+			}
+			var getNodesCalled bool
+			getNodes := func(context.Context, types.ClusterConfig) (map[string]controllers.DatastoreNode, error) {
+				getNodesCalled = true
+				return nil, nil
+			}
+
+			ctrl := controllers.NewDatastoreVoterController(s, func() {}, triggerCh, nil)
+			stopped := make(chan struct{})
+			go func() {
+				ctrl.Run(ctx, getConfig, getNodes)
+				close(stopped)
+			}()
+
+			select {
+			case triggerCh <- time.Now():
+			case <-time.After(channelSendTimeout):
+				g.Fail("Timed out while attempting to trigger controller reconcile loop")
+			}
+
+			// the controller stops without listing the nodes
+			g.Eventually(stopped).Should(BeClosed())
+			g.Expect(getNodesCalled).To(BeFalse())
+		})
+	}
+}
@@ -3,11 +3,14 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/events"
 	"github.com/canonical/k8s/pkg/k8sd/features"
+	node_local_dns "github.com/canonical/k8s/pkg/k8sd/features/node-local-dns"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/utils"
 )
@@ -17,12 +20,14 @@ import (
 type FeatureController struct {
 	snap      snap.Snap
 	waitReady func()
+	events    *events.Broker
 
 	triggerNetworkCh       chan struct{}
 	triggerGatewayCh       chan struct{}
 	triggerIngressCh       chan struct{}
 	triggerLoadBalancerCh  chan struct{}
 	triggerDNSCh           chan struct{}
+	triggerNodeLocalDNSCh  chan struct{}
 	triggerLocalStorageCh  chan struct{}
 	triggerMetricsServerCh chan struct{}
 
@@ -31,6 +36,7 @@ type FeatureController struct {
 	reconciledIngressCh       chan struct{}
 	reconciledLoadBalancerCh  chan struct{}
 	reconciledDNSCh           chan struct{}
+	reconciledNodeLocalDNSCh  chan struct{}
 	reconciledLocalStorageCh  chan struct{}
 	reconciledMetricsServerCh chan struct{}
 }
@@ -44,19 +50,25 @@ type FeatureControllerOpts struct {
 	TriggerIngressCh       chan struct{}
 	TriggerLoadBalancerCh  chan struct{}
 	TriggerDNSCh           chan struct{}
+	TriggerNodeLocalDNSCh  chan struct{}
 	TriggerLocalStorageCh  chan struct{}
 	TriggerMetricsServerCh chan struct{}
+
+	// Events receives an event when a feature is reconciled or fails to reconcile. Optional.
+	Events *events.Broker
 }
 
 func NewFeatureController(opts FeatureControllerOpts) *FeatureController {
 	return &FeatureController{
 		snap:                      opts.Snap,
 		waitReady:                 opts.WaitReady,
+		events:                    opts.Events,
 		triggerNetworkCh:          opts.TriggerNetworkCh,
 		triggerGatewayCh:          opts.TriggerGatewayCh,
 		triggerIngressCh:          opts.TriggerIngressCh,
 		triggerLoadBalancerCh:     opts.TriggerLoadBalancerCh,
 		triggerDNSCh:              opts.TriggerDNSCh,
+		triggerNodeLocalDNSCh:     opts.TriggerNodeLocalDNSCh,
 		triggerLocalStorageCh:     opts.TriggerLocalStorageCh,
 		triggerMetricsServerCh:    opts.TriggerMetricsServerCh,
 		reconciledNetworkCh:       make(chan struct{}, 1),
@@ -64,40 +76,42 @@ func NewFeatureController(opts FeatureControllerOpts) *FeatureController {
 		reconciledIngressCh:       make(chan struct{}, 1),
 		reconciledLoadBalancerCh:  make(chan struct{}, 1),
 		reconciledDNSCh:           make(chan struct{}, 1),
+		reconciledNodeLocalDNSCh:  make(chan struct{}, 1),
 		reconciledLocalStorageCh:  make(chan struct{}, 1),
 		reconciledMetricsServerCh: make(chan struct{}, 1),
 	}
 }
 
-func (c *FeatureController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), notifyDNSChangedIP func(ctx context.Context, dnsIP string) error) {
+func (c *FeatureController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), notifyDNSChangedIP func(ctx context.Context, dnsIP string) error, notifyNodeLocalDNSReady func()) {
 	c.waitReady()
+	ctx = log.WithComponent(ctx, "feature-controller")
 
-	go c.reconcileLoop(ctx, getClusterConfig, "network", c.triggerNetworkCh, c.reconciledNetworkCh, func(cfg types.ClusterConfig) error {
-		return features.Implementation.ApplyNetwork(ctx, c.snap, cfg.Network)
+	go c.reconcileLoop(ctx, getClusterConfig, "network", c.triggerNetworkCh, c.reconciledNetworkCh, func(ctx context.Context, cfg types.ClusterConfig) error {
+		return features.Implementation.ApplyNetwork(ctx, c.snap, cfg.Network, cfg.Containerd.GetImageRegistry())
 	})
 
-	go c.reconcileLoop(ctx, getClusterConfig, "gateway", c.triggerGatewayCh, c.reconciledGatewayCh, func(cfg types.ClusterConfig) error {
+	go c.reconcileLoop(ctx, getClusterConfig, "gateway", c.triggerGatewayCh, c.reconciledGatewayCh, func(ctx context.Context, cfg types.ClusterConfig) error {
 		return features.Implementation.ApplyGateway(ctx, c.snap, cfg.Gateway, cfg.Network)
 	})
 
-	go c.reconcileLoop(ctx, getClusterConfig, "ingress", c.triggerIngressCh, c.reconciledIngressCh, func(cfg types.ClusterConfig) error {
+	go c.reconcileLoop(ctx, getClusterConfig, "ingress", c.triggerIngressCh, c.reconciledIngressCh, func(ctx context.Context, cfg types.ClusterConfig) error {
 		return features.Implementation.ApplyIngress(ctx, c.snap, cfg.Ingress, cfg.Network)
 	})
 
-	go c.reconcileLoop(ctx, getClusterConfig, "load balancer", c.triggerLoadBalancerCh, c.reconciledLoadBalancerCh, func(cfg types.ClusterConfig) error {
+	go c.reconcileLoop(ctx, getClusterConfig, "load-balancer", c.triggerLoadBalancerCh, c.reconciledLoadBalancerCh, func(ctx context.Context, cfg types.ClusterConfig) error {
 		return features.Implementation.ApplyLoadBalancer(ctx, c.snap, cfg.LoadBalancer, cfg.Network)
 	})
 
-	go c.reconcileLoop(ctx, getClusterConfig, "local storage", c.triggerLocalStorageCh, c.reconciledLocalStorageCh, func(cfg types.ClusterConfig) error {
-		return features.Implementation.ApplyLocalStorage(ctx, c.snap, cfg.LocalStorage)
+	go c.reconcileLoop(ctx, getClusterConfig, "local-storage", c.triggerLocalStorageCh, c.reconciledLocalStorageCh, func(ctx context.Context, cfg types.ClusterConfig) error {
+		return features.Implementation.ApplyLocalStorage(ctx, c.snap, cfg.LocalStorage, cfg.Containerd.GetImageRegistry())
 	})
 
-	go c.reconcileLoop(ctx, getClusterConfig, "metrics server", c.triggerMetricsServerCh, c.reconciledMetricsServerCh, func(cfg types.ClusterConfig) error {
-		return features.Implementation.ApplyMetricsServer(ctx, c.snap, cfg.MetricsServer)
+	go c.reconcileLoop(ctx, getClusterConfig, "metrics-server", c.triggerMetricsServerCh, c.reconciledMetricsServerCh, func(ctx context.Context, cfg types.ClusterConfig) error {
+		return features.Implementation.ApplyMetricsServer(ctx, c.snap, cfg.MetricsServer, cfg.Containerd.GetImageRegistry())
 	})
 
-	go c.reconcileLoop(ctx, getClusterConfig, "DNS", c.triggerDNSCh, c.reconciledDNSCh, func(cfg types.ClusterConfig) error {
-		if dnsIP, err := features.Implementation.ApplyDNS(ctx, c.snap, cfg.DNS, cfg.Kubelet); err != nil {
+	go c.reconcileLoop(ctx, getClusterConfig, "dns", c.triggerDNSCh, c.reconciledDNSCh, func(ctx context.Context, cfg types.ClusterConfig) error {
+		if dnsIP, err := features.Implementation.ApplyDNS(ctx, c.snap, cfg.DNS, cfg.Kubelet, cfg.Containerd.GetImageRegistry()); err != nil {
 			return fmt.Errorf("failed to apply DNS configuration: %w", err)
 		} else if dnsIP != "" {
 			if err := notifyDNSChangedIP(ctx, dnsIP); err != nil {
@@ -106,32 +120,82 @@ func (c *FeatureController) Run(ctx context.Context, getClusterConfig func(conte
 		}
 		return nil
 	})
+
+	go c.reconcileLoop(ctx, getClusterConfig, "node-local-dns", c.triggerNodeLocalDNSCh, c.reconciledNodeLocalDNSCh, func(ctx context.Context, cfg types.ClusterConfig) error {
+		if err := features.Implementation.ApplyNodeLocalDNS(ctx, c.snap, cfg.NodeLocalDNS, cfg.Kubelet, cfg.Containerd.GetImageRegistry()); err != nil {
+			return err
+		}
+		if cfg.NodeLocalDNS.GetEnabled() {
+			// kubelets switch to the node-local-dns address once the cache is available on all nodes
+			if err := c.waitNodeLocalDNSReady(ctx); err != nil {
+				return fmt.Errorf("node-local-dns is not ready: %w", err)
+			}
+			notifyNodeLocalDNSReady()
+		}
+		return nil
+	})
+}
+
+// waitNodeLocalDNSReady blocks until the node-local-dns daemonset has rolled out to all nodes.
+func (c *FeatureController) waitNodeLocalDNSReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	client, err := c.snap.KubernetesClient("kube-system")
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	for {
+		if ready, err := client.IsDaemonSetReady(ctx, node_local_dns.DaemonSetName, "kube-system"); err != nil {
+			log.FromContext(ctx).Error("Failed to check node-local-dns rollout", "error", err)
+		} else if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
 }
 
-func (c *FeatureController) reconcile(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), apply func(cfg types.ClusterConfig) error) error {
+func (c *FeatureController) reconcile(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), apply func(ctx context.Context, cfg types.ClusterConfig) error) error {
 	cfg, err := getClusterConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve cluster configuration: %w", err)
 	}
 
-	if err := apply(cfg); err != nil {
+	if err := apply(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to apply configuration: %w", err)
 	}
 	return nil
 }
 
-func (c *FeatureController) reconcileLoop(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), componentName string, triggerCh chan struct{}, reconciledCh chan<- struct{}, apply func(cfg types.ClusterConfig) error) {
+func (c *FeatureController) reconcileLoop(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error), componentName string, triggerCh chan struct{}, reconciledCh chan<- struct{}, apply func(ctx context.Context, cfg types.ClusterConfig) error) {
+	logger := log.FromContext(ctx).With("feature", componentName)
+	ctx = log.NewContext(ctx, logger)
+
+	// lastError avoids publishing the same failure on every retry
+	var lastError string
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-triggerCh:
 			if err := c.reconcile(ctx, getClusterConfig, apply); err != nil {
-				log.Printf("failed to reconcile %s configuration, will retry in 5 seconds: %v", componentName, err)
+				logger.Error("Failed to reconcile feature, will retry in 5 seconds", "error", err)
+				if err.Error() != lastError {
+					lastError = err.Error()
+					c.events.Publish(apiv1.EventFeatureFailed, fmt.Sprintf("Failed to reconcile %s, retrying: %v", componentName, err), map[string]string{"feature": componentName, "error": err.Error()})
+				}
 
 				// notify triggerCh after 5 seconds to retry
 				time.AfterFunc(5*time.Second, func() { utils.MaybeNotify(triggerCh) })
 			} else {
+				lastError = ""
+				logger.Debug("Reconciled feature")
+				c.events.Publish(apiv1.EventFeatureReconciled, fmt.Sprintf("Reconciled %s", componentName), map[string]string{"feature": componentName})
 				utils.MaybeNotify(reconciledCh)
 			}
 
@@ -4,11 +4,12 @@ import (
 	"context"
 	"crypto/rsa"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/setup"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
 	v1 "k8s.io/api/core/v1"
@@ -41,21 +42,38 @@ func (c *NodeConfigurationController) retryNewK8sClient(ctx context.Context) (*k
 	}
 }
 
-func (c *NodeConfigurationController) Run(ctx context.Context, getRSAKey func(context.Context) (*rsa.PublicKey, error)) {
+// Run accepts a function that returns true if the node is in maintenance.
+// Changes are not applied while the node is in maintenance. The configmap is watched again until the node exits maintenance.
+func (c *NodeConfigurationController) Run(ctx context.Context, getRSAKey func(context.Context) (*rsa.PublicKey, error), isInMaintenance func(context.Context) (bool, error)) {
 	// wait for microcluster node to be ready
 	c.waitReady()
+	ctx = log.WithComponent(ctx, "node-configuration-controller")
+	logger := log.FromContext(ctx)
 
 	for {
 		client, err := c.retryNewK8sClient(ctx)
 		if err != nil {
-			log.Println(fmt.Errorf("failed to create a Kubernetes client: %w", err))
+			logger.Error("Failed to create a Kubernetes client", "error", err)
 		}
 
-		if err := client.WatchConfigMap(ctx, "kube-system", "k8sd-config", func(configMap *v1.ConfigMap) error { return c.reconcile(ctx, configMap, getRSAKey) }); err != nil {
+		// the watch is stopped while the node is in maintenance, the new watch reconciles the current configmap again
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		if err := client.WatchConfigMap(watchCtx, "kube-system", "k8sd-config", func(configMap *v1.ConfigMap) error {
+			if inMaintenance, err := isInMaintenance(ctx); err != nil {
+				stopWatch()
+				return fmt.Errorf("failed to check if this node is in maintenance: %w", err)
+			} else if inMaintenance {
+				logger.Debug("Skipping reconcile as this node is in maintenance")
+				stopWatch()
+				return nil
+			}
+			return c.reconcile(ctx, client, configMap, getRSAKey)
+		}); err != nil {
 			// This also can fail during bootstrapping/start up when api-server is not ready
 			// So the watch requests get connection refused replies
-			log.Println(fmt.Errorf("failed to watch configmap: %w", err))
+			logger.Warn("Failed to watch configmap", "namespace", "kube-system", "name", "k8sd-config", "error", err)
 		}
+		stopWatch()
 
 		select {
 		case <-ctx.Done():
@@ -65,7 +83,7 @@ func (c *NodeConfigurationController) Run(ctx context.Context, getRSAKey func(co
 	}
 }
 
-func (c *NodeConfigurationController) reconcile(ctx context.Context, configMap *v1.ConfigMap, getRSAKey func(context.Context) (*rsa.PublicKey, error)) error {
+func (c *NodeConfigurationController) reconcile(ctx context.Context, client *kubernetes.Client, configMap *v1.ConfigMap, getRSAKey func(context.Context) (*rsa.PublicKey, error)) error {
 	key, err := getRSAKey(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load the RSA public key: %w", err)
@@ -109,5 +127,101 @@ func (c *NodeConfigurationController) reconcile(ctx context.Context, configMap *
 		}
 	}
 
+	if err := c.reconcileContainerd(ctx, client, configMap, key); err != nil {
+		return fmt.Errorf("failed to apply containerd configuration: %w", err)
+	}
+
+	if err := c.reconcileAPIServerProxy(ctx, configMap, key); err != nil {
+		return fmt.Errorf("failed to apply k8s-apiserver-proxy configuration: %w", err)
+	}
+
+	return nil
+}
+
+func (c *NodeConfigurationController) reconcileContainerd(ctx context.Context, client *kubernetes.Client, configMap *v1.ConfigMap, key *rsa.PublicKey) error {
+	config, err := types.ContainerdFromConfigMap(configMap.Data, key)
+	if err != nil {
+		return fmt.Errorf("failed to parse configmap data to containerd config: %w", err)
+	}
+	if config.Registries != nil {
+		secretData, err := client.GetSecretData(ctx, "kube-system", types.ContainerdRegistryCredentialsSecret)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve registry credentials: %w", err)
+		}
+		// the secret does not exist until the control plane nodes are upgraded
+		if secretData != nil {
+			credentials, err := types.ContainerdRegistryCredentialsFromSecret(secretData, key)
+			if err != nil {
+				return fmt.Errorf("failed to parse registry credentials: %w", err)
+			}
+			config = config.WithRegistryCredentials(credentials)
+		}
+	}
+
+	var mustRestartContainerd bool
+	if config.ImageRegistry != nil {
+		mustRestart, err := setup.ContainerdConfig(c.snap, config.GetImageRegistry())
+		if err != nil {
+			return fmt.Errorf("failed to update containerd configuration: %w", err)
+		}
+		mustRestartContainerd = mustRestartContainerd || mustRestart
+	}
+	if config.Registries != nil {
+		mustRestart, err := setup.ContainerdRegistries(c.snap, config.GetRegistries())
+		if err != nil {
+			return fmt.Errorf("failed to update containerd registries: %w", err)
+		}
+		mustRestartContainerd = mustRestartContainerd || mustRestart
+	}
+	// runtime handlers are not published once the last one is removed, so the drop-in is also removed when they are unset
+	mustRestart, err := setup.ContainerdRuntimeHandlers(c.snap, config.GetRuntimeHandlers())
+	if err != nil {
+		return fmt.Errorf("failed to update containerd runtime handlers: %w", err)
+	}
+	mustRestartContainerd = mustRestartContainerd || mustRestart
+
+	if mustRestartContainerd {
+		if err := c.snap.RestartService(ctx, "containerd"); err != nil {
+			return fmt.Errorf("failed to restart containerd to apply node configuration: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileAPIServerProxy points k8s-apiserver-proxy on worker nodes to the control plane endpoint, if one is set for workers.
+// It also migrates the credentials of k8s-apiserver-proxy on existing worker nodes, see setup.K8sAPIServerProxyCredentials.
+func (c *NodeConfigurationController) reconcileAPIServerProxy(ctx context.Context, configMap *v1.ConfigMap, key *rsa.PublicKey) error {
+	if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
+		return fmt.Errorf("failed to check if this is a worker node: %w", err)
+	} else if !isWorker {
+		// control plane nodes do not run k8s-apiserver-proxy
+		return nil
+	}
+
+	// workers that joined before EndpointSlices were watched use the node credentials, which cannot watch them
+	mustRestart, err := setup.K8sAPIServerProxyCredentials(c.snap)
+	if err != nil {
+		return fmt.Errorf("failed to update k8s-apiserver-proxy credentials: %w", err)
+	}
+
+	endpoint, err := types.WorkerEndpointFromConfigMap(configMap.Data, key)
+	if err != nil {
+		return fmt.Errorf("failed to parse configmap data to control plane endpoint: %w", err)
+	}
+	if endpoint != nil {
+		endpointChanged, err := setup.K8sAPIServerProxyControlPlaneEndpoint(c.snap, *endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to update k8s-apiserver-proxy control plane endpoint: %w", err)
+		}
+		mustRestart = mustRestart || endpointChanged
+	}
+
+	if mustRestart {
+		if err := c.snap.RestartService(ctx, "k8s-apiserver-proxy"); err != nil {
+			return fmt.Errorf("failed to restart k8s-apiserver-proxy to apply node configuration: %w", err)
+		}
+	}
+
 	return nil
 }
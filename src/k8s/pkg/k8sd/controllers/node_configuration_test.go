@@ -2,16 +2,20 @@ package controllers
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/rsa"
 	"os"
 	"path"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/canonical/k8s/pkg/client/kubernetes"
 	"github.com/canonical/k8s/pkg/k8sd/setup"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap/mock"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -130,7 +134,7 @@ func TestConfigPropagation(t *testing.T) {
 	ctrl := NewNodeConfigurationController(s, func() {})
 
 	// TODO: add test with signing key
-	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil })
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil }, func(ctx context.Context) (bool, error) { return false, nil })
 	defer watcher.Stop()
 
 	for _, tc := range tests {
@@ -159,3 +163,342 @@ func TestConfigPropagation(t *testing.T) {
 		})
 	}
 }
+
+func TestControlPlaneEndpointPropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	tests := []struct {
+		name                  string
+		data                  map[string]string
+		expectRefreshInterval string
+		expectRestart         bool
+	}{
+		{
+			name:          "UseForWorkers",
+			data:          map[string]string{"apiserver-worker-endpoint": "10.0.0.100:6443"},
+			expectRestart: true,
+			// the proxy does not watch the kube-apiserver endpoints of the cluster
+			expectRefreshInterval: "0",
+		},
+		{
+			name:                  "Unchanged",
+			data:                  map[string]string{"apiserver-worker-endpoint": "10.0.0.100:6443"},
+			expectRefreshInterval: "0",
+		},
+		{
+			name:                  "Missing",
+			data:                  map[string]string{"cluster-dns": "10.152.1.1"},
+			expectRefreshInterval: "0",
+		},
+		{
+			name:          "NotForWorkers",
+			data:          map[string]string{"apiserver-worker-endpoint": ""},
+			expectRestart: true,
+		},
+	}
+
+	clientset := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:   path.Join(dir, "args"),
+			ServiceExtraConfigDir: path.Join(dir, "args/conf.d"),
+			LockFilesDir:          path.Join(dir, "lock"),
+			UID:                   os.Getuid(),
+			GID:                   os.Getgid(),
+			KubernetesNodeClient:  &kubernetes.Client{Interface: clientset},
+		},
+	}
+
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+	g.Expect(snaputil.MarkAsWorkerNode(s, true)).To(Succeed())
+
+	ctrl := NewNodeConfigurationController(s, func() {})
+
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil }, func(ctx context.Context) (bool, error) { return false, nil })
+	defer watcher.Stop()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s.RestartServiceCalledWith = nil
+
+			g := NewWithT(t)
+
+			watcher.Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+				Data:       tc.data,
+			})
+
+			// TODO: this is to ensure that the controller has handled the event. This should ideally
+			// be replaced with something like a "<-sentCh" instead
+			time.Sleep(100 * time.Millisecond)
+
+			g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--refresh-interval")).To(Equal(tc.expectRefreshInterval))
+			if tc.expectRestart {
+				g.Expect(s.RestartServiceCalledWith).To(ContainElement("k8s-apiserver-proxy"))
+			} else {
+				g.Expect(s.RestartServiceCalledWith).ToNot(ContainElement("k8s-apiserver-proxy"))
+			}
+		})
+	}
+}
+
+func TestAPIServerProxyCredentialsMigration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	clientset := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:   path.Join(dir, "args"),
+			ServiceExtraConfigDir: path.Join(dir, "args/conf.d"),
+			KubernetesConfigDir:   path.Join(dir, "kubernetes"),
+			LockFilesDir:          path.Join(dir, "lock"),
+			UID:                   os.Getuid(),
+			GID:                   os.Getgid(),
+			KubernetesNodeClient:  &kubernetes.Client{Interface: clientset},
+		},
+	}
+
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+	g.Expect(snaputil.MarkAsWorkerNode(s, true)).To(Succeed())
+
+	// workers that joined before EndpointSlices were watched use the node credentials
+	_, err := snaputil.UpdateServiceArguments(s, "k8s-apiserver-proxy", map[string]string{
+		"--kubeconfig": path.Join(dir, "kubernetes", "kubelet.conf"),
+	}, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctrl := NewNodeConfigurationController(s, func() {})
+
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil }, func(ctx context.Context) (bool, error) { return false, nil })
+	defer watcher.Stop()
+
+	watcher.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+		Data:       map[string]string{"cluster-dns": "10.152.1.1"},
+	})
+
+	g.Eventually(func() []string { return s.RestartServiceCalledWith }, time.Second, 10*time.Millisecond).Should(ContainElement("k8s-apiserver-proxy"))
+	g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--kubeconfig")).To(Equal(path.Join(dir, "kubernetes", "proxy.conf")))
+	g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--health-check-kubeconfig")).To(Equal(path.Join(dir, "kubernetes", "kubelet.conf")))
+}
+
+func TestNodeConfigurationMaintenance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	// every watch uses a new watcher, as the watch is stopped while the node is in maintenance
+	watchers := make(chan *watch.FakeWatcher, 1)
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependWatchReactor("configmaps", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFake()
+		watchers <- watcher
+		return true, watcher, nil
+	})
+
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:  path.Join(t.TempDir(), "args"),
+			UID:                  os.Getuid(),
+			GID:                  os.Getgid(),
+			KubernetesNodeClient: &kubernetes.Client{Interface: clientset},
+		},
+	}
+
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+	var inMaintenance atomic.Bool
+	inMaintenance.Store(true)
+
+	ctrl := NewNodeConfigurationController(s, func() {})
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil }, func(ctx context.Context) (bool, error) { return inMaintenance.Load(), nil })
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+		Data:       map[string]string{"cluster-dns": "10.152.1.1"},
+	}
+
+	(<-watchers).Add(configMap)
+	time.Sleep(100 * time.Millisecond)
+
+	g.Expect(s.RestartServiceCalledWith).To(BeEmpty())
+	_, err := snaputil.GetServiceArgument(s, "kubelet", "--cluster-dns")
+	g.Expect(err).To(HaveOccurred())
+
+	// the configmap is reconciled by the next watch after the node exits maintenance
+	inMaintenance.Store(false)
+	select {
+	case watcher := <-watchers:
+		watcher.Add(configMap)
+	case <-time.After(5 * time.Second):
+		g.Fail("Timed out waiting for the configmap to be watched again")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	g.Expect(s.RestartServiceCalledWith).To(Equal([]string{"kubelet"}))
+	val, err := snaputil.GetServiceArgument(s, "kubelet", "--cluster-dns")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(val).To(Equal("10.152.1.1"))
+}
+
+func TestRegistryCredentialsPropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	containerd := types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+		{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Username: "user", Password: "pass"},
+	})}
+	configMapData, err := types.Kubelet{}.ToConfigMap(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	containerdData, err := containerd.ToConfigMap(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	for k, v := range containerdData {
+		configMapData[k] = v
+	}
+	secretData, err := containerd.RegistryCredentialsToSecret(key)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: types.ContainerdRegistryCredentialsSecret, Namespace: "kube-system"},
+		Data:       secretData,
+	})
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:         path.Join(dir, "args"),
+			ContainerdExtraConfigDir:    path.Join(dir, "containerd/conf.d"),
+			ContainerdRegistryConfigDir: path.Join(dir, "containerd/hosts.d"),
+			UID:                         os.Getuid(),
+			GID:                         os.Getgid(),
+			KubernetesNodeClient:        &kubernetes.Client{Interface: clientset},
+		},
+	}
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+	ctrl := NewNodeConfigurationController(s, func() {})
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return &key.PublicKey, nil }, func(ctx context.Context) (bool, error) { return false, nil })
+	defer watcher.Stop()
+
+	g.Expect(configMapData["containerd-registries"]).ToNot(ContainSubstring("pass"))
+	watcher.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+		Data:       configMapData,
+	})
+
+	// the credentials from the secret are written to the containerd configuration
+	g.Eventually(func() string {
+		b, _ := os.ReadFile(path.Join(dir, "containerd/conf.d", "k8sd-auths.toml"))
+		return string(b)
+	}, 5*time.Second).Should(ContainSubstring("pass"))
+	g.Eventually(func() []string { return s.RestartServiceCalledWith }).Should(ContainElement("containerd"))
+}
+
+func TestImageRegistryPropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	clientset := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:  path.Join(dir, "args"),
+			ContainerdConfigDir:  path.Join(dir, "containerd"),
+			UID:                  os.Getuid(),
+			GID:                  os.Getgid(),
+			KubernetesNodeClient: &kubernetes.Client{Interface: clientset},
+		},
+	}
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+
+	ctrl := NewNodeConfigurationController(s, func() {})
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil }, func(ctx context.Context) (bool, error) { return false, nil })
+	defer watcher.Stop()
+
+	watcher.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+		Data:       map[string]string{"containerd-image-registry": "registry.internal:5000"},
+	})
+
+	// the sandbox image is pulled from the new image registry
+	g.Eventually(func() string {
+		b, _ := os.ReadFile(path.Join(dir, "containerd", "config.toml"))
+		return string(b)
+	}, 5*time.Second).Should(ContainSubstring(`sandbox_image = "registry.internal:5000/canonical/k8s-snap/pause:3.10"`))
+	g.Eventually(func() []string { return s.RestartServiceCalledWith }).Should(ContainElement("containerd"))
+}
+
+func TestRuntimeHandlersPropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := NewWithT(t)
+
+	clientset := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	clientset.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir:      path.Join(dir, "args"),
+			ContainerdConfigDir:      path.Join(dir, "containerd"),
+			ContainerdExtraConfigDir: path.Join(dir, "containerd-confd"),
+			UID:                      os.Getuid(),
+			GID:                      os.Getgid(),
+			KubernetesNodeClient:     &kubernetes.Client{Interface: clientset},
+		},
+	}
+	g.Expect(setup.EnsureAllDirectories(s)).To(Succeed())
+	runtimesFile := path.Join(dir, "containerd-confd", "k8sd-runtimes.toml")
+
+	ctrl := NewNodeConfigurationController(s, func() {})
+	go ctrl.Run(ctx, func(ctx context.Context) (*rsa.PublicKey, error) { return nil, nil }, func(ctx context.Context) (bool, error) { return false, nil })
+	defer watcher.Stop()
+
+	watcher.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+		Data:       map[string]string{"containerd-runtime-handlers": `[{"name":"kata","runtime-type":"io.containerd.kata.v2"}]`},
+	})
+	g.Eventually(func() string {
+		b, _ := os.ReadFile(runtimesFile)
+		return string(b)
+	}, 5*time.Second).Should(ContainSubstring("kata"))
+
+	// the runtime handlers are no longer published once they are unset
+	watcher.Modify(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+		Data:       map[string]string{},
+	})
+	g.Eventually(func() bool {
+		_, err := os.Stat(runtimesFile)
+		return os.IsNotExist(err)
+	}, 5*time.Second).Should(BeTrue())
+}
@@ -3,12 +3,13 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/canonical/k8s/pkg/client/kubernetes"
+	node_local_dns "github.com/canonical/k8s/pkg/k8sd/features/node-local-dns"
 	"github.com/canonical/k8s/pkg/k8sd/pki"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
 )
@@ -57,6 +58,8 @@ func (c *UpdateNodeConfigurationController) retryNewK8sClient(ctx context.Contex
 // Run will loop everytime the TriggerCh is triggered.
 func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterConfig func(context.Context) (types.ClusterConfig, error)) {
 	c.waitReady()
+	ctx = log.WithComponent(ctx, "update-node-configuration-controller")
+	logger := log.FromContext(ctx)
 
 	for {
 		select {
@@ -66,26 +69,26 @@ func (c *UpdateNodeConfigurationController) Run(ctx context.Context, getClusterC
 		}
 
 		if isWorker, err := snaputil.IsWorker(c.snap); err != nil {
-			log.Println(fmt.Errorf("failed to check if this is a worker node: %w", err))
+			logger.Error("Failed to check if this is a worker node", "error", err)
 			continue
 		} else if isWorker {
-			log.Println("Stopping UpdateClusterConfig controller as this is a worker node")
+			logger.Info("Stopping controller as this is a worker node")
 			return
 		}
 
 		config, err := getClusterConfig(ctx)
 		if err != nil {
-			log.Println(fmt.Errorf("failed to retrieve cluster config: %w", err))
+			logger.Error("Failed to retrieve cluster config", "error", err)
 			continue
 		}
 
 		client, err := c.retryNewK8sClient(ctx)
 		if err != nil {
-			log.Println(fmt.Errorf("failed to create a Kubernetes client: %w", err))
+			logger.Error("Failed to create a Kubernetes client", "error", err)
 		}
 
 		if err := c.reconcile(ctx, client, config); err != nil {
-			log.Println(fmt.Errorf("failed to reconcile cluster configuration: %w", err))
+			logger.Error("Failed to reconcile cluster configuration", "error", err)
 		}
 
 		// notify downstream that the reconciliation loop is done.
@@ -103,14 +106,57 @@ func (c *UpdateNodeConfigurationController) reconcile(ctx context.Context, clien
 		return fmt.Errorf("failed to load cluster RSA key: %w", err)
 	}
 
-	cmData, err := config.Kubelet.ToConfigMap(key)
+	kubelet, err := node_local_dns.NodeKubelet(ctx, client, config)
+	if err != nil {
+		return fmt.Errorf("failed to check node-local-dns rollout: %w", err)
+	}
+	cmData, err := kubelet.ToConfigMap(key)
 	if err != nil {
 		return fmt.Errorf("failed to format kubelet configmap data: %w", err)
 	}
+	containerdData, err := config.Containerd.ToConfigMap(key)
+	if err != nil {
+		return fmt.Errorf("failed to format containerd configmap data: %w", err)
+	}
+	for k, v := range containerdData {
+		cmData[k] = v
+	}
+	if config.Containerd.Registries != nil {
+		// registry credentials are distributed through a secret, as the configmap can be read by anyone with access to configmaps
+		credentialsData, err := config.Containerd.RegistryCredentialsToSecret(key)
+		if err != nil {
+			return fmt.Errorf("failed to format registry credentials secret data: %w", err)
+		}
+		secret, err := client.UpdateSecret(ctx, "kube-system", types.ContainerdRegistryCredentialsSecret, credentialsData)
+		if err != nil {
+			return fmt.Errorf("failed to update registry credentials: %w", err)
+		}
+		// nodes reconcile the configmap when the credentials change
+		cmData["containerd-registry-credentials-revision"] = secret.ResourceVersion
+	}
+	apiServerData, err := config.APIServer.ToConfigMap(key)
+	if err != nil {
+		return fmt.Errorf("failed to format apiserver configmap data: %w", err)
+	}
+	for k, v := range apiServerData {
+		cmData[k] = v
+	}
 	if _, err := client.UpdateConfigMap(ctx, "kube-system", "k8sd-config", cmData); err != nil {
 		return fmt.Errorf("failed to update node config: %w", err)
 	}
 
+	runtimeClasses := make([]kubernetes.RuntimeClass, 0, len(config.Containerd.GetRuntimeHandlers()))
+	for _, handler := range config.Containerd.GetRuntimeHandlers() {
+		runtimeClasses = append(runtimeClasses, kubernetes.RuntimeClass{
+			Name:         handler.Name,
+			Handler:      handler.Name,
+			NodeSelector: handler.NodeSelector,
+		})
+	}
+	if err := client.UpdateRuntimeClasses(ctx, runtimeClasses); err != nil {
+		return fmt.Errorf("failed to update runtime classes: %w", err)
+	}
+
 	return nil
 }
 
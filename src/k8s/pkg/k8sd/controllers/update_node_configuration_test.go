@@ -13,8 +13,10 @@ import (
 	"github.com/canonical/k8s/pkg/snap/mock"
 	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -104,3 +106,129 @@ func TestUpdateNodeConfigurationController(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateNodeConfigurationRegistryCredentials(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := types.ClusterConfig{
+		Kubelet: types.Kubelet{ClusterDomain: utils.Pointer("cluster.local")},
+		Containerd: types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+			{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Username: "user", Password: "pass"},
+		})},
+	}
+	clientset := fake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: types.ContainerdRegistryCredentialsSecret, Namespace: "kube-system"}},
+	)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ServiceArgumentsDir: path.Join(dir, "args"),
+			UID:                 os.Getuid(),
+			GID:                 os.Getgid(),
+			KubernetesClient:    &kubernetes.Client{Interface: clientset},
+		},
+	}
+	triggerCh := make(chan struct{})
+	defer close(triggerCh)
+
+	ctrl := controllers.NewUpdateNodeConfigurationController(s, func() {}, triggerCh)
+	go ctrl.Run(ctx, (&configProvider{config: config}).getConfig)
+
+	select {
+	case triggerCh <- struct{}{}:
+	case <-time.After(channelSendTimeout):
+		g.Fail("Timed out while attempting to trigger controller reconcile loop")
+	}
+	select {
+	case <-ctrl.ReconciledCh():
+	case <-time.After(channelSendTimeout):
+		g.Fail("Time out while waiting for the reconcile to complete")
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "k8sd-config", metav1.GetOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(configMap.Data).To(HaveKey("containerd-registry-credentials-revision"))
+	g.Expect(configMap.Data["containerd-registries"]).ToNot(ContainSubstring("pass"))
+
+	secret, err := clientset.CoreV1().Secrets("kube-system").Get(ctx, types.ContainerdRegistryCredentialsSecret, metav1.GetOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	credentials, err := types.ContainerdRegistryCredentialsFromSecret(secret.Data, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(credentials).To(Equal(map[string]types.ContainerdRegistryCredentials{"docker.io": {Password: "pass"}}))
+}
+
+func TestUpdateNodeConfigurationNodeLocalDNS(t *testing.T) {
+	readyDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-local-dns", Namespace: "kube-system"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, UpdatedNumberScheduled: 1, NumberAvailable: 1},
+	}
+	unavailableDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-local-dns", Namespace: "kube-system"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, UpdatedNumberScheduled: 1},
+	}
+
+	for _, tc := range []struct {
+		name               string
+		currentClusterDNS  string
+		daemonSet          *appsv1.DaemonSet
+		expectedClusterDNS string
+	}{
+		{name: "NotDeployed", expectedClusterDNS: "10.152.183.10"},
+		{name: "RollingOut", daemonSet: unavailableDaemonSet, expectedClusterDNS: "10.152.183.10"},
+		{name: "Ready", daemonSet: readyDaemonSet, expectedClusterDNS: "169.254.20.10"},
+		{name: "AlreadySwitched", currentClusterDNS: "169.254.20.10", daemonSet: unavailableDaemonSet, expectedClusterDNS: "169.254.20.10"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			config := types.ClusterConfig{
+				Kubelet:      types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10")},
+				NodeLocalDNS: types.NodeLocalDNS{Enabled: utils.Pointer(true), LocalIP: utils.Pointer("169.254.20.10")},
+			}
+			objects := []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "k8sd-config", Namespace: "kube-system"},
+				Data:       map[string]string{"cluster-dns": tc.currentClusterDNS},
+			}}
+			if tc.daemonSet != nil {
+				objects = append(objects, tc.daemonSet)
+			}
+			clientset := fake.NewSimpleClientset(objects...)
+
+			dir := t.TempDir()
+			s := &mock.Snap{
+				Mock: mock.Mock{
+					ServiceArgumentsDir: path.Join(dir, "args"),
+					UID:                 os.Getuid(),
+					GID:                 os.Getgid(),
+					KubernetesClient:    &kubernetes.Client{Interface: clientset},
+				},
+			}
+			triggerCh := make(chan struct{})
+			defer close(triggerCh)
+
+			ctrl := controllers.NewUpdateNodeConfigurationController(s, func() {}, triggerCh)
+			go ctrl.Run(ctx, (&configProvider{config: config}).getConfig)
+
+			select {
+			case triggerCh <- struct{}{}:
+			case <-time.After(channelSendTimeout):
+				g.Fail("Timed out while attempting to trigger controller reconcile loop")
+			}
+			select {
+			case <-ctrl.ReconciledCh():
+			case <-time.After(channelSendTimeout):
+				g.Fail("Time out while waiting for the reconcile to complete")
+			}
+
+			configMap, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "k8sd-config", metav1.GetOptions{})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue("cluster-dns", tc.expectedClusterDNS))
+		})
+	}
+}
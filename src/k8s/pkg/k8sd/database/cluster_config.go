@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/microcluster/cluster"
@@ -21,6 +22,12 @@ var (
 // SetClusterConfig will attempt to merge the existing and new configs, and return an error if any protected fields have changed.
 // SetClusterConfig will return the merged cluster configuration on success.
 func SetClusterConfig(ctx context.Context, tx *sql.Tx, new types.ClusterConfig) (types.ClusterConfig, error) {
+	return UpdateClusterConfig(ctx, tx, new, types.ClusterConfig{})
+}
+
+// UpdateClusterConfig is like SetClusterConfig, but also clears the fields that are set in unset, see types.ClusterConfigUnsetMask.
+// Fields can only be cleared if they could be changed to their zero value.
+func UpdateClusterConfig(ctx context.Context, tx *sql.Tx, new types.ClusterConfig, unset types.ClusterConfig) (types.ClusterConfig, error) {
 	old, err := GetClusterConfig(ctx, tx)
 	if err != nil {
 		return types.ClusterConfig{}, fmt.Errorf("failed to fetch existing cluster config: %w", err)
@@ -29,6 +36,15 @@ func SetClusterConfig(ctx context.Context, tx *sql.Tx, new types.ClusterConfig)
 	if err != nil {
 		return types.ClusterConfig{}, fmt.Errorf("failed to merge new cluster configuration options: %w", err)
 	}
+	if !reflect.DeepEqual(unset, types.ClusterConfig{}) {
+		if _, err := types.MergeClusterConfig(config, unset); err != nil {
+			return types.ClusterConfig{}, fmt.Errorf("failed to clear cluster configuration options: %w", err)
+		}
+		config = config.Unset(unset)
+		if err := config.Validate(); err != nil {
+			return types.ClusterConfig{}, fmt.Errorf("updated cluster configuration is not valid: %w", err)
+		}
+	}
 
 	b, err := json.Marshal(config)
 	if err != nil {
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/microcluster/cluster"
+)
+
+var (
+	clusterConfigRevisionsStmts = map[string]int{
+		"insert":        MustPrepareStatement("cluster-config-revisions", "insert.sql"),
+		"select":        MustPrepareStatement("cluster-config-revisions", "select.sql"),
+		"select-by-id":  MustPrepareStatement("cluster-config-revisions", "select-by-id.sql"),
+		"select-latest": MustPrepareStatement("cluster-config-revisions", "select-latest.sql"),
+		"delete-before": MustPrepareStatement("cluster-config-revisions", "delete-before.sql"),
+	}
+
+	// maxClusterConfigRevisions is the number of revisions that are kept. Older revisions are deleted when a new revision is recorded.
+	maxClusterConfigRevisions = 100
+)
+
+// AddClusterConfigRevision records a change of the cluster configuration from previous to config.
+// AddClusterConfigRevision stores the user-facing configuration only, certificates and keys are not recorded and registry credentials are redacted.
+// AddClusterConfigRevision deletes the oldest revisions, so that only the latest maxClusterConfigRevisions are kept.
+// AddClusterConfigRevision returns the new revision.
+func AddClusterConfigRevision(ctx context.Context, tx *sql.Tx, identity string, previous types.ClusterConfig, config types.ClusterConfig) (int, error) {
+	changes, err := types.DiffRedactedClusterConfig(previous, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute config changes: %w", err)
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode config changes: %w", err)
+	}
+	configJSON, err := json.Marshal(config.Redacted().ToUserFacing())
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	insertTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["insert"])
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	result, err := insertTxStmt.ExecContext(ctx, time.Now().UTC(), identity, string(changesJSON), string(configJSON))
+	if err != nil {
+		return 0, fmt.Errorf("insert revision query failed: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve revision: %w", err)
+	}
+
+	deleteTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["delete-before"])
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	if _, err := deleteTxStmt.ExecContext(ctx, int(id)-maxClusterConfigRevisions+1); err != nil {
+		return 0, fmt.Errorf("delete old revisions query failed: %w", err)
+	}
+	return int(id), nil
+}
+
+// ListClusterConfigRevisions returns all revisions of the cluster configuration, oldest first.
+func ListClusterConfigRevisions(ctx context.Context, tx *sql.Tx) ([]apiv1.ClusterConfigRevision, error) {
+	selectTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+	rows, err := selectTxStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []apiv1.ClusterConfigRevision{}
+	for rows.Next() {
+		revision, err := scanClusterConfigRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetClusterConfigRevision returns a single revision of the cluster configuration.
+// GetClusterConfigRevision returns an error wrapping sql.ErrNoRows if the revision does not exist.
+func GetClusterConfigRevision(ctx context.Context, tx *sql.Tx, revision int) (apiv1.ClusterConfigRevision, error) {
+	selectTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["select-by-id"])
+	if err != nil {
+		return apiv1.ClusterConfigRevision{}, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+	result, err := scanClusterConfigRevision(selectTxStmt.QueryRowContext(ctx, revision))
+	if err != nil {
+		return apiv1.ClusterConfigRevision{}, fmt.Errorf("failed to retrieve revision %d: %w", revision, err)
+	}
+	return result, nil
+}
+
+// GetLatestClusterConfigRevision returns the latest revision of the cluster configuration.
+// GetLatestClusterConfigRevision returns 0 if the cluster configuration was never updated.
+func GetLatestClusterConfigRevision(ctx context.Context, tx *sql.Tx) (int, error) {
+	selectTxStmt, err := cluster.Stmt(tx, clusterConfigRevisionsStmts["select-latest"])
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+	var revision int
+	if err := selectTxStmt.QueryRowContext(ctx).Scan(&revision); err != nil {
+		return 0, fmt.Errorf("failed to retrieve latest revision: %w", err)
+	}
+	return revision, nil
+}
+
+func scanClusterConfigRevision(row interface{ Scan(...any) error }) (apiv1.ClusterConfigRevision, error) {
+	var (
+		revision        apiv1.ClusterConfigRevision
+		changes, config string
+	)
+	if err := row.Scan(&revision.Revision, &revision.CreatedAt, &revision.Identity, &changes, &config); err != nil {
+		return apiv1.ClusterConfigRevision{}, fmt.Errorf("failed to scan revision: %w", err)
+	}
+	if err := json.Unmarshal([]byte(changes), &revision.Changes); err != nil {
+		return apiv1.ClusterConfigRevision{}, fmt.Errorf("failed to parse changes of revision %d: %w", revision.Revision, err)
+	}
+	if err := json.Unmarshal([]byte(config), &revision.Config); err != nil {
+		return apiv1.ClusterConfigRevision{}, fmt.Errorf("failed to parse config of revision %d: %w", revision.Revision, err)
+	}
+	redactClusterConfigRevision(&revision)
+	return revision, nil
+}
+
+// redactClusterConfigRevision replaces the registry credentials in revisions that were recorded before credentials were redacted.
+func redactClusterConfigRevision(revision *apiv1.ClusterConfigRevision) {
+	if registries := revision.Config.Containerd.Registries; registries != nil {
+		for i := range *registries {
+			for _, v := range []*string{&(*registries)[i].Password, &(*registries)[i].Token, &(*registries)[i].ClientKey} {
+				if *v != "" {
+					*v = types.RedactedValue
+				}
+			}
+		}
+	}
+	for i, change := range revision.Changes {
+		if change.Key == "containerd.registries" {
+			revision.Changes[i].Old, revision.Changes[i].New = redactRegistriesValue(change.Old), redactRegistriesValue(change.New)
+		}
+	}
+}
+
+// redactRegistriesValue replaces the registry credentials in the JSON value of the "containerd.registries" option.
+func redactRegistriesValue(value any) any {
+	registries, _ := value.([]any)
+	for _, registry := range registries {
+		m, ok := registry.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"password", "token", "client-key"} {
+			if v, ok := m[key].(string); ok && v != "" {
+				m[key] = types.RedactedValue
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterConfigRevisions(t *testing.T) {
+	WithDB(t, func(ctx context.Context, d DB) {
+		g := NewWithT(t)
+
+		previous := types.ClusterConfig{DNS: types.DNS{Enabled: utils.Pointer(false)}}
+		config := types.ClusterConfig{
+			DNS:          types.DNS{Enabled: utils.Pointer(true)},
+			Certificates: types.Certificates{CAKey: utils.Pointer("CA KEY DATA")},
+		}
+
+		err := d.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			latest, err := database.GetLatestClusterConfigRevision(ctx, tx)
+			g.Expect(err).To(BeNil())
+			g.Expect(latest).To(Equal(0))
+
+			revision, err := database.AddClusterConfigRevision(ctx, tx, "node1 (local)", previous, config)
+			g.Expect(err).To(BeNil())
+			g.Expect(revision).To(Equal(1))
+
+			revision, err = database.AddClusterConfigRevision(ctx, tx, "node2 (local)", config, config)
+			g.Expect(err).To(BeNil())
+			g.Expect(revision).To(Equal(2))
+			return nil
+		})
+		g.Expect(err).To(BeNil())
+
+		err = d.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			revisions, err := database.ListClusterConfigRevisions(ctx, tx)
+			g.Expect(err).To(BeNil())
+			g.Expect(revisions).To(HaveLen(2))
+			g.Expect(revisions[0].Identity).To(Equal("node1 (local)"))
+			g.Expect(revisions[0].Changes).To(Equal([]apiv1.ClusterConfigChange{{Key: "dns.enabled", Old: false, New: true}}))
+			g.Expect(revisions[0].Config.DNS.GetEnabled()).To(BeTrue())
+			g.Expect(revisions[1].Changes).To(BeEmpty())
+
+			revision, err := database.GetClusterConfigRevision(ctx, tx, 2)
+			g.Expect(err).To(BeNil())
+			g.Expect(revision.Identity).To(Equal("node2 (local)"))
+
+			latest, err := database.GetLatestClusterConfigRevision(ctx, tx)
+			g.Expect(err).To(BeNil())
+			g.Expect(latest).To(Equal(2))
+
+			_, err = database.GetClusterConfigRevision(ctx, tx, 3)
+			g.Expect(err).To(MatchError(sql.ErrNoRows))
+			return nil
+		})
+		g.Expect(err).To(BeNil())
+	})
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/canonical/microcluster/cluster"
+)
+
+var (
+	datastoreRolePinsStmts = map[string]int{
+		"insert": MustPrepareStatement("datastore-role-pins", "insert.sql"),
+		"select": MustPrepareStatement("datastore-role-pins", "select.sql"),
+		"delete": MustPrepareStatement("datastore-role-pins", "delete.sql"),
+	}
+)
+
+// PinDatastoreRole marks the k8s-dqlite role of a node as set manually, so that it is not changed when the voters are rebalanced.
+func PinDatastoreRole(ctx context.Context, tx *sql.Tx, name string) error {
+	insertTxStmt, err := cluster.Stmt(tx, datastoreRolePinsStmts["insert"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	if _, err := insertTxStmt.ExecContext(ctx, name); err != nil {
+		return fmt.Errorf("insert datastore role pin query failed: %w", err)
+	}
+	return nil
+}
+
+// ListPinnedDatastoreRoles lists the nodes with a pinned k8s-dqlite role.
+func ListPinnedDatastoreRoles(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	selectTxStmt, err := cluster.Stmt(tx, datastoreRolePinsStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+	rows, err := selectTxStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select datastore role pins query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to parse row: %w", err)
+		}
+		nodes = append(nodes, name)
+	}
+	return nodes, nil
+}
+
+// UnpinDatastoreRole lets the k8s-dqlite role of a node be changed when the voters are rebalanced.
+func UnpinDatastoreRole(ctx context.Context, tx *sql.Tx, name string) error {
+	deleteTxStmt, err := cluster.Stmt(tx, datastoreRolePinsStmts["delete"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	if _, err := deleteTxStmt.ExecContext(ctx, name); err != nil {
+		return fmt.Errorf("delete datastore role pin query failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	. "github.com/onsi/gomega"
+)
+
+func TestDatastoreRolePins(t *testing.T) {
+	WithDB(t, func(ctx context.Context, db DB) {
+		g := NewWithT(t)
+		err := db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			t.Run("Empty", func(t *testing.T) {
+				g := NewWithT(t)
+
+				nodes, err := database.ListPinnedDatastoreRoles(ctx, tx)
+				g.Expect(err).To(BeNil())
+				g.Expect(nodes).To(BeEmpty())
+			})
+
+			t.Run("Pin", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.PinDatastoreRole(ctx, tx, "n2")).To(Succeed())
+				g.Expect(database.PinDatastoreRole(ctx, tx, "n1")).To(Succeed())
+				g.Expect(database.PinDatastoreRole(ctx, tx, "n1")).To(Succeed())
+
+				nodes, err := database.ListPinnedDatastoreRoles(ctx, tx)
+				g.Expect(err).To(BeNil())
+				g.Expect(nodes).To(Equal([]string{"n1", "n2"}))
+			})
+
+			t.Run("Unpin", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.UnpinDatastoreRole(ctx, tx, "n1")).To(Succeed())
+				g.Expect(database.UnpinDatastoreRole(ctx, tx, "n3")).To(Succeed())
+
+				nodes, err := database.ListPinnedDatastoreRoles(ctx, tx)
+				g.Expect(err).To(BeNil())
+				g.Expect(nodes).To(Equal([]string{"n2"}))
+			})
+			return nil
+		})
+		g.Expect(err).To(BeNil())
+	})
+}
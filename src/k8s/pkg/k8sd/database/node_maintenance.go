@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/canonical/microcluster/cluster"
+)
+
+var (
+	nodeMaintenanceStmts = map[string]int{
+		"insert":         MustPrepareStatement("node-maintenance", "insert.sql"),
+		"select":         MustPrepareStatement("node-maintenance", "select.sql"),
+		"select-by-name": MustPrepareStatement("node-maintenance", "select-by-name.sql"),
+		"delete":         MustPrepareStatement("node-maintenance", "delete.sql"),
+	}
+)
+
+// SetNodeMaintenance marks a node as in maintenance.
+// datastoreVoter records whether the node was a k8s-dqlite voter when it entered maintenance, so that it can be promoted again when it exits maintenance.
+func SetNodeMaintenance(ctx context.Context, tx *sql.Tx, name string, datastoreVoter bool) error {
+	insertTxStmt, err := cluster.Stmt(tx, nodeMaintenanceStmts["insert"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	if _, err := insertTxStmt.ExecContext(ctx, name, datastoreVoter); err != nil {
+		return fmt.Errorf("insert node maintenance query failed: %w", err)
+	}
+	return nil
+}
+
+// GetNodeMaintenance returns true if the node is in maintenance, and whether it was demoted from a k8s-dqlite voter.
+func GetNodeMaintenance(ctx context.Context, tx *sql.Tx, name string) (inMaintenance bool, datastoreVoter bool, err error) {
+	selectTxStmt, err := cluster.Stmt(tx, nodeMaintenanceStmts["select-by-name"])
+	if err != nil {
+		return false, false, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+
+	if err := selectTxStmt.QueryRowContext(ctx, name).Scan(&datastoreVoter); err != nil {
+		if err == sql.ErrNoRows {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("select node maintenance %q query failed: %w", name, err)
+	}
+
+	return true, datastoreVoter, nil
+}
+
+// ListNodesInMaintenance lists the nodes that are in maintenance.
+func ListNodesInMaintenance(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	selectTxStmt, err := cluster.Stmt(tx, nodeMaintenanceStmts["select"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare select statement: %w", err)
+	}
+	rows, err := selectTxStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select node maintenance query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to parse row: %w", err)
+		}
+		nodes = append(nodes, name)
+	}
+	return nodes, nil
+}
+
+// DeleteNodeMaintenance marks a node as no longer in maintenance.
+func DeleteNodeMaintenance(ctx context.Context, tx *sql.Tx, name string) error {
+	deleteTxStmt, err := cluster.Stmt(tx, nodeMaintenanceStmts["delete"])
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	if _, err := deleteTxStmt.ExecContext(ctx, name); err != nil {
+		return fmt.Errorf("delete node maintenance query failed: %w", err)
+	}
+	return nil
+}
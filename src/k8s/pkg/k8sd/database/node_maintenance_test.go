@@ -0,0 +1,78 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/database"
+	. "github.com/onsi/gomega"
+)
+
+func TestNodeMaintenance(t *testing.T) {
+	WithDB(t, func(ctx context.Context, db DB) {
+		g := NewWithT(t)
+		err := db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			t.Run("Empty", func(t *testing.T) {
+				g := NewWithT(t)
+
+				inMaintenance, datastoreVoter, err := database.GetNodeMaintenance(ctx, tx, "n1")
+				g.Expect(err).To(BeNil())
+				g.Expect(inMaintenance).To(BeFalse())
+				g.Expect(datastoreVoter).To(BeFalse())
+
+				nodes, err := database.ListNodesInMaintenance(ctx, tx)
+				g.Expect(err).To(BeNil())
+				g.Expect(nodes).To(BeEmpty())
+			})
+
+			t.Run("Set", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.SetNodeMaintenance(ctx, tx, "n1", true)).To(Succeed())
+				g.Expect(database.SetNodeMaintenance(ctx, tx, "n2", false)).To(Succeed())
+
+				inMaintenance, datastoreVoter, err := database.GetNodeMaintenance(ctx, tx, "n1")
+				g.Expect(err).To(BeNil())
+				g.Expect(inMaintenance).To(BeTrue())
+				g.Expect(datastoreVoter).To(BeTrue())
+
+				inMaintenance, datastoreVoter, err = database.GetNodeMaintenance(ctx, tx, "n2")
+				g.Expect(err).To(BeNil())
+				g.Expect(inMaintenance).To(BeTrue())
+				g.Expect(datastoreVoter).To(BeFalse())
+
+				nodes, err := database.ListNodesInMaintenance(ctx, tx)
+				g.Expect(err).To(BeNil())
+				g.Expect(nodes).To(Equal([]string{"n1", "n2"}))
+			})
+
+			t.Run("SetAgain", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.SetNodeMaintenance(ctx, tx, "n1", false)).To(Succeed())
+
+				inMaintenance, datastoreVoter, err := database.GetNodeMaintenance(ctx, tx, "n1")
+				g.Expect(err).To(BeNil())
+				g.Expect(inMaintenance).To(BeTrue())
+				g.Expect(datastoreVoter).To(BeFalse())
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				g := NewWithT(t)
+
+				g.Expect(database.DeleteNodeMaintenance(ctx, tx, "n1")).To(Succeed())
+
+				inMaintenance, _, err := database.GetNodeMaintenance(ctx, tx, "n1")
+				g.Expect(err).To(BeNil())
+				g.Expect(inMaintenance).To(BeFalse())
+
+				nodes, err := database.ListNodesInMaintenance(ctx, tx)
+				g.Expect(err).To(BeNil())
+				g.Expect(nodes).To(Equal([]string{"n2"}))
+			})
+			return nil
+		})
+		g.Expect(err).To(BeNil())
+	})
+}
@@ -17,6 +17,9 @@ var (
 		schemaApplyMigration("cluster-configs", "000-create.sql"),
 		schemaApplyMigration("worker-nodes", "000-create.sql"),
 		schemaApplyMigration("worker-tokens", "000-create.sql"),
+		schemaApplyMigration("cluster-configs", "001-create-revisions.sql"),
+		schemaApplyMigration("node-maintenance", "000-create.sql"),
+		schemaApplyMigration("datastore-role-pins", "000-create.sql"),
 	}
 
 	//go:embed sql/migrations
@@ -0,0 +1,7 @@
+CREATE TABLE cluster_config_revisions (
+    id          INTEGER     PRIMARY KEY AUTOINCREMENT NOT NULL,
+    created_at  DATETIME    NOT NULL,
+    identity    TEXT        NOT NULL,
+    changes     TEXT        NOT NULL,
+    config      TEXT        NOT NULL
+)
@@ -0,0 +1,5 @@
+CREATE TABLE datastore_role_pins (
+  id                   INTEGER   PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  name                 TEXT      NOT      NULL,
+  UNIQUE(name)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE node_maintenance (
+  id                   INTEGER   PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  name                 TEXT      NOT      NULL,
+  datastore_voter      BOOLEAN   NOT      NULL,
+  UNIQUE(name)
+);
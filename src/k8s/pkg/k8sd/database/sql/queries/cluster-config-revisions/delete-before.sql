@@ -0,0 +1,4 @@
+DELETE FROM
+    cluster_config_revisions AS r
+WHERE
+    ( r.id < ? )
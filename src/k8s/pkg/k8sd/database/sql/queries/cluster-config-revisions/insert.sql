@@ -0,0 +1,4 @@
+INSERT INTO
+    cluster_config_revisions(created_at, identity, changes, config)
+VALUES
+    ( ?, ?, ?, ? )
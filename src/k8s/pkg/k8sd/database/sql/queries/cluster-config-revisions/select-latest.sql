@@ -0,0 +1,4 @@
+SELECT
+    COALESCE(MAX(r.id), 0)
+FROM
+    cluster_config_revisions AS r
@@ -0,0 +1,6 @@
+SELECT
+    r.id, r.created_at, r.identity, r.changes, r.config
+FROM
+    cluster_config_revisions AS r
+ORDER BY
+    r.id ASC
@@ -0,0 +1,4 @@
+DELETE FROM
+    datastore_role_pins AS p
+WHERE
+    ( p.name = ? )
@@ -0,0 +1,5 @@
+INSERT INTO
+    datastore_role_pins(name)
+VALUES
+    ( ? )
+ON CONFLICT(name) DO NOTHING;
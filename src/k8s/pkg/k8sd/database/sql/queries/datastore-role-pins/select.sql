@@ -0,0 +1,6 @@
+SELECT
+    p.name
+FROM
+    datastore_role_pins AS p
+ORDER BY
+    p.name ASC
@@ -0,0 +1,4 @@
+DELETE FROM
+    node_maintenance AS n
+WHERE
+    ( n.name = ? )
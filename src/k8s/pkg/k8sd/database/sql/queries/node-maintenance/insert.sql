@@ -0,0 +1,6 @@
+INSERT INTO
+    node_maintenance(name, datastore_voter)
+VALUES
+    ( ?, ? )
+ON CONFLICT(name) DO
+    UPDATE SET datastore_voter = EXCLUDED.datastore_voter;
@@ -0,0 +1,6 @@
+SELECT
+    n.datastore_voter
+FROM
+    node_maintenance AS n
+WHERE
+    ( n.name = ? )
@@ -0,0 +1,6 @@
+SELECT
+    n.name
+FROM
+    node_maintenance AS n
+ORDER BY
+    n.name ASC
@@ -51,3 +51,77 @@ func IsWorkerNode(ctx context.Context, s *state.State, name string) (bool, error
 	}
 	return exists, nil
 }
+
+// IsNodeInMaintenance returns true if the given node is in maintenance.
+func IsNodeInMaintenance(ctx context.Context, s *state.State, name string) (bool, error) {
+	var inMaintenance bool
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		inMaintenance, _, err = database.GetNodeMaintenance(ctx, tx, name)
+		if err != nil {
+			return fmt.Errorf("failed to get node maintenance from database: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return false, fmt.Errorf("failed to perform node maintenance transaction request: %w", err)
+	}
+	return inMaintenance, nil
+}
+
+// DeleteNodeMaintenanceEntry is a convenience wrapper around the database call to delete the node maintenance entry.
+func DeleteNodeMaintenanceEntry(ctx context.Context, s *state.State, name string) error {
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := database.DeleteNodeMaintenance(ctx, tx, name); err != nil {
+			return fmt.Errorf("failed to delete node maintenance from database: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to perform delete node maintenance transaction request: %w", err)
+	}
+	return nil
+}
+
+// ListPinnedDatastoreRoles returns the names of the nodes with a pinned k8s-dqlite role.
+func ListPinnedDatastoreRoles(ctx context.Context, s *state.State) ([]string, error) {
+	var names []string
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		names, err = database.ListPinnedDatastoreRoles(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to list pinned datastore roles from database: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to perform datastore role pins transaction request: %w", err)
+	}
+	return names, nil
+}
+
+// SetDatastoreRolePinned is a convenience wrapper around the database calls to pin or unpin the k8s-dqlite role of a node.
+func SetDatastoreRolePinned(ctx context.Context, s *state.State, name string, pinned bool) error {
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if pinned {
+			return database.PinDatastoreRole(ctx, tx, name)
+		}
+		return database.UnpinDatastoreRole(ctx, tx, name)
+	}); err != nil {
+		return fmt.Errorf("failed to perform datastore role pin transaction request: %w", err)
+	}
+	return nil
+}
+
+// ListNodesInMaintenance returns the names of the nodes that are in maintenance.
+func ListNodesInMaintenance(ctx context.Context, s *state.State) ([]string, error) {
+	var names []string
+	if err := s.Database.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		names, err = database.ListNodesInMaintenance(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to list nodes in maintenance from database: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to perform node maintenance transaction request: %w", err)
+	}
+	return names, nil
+}
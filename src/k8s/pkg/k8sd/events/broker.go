@@ -0,0 +1,113 @@
+// Package events publishes the cluster operations observed by k8sd to subscribers.
+package events
+
+import (
+	"sync"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+// subscriberBufferSize is the number of events that are queued for a subscriber.
+// Subscribers that fall further behind are disconnected, they can resume from the last event they received.
+const subscriberBufferSize = 64
+
+// Broker keeps the recent events in memory and fans out new events to subscribers.
+// A nil Broker discards all events.
+type Broker struct {
+	mu          sync.Mutex
+	historySize int
+	history     []apiv1.Event
+	lastID      uint64
+	subscribers map[chan apiv1.Event]struct{}
+}
+
+// NewBroker creates a new Broker that keeps the last historySize events.
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		historySize: historySize,
+		subscribers: make(map[chan apiv1.Event]struct{}),
+	}
+}
+
+// Publish records a new event and sends it to all subscribers.
+// Publish never blocks, subscribers that cannot keep up are disconnected.
+func (b *Broker) Publish(eventType apiv1.EventType, message string, details map[string]string) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := apiv1.Event{
+		ID:      b.lastID,
+		Time:    time.Now().UTC(),
+		Type:    eventType,
+		Message: message,
+		Details: details,
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Events returns the recorded events with an ID greater than since.
+// If since is ahead of the last event, e.g. because k8sd was restarted, all recorded events are returned.
+func (b *Broker) Events(since uint64) []apiv1.Event {
+	if b == nil {
+		return []apiv1.Event{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.eventsLocked(since)
+}
+
+func (b *Broker) eventsLocked(since uint64) []apiv1.Event {
+	if since > b.lastID {
+		since = 0
+	}
+	events := []apiv1.Event{}
+	for _, event := range b.history {
+		if event.ID > since {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Subscribe returns the recorded events with an ID greater than since and a channel that receives all later events.
+// The channel is closed if the subscriber falls behind. cancel must be called to stop the subscription.
+func (b *Broker) Subscribe(since uint64) (events []apiv1.Event, ch <-chan apiv1.Event, cancel func()) {
+	if b == nil {
+		return []apiv1.Event{}, make(chan apiv1.Event), func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := make(chan apiv1.Event, subscriberBufferSize)
+	b.subscribers[subscriber] = struct{}{}
+
+	return b.eventsLocked(since), subscriber, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
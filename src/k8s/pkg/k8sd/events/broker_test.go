@@ -0,0 +1,99 @@
+package events_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	. "github.com/onsi/gomega"
+)
+
+func eventIDs(events []apiv1.Event) []uint64 {
+	ids := []uint64{}
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestBroker(t *testing.T) {
+	t.Run("History", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b := events.NewBroker(3)
+		for i := 0; i < 5; i++ {
+			b.Publish(apiv1.EventConfigUpdated, "updated", nil)
+		}
+
+		g.Expect(eventIDs(b.Events(0))).To(Equal([]uint64{3, 4, 5}))
+		g.Expect(eventIDs(b.Events(4))).To(Equal([]uint64{5}))
+		g.Expect(eventIDs(b.Events(5))).To(BeEmpty())
+		// a subscriber that saw events of a previous k8sd instance gets all recorded events
+		g.Expect(eventIDs(b.Events(10))).To(Equal([]uint64{3, 4, 5}))
+	})
+
+	t.Run("Subscribe", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b := events.NewBroker(10)
+		b.Publish(apiv1.EventNodeJoined, "joined", map[string]string{"node": "node1"})
+
+		history, ch, cancel := b.Subscribe(0)
+		g.Expect(eventIDs(history)).To(Equal([]uint64{1}))
+		g.Expect(history[0].Details).To(HaveKeyWithValue("node", "node1"))
+
+		b.Publish(apiv1.EventFeatureReconciled, "reconciled", nil)
+		g.Expect(ch).To(Receive(HaveField("ID", uint64(2))))
+
+		cancel()
+		g.Expect(ch).To(BeClosed())
+		// cancel can be called more than once, publishing does not block after cancel
+		cancel()
+		b.Publish(apiv1.EventFeatureReconciled, "reconciled", nil)
+	})
+
+	t.Run("SlowSubscriber", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b := events.NewBroker(1000)
+		_, ch, cancel := b.Subscribe(0)
+		defer cancel()
+
+		for i := 0; i < 100; i++ {
+			b.Publish(apiv1.EventFeatureFailed, "failed", nil)
+		}
+
+		received := 0
+		for range ch {
+			received++
+		}
+		g.Expect(received).To(BeNumerically("<", 100))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var b *events.Broker
+		b.Publish(apiv1.EventConfigUpdated, "updated", nil)
+		g.Expect(b.Events(0)).To(BeEmpty())
+	})
+}
+
+func TestPublishMemberChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	b := events.NewBroker(10)
+	b.PublishMemberChanges(
+		map[string]apiv1.DatastoreRole{"node1": apiv1.DatastoreRoleVoter, "node2": apiv1.DatastoreRoleVoter, "node3": apiv1.DatastoreRoleStandBy},
+		map[string]apiv1.DatastoreRole{"node1": apiv1.DatastoreRoleVoter, "node3": apiv1.DatastoreRoleVoter, "node4": apiv1.DatastoreRoleSpare},
+	)
+
+	published := b.Events(0)
+	g.Expect(published).To(HaveLen(3))
+	g.Expect(published[0].Type).To(Equal(apiv1.EventNodeRemoved))
+	g.Expect(published[0].Details).To(HaveKeyWithValue("node", "node2"))
+	g.Expect(published[1].Type).To(Equal(apiv1.EventDatastoreRoleChanged))
+	g.Expect(published[1].Details).To(Equal(map[string]string{"node": "node3", "old-role": "stand-by", "new-role": "voter"}))
+	g.Expect(published[2].Type).To(Equal(apiv1.EventNodeJoined))
+	g.Expect(published[2].Details).To(HaveKeyWithValue("node", "node4"))
+}
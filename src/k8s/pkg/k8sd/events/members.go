@@ -0,0 +1,43 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+// PublishMemberChanges publishes the differences between two snapshots of the control plane members.
+// The snapshots map the name of each member to its datastore role.
+// Members that appear are published as joined, members that disappear as removed.
+func (b *Broker) PublishMemberChanges(previous map[string]apiv1.DatastoreRole, current map[string]apiv1.DatastoreRole) {
+	names := make([]string, 0, len(previous)+len(current))
+	for name := range previous {
+		names = append(names, name)
+	}
+	for name := range current {
+		if _, ok := previous[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldRole, existed := previous[name]
+		newRole, exists := current[name]
+		switch {
+		case !existed:
+			b.Publish(apiv1.EventNodeJoined, fmt.Sprintf("Control plane node %s joined the cluster", name), map[string]string{
+				"node": name, "role": string(apiv1.ClusterRoleControlPlane),
+			})
+		case !exists:
+			b.Publish(apiv1.EventNodeRemoved, fmt.Sprintf("Control plane node %s was removed from the cluster", name), map[string]string{
+				"node": name, "role": string(apiv1.ClusterRoleControlPlane),
+			})
+		case oldRole != newRole:
+			b.Publish(apiv1.EventDatastoreRoleChanged, fmt.Sprintf("Datastore role of %s changed from %s to %s", name, oldRole, newRole), map[string]string{
+				"node": name, "old-role": string(oldRole), "new-role": string(newRole),
+			})
+		}
+	}
+}
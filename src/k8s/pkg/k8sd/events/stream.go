@@ -0,0 +1,70 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+// ContentType is the content type of an event stream.
+const ContentType = "text/event-stream"
+
+// WriteEvent writes event to w as a server-sent event.
+func WriteEvent(w io.Writer, event apiv1.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+// WriteKeepalive writes a comment to w. Comments are ignored by readers, they detect closed connections.
+func WriteKeepalive(w io.Writer) error {
+	if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+		return fmt.Errorf("failed to write keepalive: %w", err)
+	}
+	return nil
+}
+
+// Reader reads server-sent events that were written with WriteEvent.
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+// NewReader creates a new Reader that reads events from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(r)}
+}
+
+// Next returns the next event of the stream. Next returns io.EOF if the stream ends.
+func (r *Reader) Next() (apiv1.Event, error) {
+	var data strings.Builder
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var event apiv1.Event
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return apiv1.Event{}, fmt.Errorf("failed to parse event: %w", err)
+			}
+			return event, nil
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// "id" and "event" are redundant with the data, comments are ignored.
+	}
+	if err := r.scanner.Err(); err != nil {
+		return apiv1.Event{}, fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return apiv1.Event{}, io.EOF
+}
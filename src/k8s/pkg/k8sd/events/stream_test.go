@@ -0,0 +1,36 @@
+package events_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/events"
+	. "github.com/onsi/gomega"
+)
+
+func TestStream(t *testing.T) {
+	g := NewWithT(t)
+
+	sent := []apiv1.Event{
+		{ID: 1, Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Node: "node1", Type: apiv1.EventConfigUpdated, Message: "updated", Details: map[string]string{"revision": "3"}},
+		{ID: 2, Time: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC), Node: "node1", Type: apiv1.EventFeatureFailed, Message: "line 1\nline 2"},
+	}
+
+	buf := &bytes.Buffer{}
+	g.Expect(events.WriteEvent(buf, sent[0])).To(Succeed())
+	g.Expect(events.WriteKeepalive(buf)).To(Succeed())
+	g.Expect(events.WriteEvent(buf, sent[1])).To(Succeed())
+	g.Expect(buf.String()).To(HavePrefix("id: 1\nevent: config-updated\ndata: {"))
+
+	r := events.NewReader(buf)
+	for _, expected := range sent {
+		event, err := r.Next()
+		g.Expect(err).To(BeNil())
+		g.Expect(event).To(Equal(expected))
+	}
+	_, err := r.Next()
+	g.Expect(err).To(MatchError(io.EOF))
+}
@@ -4,6 +4,7 @@ import (
 	"path"
 
 	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/images"
 )
 
 var (
@@ -28,15 +29,14 @@ var (
 		ManifestPath: path.Join("charts", "gateway-api-1.0.0.tgz"),
 	}
 
-	// ciliumAgentImageRepo represents the image to use for cilium-agent.
-	ciliumAgentImageRepo = "ghcr.io/canonical/cilium"
+	// ciliumAgentImage represents the image to use for cilium-agent.
+	ciliumAgentImage = images.Image{Repository: "ghcr.io/canonical/cilium", Tag: "1.15.2-ck1"}
 
-	// ciliumAgentImageTag is the tag to use for the cilium-agent image.
-	ciliumAgentImageTag = "1.15.2-ck1"
-
-	// ciliumOperatorImageRepo is the image to use for cilium-operator.
-	ciliumOperatorImageRepository = "ghcr.io/canonical/cilium-operator"
-
-	// ciliumOperatorImageTag is the tag to use for the cilium-operator image.
-	ciliumOperatorImageTag = "1.15.2-ck1"
+	// ciliumOperatorImage is the image to use for cilium-operator.
+	ciliumOperatorImage = images.Image{Repository: "ghcr.io/canonical/cilium-operator", Tag: "1.15.2-ck1"}
 )
+
+// Images returns the images that are deployed by the network feature.
+func Images() []images.Image {
+	return []images.Image{ciliumAgentImage, ciliumOperatorImage}
+}
@@ -3,12 +3,12 @@ package cilium
 import (
 	"context"
 	"fmt"
-	"log"
 	"net"
 	"strings"
 
 	"github.com/canonical/k8s/pkg/client/helm"
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	"github.com/canonical/k8s/pkg/utils"
 	"github.com/canonical/k8s/pkg/utils/control"
@@ -18,8 +18,9 @@ import (
 // ApplyNetwork will remove Cilium when cfg.Enabled is false.
 // ApplyNetwork requires that bpf and cgroups2 are already mounted and available when running under strict snap confinement. If they are not, it will fail (since Cilium will not have the required permissions to mount them).
 // ApplyNetwork requires that `/sys` is mounted as a shared mount when running under classic snap confinement. This is to ensure that Cilium will be able to automatically mount bpf and cgroups2 on the pods.
+// ApplyNetwork pulls the Cilium images from imageRegistry, if set.
 // ApplyNetwork returns an error if anything fails.
-func ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network) error {
+func ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network, imageRegistry string) error {
 	m := snap.HelmClient()
 
 	if !cfg.GetEnabled() {
@@ -50,10 +51,12 @@ func ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network) error
 		}
 	}
 
+	agentImage := ciliumAgentImage.WithRegistry(imageRegistry)
+	operatorImage := ciliumOperatorImage.WithRegistry(imageRegistry)
 	values := map[string]any{
 		"image": map[string]any{
-			"repository": ciliumAgentImageRepo,
-			"tag":        ciliumAgentImageTag,
+			"repository": agentImage.Repository,
+			"tag":        agentImage.Tag,
 			"useDigest":  false,
 		},
 		"socketLB": map[string]any{
@@ -66,8 +69,8 @@ func ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network) error
 		"operator": map[string]any{
 			"replicas": 1,
 			"image": map[string]any{
-				"repository": ciliumOperatorImageRepository,
-				"tag":        ciliumOperatorImageTag,
+				"repository": operatorImage.Repository,
+				"tag":        operatorImage.Tag,
 				"useDigest":  false,
 			},
 		},
@@ -114,7 +117,7 @@ func ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network) error
 		if p == "private" {
 			onLXD, err := snap.OnLXD(ctx)
 			if err != nil {
-				log.Printf("failed to check if on LXD: %v", err)
+				log.FromContext(ctx).Warn("Failed to check if on LXD", "error", err)
 			}
 			if onLXD {
 				return fmt.Errorf("/sys is not a shared mount on the LXD container, this might be resolved by updating LXD on the host to version 5.0.2 or newer")
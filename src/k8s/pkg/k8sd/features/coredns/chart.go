@@ -4,6 +4,7 @@ import (
 	"path"
 
 	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/images"
 )
 
 var (
@@ -14,9 +15,11 @@ var (
 		ManifestPath: path.Join("charts", "coredns-1.29.0.tgz"),
 	}
 
-	// imageRepo is the image to use for CoreDNS.
-	imageRepo = "ghcr.io/canonical/coredns"
-
-	// imageTag is the tag to use for the CoreDNS image.
-	imageTag = "1.11.1-ck4"
+	// image is the image to use for CoreDNS.
+	image = images.Image{Repository: "ghcr.io/canonical/coredns", Tag: "1.11.1-ck4"}
 )
+
+// Images returns the images that are deployed by the DNS feature.
+func Images() []images.Image {
+	return []images.Image{image}
+}
@@ -14,8 +14,9 @@ import (
 // ApplyDNS will uninstall CoreDNS from the cluster if dns.Enabled is false.
 // ApplyDNS will install or refresh CoreDNS if dns.Enabled is true.
 // ApplyDNS will return the ClusterIP address of the coredns service, if successful.
+// ApplyDNS pulls the CoreDNS image from imageRegistry, if set.
 // ApplyDNS returns an error if anything fails.
-func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.Kubelet) (string, error) {
+func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.Kubelet, imageRegistry string) (string, error) {
 	m := snap.HelmClient()
 
 	if !dns.GetEnabled() {
@@ -25,10 +26,11 @@ func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.
 		return "", nil
 	}
 
+	coreDNSImage := image.WithRegistry(imageRegistry)
 	values := map[string]any{
 		"image": map[string]any{
-			"repository": imageRepo,
-			"tag":        imageTag,
+			"repository": coreDNSImage.Repository,
+			"tag":        coreDNSImage.Tag,
 		},
 		"service": map[string]any{
 			"name":      "coredns",
@@ -37,30 +39,7 @@ func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.
 		"deployment": map[string]any{
 			"name": "coredns",
 		},
-		"servers": []map[string]any{
-			{
-				"zones": []map[string]any{
-					{"zone": "."},
-				},
-				"port": 53,
-				"plugins": []map[string]any{
-					{"name": "errors"},
-					{"name": "health", "configBlock": "lameduck 5s"},
-					{"name": "ready"},
-					{
-						"name":        "kubernetes",
-						"parameters":  fmt.Sprintf("%s in-addr.arpa ip6.arpa", kubelet.GetClusterDomain()),
-						"configBlock": "pods insecure\nfallthrough in-addr.arpa ip6.arpa\nttl 30",
-					},
-					{"name": "prometheus", "parameters": "0.0.0.0:9153"},
-					{"name": "forward", "parameters": fmt.Sprintf(". %s", strings.Join(dns.GetUpstreamNameservers(), " "))},
-					{"name": "cache", "parameters": "30"},
-					{"name": "loop"},
-					{"name": "reload"},
-					{"name": "loadbalance"},
-				},
-			},
-		},
+		"servers": servers(dns, kubelet),
 	}
 
 	if _, err := m.Apply(ctx, chart, helm.StatePresent, values); err != nil {
@@ -78,3 +57,103 @@ func ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.
 
 	return dnsIP, nil
 }
+
+// servers returns the CoreDNS server blocks for the chart values.
+func servers(dns types.DNS, kubelet types.Kubelet) []map[string]any {
+	// clusters bootstrapped before cache-ttl was configurable do not have it set
+	cacheTTL := 30
+	if dns.CacheTTL != nil {
+		cacheTTL = dns.GetCacheTTL()
+	}
+
+	plugins := []map[string]any{
+		{"name": "errors"},
+		{"name": "health", "configBlock": "lameduck 5s"},
+		{"name": "ready"},
+	}
+	if hosts := dns.GetHosts(); len(hosts) > 0 {
+		lines := make([]string, 0, len(hosts)+1)
+		for _, host := range hosts {
+			lines = append(lines, fmt.Sprintf("%s %s", host.IP, strings.Join(host.Hostnames, " ")))
+		}
+		lines = append(lines, "fallthrough")
+		plugins = append(plugins, map[string]any{"name": "hosts", "configBlock": strings.Join(lines, "\n")})
+	}
+	plugins = append(plugins,
+		map[string]any{
+			"name":        "kubernetes",
+			"parameters":  fmt.Sprintf("%s in-addr.arpa ip6.arpa", kubelet.GetClusterDomain()),
+			"configBlock": "pods insecure\nfallthrough in-addr.arpa ip6.arpa\nttl 30",
+		},
+		map[string]any{"name": "prometheus", "parameters": "0.0.0.0:9153"},
+		map[string]any{"name": "forward", "parameters": fmt.Sprintf(". %s", strings.Join(dns.GetUpstreamNameservers(), " "))},
+	)
+	if cacheTTL > 0 {
+		plugins = append(plugins, map[string]any{"name": "cache", "parameters": fmt.Sprintf("%d", cacheTTL)})
+	}
+	plugins = append(plugins,
+		map[string]any{"name": "loop"},
+		map[string]any{"name": "reload"},
+		map[string]any{"name": "loadbalance"},
+	)
+	for _, plugin := range dns.GetExtraPlugins() {
+		plugins = append(plugins, pluginValues(plugin))
+	}
+
+	result := []map[string]any{
+		{
+			"zones":   []map[string]any{{"zone": "."}},
+			"port":    53,
+			"plugins": plugins,
+		},
+	}
+
+	for _, stubZone := range dns.GetStubZones() {
+		stubPlugins := []map[string]any{{"name": "errors"}}
+		if cacheTTL > 0 {
+			stubPlugins = append(stubPlugins, map[string]any{"name": "cache", "parameters": fmt.Sprintf("%d", cacheTTL)})
+		}
+		stubPlugins = append(stubPlugins, map[string]any{"name": "forward", "parameters": fmt.Sprintf(". %s", strings.Join(stubZone.Nameservers, " "))})
+
+		result = append(result, map[string]any{
+			"zones":   []map[string]any{{"zone": stubZone.Zone}},
+			"port":    53,
+			"plugins": stubPlugins,
+		})
+	}
+
+	for _, server := range dns.GetExtraServers() {
+		zones := make([]map[string]any, 0, len(server.Zones))
+		for _, zone := range server.Zones {
+			zones = append(zones, map[string]any{"zone": zone})
+		}
+		port := server.Port
+		if port == 0 {
+			port = 53
+		}
+		serverPlugins := make([]map[string]any, 0, len(server.Plugins))
+		for _, plugin := range server.Plugins {
+			serverPlugins = append(serverPlugins, pluginValues(plugin))
+		}
+
+		result = append(result, map[string]any{
+			"zones":   zones,
+			"port":    port,
+			"plugins": serverPlugins,
+		})
+	}
+
+	return result
+}
+
+// pluginValues returns the chart values for a CoreDNS plugin.
+func pluginValues(plugin types.DNSPlugin) map[string]any {
+	values := map[string]any{"name": plugin.Name}
+	if plugin.Parameters != "" {
+		values["parameters"] = plugin.Parameters
+	}
+	if plugin.ConfigBlock != "" {
+		values["configBlock"] = plugin.ConfigBlock
+	}
+	return values
+}
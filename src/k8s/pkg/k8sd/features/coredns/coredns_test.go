@@ -0,0 +1,122 @@
+package coredns_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	helmmock "github.com/canonical/k8s/pkg/client/helm/mock"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/features/coredns"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyDNS(t *testing.T) {
+	g := NewWithT(t)
+	h := &helmmock.Mock{}
+	s := &snapmock.Snap{
+		Mock: snapmock.Mock{
+			HelmClient: h,
+			KubernetesClient: &kubernetes.Client{Interface: fake.NewSimpleClientset(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+				Spec:       corev1.ServiceSpec{ClusterIP: "10.152.183.10"},
+			})},
+		},
+	}
+
+	dns := types.DNS{
+		Enabled:             utils.Pointer(true),
+		UpstreamNameservers: utils.Pointer([]string{"/etc/resolv.conf"}),
+		CacheTTL:            utils.Pointer(60),
+		StubZones:           utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10", "10.0.0.11"}}}),
+		Hosts:               utils.Pointer([]types.DNSHostEntry{{IP: "10.0.0.20", Hostnames: []string{"git.corp.internal", "git"}}}),
+		ExtraPlugins:        utils.Pointer([]types.DNSPlugin{{Name: "log"}}),
+		ExtraServers:        utils.Pointer([]types.DNSServer{{Zones: []string{"example.com"}, Port: 5353, Plugins: []types.DNSPlugin{{Name: "file", Parameters: "/etc/coredns/example.db"}}}}),
+	}
+	kubelet := types.Kubelet{ClusterDomain: utils.Pointer("cluster.local")}
+
+	ip, err := coredns.ApplyDNS(context.Background(), s, dns, kubelet, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ip).To(Equal("10.152.183.10"))
+
+	g.Expect(h.ApplyCalledWith).To(HaveLen(1))
+	g.Expect(h.ApplyCalledWith[0].State).To(Equal(helm.StatePresent))
+	g.Expect(h.ApplyCalledWith[0].Values["servers"]).To(Equal([]map[string]any{
+		{
+			"zones": []map[string]any{{"zone": "."}},
+			"port":  53,
+			"plugins": []map[string]any{
+				{"name": "errors"},
+				{"name": "health", "configBlock": "lameduck 5s"},
+				{"name": "ready"},
+				{"name": "hosts", "configBlock": "10.0.0.20 git.corp.internal git\nfallthrough"},
+				{"name": "kubernetes", "parameters": "cluster.local in-addr.arpa ip6.arpa", "configBlock": "pods insecure\nfallthrough in-addr.arpa ip6.arpa\nttl 30"},
+				{"name": "prometheus", "parameters": "0.0.0.0:9153"},
+				{"name": "forward", "parameters": ". /etc/resolv.conf"},
+				{"name": "cache", "parameters": "60"},
+				{"name": "loop"},
+				{"name": "reload"},
+				{"name": "loadbalance"},
+				{"name": "log"},
+			},
+		},
+		{
+			"zones": []map[string]any{{"zone": "corp.internal"}},
+			"port":  53,
+			"plugins": []map[string]any{
+				{"name": "errors"},
+				{"name": "cache", "parameters": "60"},
+				{"name": "forward", "parameters": ". 10.0.0.10 10.0.0.11"},
+			},
+		},
+		{
+			"zones": []map[string]any{{"zone": "example.com"}},
+			"port":  5353,
+			"plugins": []map[string]any{
+				{"name": "file", "parameters": "/etc/coredns/example.db"},
+			},
+		},
+	}))
+}
+
+func TestApplyDNSCacheTTL(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		cacheTTL    *int
+		expectCache bool
+	}{
+		{name: "Unset", cacheTTL: nil, expectCache: true},
+		{name: "Disabled", cacheTTL: utils.Pointer(0), expectCache: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			h := &helmmock.Mock{}
+			s := &snapmock.Snap{
+				Mock: snapmock.Mock{
+					HelmClient: h,
+					KubernetesClient: &kubernetes.Client{Interface: fake.NewSimpleClientset(&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+					})},
+				},
+			}
+
+			_, err := coredns.ApplyDNS(context.Background(), s, types.DNS{Enabled: utils.Pointer(true), CacheTTL: tc.cacheTTL}, types.Kubelet{}, "")
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(h.ApplyCalledWith).To(HaveLen(1))
+			servers := h.ApplyCalledWith[0].Values["servers"].([]map[string]any)
+			cache := ContainElement(HaveKeyWithValue("name", "cache"))
+			if tc.expectCache {
+				g.Expect(servers[0]["plugins"]).To(cache)
+			} else {
+				g.Expect(servers[0]["plugins"]).ToNot(cache)
+			}
+		})
+	}
+}
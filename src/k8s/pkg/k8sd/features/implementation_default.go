@@ -5,19 +5,50 @@ import (
 	"github.com/canonical/k8s/pkg/k8sd/features/coredns"
 	"github.com/canonical/k8s/pkg/k8sd/features/localpv"
 	metrics_server "github.com/canonical/k8s/pkg/k8sd/features/metrics-server"
+	node_local_dns "github.com/canonical/k8s/pkg/k8sd/features/node-local-dns"
+	"github.com/canonical/k8s/pkg/k8sd/images"
+	"github.com/canonical/k8s/pkg/k8sd/types"
 )
 
 // Default implements the Canonical Kubernetes built-in features.
 // Cilium is used for networking (network + load-balancer + ingress + gateway).
 // CoreDNS is used for DNS.
+// NodeLocal DNSCache is used for node-local-dns.
 // MetricsServer is used for metrics-server.
 // LocalPV Rawfile CSI is used for local-storage.
 var Implementation Interface = &implementation{
 	applyDNS:           coredns.ApplyDNS,
+	applyNodeLocalDNS:  node_local_dns.ApplyNodeLocalDNS,
 	applyNetwork:       cilium.ApplyNetwork,
 	applyLoadBalancer:  cilium.ApplyLoadBalancer,
 	applyIngress:       cilium.ApplyIngress,
 	applyGateway:       cilium.ApplyGateway,
 	applyMetricsServer: metrics_server.ApplyMetricsServer,
 	applyLocalStorage:  localpv.ApplyLocalStorage,
+	images:             defaultImages,
+}
+
+// defaultImages returns the images of the built-in features that are enabled in the cluster configuration.
+func defaultImages(cfg types.ClusterConfig) []images.Image {
+	var result []images.Image
+	if cfg.Network.GetEnabled() {
+		result = append(result, cilium.Images()...)
+	}
+	if cfg.DNS.GetEnabled() {
+		result = append(result, coredns.Images()...)
+	}
+	if cfg.NodeLocalDNS.GetEnabled() {
+		result = append(result, node_local_dns.Images()...)
+	}
+	if cfg.MetricsServer.GetEnabled() {
+		result = append(result, metrics_server.Images()...)
+	}
+	if cfg.LocalStorage.GetEnabled() {
+		result = append(result, localpv.Images()...)
+	}
+
+	for i := range result {
+		result[i] = result[i].WithRegistry(cfg.Containerd.GetImageRegistry())
+	}
+	return result
 }
@@ -3,6 +3,7 @@ package features
 import (
 	"context"
 
+	"github.com/canonical/k8s/pkg/k8sd/images"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap"
 )
@@ -10,9 +11,11 @@ import (
 // Interface abstracts the management of built-in Canonical Kubernetes features.
 type Interface interface {
 	// ApplyDNS is used to configure the DNS feature on Canonical Kubernetes.
-	ApplyDNS(context.Context, snap.Snap, types.DNS, types.Kubelet) (string, error)
+	ApplyDNS(context.Context, snap.Snap, types.DNS, types.Kubelet, string) (string, error)
+	// ApplyNodeLocalDNS is used to configure the node-local-dns feature on Canonical Kubernetes.
+	ApplyNodeLocalDNS(context.Context, snap.Snap, types.NodeLocalDNS, types.Kubelet, string) error
 	// ApplyNetwork is used to configure the network feature on Canonical Kubernetes.
-	ApplyNetwork(context.Context, snap.Snap, types.Network) error
+	ApplyNetwork(context.Context, snap.Snap, types.Network, string) error
 	// ApplyLoadBalancer is used to configure the load-balancer feature on Canonical Kubernetes.
 	ApplyLoadBalancer(context.Context, snap.Snap, types.LoadBalancer, types.Network) error
 	// ApplyIngress is used to configure the ingress controller feature on Canonical Kubernetes.
@@ -20,28 +23,36 @@ type Interface interface {
 	// ApplyGateway is used to configure the gateway feature on Canonical Kubernetes.
 	ApplyGateway(context.Context, snap.Snap, types.Gateway, types.Network) error
 	// ApplyMetricsServer is used to configure the metrics-server feature on Canonical Kubernetes.
-	ApplyMetricsServer(context.Context, snap.Snap, types.MetricsServer) error
+	ApplyMetricsServer(context.Context, snap.Snap, types.MetricsServer, string) error
 	// ApplyLocalStorage is used to configure the Local Storage feature on Canonical Kubernetes.
-	ApplyLocalStorage(context.Context, snap.Snap, types.LocalStorage) error
+	ApplyLocalStorage(context.Context, snap.Snap, types.LocalStorage, string) error
+	// Images returns the images that are needed by the enabled features, pulled from the image registry of the cluster configuration.
+	Images(types.ClusterConfig) []images.Image
 }
 
 // implementation implements Interface.
 type implementation struct {
-	applyDNS           func(context.Context, snap.Snap, types.DNS, types.Kubelet) (string, error)
-	applyNetwork       func(context.Context, snap.Snap, types.Network) error
+	applyDNS           func(context.Context, snap.Snap, types.DNS, types.Kubelet, string) (string, error)
+	applyNodeLocalDNS  func(context.Context, snap.Snap, types.NodeLocalDNS, types.Kubelet, string) error
+	applyNetwork       func(context.Context, snap.Snap, types.Network, string) error
 	applyLoadBalancer  func(context.Context, snap.Snap, types.LoadBalancer, types.Network) error
 	applyIngress       func(context.Context, snap.Snap, types.Ingress, types.Network) error
 	applyGateway       func(context.Context, snap.Snap, types.Gateway, types.Network) error
-	applyMetricsServer func(context.Context, snap.Snap, types.MetricsServer) error
-	applyLocalStorage  func(context.Context, snap.Snap, types.LocalStorage) error
+	applyMetricsServer func(context.Context, snap.Snap, types.MetricsServer, string) error
+	applyLocalStorage  func(context.Context, snap.Snap, types.LocalStorage, string) error
+	images             func(types.ClusterConfig) []images.Image
 }
 
-func (i *implementation) ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.Kubelet) (string, error) {
-	return i.applyDNS(ctx, snap, dns, kubelet)
+func (i *implementation) ApplyDNS(ctx context.Context, snap snap.Snap, dns types.DNS, kubelet types.Kubelet, imageRegistry string) (string, error) {
+	return i.applyDNS(ctx, snap, dns, kubelet, imageRegistry)
 }
 
-func (i *implementation) ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network) error {
-	return i.applyNetwork(ctx, snap, cfg)
+func (i *implementation) ApplyNodeLocalDNS(ctx context.Context, snap snap.Snap, cfg types.NodeLocalDNS, kubelet types.Kubelet, imageRegistry string) error {
+	return i.applyNodeLocalDNS(ctx, snap, cfg, kubelet, imageRegistry)
+}
+
+func (i *implementation) ApplyNetwork(ctx context.Context, snap snap.Snap, cfg types.Network, imageRegistry string) error {
+	return i.applyNetwork(ctx, snap, cfg, imageRegistry)
 }
 
 func (i *implementation) ApplyLoadBalancer(ctx context.Context, snap snap.Snap, loadbalancer types.LoadBalancer, network types.Network) error {
@@ -56,10 +67,14 @@ func (i *implementation) ApplyGateway(ctx context.Context, snap snap.Snap, gatew
 	return i.applyGateway(ctx, snap, gateway, network)
 }
 
-func (i *implementation) ApplyMetricsServer(ctx context.Context, snap snap.Snap, cfg types.MetricsServer) error {
-	return i.applyMetricsServer(ctx, snap, cfg)
+func (i *implementation) ApplyMetricsServer(ctx context.Context, snap snap.Snap, cfg types.MetricsServer, imageRegistry string) error {
+	return i.applyMetricsServer(ctx, snap, cfg, imageRegistry)
+}
+
+func (i *implementation) ApplyLocalStorage(ctx context.Context, snap snap.Snap, cfg types.LocalStorage, imageRegistry string) error {
+	return i.applyLocalStorage(ctx, snap, cfg, imageRegistry)
 }
 
-func (i *implementation) ApplyLocalStorage(ctx context.Context, snap snap.Snap, cfg types.LocalStorage) error {
-	return i.applyLocalStorage(ctx, snap, cfg)
+func (i *implementation) Images(cfg types.ClusterConfig) []images.Image {
+	return i.images(cfg)
 }
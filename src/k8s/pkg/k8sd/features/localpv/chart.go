@@ -4,6 +4,7 @@ import (
 	"path"
 
 	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/images"
 )
 
 var (
@@ -14,9 +15,11 @@ var (
 		ManifestPath: path.Join("charts", "rawfile-csi-0.8.0.tgz"),
 	}
 
-	// imageRepo is the image to use for Rawfile LocalPV CSI.
-	imageRepo = "ghcr.io/canonical/rawfile-localpv"
-
-	// imageTag is the image tag to use.
-	imageTag = "0.8.0-ck5"
+	// image is the image to use for Rawfile LocalPV CSI.
+	image = images.Image{Repository: "ghcr.io/canonical/rawfile-localpv", Tag: "0.8.0-ck5"}
 )
+
+// Images returns the images that are deployed by the local-storage feature.
+func Images() []images.Image {
+	return []images.Image{image}
+}
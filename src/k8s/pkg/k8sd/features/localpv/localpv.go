@@ -10,10 +10,12 @@ import (
 
 // ApplyLocalStorage deploys the rawfile-localpv CSI driver on the cluster based on the given configuration, when cfg.Enabled is true.
 // ApplyLocalStorage removes the rawfile-localpv when cfg.Enabled is false.
+// ApplyLocalStorage pulls the rawfile-localpv image from imageRegistry, if set.
 // ApplyLocalStorage returns an error if anything fails.
-func ApplyLocalStorage(ctx context.Context, snap snap.Snap, cfg types.LocalStorage) error {
+func ApplyLocalStorage(ctx context.Context, snap snap.Snap, cfg types.LocalStorage, imageRegistry string) error {
 	m := snap.HelmClient()
 
+	localPVImage := image.WithRegistry(imageRegistry)
 	values := map[string]any{
 		"storageClass": map[string]any{
 			"enabled":       true,
@@ -26,14 +28,14 @@ func ApplyLocalStorage(ctx context.Context, snap snap.Snap, cfg types.LocalStora
 		"controller": map[string]any{
 			"csiDriverArgs": []string{"--args", "rawfile", "csi-driver", "--disable-metrics"},
 			"image": map[string]any{
-				"repository": imageRepo,
-				"tag":        imageTag,
+				"repository": localPVImage.Repository,
+				"tag":        localPVImage.Tag,
 			},
 		},
 		"node": map[string]any{
 			"image": map[string]any{
-				"repository": imageRepo,
-				"tag":        imageTag,
+				"repository": localPVImage.Repository,
+				"tag":        localPVImage.Tag,
 			},
 			"storage": map[string]any{
 				"path": cfg.GetLocalPath(),
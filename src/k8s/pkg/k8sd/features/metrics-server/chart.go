@@ -4,6 +4,7 @@ import (
 	"path"
 
 	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/images"
 )
 
 var (
@@ -14,9 +15,11 @@ var (
 		ManifestPath: path.Join("charts", "metrics-server-3.12.0.tgz"),
 	}
 
-	// imageRepo is the image to use for metrics-server.
-	imageRepo = "ghcr.io/canonical/metrics-server"
-
-	// imageTag is the image tag to use for metrics-server.
-	imageTag = "0.7.0-ck0"
+	// image is the image to use for metrics-server.
+	image = images.Image{Repository: "ghcr.io/canonical/metrics-server", Tag: "0.7.0-ck0"}
 )
+
+// Images returns the images that are deployed by the metrics-server feature.
+func Images() []images.Image {
+	return []images.Image{image}
+}
@@ -10,14 +10,16 @@ import (
 
 // ApplyMetricsServer deploys metrics-server when cfg.Enabled is true.
 // ApplyMetricsServer removes metrics-server when cfg.Enabled is false.
+// ApplyMetricsServer pulls the metrics-server image from imageRegistry, if set.
 // ApplyMetricsServer returns an error if anything fails.
-func ApplyMetricsServer(ctx context.Context, snap snap.Snap, cfg types.MetricsServer) error {
+func ApplyMetricsServer(ctx context.Context, snap snap.Snap, cfg types.MetricsServer, imageRegistry string) error {
 	m := snap.HelmClient()
 
+	metricsServerImage := image.WithRegistry(imageRegistry)
 	values := map[string]any{
 		"image": map[string]any{
-			"repository": imageRepo,
-			"tag":        imageTag,
+			"repository": metricsServerImage.Repository,
+			"tag":        metricsServerImage.Tag,
 		},
 		"securityContext": map[string]any{
 			// ROCKs with Pebble as the entrypoint do not work with this option.
@@ -43,7 +43,7 @@ func TestApplyMetricsServer(t *testing.T) {
 				},
 			}
 
-			err := metrics_server.ApplyMetricsServer(context.Background(), s, tc.config)
+			err := metrics_server.ApplyMetricsServer(context.Background(), s, tc.config, "")
 			g.Expect(err).ToNot(HaveOccurred())
 
 			g.Expect(h.ApplyCalledWith).To(ConsistOf(SatisfyAll(
@@ -54,3 +54,20 @@ func TestApplyMetricsServer(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyMetricsServerImageRegistry(t *testing.T) {
+	g := NewWithT(t)
+	h := &helmmock.Mock{}
+	s := &snapmock.Snap{
+		Mock: snapmock.Mock{
+			HelmClient: h,
+		},
+	}
+
+	err := metrics_server.ApplyMetricsServer(context.Background(), s, types.MetricsServer{Enabled: utils.Pointer(true)}, "registry.internal:5000")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(h.ApplyCalledWith).To(ConsistOf(
+		HaveField("Values", HaveKeyWithValue("image", HaveKeyWithValue("repository", "registry.internal:5000/canonical/metrics-server"))),
+	))
+}
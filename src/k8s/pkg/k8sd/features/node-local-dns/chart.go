@@ -0,0 +1,28 @@
+package node_local_dns
+
+import (
+	"path"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/images"
+)
+
+// DaemonSetName is the name of the NodeLocal DNSCache daemonset in the kube-system namespace.
+const DaemonSetName = "node-local-dns"
+
+var (
+	// chart represents manifests to deploy NodeLocal DNSCache.
+	chart = helm.InstallableChart{
+		Name:         "ck-node-local-dns",
+		Namespace:    "kube-system",
+		ManifestPath: path.Join("charts", "ck-node-local-dns"),
+	}
+
+	// image is the image to use for NodeLocal DNSCache.
+	image = images.Image{Repository: "registry.k8s.io/dns/k8s-dns-node-cache", Tag: "1.23.1"}
+)
+
+// Images returns the images that are deployed by the node-local-dns feature.
+func Images() []images.Image {
+	return []images.Image{image}
+}
@@ -0,0 +1,133 @@
+package node_local_dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// disabledAtAnnotation records on the daemonset when node-local-dns was disabled.
+	disabledAtAnnotation = "k8sd.io/node-local-dns-disabled-at"
+
+	// kubeletSwitchTimeout is the time given to kubelets to switch back to the cluster DNS service after node-local-dns is disabled.
+	kubeletSwitchTimeout = time.Minute
+)
+
+// NodeKubelet returns the kubelet configuration to publish to the nodes.
+// Kubelets only switch to the node-local-dns address once the cache is available on all nodes, otherwise pods would lose DNS during the rollout.
+// Once switched, the address is kept while the daemonset rolls out further changes.
+func NodeKubelet(ctx context.Context, client *kubernetes.Client, config types.ClusterConfig) (types.Kubelet, error) {
+	kubelet := config.NodeKubelet()
+	if kubelet.GetClusterDNS() == config.Kubelet.GetClusterDNS() {
+		return kubelet, nil
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, "k8sd-config", metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return types.Kubelet{}, fmt.Errorf("failed to get node config: %w", err)
+	} else if err == nil && configMap.Data["cluster-dns"] == kubelet.GetClusterDNS() {
+		return kubelet, nil
+	}
+
+	ready, err := client.IsDaemonSetReady(ctx, DaemonSetName, "kube-system")
+	if err != nil {
+		return types.Kubelet{}, err
+	}
+	if !ready {
+		log.FromContext(ctx).Info("Waiting for node-local-dns rollout before updating kubelet cluster DNS")
+		return config.Kubelet, nil
+	}
+	return kubelet, nil
+}
+
+// podsUsingCache returns the names of the pods that still resolve names through node-local-dns after it was disabled.
+// Pods keep the nameserver of kubelet at the time they were created, so pods created before kubelets switched back to the cluster DNS service keep using the cache until they are recreated.
+// The first call records the current time on the daemonset, later calls compare the pods against it.
+// podsUsingCache returns nil if the daemonset does not exist.
+func podsUsingCache(ctx context.Context, client *kubernetes.Client) ([]string, error) {
+	var disabledAt time.Time
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		daemonset, err := client.AppsV1().DaemonSets("kube-system").Get(ctx, DaemonSetName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if v, ok := daemonset.Annotations[disabledAtAnnotation]; ok {
+			if disabledAt, err = time.Parse(time.RFC3339, v); err != nil {
+				return fmt.Errorf("invalid %s annotation %q: %w", disabledAtAnnotation, v, err)
+			}
+			return nil
+		}
+		if daemonset.Annotations == nil {
+			daemonset.Annotations = make(map[string]string)
+		}
+		disabledAt = time.Now()
+		daemonset.Annotations[disabledAtAnnotation] = disabledAt.UTC().Format(time.RFC3339)
+		_, err = client.AppsV1().DaemonSets("kube-system").Update(ctx, daemonset, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to mark the node-local-dns daemonset as disabled: %w", err)
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	switchedAt := disabledAt.Add(kubeletSwitchTimeout)
+	var names []string
+	for _, pod := range pods.Items {
+		if !usesClusterDNS(pod) || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.CreationTimestamp.Time.Before(switchedAt) {
+			names = append(names, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// clearDisabled removes the record of a previous disable from the daemonset, if any.
+func clearDisabled(ctx context.Context, client *kubernetes.Client) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		daemonset, err := client.AppsV1().DaemonSets("kube-system").Get(ctx, DaemonSetName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		if _, ok := daemonset.Annotations[disabledAtAnnotation]; !ok {
+			return nil
+		}
+		delete(daemonset.Annotations, disabledAtAnnotation)
+		if _, err := client.AppsV1().DaemonSets("kube-system").Update(ctx, daemonset, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update daemonset: %w", err)
+		}
+		return nil
+	})
+}
+
+// usesClusterDNS returns true if the nameserver of the pod is the cluster DNS address of kubelet.
+func usesClusterDNS(pod corev1.Pod) bool {
+	switch pod.Spec.DNSPolicy {
+	case corev1.DNSClusterFirstWithHostNet:
+		return true
+	case corev1.DNSClusterFirst, "":
+		return !pod.Spec.HostNetwork
+	default:
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+package node_local_dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/snap"
+)
+
+// ApplyNodeLocalDNS deploys a DNS cache on every node when cfg.Enabled is true.
+// ApplyNodeLocalDNS removes the DNS cache when cfg.Enabled is false, once no pod uses it anymore.
+// Pods keep the node-local-dns address until they are recreated, so ApplyNodeLocalDNS returns an error listing the pods that must be restarted before the cache is removed.
+// The cache listens on cfg.LocalIP and forwards cache misses to the cluster DNS service of kubelet.
+// ApplyNodeLocalDNS pulls the node-cache image from imageRegistry, if set.
+// ApplyNodeLocalDNS returns an error if anything fails.
+func ApplyNodeLocalDNS(ctx context.Context, snap snap.Snap, cfg types.NodeLocalDNS, kubelet types.Kubelet, imageRegistry string) error {
+	m := snap.HelmClient()
+
+	client, err := snap.KubernetesClient("")
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	if !cfg.GetEnabled() {
+		pods, err := podsUsingCache(ctx, client)
+		if err != nil {
+			return fmt.Errorf("failed to check pods using node-local-dns: %w", err)
+		}
+		if len(pods) > 0 {
+			names := pods
+			if len(names) > 10 {
+				names = append(names[:10:10], fmt.Sprintf("and %d more", len(pods)-10))
+			}
+			return fmt.Errorf("%d pods were created while node-local-dns was enabled and must be restarted before it is removed: %s", len(pods), strings.Join(names, ", "))
+		}
+		if _, err := m.Apply(ctx, chart, helm.StateDeleted, nil); err != nil {
+			return fmt.Errorf("failed to uninstall node-local-dns: %w", err)
+		}
+		return nil
+	}
+
+	// the cluster DNS address is only known once the DNS feature has been deployed
+	if kubelet.GetClusterDNS() == "" {
+		return fmt.Errorf("cluster DNS address is not known yet")
+	}
+
+	nodeCacheImage := image.WithRegistry(imageRegistry)
+	values := map[string]any{
+		"image": map[string]any{
+			"repository": nodeCacheImage.Repository,
+			"tag":        nodeCacheImage.Tag,
+		},
+		"localIP":       cfg.GetLocalIP(),
+		"clusterDomain": kubelet.GetClusterDomain(),
+		"upstreamIP":    kubelet.GetClusterDNS(),
+	}
+
+	if _, err := m.Apply(ctx, chart, helm.StatePresent, values); err != nil {
+		return fmt.Errorf("failed to apply node-local-dns: %w", err)
+	}
+	if err := clearDisabled(ctx, client); err != nil {
+		return fmt.Errorf("failed to clear previous disable of node-local-dns: %w", err)
+	}
+	return nil
+}
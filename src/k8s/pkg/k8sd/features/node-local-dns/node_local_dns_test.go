@@ -0,0 +1,139 @@
+package node_local_dns_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canonical/k8s/pkg/client/helm"
+	helmmock "github.com/canonical/k8s/pkg/client/helm/mock"
+	"github.com/canonical/k8s/pkg/client/kubernetes"
+	node_local_dns "github.com/canonical/k8s/pkg/k8sd/features/node-local-dns"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	snapmock "github.com/canonical/k8s/pkg/snap/mock"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyNodeLocalDNS(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		config      types.NodeLocalDNS
+		kubelet     types.Kubelet
+		objects     []runtime.Object
+		expectErr   bool
+		expectState helm.State
+	}{
+		{
+			name:        "Enable",
+			config:      types.NodeLocalDNS{Enabled: utils.Pointer(true), LocalIP: utils.Pointer("169.254.20.10")},
+			kubelet:     types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10"), ClusterDomain: utils.Pointer("cluster.local")},
+			expectState: helm.StatePresent,
+		},
+		{
+			name:        "Disable",
+			config:      types.NodeLocalDNS{Enabled: utils.Pointer(false)},
+			expectState: helm.StateDeleted,
+		},
+		{
+			name:        "DisableUnused",
+			config:      types.NodeLocalDNS{Enabled: utils.Pointer(false)},
+			objects:     []runtime.Object{daemonSet(time.Now().Add(-2 * time.Hour)), pod("restarted", time.Now(), corev1.DNSClusterFirst, false)},
+			expectState: helm.StateDeleted,
+		},
+		{
+			name:        "DisableHostNetwork",
+			config:      types.NodeLocalDNS{Enabled: utils.Pointer(false)},
+			objects:     []runtime.Object{daemonSet(time.Time{}), pod("host", time.Now().Add(-time.Hour), corev1.DNSClusterFirst, true)},
+			expectState: helm.StateDeleted,
+		},
+		{
+			name:      "DisableInUse",
+			config:    types.NodeLocalDNS{Enabled: utils.Pointer(false)},
+			objects:   []runtime.Object{daemonSet(time.Time{}), pod("running", time.Now().Add(-time.Hour), corev1.DNSClusterFirst, false)},
+			expectErr: true,
+		},
+		{
+			name:      "DisableInUseHostNetwork",
+			config:    types.NodeLocalDNS{Enabled: utils.Pointer(false)},
+			objects:   []runtime.Object{daemonSet(time.Now()), pod("host", time.Now(), corev1.DNSClusterFirstWithHostNet, true)},
+			expectErr: true,
+		},
+		{
+			name:        "EnableAfterDisable",
+			config:      types.NodeLocalDNS{Enabled: utils.Pointer(true), LocalIP: utils.Pointer("169.254.20.10")},
+			kubelet:     types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10"), ClusterDomain: utils.Pointer("cluster.local")},
+			objects:     []runtime.Object{daemonSet(time.Now())},
+			expectState: helm.StatePresent,
+		},
+		{
+			name:      "NoClusterDNS",
+			config:    types.NodeLocalDNS{Enabled: utils.Pointer(true), LocalIP: utils.Pointer("169.254.20.10")},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			h := &helmmock.Mock{}
+			clientset := fake.NewSimpleClientset(tc.objects...)
+			s := &snapmock.Snap{
+				Mock: snapmock.Mock{
+					HelmClient:       h,
+					KubernetesClient: &kubernetes.Client{Interface: clientset},
+				},
+			}
+
+			err := node_local_dns.ApplyNodeLocalDNS(context.Background(), s, tc.config, tc.kubelet, "")
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(h.ApplyCalledWith).To(BeEmpty())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			if daemonset, err := clientset.AppsV1().DaemonSets("kube-system").Get(context.Background(), node_local_dns.DaemonSetName, metav1.GetOptions{}); err == nil {
+				// the disable is recorded until node-local-dns is enabled again
+				if tc.expectState == helm.StatePresent {
+					g.Expect(daemonset.Annotations).To(BeEmpty())
+				} else {
+					g.Expect(daemonset.Annotations).To(HaveKey("k8sd.io/node-local-dns-disabled-at"))
+				}
+			}
+
+			g.Expect(h.ApplyCalledWith).To(ConsistOf(SatisfyAll(
+				HaveField("Chart.Name", Equal("ck-node-local-dns")),
+				HaveField("Chart.Namespace", Equal("kube-system")),
+				HaveField("State", Equal(tc.expectState)),
+			)))
+			if tc.expectState == helm.StatePresent {
+				g.Expect(h.ApplyCalledWith[0].Values).To(SatisfyAll(
+					HaveKeyWithValue("localIP", "169.254.20.10"),
+					HaveKeyWithValue("upstreamIP", "10.152.183.10"),
+					HaveKeyWithValue("clusterDomain", "cluster.local"),
+				))
+			}
+		})
+	}
+}
+
+// daemonSet returns the node-local-dns daemonset, marked as disabled at the given time if it is not zero.
+func daemonSet(disabledAt time.Time) *appsv1.DaemonSet {
+	daemonset := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: node_local_dns.DaemonSetName, Namespace: "kube-system"}}
+	if !disabledAt.IsZero() {
+		daemonset.Annotations = map[string]string{"k8sd.io/node-local-dns-disabled-at": disabledAt.UTC().Format(time.RFC3339)}
+	}
+	return daemonset
+}
+
+func pod(name string, createdAt time.Time, dnsPolicy corev1.DNSPolicy, hostNetwork bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(createdAt)},
+		Spec:       corev1.PodSpec{DNSPolicy: dnsPolicy, HostNetwork: hostNetwork},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
@@ -0,0 +1,38 @@
+// Package images describes the container images that are deployed by k8sd.
+package images
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Image is a container image reference.
+type Image struct {
+	// Repository is the image repository, including the registry, e.g. "ghcr.io/canonical/coredns".
+	Repository string
+	// Tag is the image tag, e.g. "1.11.1-ck4".
+	Tag string
+}
+
+// String returns the full image reference, e.g. "ghcr.io/canonical/coredns:1.11.1-ck4".
+func (i Image) String() string {
+	return fmt.Sprintf("%s:%s", i.Repository, i.Tag)
+}
+
+// WithRegistry returns a copy of the image that is pulled from a different registry.
+// The registry may include a path prefix, e.g. "registry.internal:5000/mirror".
+// WithRegistry returns the image unchanged if registry is empty.
+func (i Image) WithRegistry(registry string) Image {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "" {
+		return i
+	}
+
+	repository := i.Repository
+	// the first path component is a registry if it looks like a hostname, otherwise the image is from docker.io
+	if parts := strings.SplitN(repository, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		repository = parts[1]
+	}
+
+	return Image{Repository: fmt.Sprintf("%s/%s", registry, repository), Tag: i.Tag}
+}
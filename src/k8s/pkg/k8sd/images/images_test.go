@@ -0,0 +1,27 @@
+package images_test
+
+import (
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/images"
+	. "github.com/onsi/gomega"
+)
+
+func TestWithRegistry(t *testing.T) {
+	for _, tc := range []struct {
+		image    images.Image
+		registry string
+		expect   string
+	}{
+		{image: images.Image{Repository: "ghcr.io/canonical/coredns", Tag: "1.11.1"}, expect: "ghcr.io/canonical/coredns:1.11.1"},
+		{image: images.Image{Repository: "ghcr.io/canonical/coredns", Tag: "1.11.1"}, registry: "registry.internal", expect: "registry.internal/canonical/coredns:1.11.1"},
+		{image: images.Image{Repository: "ghcr.io/canonical/coredns", Tag: "1.11.1"}, registry: "registry.internal:5000/mirror/", expect: "registry.internal:5000/mirror/canonical/coredns:1.11.1"},
+		{image: images.Image{Repository: "localhost/pause", Tag: "3.10"}, registry: "registry.internal", expect: "registry.internal/pause:3.10"},
+		{image: images.Image{Repository: "library/busybox", Tag: "latest"}, registry: "registry.internal", expect: "registry.internal/library/busybox:latest"},
+	} {
+		t.Run(tc.expect, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tc.image.WithRegistry(tc.registry).String()).To(Equal(tc.expect))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package pki
 
 import (
 	"crypto/x509/pkix"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 )
 
 // ControlPlanePKI is a list of all certificates we require for a control plane node.
@@ -300,3 +302,52 @@ func (c *ControlPlanePKI) CompleteCertificates() error {
 
 	return nil
 }
+
+// ErrNoCAKey is returned by EnsureServerCertificateSANs if the certificate must be re-issued, but the kubernetes CA key is not available.
+var ErrNoCAKey = errors.New("using an external kubernetes CA without a key, the certificate must be re-issued manually")
+
+// EnsureServerCertificateSANs re-issues a server certificate if it does not include all of dnsSANs and ipSANs.
+// The re-issued certificate keeps the subject, SANs and expiry date of the existing certificate, and is signed by the kubernetes CA.
+// EnsureServerCertificateSANs returns the new certificate and key, and true if the certificate was re-issued.
+func EnsureServerCertificateSANs(certPEM string, caCertPEM string, caKeyPEM string, dnsSANs []string, ipSANs []net.IP) (string, string, bool, error) {
+	cert, _, err := loadCertificate(certPEM, "")
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	newDNSSANs := cert.DNSNames
+	for _, name := range dnsSANs {
+		if !slices.Contains(newDNSSANs, name) {
+			newDNSSANs = append(newDNSSANs, name)
+		}
+	}
+	newIPSANs := cert.IPAddresses
+	for _, ip := range ipSANs {
+		if !slices.ContainsFunc(newIPSANs, ip.Equal) {
+			newIPSANs = append(newIPSANs, ip)
+		}
+	}
+	if len(newDNSSANs) == len(cert.DNSNames) && len(newIPSANs) == len(cert.IPAddresses) {
+		return "", "", false, nil
+	}
+
+	caCert, caKey, err := loadCertificate(caCertPEM, caKeyPEM)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to load kubernetes CA: %w", err)
+	}
+	if caKey == nil {
+		return "", "", false, ErrNoCAKey
+	}
+
+	template, err := generateCertificate(cert.Subject, 1, false, newDNSSANs, newIPSANs)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to generate certificate: %w", err)
+	}
+	template.NotAfter = cert.NotAfter
+
+	newCert, newKey, err := signCertificate(template, 2048, caCert, &caKey.PublicKey, caKey)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	return newCert, newKey, true, nil
+}
@@ -136,3 +136,53 @@ func TestControlPlaneCertificates(t *testing.T) {
 		})
 	})
 }
+
+func TestEnsureServerCertificateSANs(t *testing.T) {
+	c := pki.NewControlPlanePKI(pki.ControlPlanePKIOpts{
+		Hostname:          "h1",
+		Years:             10,
+		AllowSelfSignedCA: true,
+	})
+
+	g := NewWithT(t)
+	g.Expect(c.CompleteCertificates()).To(Succeed())
+
+	parse := func(g Gomega, certPEM string) *x509.Certificate {
+		block, _ := pem.Decode([]byte(certPEM))
+		g.Expect(block).ToNot(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		g.Expect(err).ToNot(HaveOccurred())
+		return cert
+	}
+
+	t.Run("AddSANs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cert, key, changed, err := pki.EnsureServerCertificateSANs(c.APIServerCert, c.CACert, c.CAKey, []string{"k8s.internal"}, []net.IP{net.ParseIP("10.0.0.100")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changed).To(BeTrue())
+		g.Expect(key).ToNot(BeEmpty())
+
+		old := parse(g, c.APIServerCert)
+		new := parse(g, cert)
+		g.Expect(new.Subject.CommonName).To(Equal("kube-apiserver"))
+		g.Expect(new.NotAfter).To(Equal(old.NotAfter))
+		g.Expect(new.DNSNames).To(ContainElements(append(old.DNSNames, "k8s.internal")))
+		g.Expect(new.IPAddresses).To(ContainElement(net.ParseIP("10.0.0.100").To4()))
+
+		t.Run("Unchanged", func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, _, changed, err := pki.EnsureServerCertificateSANs(cert, c.CACert, c.CAKey, []string{"k8s.internal"}, []net.IP{net.ParseIP("10.0.0.100")})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changed).To(BeFalse())
+		})
+	})
+
+	t.Run("ExternalCA", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, _, _, err := pki.EnsureServerCertificateSANs(c.APIServerCert, c.CACert, "", []string{"k8s.internal"}, nil)
+		g.Expect(err).To(MatchError(pki.ErrNoCAKey))
+	})
+}
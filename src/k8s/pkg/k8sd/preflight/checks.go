@@ -0,0 +1,203 @@
+package preflight
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
+)
+
+var (
+	// requiredKernelModules are the kernel modules that containerd and the network need.
+	requiredKernelModules = []string{"overlay", "br_netfilter"}
+
+	// serviceArgumentFiles are the argument files of the Kubernetes services, which are written when a node is bootstrapped or joins.
+	// The arguments of k8sd are not included, as the snap install hook creates them.
+	serviceArgumentFiles = []string{
+		"containerd",
+		"k8s-apiserver-proxy",
+		"k8s-dqlite",
+		"kube-apiserver",
+		"kube-controller-manager",
+		"kube-proxy",
+		"kube-scheduler",
+		"kubelet",
+	}
+
+	// minDiskSpaceFail is the free disk space below which the node cannot pull images.
+	minDiskSpaceFail uint64 = 2 << 30
+	// minDiskSpaceWarn is the free disk space below which image garbage collection is likely to kick in.
+	minDiskSpaceWarn uint64 = 10 << 30
+
+	// minClockTime is a point in time that the system clock must be after. Certificates
+	// generated with an earlier clock are not valid once the clock is corrected.
+	minClockTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// checkPorts fails if any of the ports is already in use on the node.
+func checkPorts(ports []int) Result {
+	var busy []string
+	for _, port := range ports {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			busy = append(busy, fmt.Sprintf("%d", port))
+			continue
+		}
+		l.Close()
+	}
+	if len(busy) > 0 {
+		return Result{Name: "ports", Status: StatusFail, Message: fmt.Sprintf("ports are already in use: %s", strings.Join(busy, ", "))}
+	}
+	return Result{Name: "ports", Status: StatusPass, Message: "required ports are available"}
+}
+
+// checkExistingState fails if any of the directories or files contains state from a previous installation.
+// Subdirectories are ignored, as they may be created by the snap or contain extra configuration.
+func checkExistingState(dirs []string, files []string) Result {
+	var found []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return Result{Name: "existing-state", Status: StatusFail, Message: fmt.Sprintf("failed to read %s: %v", dir, err)}
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				found = append(found, dir)
+				break
+			}
+		}
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			found = append(found, file)
+		} else if !os.IsNotExist(err) {
+			return Result{Name: "existing-state", Status: StatusFail, Message: fmt.Sprintf("failed to check %s: %v", file, err)}
+		}
+	}
+	if len(found) > 0 {
+		return Result{Name: "existing-state", Status: StatusFail, Message: fmt.Sprintf("found files from a previous installation in %s", strings.Join(found, ", "))}
+	}
+	return Result{Name: "existing-state", Status: StatusPass, Message: "no existing state found"}
+}
+
+// checkSwap warns if swap is enabled. swapsFile is the path to /proc/swaps.
+func checkSwap(swapsFile string) Result {
+	f, err := os.Open(swapsFile)
+	if err != nil {
+		return Result{Name: "swap", Status: StatusWarn, Message: fmt.Sprintf("failed to check swap: %v", err)}
+	}
+	defer f.Close()
+
+	var devices []string
+	scanner := bufio.NewScanner(f)
+	for lineNo := 0; scanner.Scan(); lineNo++ {
+		// the first line is the header
+		if fields := strings.Fields(scanner.Text()); lineNo > 0 && len(fields) > 0 {
+			devices = append(devices, fields[0])
+		}
+	}
+	if len(devices) > 0 {
+		return Result{Name: "swap", Status: StatusWarn, Message: fmt.Sprintf("swap is enabled on %s, which may affect workload performance", strings.Join(devices, ", "))}
+	}
+	return Result{Name: "swap", Status: StatusPass, Message: "swap is disabled"}
+}
+
+// checkKernelModules warns if any of the modules is not loaded. moduleDir is the path to /sys/module.
+func checkKernelModules(moduleDir string, modules []string) Result {
+	var missing []string
+	for _, module := range modules {
+		if exists, _ := utils.FileExists(moduleDir, module); !exists {
+			missing = append(missing, module)
+		}
+	}
+	if len(missing) > 0 {
+		return Result{Name: "kernel-modules", Status: StatusWarn, Message: fmt.Sprintf("kernel modules are not loaded: %s", strings.Join(missing, ", "))}
+	}
+	return Result{Name: "kernel-modules", Status: StatusPass, Message: "required kernel modules are loaded"}
+}
+
+// checkDiskSpace checks the free space on the filesystem that holds dir.
+// If dir does not exist yet, the closest existing parent directory is checked.
+func checkDiskSpace(dir string) Result {
+	for {
+		if _, err := os.Stat(dir); err == nil || dir == filepath.Dir(dir) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return Result{Name: "disk-space", Status: StatusWarn, Message: fmt.Sprintf("failed to check free disk space on %s: %v", dir, err)}
+	}
+	free := stat.Bavail * uint64(stat.Bsize)
+
+	switch {
+	case free < minDiskSpaceFail:
+		return Result{Name: "disk-space", Status: StatusFail, Message: fmt.Sprintf("only %d MiB free on %s, at least %d MiB are required", free>>20, dir, minDiskSpaceFail>>20)}
+	case free < minDiskSpaceWarn:
+		return Result{Name: "disk-space", Status: StatusWarn, Message: fmt.Sprintf("only %d MiB free on %s, at least %d MiB are recommended", free>>20, dir, minDiskSpaceWarn>>20)}
+	}
+	return Result{Name: "disk-space", Status: StatusPass, Message: fmt.Sprintf("%d MiB free on %s", free>>20, dir)}
+}
+
+// checkClock fails if the system clock is obviously wrong and warns if it is not synchronized.
+func checkClock() Result {
+	if now := time.Now(); now.Before(minClockTime) {
+		return Result{Name: "clock", Status: StatusFail, Message: fmt.Sprintf("system clock is set to %s, which is in the past", now.UTC().Format(time.RFC3339))}
+	}
+
+	// TIME_ERROR (5) means that the kernel clock is not synchronized, see adjtimex(2).
+	var timex syscall.Timex
+	if state, err := syscall.Adjtimex(&timex); err != nil {
+		return Result{Name: "clock", Status: StatusWarn, Message: fmt.Sprintf("failed to check clock synchronization: %v", err)}
+	} else if state == 5 {
+		return Result{Name: "clock", Status: StatusWarn, Message: "system clock is not synchronized, certificates and tokens may be rejected by other nodes"}
+	}
+	return Result{Name: "clock", Status: StatusPass, Message: "system clock is synchronized"}
+}
+
+// checkNetworkMounts checks the mounts that Cilium needs.
+// Under strict confinement, bpf and cgroup2 must already be mounted, as Cilium cannot mount them.
+// Under classic confinement, /sys must be a shared mount so that Cilium can mount them.
+func checkNetworkMounts(ctx context.Context, snap snap.Snap) Result {
+	if snap.Strict() {
+		var missing []string
+		for _, fsType := range []string{"bpf", "cgroup2"} {
+			if _, err := utils.GetMountPath(fsType); err != nil {
+				if !errors.Is(err, utils.ErrUnknownMount) {
+					return Result{Name: "mounts", Status: StatusFail, Message: fmt.Sprintf("failed to get %s mount path: %v", fsType, err)}
+				}
+				missing = append(missing, fsType)
+			}
+		}
+		if len(missing) > 0 {
+			return Result{Name: "mounts", Status: StatusFail, Message: fmt.Sprintf("%s must be mounted for the network to work under strict confinement", strings.Join(missing, " and "))}
+		}
+		return Result{Name: "mounts", Status: StatusPass, Message: "bpf and cgroup2 are mounted"}
+	}
+
+	p, err := utils.GetMountPropagation("/sys")
+	if err != nil {
+		return Result{Name: "mounts", Status: StatusFail, Message: fmt.Sprintf("failed to get mount propagation for /sys: %v", err)}
+	}
+	if p == "private" {
+		if onLXD, _ := snap.OnLXD(ctx); onLXD {
+			return Result{Name: "mounts", Status: StatusFail, Message: "/sys is not a shared mount on the LXD container, this might be resolved by updating LXD on the host to version 5.0.2 or newer"}
+		}
+		return Result{Name: "mounts", Status: StatusFail, Message: "/sys is not a shared mount"}
+	}
+	return Result{Name: "mounts", Status: StatusPass, Message: "/sys is a shared mount"}
+}
@@ -0,0 +1,154 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/snap/mock"
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckPorts(t *testing.T) {
+	g := NewWithT(t)
+
+	l, err := net.Listen("tcp", ":0")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer l.Close()
+	busyPort := l.Addr().(*net.TCPAddr).Port
+
+	g.Expect(checkPorts(nil).Status).To(Equal(StatusPass))
+
+	result := checkPorts([]int{busyPort})
+	g.Expect(result.Status).To(Equal(StatusFail))
+	g.Expect(result.Message).To(ContainSubstring(fmt.Sprintf("%d", busyPort)))
+}
+
+func TestCheckExistingState(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	pkiDir := filepath.Join(dir, "pki")
+	argsDir := filepath.Join(dir, "args")
+	kubeletArgs := filepath.Join(argsDir, "kubelet")
+
+	g.Expect(checkExistingState([]string{pkiDir}, []string{kubeletArgs}).Status).To(Equal(StatusPass))
+
+	g.Expect(os.MkdirAll(pkiDir, 0700)).To(Succeed())
+	g.Expect(os.MkdirAll(filepath.Join(pkiDir, "etcd"), 0700)).To(Succeed())
+	g.Expect(checkExistingState([]string{pkiDir}, []string{kubeletArgs}).Status).To(Equal(StatusPass))
+
+	g.Expect(os.MkdirAll(argsDir, 0700)).To(Succeed())
+	g.Expect(os.WriteFile(kubeletArgs, []byte("--v=2"), 0600)).To(Succeed())
+	result := checkExistingState([]string{pkiDir}, []string{kubeletArgs})
+	g.Expect(result.Status).To(Equal(StatusFail))
+	g.Expect(result.Message).To(ContainSubstring(kubeletArgs))
+	g.Expect(result.Message).ToNot(ContainSubstring(pkiDir))
+
+	g.Expect(os.WriteFile(filepath.Join(pkiDir, "ca.crt"), []byte("cert"), 0600)).To(Succeed())
+	result = checkExistingState([]string{pkiDir}, nil)
+	g.Expect(result.Status).To(Equal(StatusFail))
+	g.Expect(result.Message).To(ContainSubstring(pkiDir))
+}
+
+func TestRunExistingStateFreshInstall(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			KubernetesPKIDir:    filepath.Join(dir, "pki"),
+			ServiceArgumentsDir: filepath.Join(dir, "args"),
+			K8sDqliteStateDir:   filepath.Join(dir, "k8s-dqlite"),
+			ContainerdRootDir:   filepath.Join(dir, "containerd"),
+		},
+	}
+	// the snap install hook creates the k8sd arguments
+	g.Expect(os.MkdirAll(s.ServiceArgumentsDir(), 0700)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(s.ServiceArgumentsDir(), "k8sd"), []byte("--port=6400"), 0600)).To(Succeed())
+
+	var existingState Result
+	for _, result := range Run(context.Background(), s, Options{ControlPlane: true}) {
+		if result.Name == "existing-state" {
+			existingState = result
+		}
+	}
+	g.Expect(existingState.Status).To(Equal(StatusPass))
+}
+
+func TestCheckSwap(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		content      string
+		expectStatus Status
+	}{
+		{name: "Disabled", content: "Filename\tType\tSize\tUsed\tPriority\n", expectStatus: StatusPass},
+		{name: "Enabled", content: "Filename\tType\tSize\tUsed\tPriority\n/swap.img\tfile\t4194300\t0\t-2\n", expectStatus: StatusWarn},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			file := filepath.Join(t.TempDir(), "swaps")
+			g.Expect(os.WriteFile(file, []byte(tc.content), 0600)).To(Succeed())
+
+			g.Expect(checkSwap(file).Status).To(Equal(tc.expectStatus))
+		})
+	}
+}
+
+func TestCheckKernelModules(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.Mkdir(filepath.Join(dir, "overlay"), 0700)).To(Succeed())
+
+	g.Expect(checkKernelModules(dir, []string{"overlay"}).Status).To(Equal(StatusPass))
+
+	result := checkKernelModules(dir, []string{"overlay", "br_netfilter"})
+	g.Expect(result.Status).To(Equal(StatusWarn))
+	g.Expect(result.Message).To(ContainSubstring("br_netfilter"))
+	g.Expect(result.Message).ToNot(ContainSubstring("overlay"))
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	g := NewWithT(t)
+
+	// a directory that does not exist yet is checked against its closest existing parent
+	dir := filepath.Join(t.TempDir(), "does", "not", "exist")
+
+	defer func(fail, warn uint64) { minDiskSpaceFail, minDiskSpaceWarn = fail, warn }(minDiskSpaceFail, minDiskSpaceWarn)
+
+	minDiskSpaceFail, minDiskSpaceWarn = 0, 0
+	g.Expect(checkDiskSpace(dir).Status).To(Equal(StatusPass))
+
+	minDiskSpaceWarn = 1 << 62
+	g.Expect(checkDiskSpace(dir).Status).To(Equal(StatusWarn))
+
+	minDiskSpaceFail = 1 << 62
+	g.Expect(checkDiskSpace(dir).Status).To(Equal(StatusFail))
+}
+
+func TestFailures(t *testing.T) {
+	results := []Result{
+		{Name: "ports", Status: StatusFail},
+		{Name: "swap", Status: StatusWarn},
+		{Name: "clock", Status: StatusPass},
+		{Name: "mounts", Status: StatusFail},
+	}
+
+	t.Run("None", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(Failures(results, nil)).To(ConsistOf(results[0], results[3]))
+	})
+	t.Run("Some", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(Failures(results, []string{"mounts", "swap"})).To(ConsistOf(results[0]))
+	})
+	t.Run("All", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(Failures(results, []string{"all"})).To(BeEmpty())
+	})
+}
@@ -0,0 +1,86 @@
+// Package preflight implements the checks that run on a node before it is bootstrapped or joins a cluster.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/canonical/k8s/pkg/snap"
+)
+
+// Status is the outcome of a preflight check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of a single preflight check.
+type Result struct {
+	// Name identifies the check. Failures of the check can be ignored by passing the name to Failures.
+	Name string `json:"name" yaml:"name"`
+	// Status is the outcome of the check.
+	Status Status `json:"status" yaml:"status"`
+	// Message describes the outcome of the check.
+	Message string `json:"message" yaml:"message"`
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(r.Status)), r.Name, r.Message)
+}
+
+// Options configure which preflight checks run and with what parameters.
+type Options struct {
+	// ControlPlane is true if the node will run control plane services.
+	ControlPlane bool
+	// Ports are the TCP ports that must be free on the node.
+	Ports []int
+	// Network is true if the built-in network (Cilium) will be deployed.
+	Network bool
+}
+
+// Run executes all preflight checks on the local node and returns their results.
+// Run does not change anything on the node.
+func Run(ctx context.Context, snap snap.Snap, opts Options) []Result {
+	stateDirs := []string{snap.KubernetesPKIDir()}
+	if opts.ControlPlane {
+		stateDirs = append(stateDirs, snap.K8sDqliteStateDir())
+	}
+	stateFiles := make([]string, 0, len(serviceArgumentFiles))
+	for _, service := range serviceArgumentFiles {
+		stateFiles = append(stateFiles, filepath.Join(snap.ServiceArgumentsDir(), service))
+	}
+
+	results := []Result{
+		checkPorts(opts.Ports),
+		checkExistingState(stateDirs, stateFiles),
+		checkSwap("/proc/swaps"),
+		checkKernelModules("/sys/module", requiredKernelModules),
+		checkDiskSpace(snap.ContainerdRootDir()),
+		checkClock(),
+	}
+	if opts.Network {
+		results = append(results, checkNetworkMounts(ctx, snap))
+	}
+	return results
+}
+
+// Failures returns the results that failed and are not in the ignore list.
+// The ignore list contains check names, or "all" to ignore all failures.
+func Failures(results []Result, ignore []string) []Result {
+	if slices.Contains(ignore, "all") {
+		return nil
+	}
+	var failures []Result
+	for _, result := range results {
+		if result.Status == StatusFail && !slices.Contains(ignore, result.Name) {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
@@ -3,6 +3,7 @@ package setup
 import (
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"path"
 
@@ -123,3 +124,26 @@ func EnsureWorkerPKI(snap snap.Snap, certificates *pki.WorkerNodePKI) (bool, err
 		path.Join(snap.KubernetesPKIDir(), "kubelet.key"):   certificates.KubeletKey,
 	})
 }
+
+// EnsureAPIServerCertificateSANs re-issues the kube-apiserver certificate of the node if it does not include all
+// of dnsSANs and ipSANs. The certificate is signed by the kubernetes CA.
+// It returns true if the certificate was re-issued and any error that occured.
+func EnsureAPIServerCertificateSANs(snap snap.Snap, caCert string, caKey string, dnsSANs []string, ipSANs []net.IP) (bool, error) {
+	certFile := path.Join(snap.KubernetesPKIDir(), "apiserver.crt")
+	b, err := os.ReadFile(certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read kube-apiserver certificate: %w", err)
+	}
+
+	cert, key, changed, err := pki.EnsureServerCertificateSANs(string(b), caCert, caKey, dnsSANs, ipSANs)
+	if err != nil {
+		return false, fmt.Errorf("failed to re-issue kube-apiserver certificate: %w", err)
+	} else if !changed {
+		return false, nil
+	}
+
+	return ensureFiles(snap.UID(), snap.GID(), 0600, map[string]string{
+		certFile: cert,
+		path.Join(snap.KubernetesPKIDir(), "apiserver.key"): key,
+	})
+}
@@ -1,11 +1,14 @@
 package setup
 
 import (
+	"bytes"
 	_ "embed"
 	"fmt"
+	"math"
 	"os"
 	"path"
 
+	"github.com/canonical/k8s/pkg/k8sd/images"
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
@@ -15,8 +18,16 @@ import (
 
 var (
 	containerdConfigTomlTemplate = mustTemplate("containerd", "config.toml")
+
+	// containerdPauseImage is the sandbox image used by containerd.
+	containerdPauseImage = images.Image{Repository: "ghcr.io/canonical/k8s-snap/pause", Tag: "3.10"}
 )
 
+// ContainerdImages returns the images that are needed by containerd on all nodes.
+func ContainerdImages() []images.Image {
+	return []images.Image{containerdPauseImage}
+}
+
 type containerdConfigTomlConfig struct {
 	CNIConfDir        string
 	CNIBinDir         string
@@ -35,7 +46,18 @@ type containerdConfigPlugins struct {
 }
 
 type containerdConfigPluginsCRI struct {
-	Registry containerdConfigPluginsCRIRegistry `toml:"registry,omitempty"`
+	Containerd containerdConfigPluginsCRIContainerd `toml:"containerd,omitempty"`
+	Registry   containerdConfigPluginsCRIRegistry   `toml:"registry,omitempty"`
+}
+
+type containerdConfigPluginsCRIContainerd struct {
+	Runtimes map[string]containerdConfigPluginsCRIContainerdRuntime `toml:"runtimes,omitempty"`
+}
+
+type containerdConfigPluginsCRIContainerdRuntime struct {
+	RuntimeType string         `toml:"runtime_type,omitempty"`
+	RuntimePath string         `toml:"runtime_path,omitempty"`
+	Options     map[string]any `toml:"options,omitempty"`
 }
 
 type containerdConfigPluginsCRIRegistry struct {
@@ -58,9 +80,11 @@ type containerdHostsConfig struct {
 }
 
 type containerdHostsConfigHost struct {
-	Capabilities []string `toml:"capabilities,omitempty"`
-	SkipVerify   bool     `toml:"skip_verify,omitempty"`
-	OverridePath bool     `toml:"override_path,omitempty"`
+	Capabilities []string   `toml:"capabilities,omitempty"`
+	SkipVerify   bool       `toml:"skip_verify,omitempty"`
+	OverridePath bool       `toml:"override_path,omitempty"`
+	CA           string     `toml:"ca,omitempty"`
+	Client       [][]string `toml:"client,omitempty"`
 }
 
 func containerdAuthConfig(registries []types.ContainerdRegistry) containerdConfig {
@@ -91,17 +115,74 @@ func containerdAuthConfig(registries []types.ContainerdRegistry) containerdConfi
 	}
 }
 
-func containerdHostConfig(registry types.ContainerdRegistry) containerdHostsConfig {
+func containerdRuntimesConfig(handlers []types.ContainerdRuntimeHandler) containerdConfig {
+	runtimes := make(map[string]containerdConfigPluginsCRIContainerdRuntime, len(handlers))
+	for _, handler := range handlers {
+		runtimeType := handler.RuntimeType
+		if runtimeType == "" {
+			runtimeType = "io.containerd.runc.v2"
+		}
+
+		var options map[string]any
+		if len(handler.Options) > 0 {
+			options = make(map[string]any, len(handler.Options))
+			for k, v := range handler.Options {
+				// numbers are decoded as float64 from JSON, render whole numbers as integers
+				if f, ok := v.(float64); ok && f == math.Trunc(f) {
+					v = int64(f)
+				}
+				options[k] = v
+			}
+		}
+
+		runtimes[handler.Name] = containerdConfigPluginsCRIContainerdRuntime{
+			RuntimeType: runtimeType,
+			RuntimePath: handler.BinaryPath,
+			Options:     options,
+		}
+	}
+
+	return containerdConfig{
+		Version: 2,
+		Plugins: containerdConfigPlugins{
+			CRI: containerdConfigPluginsCRI{
+				Containerd: containerdConfigPluginsCRIContainerd{
+					Runtimes: runtimes,
+				},
+			},
+		},
+	}
+}
+
+// containerdHostConfig renders the hosts.toml configuration for a registry.
+// certsDir is the directory where the CA and client certificates of the registry are stored.
+func containerdHostConfig(registry types.ContainerdRegistry, certsDir string) containerdHostsConfig {
 	if len(registry.URLs) == 0 {
 		return containerdHostsConfig{}
 	}
 
+	capabilities := registry.Capabilities
+	if len(capabilities) == 0 {
+		capabilities = []string{"pull", "resolve"}
+	}
+
+	var ca string
+	if registry.CACert != "" {
+		ca = path.Join(certsDir, "ca.crt")
+	}
+	var client [][]string
+	if registry.ClientCert != "" && registry.ClientKey != "" {
+		client = [][]string{{path.Join(certsDir, "client.crt"), path.Join(certsDir, "client.key")}}
+	}
+
 	hosts := make(map[string]containerdHostsConfigHost, len(registry.URLs))
 	for _, url := range registry.URLs {
 		hosts[url] = containerdHostsConfigHost{
-			Capabilities: []string{"pull", "resolve"},
+			Capabilities: capabilities,
 			SkipVerify:   registry.SkipVerify,
 			OverridePath: registry.OverridePath,
+			CA:           ca,
+			Client:       client,
 		}
 	}
 
@@ -112,21 +193,11 @@ func containerdHostConfig(registry types.ContainerdRegistry) containerdHostsConf
 }
 
 // Containerd configures configuration and arguments for containerd on the local node.
-// Optionally, a number of registry mirrors and auths can be configured.
-func Containerd(snap snap.Snap, registries []types.ContainerdRegistry) error {
-	configToml, err := os.OpenFile(path.Join(snap.ContainerdConfigDir(), "config.toml"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open config.toml: %w", err)
-	}
-	defer configToml.Close()
-	if err := containerdConfigTomlTemplate.Execute(configToml, containerdConfigTomlConfig{
-		CNIConfDir:        snap.CNIConfDir(),
-		CNIBinDir:         snap.CNIBinDir(),
-		ImportsDir:        snap.ContainerdExtraConfigDir(),
-		RegistryConfigDir: snap.ContainerdRegistryConfigDir(),
-		PauseImage:        "ghcr.io/canonical/k8s-snap/pause:3.10",
-	}); err != nil {
-		return fmt.Errorf("failed to write config.toml: %w", err)
+// Optionally, a number of registry mirrors and auths, as well as additional runtime handlers can be configured.
+// The sandbox image is pulled from imageRegistry, if set.
+func Containerd(snap snap.Snap, registries []types.ContainerdRegistry, runtimeHandlers []types.ContainerdRuntimeHandler, imageRegistry string) error {
+	if _, err := ContainerdConfig(snap, imageRegistry); err != nil {
+		return err
 	}
 
 	if _, err := snaputil.UpdateServiceArguments(snap, "containerd", map[string]string{
@@ -170,35 +241,162 @@ func Containerd(snap snap.Snap, registries []types.ContainerdRegistry) error {
 		}
 	}
 
+	if _, err := ContainerdRegistries(snap, registries); err != nil {
+		return fmt.Errorf("failed to configure registries: %w", err)
+	}
+
+	if _, err := ContainerdRuntimeHandlers(snap, runtimeHandlers); err != nil {
+		return fmt.Errorf("failed to configure runtime handlers: %w", err)
+	}
+
+	return nil
+}
+
+// ContainerdConfig renders the config.toml of containerd. The sandbox image is pulled from imageRegistry, if set.
+// ContainerdConfig returns true if containerd must be restarted to apply the changes.
+func ContainerdConfig(snap snap.Snap, imageRegistry string) (bool, error) {
+	var b bytes.Buffer
+	if err := containerdConfigTomlTemplate.Execute(&b, containerdConfigTomlConfig{
+		CNIConfDir:        snap.CNIConfDir(),
+		CNIBinDir:         snap.CNIBinDir(),
+		ImportsDir:        snap.ContainerdExtraConfigDir(),
+		RegistryConfigDir: snap.ContainerdRegistryConfigDir(),
+		PauseImage:        containerdPauseImage.WithRegistry(imageRegistry).String(),
+	}); err != nil {
+		return false, fmt.Errorf("failed to render config.toml: %w", err)
+	}
+	changed, err := writeFileIfChanged(path.Join(snap.ContainerdConfigDir(), "config.toml"), b.Bytes(), 0600)
+	if err != nil {
+		return false, fmt.Errorf("failed to write config.toml: %w", err)
+	}
+	return changed, nil
+}
+
+// ContainerdRuntimeHandlers renders additional runtime handlers for containerd in a conf.d drop-in.
+// The drop-in is removed if no runtime handlers are configured.
+// ContainerdRuntimeHandlers returns true if containerd must be restarted to apply the changes.
+func ContainerdRuntimeHandlers(snap snap.Snap, runtimeHandlers []types.ContainerdRuntimeHandler) (bool, error) {
+	runtimesFile := path.Join(snap.ContainerdExtraConfigDir(), "k8sd-runtimes.toml")
+	if len(runtimeHandlers) == 0 {
+		if err := os.Remove(runtimesFile); err != nil {
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to remove runtime handler configurations: %w", err)
+		}
+		return true, nil
+	}
+
+	b, err := toml.Marshal(containerdRuntimesConfig(runtimeHandlers))
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal runtime handler configurations: %w", err)
+	}
+	changed, err := writeFileIfChanged(runtimesFile, b, 0600)
+	if err != nil {
+		return false, fmt.Errorf("failed to write runtime handler configurations: %w", err)
+	}
+	return changed, nil
+}
+
+// containerdManagedRegistryMarker is created in the hosts.d directory of each registry that is managed by k8sd.
+// Registry directories without the marker have been created by the user and are never removed.
+const containerdManagedRegistryMarker = ".k8sd-managed"
+
+// ContainerdRegistries configures registry mirrors, authentication and TLS certificates for containerd.
+// Registries that were previously configured by k8sd but are no longer in the list are removed.
+// ContainerdRegistries returns true if containerd must be restarted to apply the changes.
+func ContainerdRegistries(snap snap.Snap, registries []types.ContainerdRegistry) (bool, error) {
+	var mustRestart bool
+
 	// registry auths
+	authsFile := path.Join(snap.ContainerdExtraConfigDir(), "k8sd-auths.toml")
 	if authConfig := containerdAuthConfig(registries); len(authConfig.Plugins.CRI.Registry.Configs) > 0 {
 		b, err := toml.Marshal(authConfig)
 		if err != nil {
-			return fmt.Errorf("failed to marshal registry auth configurations: %w", err)
+			return false, fmt.Errorf("failed to marshal registry auth configurations: %w", err)
 		}
 
-		if err := os.WriteFile(path.Join(snap.ContainerdExtraConfigDir(), "k8sd-auths.toml"), b, 0600); err != nil {
-			return fmt.Errorf("failed to write registry auth configurations: %w", err)
+		changed, err := writeFileIfChanged(authsFile, b, 0600)
+		if err != nil {
+			return false, fmt.Errorf("failed to write registry auth configurations: %w", err)
 		}
+		mustRestart = mustRestart || changed
+	} else if err := os.Remove(authsFile); err == nil {
+		mustRestart = true
+	} else if !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove registry auth configurations: %w", err)
 	}
 
-	// registry mirrors
+	// registry mirrors and certificates
+	managed := make(map[string]struct{}, len(registries))
 	for _, registry := range registries {
-		if hostConfig := containerdHostConfig(registry); len(hostConfig.Host) > 0 {
-			b, err := toml.Marshal(hostConfig)
-			if err != nil {
-				return fmt.Errorf("failed to render registry mirrors for %s: %w", registry.Host, err)
-			}
+		dir := path.Join(snap.ContainerdRegistryConfigDir(), registry.Host)
+		hostConfig := containerdHostConfig(registry, dir)
+		if len(hostConfig.Host) == 0 {
+			continue
+		}
 
-			dir := path.Join(snap.ContainerdRegistryConfigDir(), registry.Host)
-			if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
-				return fmt.Errorf("failed to create directory for registry %s: %w", registry.Host, err)
+		b, err := toml.Marshal(hostConfig)
+		if err != nil {
+			return false, fmt.Errorf("failed to render registry mirrors for %s: %w", registry.Host, err)
+		}
+
+		if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+			return false, fmt.Errorf("failed to create directory for registry %s: %w", registry.Host, err)
+		}
+		for _, file := range []struct {
+			name     string
+			contents string
+		}{
+			{name: containerdManagedRegistryMarker},
+			{name: "hosts.toml", contents: string(b)},
+			{name: "ca.crt", contents: registry.CACert},
+			{name: "client.crt", contents: registry.ClientCert},
+			{name: "client.key", contents: registry.ClientKey},
+		} {
+			filePath := path.Join(dir, file.name)
+			if file.contents == "" && file.name != containerdManagedRegistryMarker {
+				if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+					return false, fmt.Errorf("failed to remove %s for registry %s: %w", file.name, registry.Host, err)
+				}
+				continue
 			}
-			if err := os.WriteFile(path.Join(dir, "hosts.toml"), b, 0600); err != nil {
-				return fmt.Errorf("failed to write hosts.toml for registry %s: %w", registry.Host, err)
+			if _, err := writeFileIfChanged(filePath, []byte(file.contents), 0600); err != nil {
+				return false, fmt.Errorf("failed to write %s for registry %s: %w", file.name, registry.Host, err)
 			}
 		}
+		managed[registry.Host] = struct{}{}
 	}
 
-	return nil
+	// remove registries that are no longer configured
+	entries, err := os.ReadDir(snap.ContainerdRegistryConfigDir())
+	if err != nil {
+		return false, fmt.Errorf("failed to list registry configurations: %w", err)
+	}
+	for _, entry := range entries {
+		if _, ok := managed[entry.Name()]; ok || !entry.IsDir() {
+			continue
+		}
+		dir := path.Join(snap.ContainerdRegistryConfigDir(), entry.Name())
+		if _, err := os.Stat(path.Join(dir, containerdManagedRegistryMarker)); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return false, fmt.Errorf("failed to remove configuration for registry %s: %w", entry.Name(), err)
+		}
+	}
+
+	return mustRestart, nil
+}
+
+// writeFileIfChanged writes data to the file only if the contents are different.
+// writeFileIfChanged returns true if the file was written.
+func writeFileIfChanged(file string, data []byte, perm os.FileMode) (bool, error) {
+	if b, err := os.ReadFile(file); err == nil && bytes.Equal(b, data) {
+		return false, nil
+	}
+	if err := os.WriteFile(file, data, perm); err != nil {
+		return false, err
+	}
+	return true, nil
 }
@@ -53,7 +53,18 @@ func TestContainerd(t *testing.T) {
 			URLs:  []string{"https://ghcr.mirror.internal"},
 			Token: "token",
 		},
-	})).To(Succeed())
+	}, []types.ContainerdRuntimeHandler{
+		{
+			Name:        "gvisor",
+			RuntimeType: "io.containerd.runsc.v1",
+			BinaryPath:  "/usr/local/bin/containerd-shim-runsc-v1",
+			Options:     map[string]any{"TypeUrl": "io.containerd.runsc.v1.options", "ConfigPath": "/etc/containerd/runsc.toml"},
+		},
+		{
+			Name:    "runc-systemd",
+			Options: map[string]any{"SystemdCgroup": true, "ShimCgroup": "", "IoUid": float64(1000)},
+		},
+	}, "registry.internal:5000")).To(Succeed())
 
 	t.Run("Config", func(t *testing.T) {
 		g := NewWithT(t)
@@ -64,6 +75,7 @@ func TestContainerd(t *testing.T) {
 			ContainSubstring(fmt.Sprintf(`conf_dir = "%s"`, path.Join(dir, "cni-netd"))),
 			ContainSubstring(fmt.Sprintf(`bin_dir = "%s"`, path.Join(dir, "opt-cni-bin"))),
 			ContainSubstring(fmt.Sprintf(`config_path = "%s"`, path.Join(dir, "containerd-hosts"))),
+			ContainSubstring(`sandbox_image = "registry.internal:5000/canonical/k8s-snap/pause:3.10"`),
 		))
 
 		info, err := os.Stat(path.Join(dir, "containerd", "config.toml"))
@@ -116,6 +128,39 @@ func TestContainerd(t *testing.T) {
 		}
 	})
 
+	t.Run("RuntimeHandlers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b, err := os.ReadFile(path.Join(dir, "containerd-confd", "k8sd-runtimes.toml"))
+		g.Expect(err).To(BeNil())
+		g.Expect(string(b)).To(Equal(`version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.gvisor]
+          runtime_path = "/usr/local/bin/containerd-shim-runsc-v1"
+          runtime_type = "io.containerd.runsc.v1"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.gvisor.options]
+            ConfigPath = "/etc/containerd/runsc.toml"
+            TypeUrl = "io.containerd.runsc.v1.options"
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc-systemd]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc-systemd.options]
+            IoUid = 1000
+            ShimCgroup = ""
+            SystemdCgroup = true
+`))
+	})
+
 	t.Run("Registries", func(t *testing.T) {
 		t.Run("Mirrors", func(t *testing.T) {
 			t.Run("docker.io", func(t *testing.T) {
@@ -185,3 +230,182 @@ func TestContainerd(t *testing.T) {
 		})
 	})
 }
+
+func TestContainerdConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ContainerdConfigDir: dir,
+		},
+	}
+
+	mustRestart, err := setup.ContainerdConfig(s, "")
+	g.Expect(err).To(BeNil())
+	g.Expect(mustRestart).To(BeTrue())
+
+	t.Run("NoChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mustRestart, err := setup.ContainerdConfig(s, "")
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeFalse())
+	})
+
+	t.Run("ImageRegistry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mustRestart, err := setup.ContainerdConfig(s, "registry.internal:5000")
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeTrue())
+
+		b, err := os.ReadFile(path.Join(dir, "config.toml"))
+		g.Expect(err).To(BeNil())
+		g.Expect(string(b)).To(ContainSubstring(`sandbox_image = "registry.internal:5000/canonical/k8s-snap/pause:3.10"`))
+	})
+}
+
+func TestContainerdRuntimeHandlers(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ContainerdExtraConfigDir: dir,
+		},
+	}
+
+	handlers := []types.ContainerdRuntimeHandler{{Name: "kata", RuntimeType: "io.containerd.kata.v2"}}
+
+	mustRestart, err := setup.ContainerdRuntimeHandlers(s, handlers)
+	g.Expect(err).To(BeNil())
+	g.Expect(mustRestart).To(BeTrue())
+
+	t.Run("NoChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mustRestart, err := setup.ContainerdRuntimeHandlers(s, handlers)
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeFalse())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mustRestart, err := setup.ContainerdRuntimeHandlers(s, nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeTrue())
+
+		_, err = os.Stat(path.Join(dir, "k8sd-runtimes.toml"))
+		g.Expect(os.IsNotExist(err)).To(BeTrue())
+
+		mustRestart, err = setup.ContainerdRuntimeHandlers(s, nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeFalse())
+	})
+}
+
+func TestContainerdRegistries(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := &mock.Snap{
+		Mock: mock.Mock{
+			ContainerdRegistryConfigDir: path.Join(dir, "containerd-hosts"),
+			ContainerdExtraConfigDir:    path.Join(dir, "containerd-confd"),
+		},
+	}
+	g.Expect(os.MkdirAll(s.Mock.ContainerdRegistryConfigDir, 0700)).To(Succeed())
+	g.Expect(os.MkdirAll(s.Mock.ContainerdExtraConfigDir, 0700)).To(Succeed())
+
+	// registry configured manually by the user, must not be touched
+	g.Expect(os.MkdirAll(path.Join(dir, "containerd-hosts", "user.internal"), 0700)).To(Succeed())
+
+	mustRestart, err := setup.ContainerdRegistries(s, []types.ContainerdRegistry{
+		{
+			Host:         "registry.internal",
+			URLs:         []string{"https://registry.internal:5000"},
+			Capabilities: []string{"pull", "resolve", "push"},
+			Username:     "username",
+			Password:     "password",
+			CACert:       "CA CERT",
+			ClientCert:   "CLIENT CERT",
+			ClientKey:    "CLIENT KEY",
+		},
+	})
+	g.Expect(err).To(BeNil())
+	g.Expect(mustRestart).To(BeTrue())
+
+	registryDir := path.Join(dir, "containerd-hosts", "registry.internal")
+	t.Run("HostsTOML", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b, err := os.ReadFile(path.Join(registryDir, "hosts.toml"))
+		g.Expect(err).To(BeNil())
+		g.Expect(string(b)).To(Equal(fmt.Sprintf(`server = "https://registry.internal:5000"
+
+[hosts]
+
+  [hosts."https://registry.internal:5000"]
+    ca = "%s"
+    capabilities = ["pull", "resolve", "push"]
+    client = [["%s", "%s"]]
+`, path.Join(registryDir, "ca.crt"), path.Join(registryDir, "client.crt"), path.Join(registryDir, "client.key"))))
+	})
+
+	t.Run("Certificates", func(t *testing.T) {
+		for file, contents := range map[string]string{
+			"ca.crt":     "CA CERT",
+			"client.crt": "CLIENT CERT",
+			"client.key": "CLIENT KEY",
+		} {
+			t.Run(file, func(t *testing.T) {
+				g := NewWithT(t)
+
+				b, err := os.ReadFile(path.Join(registryDir, file))
+				g.Expect(err).To(BeNil())
+				g.Expect(string(b)).To(Equal(contents))
+
+				info, err := os.Stat(path.Join(registryDir, file))
+				g.Expect(err).To(BeNil())
+				g.Expect(info.Mode().Perm()).To(Equal(fs.FileMode(0600)))
+			})
+		}
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mustRestart, err := setup.ContainerdRegistries(s, []types.ContainerdRegistry{
+			{
+				Host:         "registry.internal",
+				URLs:         []string{"https://registry.internal:5000"},
+				Capabilities: []string{"pull", "resolve", "push"},
+				Username:     "username",
+				Password:     "password",
+				CACert:       "CA CERT",
+				ClientCert:   "CLIENT CERT",
+				ClientKey:    "CLIENT KEY",
+			},
+		})
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeFalse())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mustRestart, err := setup.ContainerdRegistries(s, nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(mustRestart).To(BeTrue())
+
+		_, err = os.Stat(registryDir)
+		g.Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = os.Stat(path.Join(dir, "containerd-confd", "k8sd-auths.toml"))
+		g.Expect(os.IsNotExist(err)).To(BeTrue())
+
+		_, err = os.Stat(path.Join(dir, "containerd-hosts", "user.internal"))
+		g.Expect(err).To(BeNil())
+	})
+}
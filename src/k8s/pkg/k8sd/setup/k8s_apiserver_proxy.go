@@ -1,8 +1,11 @@
 package setup
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"path"
+	"slices"
 
 	"github.com/canonical/k8s/pkg/proxy"
 	"github.com/canonical/k8s/pkg/snap"
@@ -10,19 +13,75 @@ import (
 )
 
 // K8sAPIServerProxy prepares configuration for k8s-apiserver-proxy.
-func K8sAPIServerProxy(snap snap.Snap, servers []string) error {
+// The proxy listens on the local securePort, which matches the kube-apiserver port of the cluster.
+// If controlPlaneEndpoint is set, the proxy only uses it instead of the kube-apiserver endpoints in servers.
+func K8sAPIServerProxy(snap snap.Snap, servers []string, securePort int, controlPlaneEndpoint string) error {
 	configFile := path.Join(snap.ServiceExtraConfigDir(), "k8s-apiserver-proxy.json")
 	if err := proxy.WriteEndpointsConfig(servers, configFile); err != nil {
 		return fmt.Errorf("failed to write proxy configuration file: %w", err)
 	}
 
 	if _, err := snaputil.UpdateServiceArguments(snap, "k8s-apiserver-proxy", map[string]string{
-		"--endpoints":  configFile,
-		"--kubeconfig": path.Join(snap.KubernetesConfigDir(), "kubelet.conf"),
-		"--listen":     "127.0.0.1:6443",
+		"--endpoints":      configFile,
+		"--listen":         fmt.Sprintf("127.0.0.1:%d", securePort),
+		"--status-address": proxy.StatusAddress(securePort),
 	}, nil); err != nil {
 		return fmt.Errorf("failed to write arguments file: %w", err)
 	}
 
+	if _, err := K8sAPIServerProxyCredentials(snap); err != nil {
+		return fmt.Errorf("failed to configure credentials: %w", err)
+	}
+
+	if _, err := K8sAPIServerProxyControlPlaneEndpoint(snap, controlPlaneEndpoint); err != nil {
+		return fmt.Errorf("failed to configure control plane endpoint: %w", err)
+	}
+
 	return nil
 }
+
+// K8sAPIServerProxyCredentials configures the kubeconfig files of k8s-apiserver-proxy.
+// kube-proxy credentials can watch EndpointSlices, which node credentials cannot. Readiness checks use the node
+// credentials, like the kubelet connections that go through the proxy.
+// Workers that joined before EndpointSlices were watched use the node credentials for both, so this is also applied
+// to existing nodes. It returns true if k8s-apiserver-proxy must be restarted to apply the change.
+func K8sAPIServerProxyCredentials(snap snap.Snap) (bool, error) {
+	mustRestart, err := snaputil.UpdateServiceArguments(snap, "k8s-apiserver-proxy", map[string]string{
+		"--kubeconfig":              path.Join(snap.KubernetesConfigDir(), "proxy.conf"),
+		"--health-check-kubeconfig": path.Join(snap.KubernetesConfigDir(), "kubelet.conf"),
+	}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to write arguments file: %w", err)
+	}
+	return mustRestart, nil
+}
+
+// K8sAPIServerProxyControlPlaneEndpoint configures k8s-apiserver-proxy to only use the controlPlaneEndpoint, instead of
+// watching the kube-apiserver endpoints of the cluster. An empty controlPlaneEndpoint enables the watch again.
+// It returns true if k8s-apiserver-proxy must be restarted to apply the change.
+func K8sAPIServerProxyControlPlaneEndpoint(snap snap.Snap, controlPlaneEndpoint string) (bool, error) {
+	if controlPlaneEndpoint == "" {
+		// the watch replaces the endpoints in the config file with the kube-apiserver endpoints of the cluster
+		mustRestart, err := snaputil.UpdateServiceArguments(snap, "k8s-apiserver-proxy", nil, []string{"--refresh-interval"})
+		if err != nil {
+			return false, fmt.Errorf("failed to write arguments file: %w", err)
+		}
+		return mustRestart, nil
+	}
+
+	var configChanged bool
+	configFile := path.Join(snap.ServiceExtraConfigDir(), "k8s-apiserver-proxy.json")
+	var cfg proxy.Configuration
+	if b, err := os.ReadFile(configFile); err != nil || json.Unmarshal(b, &cfg) != nil || !slices.Equal(cfg.Endpoints, []string{controlPlaneEndpoint}) {
+		if err := proxy.WriteEndpointsConfig([]string{controlPlaneEndpoint}, configFile); err != nil {
+			return false, fmt.Errorf("failed to write proxy configuration file: %w", err)
+		}
+		configChanged = true
+	}
+
+	argsChanged, err := snaputil.UpdateServiceArguments(snap, "k8s-apiserver-proxy", map[string]string{"--refresh-interval": "0"}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to write arguments file: %w", err)
+	}
+	return configChanged || argsChanged, nil
+}
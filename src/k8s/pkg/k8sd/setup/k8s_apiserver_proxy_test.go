@@ -27,15 +27,17 @@ func TestK8sApiServerProxy(t *testing.T) {
 
 		s := mustSetupSnapAndDirectories(t, setK8sApiServerMock)
 
-		g.Expect(setup.K8sAPIServerProxy(s, nil)).To(Succeed())
+		g.Expect(setup.K8sAPIServerProxy(s, nil, 6443, "")).To(Succeed())
 
 		tests := []struct {
 			key         string
 			expectedVal string
 		}{
 			{key: "--endpoints", expectedVal: path.Join(s.Mock.ServiceExtraConfigDir, "k8s-apiserver-proxy.json")},
-			{key: "--kubeconfig", expectedVal: path.Join(s.Mock.KubernetesConfigDir, "kubelet.conf")},
+			{key: "--kubeconfig", expectedVal: path.Join(s.Mock.KubernetesConfigDir, "proxy.conf")},
+			{key: "--health-check-kubeconfig", expectedVal: path.Join(s.Mock.KubernetesConfigDir, "kubelet.conf")},
 			{key: "--listen", expectedVal: "127.0.0.1:6443"},
+			{key: "--status-address", expectedVal: "127.0.0.1:6444"},
 		}
 		for _, tc := range tests {
 			t.Run(tc.key, func(t *testing.T) {
@@ -50,13 +52,42 @@ func TestK8sApiServerProxy(t *testing.T) {
 		g.Expect(len(args)).To(Equal(len(tests)))
 	})
 
+	t.Run("CustomSecurePort", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setK8sApiServerMock)
+
+		g.Expect(setup.K8sAPIServerProxy(s, nil, 16443, "")).To(Succeed())
+
+		g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--listen")).To(Equal("127.0.0.1:16443"))
+		g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--status-address")).To(Equal("127.0.0.1:16444"))
+	})
+
+	t.Run("UpgradedArgs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setK8sApiServerMock)
+
+		// workers that joined before EndpointSlices were watched use the node credentials
+		_, err := snaputil.UpdateServiceArguments(s, "k8s-apiserver-proxy", map[string]string{
+			"--kubeconfig": path.Join(s.Mock.KubernetesConfigDir, "kubelet.conf"),
+		}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(setup.K8sAPIServerProxyCredentials(s)).To(BeTrue())
+		g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--kubeconfig")).To(Equal(path.Join(s.Mock.KubernetesConfigDir, "proxy.conf")))
+		g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--health-check-kubeconfig")).To(Equal(path.Join(s.Mock.KubernetesConfigDir, "kubelet.conf")))
+
+		g.Expect(setup.K8sAPIServerProxyCredentials(s)).To(BeFalse())
+	})
+
 	t.Run("MissingExtraConfigDir", func(t *testing.T) {
 		g := NewWithT(t)
 
 		s := mustSetupSnapAndDirectories(t, setK8sApiServerMock)
 
 		s.Mock.ServiceExtraConfigDir = "nonexistent"
-		g.Expect(setup.K8sAPIServerProxy(s, nil)).ToNot(Succeed())
+		g.Expect(setup.K8sAPIServerProxy(s, nil, 6443, "")).ToNot(Succeed())
 	})
 
 	t.Run("MissingServiceArgumentsDir", func(t *testing.T) {
@@ -65,7 +96,7 @@ func TestK8sApiServerProxy(t *testing.T) {
 		s := mustSetupSnapAndDirectories(t, setK8sApiServerMock)
 
 		s.Mock.ServiceArgumentsDir = "nonexistent"
-		g.Expect(setup.K8sAPIServerProxy(s, nil)).ToNot(Succeed())
+		g.Expect(setup.K8sAPIServerProxy(s, nil, 6443, "")).ToNot(Succeed())
 	})
 
 	t.Run("JSONFileContent", func(t *testing.T) {
@@ -76,7 +107,7 @@ func TestK8sApiServerProxy(t *testing.T) {
 		endpoints := []string{"192.168.0.1", "192.168.0.2", "192.168.0.3"}
 		fileName := path.Join(s.Mock.ServiceExtraConfigDir, "k8s-apiserver-proxy.json")
 
-		g.Expect(setup.K8sAPIServerProxy(s, endpoints)).To(Succeed())
+		g.Expect(setup.K8sAPIServerProxy(s, endpoints, 6443, "")).To(Succeed())
 
 		b, err := os.ReadFile(fileName)
 		g.Expect(err).NotTo(HaveOccurred())
@@ -88,4 +119,42 @@ func TestK8sApiServerProxy(t *testing.T) {
 		// Compare the expected endpoints with those in the file
 		g.Expect(config.Endpoints).To(Equal(endpoints))
 	})
+
+	t.Run("ControlPlaneEndpoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := mustSetupSnapAndDirectories(t, setK8sApiServerMock)
+		fileName := path.Join(s.Mock.ServiceExtraConfigDir, "k8s-apiserver-proxy.json")
+
+		g.Expect(setup.K8sAPIServerProxy(s, []string{"192.168.0.1"}, 6443, "10.0.0.100:6443")).To(Succeed())
+
+		b, err := os.ReadFile(fileName)
+		g.Expect(err).NotTo(HaveOccurred())
+		var config proxy.Configuration
+		g.Expect(json.Unmarshal(b, &config)).To(Succeed())
+		g.Expect(config.Endpoints).To(Equal([]string{"10.0.0.100:6443"}))
+		g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--refresh-interval")).To(Equal("0"))
+
+		t.Run("Unchanged", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(setup.K8sAPIServerProxyControlPlaneEndpoint(s, "10.0.0.100:6443")).To(BeFalse())
+		})
+
+		t.Run("Changed", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(setup.K8sAPIServerProxyControlPlaneEndpoint(s, "10.0.0.200:6443")).To(BeTrue())
+
+			b, err := os.ReadFile(fileName)
+			g.Expect(err).NotTo(HaveOccurred())
+			var config proxy.Configuration
+			g.Expect(json.Unmarshal(b, &config)).To(Succeed())
+			g.Expect(config.Endpoints).To(Equal([]string{"10.0.0.200:6443"}))
+		})
+
+		t.Run("Removed", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(setup.K8sAPIServerProxyControlPlaneEndpoint(s, "")).To(BeTrue())
+			g.Expect(snaputil.GetServiceArgument(s, "k8s-apiserver-proxy", "--refresh-interval")).To(BeEmpty())
+		})
+	})
 }
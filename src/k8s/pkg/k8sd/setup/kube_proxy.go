@@ -3,9 +3,9 @@ package setup
 import (
 	"context"
 	"fmt"
-	"log"
 	"path"
 
+	"github.com/canonical/k8s/pkg/log"
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
 )
@@ -22,7 +22,7 @@ func KubeProxy(ctx context.Context, snap snap.Snap, hostname string, podCIDR str
 
 	onLXD, err := snap.OnLXD(ctx)
 	if err != nil {
-		log.Printf("failed to check if on lxd: %v", err)
+		log.FromContext(ctx).Warn("Failed to check if on LXD", "error", err)
 	}
 	if onLXD {
 		// A container cannot set this sysctl config in LXD. So, we disable it by setting it to "0".
@@ -8,6 +8,7 @@ import (
 
 	"github.com/canonical/k8s/pkg/snap"
 	snaputil "github.com/canonical/k8s/pkg/snap/util"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var kubeletTLSCipherSuites = []string{
@@ -30,8 +31,13 @@ var kubeletWorkerLabels = []string{
 }
 
 // KubeletControlPlane configures kubelet on a control plane node.
-func KubeletControlPlane(snap snap.Snap, hostname string, nodeIP net.IP, clusterDNS string, clusterDomain string, cloudProvider string, registerWithTaints []string) error {
-	return kubelet(snap, hostname, nodeIP, clusterDNS, clusterDomain, cloudProvider, registerWithTaints, append(kubeletControlPlaneLabels, kubeletWorkerLabels...))
+// If zone is not empty, the node is registered with the "topology.kubernetes.io/zone" label.
+func KubeletControlPlane(snap snap.Snap, hostname string, nodeIP net.IP, clusterDNS string, clusterDomain string, cloudProvider string, registerWithTaints []string, zone string) error {
+	labels := append(kubeletControlPlaneLabels, kubeletWorkerLabels...)
+	if zone != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", corev1.LabelTopologyZone, zone))
+	}
+	return kubelet(snap, hostname, nodeIP, clusterDNS, clusterDomain, cloudProvider, registerWithTaints, labels)
 }
 
 // KubeletWorker configures kubelet on a worker node.
@@ -9,6 +9,7 @@ type ClusterConfig struct {
 
 	Network       Network       `json:"network,omitempty"`
 	DNS           DNS           `json:"dns,omitempty"`
+	NodeLocalDNS  NodeLocalDNS  `json:"node-local-dns,omitempty"`
 	Ingress       Ingress       `json:"ingress,omitempty"`
 	LoadBalancer  LoadBalancer  `json:"load-balancer,omitempty"`
 	Gateway       Gateway       `json:"gateway,omitempty"`
@@ -17,3 +18,19 @@ type ClusterConfig struct {
 }
 
 func (c ClusterConfig) Empty() bool { return c == ClusterConfig{} }
+
+// NodeKubelet returns the kubelet configuration that is applied on the cluster nodes.
+// If NodeLocal DNSCache is enabled, kubelets use the node-local address instead of the cluster DNS service.
+func (c ClusterConfig) NodeKubelet() Kubelet {
+	kubelet := c.Kubelet
+	if c.NodeLocalDNS.GetEnabled() && kubelet.GetClusterDNS() != "" {
+		kubelet.ClusterDNS = c.NodeLocalDNS.LocalIP
+	}
+	return kubelet
+}
+
+// Redacted returns a copy of the cluster configuration without secrets, to return to clients.
+func (c ClusterConfig) Redacted() ClusterConfig {
+	c.Containerd = c.Containerd.Redacted()
+	return c
+}
@@ -1,10 +1,137 @@
 package types
 
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+)
+
 type APIServer struct {
 	SecurePort        *int    `json:"port,omitempty"`
 	AuthorizationMode *string `json:"authorization-mode,omitempty"`
+	// ControlPlaneEndpoint is the host or host:port of an external endpoint of the control plane, e.g. a load balancer or a VIP.
+	ControlPlaneEndpoint *string `json:"control-plane-endpoint,omitempty"`
+	// ControlPlaneEndpointForWorkers makes worker nodes use the ControlPlaneEndpoint instead of the kube-apiserver endpoints of the control plane nodes.
+	ControlPlaneEndpointForWorkers *bool `json:"control-plane-endpoint-for-workers,omitempty"`
+}
+
+func (c APIServer) GetSecurePort() int              { return getField(c.SecurePort) }
+func (c APIServer) GetAuthorizationMode() string    { return getField(c.AuthorizationMode) }
+func (c APIServer) GetControlPlaneEndpoint() string { return getField(c.ControlPlaneEndpoint) }
+func (c APIServer) GetControlPlaneEndpointForWorkers() bool {
+	return getField(c.ControlPlaneEndpointForWorkers)
+}
+func (c APIServer) Empty() bool { return c == APIServer{} }
+
+// ControlPlaneEndpointHost returns the host of the control plane endpoint, or the empty string if it is not set.
+func (c APIServer) ControlPlaneEndpointHost() string {
+	host, _ := splitControlPlaneEndpoint(c.GetControlPlaneEndpoint())
+	return host
+}
+
+// ControlPlaneEndpointAddress returns the host:port of the control plane endpoint, or the empty string if it is not set.
+// The port defaults to the kube-apiserver secure port.
+func (c APIServer) ControlPlaneEndpointAddress() string {
+	host, port := splitControlPlaneEndpoint(c.GetControlPlaneEndpoint())
+	if host == "" {
+		return ""
+	}
+	if port == "" {
+		port = strconv.Itoa(c.GetSecurePort())
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// WorkerEndpoint returns the host:port that worker nodes use to reach the kube-apiserver, or the empty string if
+// worker nodes use the kube-apiserver endpoints of the control plane nodes.
+func (c APIServer) WorkerEndpoint() string {
+	if !c.GetControlPlaneEndpointForWorkers() {
+		return ""
+	}
+	return c.ControlPlaneEndpointAddress()
+}
+
+// splitControlPlaneEndpoint splits a "host" or "host:port" endpoint. IPv6 hosts may be specified with or without brackets.
+func splitControlPlaneEndpoint(endpoint string) (string, string) {
+	if host, port, err := net.SplitHostPort(endpoint); err == nil {
+		return host, port
+	}
+	if len(endpoint) > 1 && endpoint[0] == '[' && endpoint[len(endpoint)-1] == ']' {
+		return endpoint[1 : len(endpoint)-1], ""
+	}
+	return endpoint, ""
 }
 
-func (c APIServer) GetSecurePort() int           { return getField(c.SecurePort) }
-func (c APIServer) GetAuthorizationMode() string { return getField(c.AuthorizationMode) }
-func (c APIServer) Empty() bool                  { return c == APIServer{} }
+// hashWorkerEndpoint returns a sha256 sum of the worker endpoint that is distributed to the worker nodes.
+func hashWorkerEndpoint(endpoint string) ([]byte, error) {
+	b, err := json.Marshal(map[string]string{"worker-endpoint": endpoint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash config: %w", err)
+	}
+
+	h := sha256.New()
+	if _, err := h.Write(b); err != nil {
+		return nil, fmt.Errorf("failed to compute sha256: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// ToConfigMap converts an APIServer config to a map[string]string to store in a Kubernetes configmap.
+// ToConfigMap will append an "apiserver-mac" field with a signed hash of the contents, if a key is specified.
+// The keys do not overlap with Kubelet.ToConfigMap() and Containerd.ToConfigMap(), so all can be stored in the same configmap.
+func (c APIServer) ToConfigMap(key *rsa.PrivateKey) (map[string]string, error) {
+	data := make(map[string]string)
+	if c.ControlPlaneEndpointForWorkers == nil {
+		return data, nil
+	}
+	// the endpoint is empty if it is not used for workers, so that worker nodes switch back to the control plane nodes
+	data["apiserver-worker-endpoint"] = c.WorkerEndpoint()
+
+	if key != nil {
+		hash, err := hashWorkerEndpoint(c.WorkerEndpoint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute hash: %w", err)
+		}
+		mac, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign hash: %w", err)
+		}
+		data["apiserver-mac"] = base64.StdEncoding.EncodeToString(mac)
+	}
+
+	return data, nil
+}
+
+// WorkerEndpointFromConfigMap parses configmap data into the kube-apiserver endpoint of the worker nodes.
+// WorkerEndpointFromConfigMap returns nil if the configmap does not specify the endpoint, and a pointer to the empty
+// string if worker nodes use the kube-apiserver endpoints of the control plane nodes.
+// WorkerEndpointFromConfigMap will attempt to validate the signature (found in the "apiserver-mac" field) if a key is specified.
+// WorkerEndpointFromConfigMap can parse and validate maps created with APIServer.ToConfigMap().
+func WorkerEndpointFromConfigMap(m map[string]string, key *rsa.PublicKey) (*string, error) {
+	v, ok := m["apiserver-worker-endpoint"]
+	if !ok {
+		return nil, nil
+	}
+
+	if key != nil {
+		hash, err := hashWorkerEndpoint(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute config hash: %w", err)
+		}
+		signature, err := base64.StdEncoding.DecodeString(m["apiserver-mac"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signature: %w", err)
+		}
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, signature); err != nil {
+			return nil, fmt.Errorf("failed to verify signature: %w", err)
+		}
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,116 @@
+package types_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestAPIServerControlPlaneEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		apiserver     types.APIServer
+		expectHost    string
+		expectAddress string
+		expectWorker  string
+	}{
+		{
+			name:      "Nil",
+			apiserver: types.APIServer{SecurePort: utils.Pointer(6443)},
+		},
+		{
+			name:          "Host",
+			apiserver:     types.APIServer{SecurePort: utils.Pointer(6443), ControlPlaneEndpoint: utils.Pointer("k8s.internal")},
+			expectHost:    "k8s.internal",
+			expectAddress: "k8s.internal:6443",
+		},
+		{
+			name:          "HostPort",
+			apiserver:     types.APIServer{SecurePort: utils.Pointer(6443), ControlPlaneEndpoint: utils.Pointer("10.0.0.100:443")},
+			expectHost:    "10.0.0.100",
+			expectAddress: "10.0.0.100:443",
+		},
+		{
+			name:          "IPv6",
+			apiserver:     types.APIServer{SecurePort: utils.Pointer(6443), ControlPlaneEndpoint: utils.Pointer("[fd00::100]")},
+			expectHost:    "fd00::100",
+			expectAddress: "[fd00::100]:6443",
+		},
+		{
+			name:          "UseForWorkers",
+			apiserver:     types.APIServer{SecurePort: utils.Pointer(6443), ControlPlaneEndpoint: utils.Pointer("k8s.internal"), ControlPlaneEndpointForWorkers: utils.Pointer(true)},
+			expectHost:    "k8s.internal",
+			expectAddress: "k8s.internal:6443",
+			expectWorker:  "k8s.internal:6443",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(tc.apiserver.ControlPlaneEndpointHost()).To(Equal(tc.expectHost))
+			g.Expect(tc.apiserver.ControlPlaneEndpointAddress()).To(Equal(tc.expectAddress))
+			g.Expect(tc.apiserver.WorkerEndpoint()).To(Equal(tc.expectWorker))
+		})
+	}
+}
+
+func TestAPIServerConfigMap(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		apiserver types.APIServer
+		configmap map[string]string
+	}{
+		{
+			name:      "Nil",
+			configmap: map[string]string{},
+		},
+		{
+			name:      "NotForWorkers",
+			apiserver: types.APIServer{ControlPlaneEndpoint: utils.Pointer("k8s.internal:6443"), ControlPlaneEndpointForWorkers: utils.Pointer(false)},
+			configmap: map[string]string{"apiserver-worker-endpoint": ""},
+		},
+		{
+			name:      "ForWorkers",
+			apiserver: types.APIServer{ControlPlaneEndpoint: utils.Pointer("k8s.internal:6443"), ControlPlaneEndpointForWorkers: utils.Pointer(true)},
+			configmap: map[string]string{"apiserver-worker-endpoint": "k8s.internal:6443"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cm, err := tc.apiserver.ToConfigMap(nil)
+			g.Expect(err).To(BeNil())
+			g.Expect(cm).To(Equal(tc.configmap))
+		})
+	}
+
+	t.Run("SignAndVerify", func(t *testing.T) {
+		g := NewWithT(t)
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		g.Expect(err).To(BeNil())
+
+		configmap, err := types.APIServer{ControlPlaneEndpoint: utils.Pointer("k8s.internal:6443"), ControlPlaneEndpointForWorkers: utils.Pointer(true)}.ToConfigMap(key)
+		g.Expect(err).To(BeNil())
+		g.Expect(configmap).To(HaveKeyWithValue("apiserver-mac", Not(BeEmpty())))
+
+		endpoint, err := types.WorkerEndpointFromConfigMap(configmap, &key.PublicKey)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint).To(Equal(utils.Pointer("k8s.internal:6443")))
+
+		configmap["apiserver-worker-endpoint"] = "attacker.internal:6443"
+		_, err = types.WorkerEndpointFromConfigMap(configmap, &key.PublicKey)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		endpoint, err := types.WorkerEndpointFromConfigMap(map[string]string{"cluster-dns": "10.0.0.1"}, nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint).To(BeNil())
+	})
+}
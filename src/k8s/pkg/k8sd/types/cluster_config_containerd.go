@@ -1,5 +1,15 @@
 package types
 
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
 type ContainerdRegistry struct {
 	Host         string   `json:"host,omitempty"`
 	URLs         []string `json:"urls,omitempty"`
@@ -8,12 +18,289 @@ type ContainerdRegistry struct {
 	Token        string   `json:"token,omitempty"`
 	OverridePath bool     `json:"override-path,omitempty"`
 	SkipVerify   bool     `json:"skip-verify,omitempty"`
-	// TODO(neoaggelos): add option to configure certificates for containerd registries
-	// CACert       string
-	// ClientCert   string
-	// ClientKey    string
+	Capabilities []string `json:"capabilities,omitempty"`
+	CACert       string   `json:"ca-crt,omitempty"`
+	ClientCert   string   `json:"client-crt,omitempty"`
+	ClientKey    string   `json:"client-key,omitempty"`
+}
+
+// ContainerdRegistryCredentials are the secret fields of a registry.
+// They are not distributed through the k8sd-config configmap, see Containerd.RegistryCredentialsToSecret().
+type ContainerdRegistryCredentials struct {
+	Password  string `json:"password,omitempty"`
+	Token     string `json:"token,omitempty"`
+	ClientKey string `json:"client-key,omitempty"`
+}
+
+// ContainerdRegistryCredentialsSecret is the name of the secret in the kube-system namespace with the registry credentials.
+// All nodes are allowed to get it, as containerd is configured with the credentials of all registries on every node and
+// worker nodes can only reach the cluster with their kubelet credentials. Nodes are not allowed to list or watch secrets,
+// they notice changes through the credentials revision in the k8sd-config configmap.
+const ContainerdRegistryCredentialsSecret = "k8sd-registry-credentials"
+
+// RedactedValue replaces the registry credentials in the cluster configuration that is returned to clients.
+const RedactedValue = "<redacted>"
+
+// credentials returns pointers to the secret fields of the registry.
+func (r *ContainerdRegistry) credentials() []*string {
+	return []*string{&r.Password, &r.Token, &r.ClientKey}
+}
+
+type ContainerdRuntimeHandler struct {
+	Name         string            `json:"name,omitempty"`
+	RuntimeType  string            `json:"runtime-type,omitempty"`
+	BinaryPath   string            `json:"binary-path,omitempty"`
+	Options      map[string]any    `json:"options,omitempty"`
+	NodeSelector map[string]string `json:"node-selector,omitempty"`
 }
 
 type Containerd struct {
-	Registries *[]ContainerdRegistry `json:"registries,omitempty"`
+	Registries      *[]ContainerdRegistry       `json:"registries,omitempty"`
+	RuntimeHandlers *[]ContainerdRuntimeHandler `json:"runtime-handlers,omitempty"`
+	ImageRegistry   *string                     `json:"image-registry,omitempty"`
+}
+
+func (c Containerd) GetRegistries() []ContainerdRegistry { return getField(c.Registries) }
+func (c Containerd) GetRuntimeHandlers() []ContainerdRuntimeHandler {
+	return getField(c.RuntimeHandlers)
+}
+func (c Containerd) GetImageRegistry() string { return getField(c.ImageRegistry) }
+func (c Containerd) Empty() bool              { return c == Containerd{} }
+
+// mapRegistries returns a copy of c with f applied to a copy of each registry.
+func (c Containerd) mapRegistries(f func(r *ContainerdRegistry)) Containerd {
+	if c.Registries == nil {
+		return c
+	}
+	registries := make([]ContainerdRegistry, 0, len(*c.Registries))
+	for _, r := range *c.Registries {
+		f(&r)
+		registries = append(registries, r)
+	}
+	c.Registries = &registries
+	return c
+}
+
+// Redacted returns a copy of c with the registry credentials replaced by RedactedValue.
+func (c Containerd) Redacted() Containerd {
+	return c.mapRegistries(func(r *ContainerdRegistry) {
+		for _, v := range r.credentials() {
+			if *v != "" {
+				*v = RedactedValue
+			}
+		}
+	})
+}
+
+// RestoreRedacted returns a copy of c where credentials set to RedactedValue keep their value from the registry with the same host in previous.
+// Clients that update the registries from a redacted cluster configuration do not reset the credentials.
+// RestoreRedacted returns an error if previous does not have the redacted credential, e.g. because the registry was removed.
+func (c Containerd) RestoreRedacted(previous Containerd) (Containerd, error) {
+	var err error
+	restored := c.mapRegistries(func(r *ContainerdRegistry) {
+		var old ContainerdRegistry
+		for _, p := range previous.GetRegistries() {
+			if p.Host == r.Host {
+				old = p
+				break
+			}
+		}
+		oldCredentials := old.credentials()
+		for i, v := range r.credentials() {
+			if *v != RedactedValue {
+				continue
+			}
+			if *oldCredentials[i] == "" && err == nil {
+				err = fmt.Errorf("registry %q has redacted credentials that are not known, they must be set again", r.Host)
+			}
+			*v = *oldCredentials[i]
+		}
+	})
+	if err != nil {
+		return Containerd{}, err
+	}
+	return restored, nil
+}
+
+// withoutRegistryCredentials returns a copy of c without the registry credentials.
+func (c Containerd) withoutRegistryCredentials() Containerd {
+	return c.mapRegistries(func(r *ContainerdRegistry) {
+		for _, v := range r.credentials() {
+			*v = ""
+		}
+	})
+}
+
+// WithRegistryCredentials returns a copy of c with the registry credentials of each host set from credentials.
+func (c Containerd) WithRegistryCredentials(credentials map[string]ContainerdRegistryCredentials) Containerd {
+	return c.mapRegistries(func(r *ContainerdRegistry) {
+		if v, ok := credentials[r.Host]; ok {
+			r.Password, r.Token, r.ClientKey = v.Password, v.Token, v.ClientKey
+		}
+	})
+}
+
+// hash returns a sha256 sum from the Containerd configuration.
+func (c Containerd) hash() ([]byte, error) {
+	return hashJSON(c)
+}
+
+// hashJSON returns a sha256 sum of the JSON encoding of v.
+func hashJSON(v any) ([]byte, error) {
+	// encoding/json.Marshal() ensures alphabetical order on JSON fields, so will
+	// always produce the same JSON document.
+	hash, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash config: %w", err)
+	}
+
+	h := sha256.New()
+	if _, err := h.Write(hash); err != nil {
+		return nil, fmt.Errorf("failed to compute sha256: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// ToConfigMap converts a Containerd config to a map[string]string to store in a Kubernetes configmap.
+// ToConfigMap will append a "containerd-mac" field with a signed hash of the contents, if a key is specified.
+// The registry credentials are not included, see RegistryCredentialsToSecret().
+// The keys do not overlap with Kubelet.ToConfigMap(), so both can be stored in the same configmap.
+func (c Containerd) ToConfigMap(key *rsa.PrivateKey) (map[string]string, error) {
+	c = c.withoutRegistryCredentials()
+	data := make(map[string]string)
+
+	if v := c.Registries; v != nil {
+		b, err := json.Marshal(*v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode containerd registries: %w", err)
+		}
+		data["containerd-registries"] = string(b)
+	}
+	if v := c.RuntimeHandlers; v != nil {
+		b, err := json.Marshal(*v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode containerd runtime handlers: %w", err)
+		}
+		data["containerd-runtime-handlers"] = string(b)
+	}
+	if v := c.ImageRegistry; v != nil {
+		data["containerd-image-registry"] = *v
+	}
+
+	if key != nil {
+		hash, err := c.hash()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute hash: %w", err)
+		}
+		mac, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign hash: %w", err)
+		}
+		data["containerd-mac"] = base64.StdEncoding.EncodeToString(mac)
+	}
+
+	return data, nil
+}
+
+// ContainerdFromConfigMap parses configmap data into a Containerd config.
+// ContainerdFromConfigMap will attempt to validate the signature (found in the "containerd-mac" field) if a key is specified.
+// ContainerdFromConfigMap can parse and validate maps created with Containerd.ToConfigMap().
+func ContainerdFromConfigMap(m map[string]string, key *rsa.PublicKey) (Containerd, error) {
+	var c Containerd
+	if m == nil {
+		return c, nil
+	}
+
+	if v, ok := m["containerd-registries"]; ok {
+		var registries []ContainerdRegistry
+		if err := json.Unmarshal([]byte(v), &registries); err != nil {
+			return Containerd{}, fmt.Errorf("failed to parse containerd registries: %w", err)
+		}
+		c.Registries = &registries
+	}
+	if v, ok := m["containerd-runtime-handlers"]; ok {
+		var runtimeHandlers []ContainerdRuntimeHandler
+		if err := json.Unmarshal([]byte(v), &runtimeHandlers); err != nil {
+			return Containerd{}, fmt.Errorf("failed to parse containerd runtime handlers: %w", err)
+		}
+		c.RuntimeHandlers = &runtimeHandlers
+	}
+	if v, ok := m["containerd-image-registry"]; ok {
+		c.ImageRegistry = &v
+	}
+
+	if key != nil {
+		hash, err := c.hash()
+		if err != nil {
+			return Containerd{}, fmt.Errorf("failed to compute config hash: %w", err)
+		}
+		signature, err := base64.StdEncoding.DecodeString(m["containerd-mac"])
+		if err != nil {
+			return Containerd{}, fmt.Errorf("failed to parse signature: %w", err)
+		}
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, signature); err != nil {
+			return Containerd{}, fmt.Errorf("failed to verify signature: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// RegistryCredentialsToSecret converts the registry credentials of a Containerd config to a map[string][]byte to store in a Kubernetes secret.
+// RegistryCredentialsToSecret will append a "registry-credentials-mac" field with a signed hash of the credentials, if a key is specified.
+func (c Containerd) RegistryCredentialsToSecret(key *rsa.PrivateKey) (map[string][]byte, error) {
+	credentials := make(map[string]ContainerdRegistryCredentials)
+	for _, r := range c.GetRegistries() {
+		if v := (ContainerdRegistryCredentials{Password: r.Password, Token: r.Token, ClientKey: r.ClientKey}); v != (ContainerdRegistryCredentials{}) {
+			credentials[r.Host] = v
+		}
+	}
+
+	b, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode registry credentials: %w", err)
+	}
+	data := map[string][]byte{"registry-credentials": b}
+
+	if key != nil {
+		hash, err := hashJSON(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute hash: %w", err)
+		}
+		mac, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign hash: %w", err)
+		}
+		data["registry-credentials-mac"] = []byte(base64.StdEncoding.EncodeToString(mac))
+	}
+
+	return data, nil
+}
+
+// ContainerdRegistryCredentialsFromSecret parses secret data into the registry credentials of each host.
+// ContainerdRegistryCredentialsFromSecret will attempt to validate the signature (found in the "registry-credentials-mac" field) if a key is specified.
+// ContainerdRegistryCredentialsFromSecret can parse and validate maps created with Containerd.RegistryCredentialsToSecret().
+func ContainerdRegistryCredentialsFromSecret(m map[string][]byte, key *rsa.PublicKey) (map[string]ContainerdRegistryCredentials, error) {
+	credentials := make(map[string]ContainerdRegistryCredentials)
+	if v, ok := m["registry-credentials"]; ok {
+		if err := json.Unmarshal(v, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to parse registry credentials: %w", err)
+		}
+	}
+
+	if key != nil {
+		hash, err := hashJSON(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute credentials hash: %w", err)
+		}
+		signature, err := base64.StdEncoding.DecodeString(string(m["registry-credentials-mac"]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signature: %w", err)
+		}
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, signature); err != nil {
+			return nil, fmt.Errorf("failed to verify signature: %w", err)
+		}
+	}
+
+	return credentials, nil
 }
@@ -0,0 +1,243 @@
+package types_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestContainerd(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		containerd types.Containerd
+		configmap  map[string]string
+	}{
+		{
+			name:      "Nil",
+			configmap: map[string]string{},
+		},
+		{
+			name:       "Empty",
+			containerd: types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{})},
+			configmap:  map[string]string{"containerd-registries": "[]"},
+		},
+		{
+			name: "Registries",
+			containerd: types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+				{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Username: "user"},
+			})},
+			configmap: map[string]string{
+				"containerd-registries": `[{"host":"docker.io","urls":["https://mirror.internal"],"username":"user"}]`,
+			},
+		},
+		{
+			name:       "ImageRegistry",
+			containerd: types.Containerd{ImageRegistry: utils.Pointer("registry.internal:5000")},
+			configmap:  map[string]string{"containerd-image-registry": "registry.internal:5000"},
+		},
+		{
+			name: "RuntimeHandlers",
+			containerd: types.Containerd{RuntimeHandlers: utils.Pointer([]types.ContainerdRuntimeHandler{
+				{
+					Name:         "gvisor",
+					RuntimeType:  "io.containerd.runsc.v1",
+					BinaryPath:   "/usr/local/bin/containerd-shim-runsc-v1",
+					Options:      map[string]any{"TypeUrl": "io.containerd.runsc.v1.options", "Debug": true},
+					NodeSelector: map[string]string{"sandbox": "gvisor"},
+				},
+			})},
+			configmap: map[string]string{
+				"containerd-runtime-handlers": `[{"name":"gvisor","runtime-type":"io.containerd.runsc.v1","binary-path":"/usr/local/bin/containerd-shim-runsc-v1","options":{"Debug":true,"TypeUrl":"io.containerd.runsc.v1.options"},"node-selector":{"sandbox":"gvisor"}}]`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("ToConfigMap", func(t *testing.T) {
+				g := NewWithT(t)
+
+				cm, err := tc.containerd.ToConfigMap(nil)
+				g.Expect(err).To(BeNil())
+				g.Expect(cm).To(Equal(tc.configmap))
+			})
+
+			t.Run("FromConfigMap", func(t *testing.T) {
+				g := NewWithT(t)
+
+				c, err := types.ContainerdFromConfigMap(tc.configmap, nil)
+				g.Expect(err).To(BeNil())
+				g.Expect(c).To(Equal(tc.containerd))
+			})
+		})
+	}
+}
+
+func TestContainerdSign(t *testing.T) {
+	g := NewWithT(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).To(BeNil())
+
+	containerd := types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+		{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Username: "user"},
+	})}
+
+	configmap, err := containerd.ToConfigMap(key)
+	g.Expect(err).To(BeNil())
+	g.Expect(configmap).To(HaveKeyWithValue("containerd-mac", Not(BeEmpty())))
+
+	t.Run("SignAndVerify", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fromContainerd, err := types.ContainerdFromConfigMap(configmap, &key.PublicKey)
+		g.Expect(err).To(BeNil())
+		g.Expect(fromContainerd).To(Equal(containerd))
+	})
+
+	t.Run("SharedWithKubelet", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kubeletConfigMap, err := types.Kubelet{ClusterDNS: utils.Pointer("10.0.0.1")}.ToConfigMap(key)
+		g.Expect(err).To(BeNil())
+		for k, v := range configmap {
+			kubeletConfigMap[k] = v
+		}
+
+		_, err = types.KubeletFromConfigMap(kubeletConfigMap, &key.PublicKey)
+		g.Expect(err).To(BeNil())
+		_, err = types.ContainerdFromConfigMap(kubeletConfigMap, &key.PublicKey)
+		g.Expect(err).To(BeNil())
+	})
+
+	t.Run("ImageRegistry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// nodes render the sandbox image from the image registry
+		withImageRegistry := containerd
+		withImageRegistry.ImageRegistry = utils.Pointer("registry.internal")
+
+		configmap, err := withImageRegistry.ToConfigMap(key)
+		g.Expect(err).To(BeNil())
+		g.Expect(configmap).To(HaveKeyWithValue("containerd-image-registry", "registry.internal"))
+
+		fromContainerd, err := types.ContainerdFromConfigMap(configmap, &key.PublicKey)
+		g.Expect(err).To(BeNil())
+		g.Expect(fromContainerd).To(Equal(withImageRegistry))
+
+		// the image registry is signed
+		configmap["containerd-image-registry"] = "attacker.internal"
+		_, err = types.ContainerdFromConfigMap(configmap, &key.PublicKey)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Manipulated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := map[string]string{
+			"containerd-registries": `[{"host":"docker.io","urls":["https://attacker.internal"]}]`,
+			"containerd-mac":        configmap["containerd-mac"],
+		}
+		fromContainerd, err := types.ContainerdFromConfigMap(c, &key.PublicKey)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(fromContainerd).To(BeZero())
+	})
+}
+
+func TestContainerdRegistryCredentials(t *testing.T) {
+	g := NewWithT(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).To(BeNil())
+
+	containerd := types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+		{Host: "docker.io", Username: "user", Password: "pass"},
+		{Host: "ghcr.io", Token: "token"},
+		{Host: "registry.internal", ClientCert: "crt", ClientKey: "key"},
+		{Host: "quay.io"},
+	})}
+
+	t.Run("NotInConfigMap", func(t *testing.T) {
+		g := NewWithT(t)
+
+		configmap, err := containerd.ToConfigMap(key)
+		g.Expect(err).To(BeNil())
+		g.Expect(configmap["containerd-registries"]).ToNot(SatisfyAny(ContainSubstring("pass"), ContainSubstring("token"), ContainSubstring(`"key"`)))
+
+		fromConfigMap, err := types.ContainerdFromConfigMap(configmap, &key.PublicKey)
+		g.Expect(err).To(BeNil())
+		g.Expect(fromConfigMap.GetRegistries()).To(Equal([]types.ContainerdRegistry{
+			{Host: "docker.io", Username: "user"},
+			{Host: "ghcr.io"},
+			{Host: "registry.internal", ClientCert: "crt"},
+			{Host: "quay.io"},
+		}))
+
+		t.Run("FromSecret", func(t *testing.T) {
+			g := NewWithT(t)
+
+			secret, err := containerd.RegistryCredentialsToSecret(key)
+			g.Expect(err).To(BeNil())
+			g.Expect(secret).To(HaveKeyWithValue("registry-credentials-mac", Not(BeEmpty())))
+
+			credentials, err := types.ContainerdRegistryCredentialsFromSecret(secret, &key.PublicKey)
+			g.Expect(err).To(BeNil())
+			g.Expect(credentials).To(HaveLen(3))
+			g.Expect(fromConfigMap.WithRegistryCredentials(credentials)).To(Equal(containerd))
+		})
+	})
+
+	t.Run("Manipulated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret, err := containerd.RegistryCredentialsToSecret(key)
+		g.Expect(err).To(BeNil())
+		secret["registry-credentials"] = []byte(`{"docker.io":{"password":"attacker"}}`)
+
+		_, err = types.ContainerdRegistryCredentialsFromSecret(secret, &key.PublicKey)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		redacted := containerd.Redacted()
+		g.Expect(redacted.GetRegistries()).To(Equal([]types.ContainerdRegistry{
+			{Host: "docker.io", Username: "user", Password: types.RedactedValue},
+			{Host: "ghcr.io", Token: types.RedactedValue},
+			{Host: "registry.internal", ClientCert: "crt", ClientKey: types.RedactedValue},
+			{Host: "quay.io"},
+		}))
+		// the original config is not changed
+		g.Expect(containerd.GetRegistries()[0].Password).To(Equal("pass"))
+
+		t.Run("RestoreRedacted", func(t *testing.T) {
+			g := NewWithT(t)
+
+			update := types.Containerd{Registries: utils.Pointer(append(redacted.GetRegistries(),
+				types.ContainerdRegistry{Host: "new.internal", Password: "new-pass"},
+			))}
+			(*update.Registries)[1].Token = "new-token"
+
+			restored, err := update.RestoreRedacted(containerd)
+			g.Expect(err).To(BeNil())
+			g.Expect(restored.GetRegistries()).To(Equal([]types.ContainerdRegistry{
+				{Host: "docker.io", Username: "user", Password: "pass"},
+				{Host: "ghcr.io", Token: "new-token"},
+				{Host: "registry.internal", ClientCert: "crt", ClientKey: "key"},
+				{Host: "quay.io"},
+				{Host: "new.internal", Password: "new-pass"},
+			}))
+		})
+
+		t.Run("RestoreRedactedRemovedRegistry", func(t *testing.T) {
+			g := NewWithT(t)
+
+			update := types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+				{Host: "removed.internal", Password: types.RedactedValue},
+			})}
+			_, err := update.RestoreRedacted(containerd)
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
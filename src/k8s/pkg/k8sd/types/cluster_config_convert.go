@@ -80,6 +80,10 @@ func ClusterConfigFromUserFacing(u apiv1.UserFacingClusterConfig) (ClusterConfig
 	}
 
 	return ClusterConfig{
+		APIServer: APIServer{
+			ControlPlaneEndpoint:           u.ControlPlaneEndpoint.Address,
+			ControlPlaneEndpointForWorkers: u.ControlPlaneEndpoint.UseForWorkers,
+		},
 		Kubelet: Kubelet{
 			ClusterDNS:    u.DNS.ServiceIP,
 			ClusterDomain: u.DNS.ClusterDomain,
@@ -91,6 +95,15 @@ func ClusterConfigFromUserFacing(u apiv1.UserFacingClusterConfig) (ClusterConfig
 		DNS: DNS{
 			Enabled:             u.DNS.Enabled,
 			UpstreamNameservers: u.DNS.UpstreamNameservers,
+			CacheTTL:            u.DNS.CacheTTL,
+			StubZones:           dnsStubZonesFromAPI(u.DNS.StubZones),
+			Hosts:               dnsHostsFromAPI(u.DNS.Hosts),
+			ExtraPlugins:        dnsPluginsFromAPI(u.DNS.ExtraPlugins),
+			ExtraServers:        dnsServersFromAPI(u.DNS.ExtraServers),
+		},
+		NodeLocalDNS: NodeLocalDNS{
+			Enabled: u.NodeLocalDNS.Enabled,
+			LocalIP: u.NodeLocalDNS.LocalIP,
 		},
 		Ingress: Ingress{
 			Enabled:             u.Ingress.Enabled,
@@ -121,6 +134,11 @@ func ClusterConfigFromUserFacing(u apiv1.UserFacingClusterConfig) (ClusterConfig
 		Gateway: Gateway{
 			Enabled: u.Gateway.Enabled,
 		},
+		Containerd: Containerd{
+			Registries:      containerdRegistriesFromAPI(u.Containerd.Registries),
+			RuntimeHandlers: containerdRuntimeHandlersFromAPI(u.Containerd.RuntimeHandlers),
+			ImageRegistry:   u.ImageRegistry,
+		},
 	}, nil
 }
 
@@ -135,6 +153,15 @@ func (c ClusterConfig) ToUserFacing() apiv1.UserFacingClusterConfig {
 			ClusterDomain:       c.Kubelet.ClusterDomain,
 			ServiceIP:           c.Kubelet.ClusterDNS,
 			UpstreamNameservers: c.DNS.UpstreamNameservers,
+			CacheTTL:            c.DNS.CacheTTL,
+			StubZones:           dnsStubZonesToAPI(c.DNS.StubZones),
+			Hosts:               dnsHostsToAPI(c.DNS.Hosts),
+			ExtraPlugins:        dnsPluginsToAPI(c.DNS.ExtraPlugins),
+			ExtraServers:        dnsServersToAPI(c.DNS.ExtraServers),
+		},
+		NodeLocalDNS: apiv1.NodeLocalDNSConfig{
+			Enabled: c.NodeLocalDNS.Enabled,
+			LocalIP: c.NodeLocalDNS.LocalIP,
 		},
 		Ingress: apiv1.IngressConfig{
 			Enabled:             c.Ingress.Enabled,
@@ -165,5 +192,14 @@ func (c ClusterConfig) ToUserFacing() apiv1.UserFacingClusterConfig {
 			Enabled: c.Gateway.Enabled,
 		},
 		CloudProvider: c.Kubelet.CloudProvider,
+		ImageRegistry: c.Containerd.ImageRegistry,
+		ControlPlaneEndpoint: apiv1.ControlPlaneEndpointConfig{
+			Address:       c.APIServer.ControlPlaneEndpoint,
+			UseForWorkers: c.APIServer.ControlPlaneEndpointForWorkers,
+		},
+		Containerd: apiv1.ContainerdConfig{
+			Registries:      containerdRegistriesToAPI(c.Containerd.Registries),
+			RuntimeHandlers: containerdRuntimeHandlersToAPI(c.Containerd.RuntimeHandlers),
+		},
 	}
 }
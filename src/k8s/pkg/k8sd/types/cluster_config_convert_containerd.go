@@ -0,0 +1,105 @@
+package types
+
+import (
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+func containerdRegistriesFromAPI(registries *[]apiv1.ContainerdRegistryConfig) *[]ContainerdRegistry {
+	if registries == nil {
+		return nil
+	}
+	result := make([]ContainerdRegistry, 0, len(*registries))
+	for _, r := range *registries {
+		result = append(result, ContainerdRegistry{
+			Host:         r.Host,
+			URLs:         r.URLs,
+			Username:     r.Username,
+			Password:     r.Password,
+			Token:        r.Token,
+			OverridePath: r.OverridePath,
+			SkipVerify:   r.SkipVerify,
+			Capabilities: r.Capabilities,
+			CACert:       r.CACert,
+			ClientCert:   r.ClientCert,
+			ClientKey:    r.ClientKey,
+		})
+	}
+	return &result
+}
+
+func containerdRegistriesToAPI(registries *[]ContainerdRegistry) *[]apiv1.ContainerdRegistryConfig {
+	if registries == nil {
+		return nil
+	}
+	result := make([]apiv1.ContainerdRegistryConfig, 0, len(*registries))
+	for _, r := range *registries {
+		result = append(result, apiv1.ContainerdRegistryConfig{
+			Host:         r.Host,
+			URLs:         r.URLs,
+			Username:     r.Username,
+			Password:     r.Password,
+			Token:        r.Token,
+			OverridePath: r.OverridePath,
+			SkipVerify:   r.SkipVerify,
+			Capabilities: r.Capabilities,
+			CACert:       r.CACert,
+			ClientCert:   r.ClientCert,
+			ClientKey:    r.ClientKey,
+		})
+	}
+	return &result
+}
+
+func containerdRuntimeHandlersFromAPI(handlers *[]apiv1.ContainerdRuntimeHandlerConfig) *[]ContainerdRuntimeHandler {
+	if handlers == nil {
+		return nil
+	}
+	result := make([]ContainerdRuntimeHandler, 0, len(*handlers))
+	for _, h := range *handlers {
+		result = append(result, ContainerdRuntimeHandler{
+			Name:         h.Name,
+			RuntimeType:  h.RuntimeType,
+			BinaryPath:   h.BinaryPath,
+			Options:      h.Options,
+			NodeSelector: h.NodeSelector,
+		})
+	}
+	return &result
+}
+
+func containerdRuntimeHandlersToAPI(handlers *[]ContainerdRuntimeHandler) *[]apiv1.ContainerdRuntimeHandlerConfig {
+	if handlers == nil {
+		return nil
+	}
+	result := make([]apiv1.ContainerdRuntimeHandlerConfig, 0, len(*handlers))
+	for _, h := range *handlers {
+		result = append(result, apiv1.ContainerdRuntimeHandlerConfig{
+			Name:         h.Name,
+			RuntimeType:  h.RuntimeType,
+			BinaryPath:   h.BinaryPath,
+			Options:      h.Options,
+			NodeSelector: h.NodeSelector,
+		})
+	}
+	return &result
+}
+
+// ContainerdRegistriesFromAPI converts a list of registries from the public API.
+func ContainerdRegistriesFromAPI(registries []apiv1.ContainerdRegistryConfig) []ContainerdRegistry {
+	return getField(containerdRegistriesFromAPI(&registries))
+}
+
+// ContainerdRegistriesToAPI converts a list of registries to the public API.
+func ContainerdRegistriesToAPI(registries []ContainerdRegistry) []apiv1.ContainerdRegistryConfig {
+	return getField(containerdRegistriesToAPI(&registries))
+}
+
+// ContainerdRuntimeHandlersFromAPI converts a list of runtime handlers from the public API.
+func ContainerdRuntimeHandlersFromAPI(handlers []apiv1.ContainerdRuntimeHandlerConfig) []ContainerdRuntimeHandler {
+	return getField(containerdRuntimeHandlersFromAPI(&handlers))
+}
+
+// ContainerdRuntimeHandlersToAPI converts a list of runtime handlers to the public API.
+func ContainerdRuntimeHandlersToAPI(handlers []ContainerdRuntimeHandler) []apiv1.ContainerdRuntimeHandlerConfig {
+	return getField(containerdRuntimeHandlersToAPI(&handlers))
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+func dnsStubZonesFromAPI(zones *[]apiv1.DNSStubZoneConfig) *[]DNSStubZone {
+	if zones == nil {
+		return nil
+	}
+	result := make([]DNSStubZone, 0, len(*zones))
+	for _, z := range *zones {
+		result = append(result, DNSStubZone{Zone: z.Zone, Nameservers: z.Nameservers})
+	}
+	return &result
+}
+
+func dnsStubZonesToAPI(zones *[]DNSStubZone) *[]apiv1.DNSStubZoneConfig {
+	if zones == nil {
+		return nil
+	}
+	result := make([]apiv1.DNSStubZoneConfig, 0, len(*zones))
+	for _, z := range *zones {
+		result = append(result, apiv1.DNSStubZoneConfig{Zone: z.Zone, Nameservers: z.Nameservers})
+	}
+	return &result
+}
+
+func dnsHostsFromAPI(hosts *[]apiv1.DNSHostConfig) *[]DNSHostEntry {
+	if hosts == nil {
+		return nil
+	}
+	result := make([]DNSHostEntry, 0, len(*hosts))
+	for _, h := range *hosts {
+		result = append(result, DNSHostEntry{IP: h.IP, Hostnames: h.Hostnames})
+	}
+	return &result
+}
+
+func dnsHostsToAPI(hosts *[]DNSHostEntry) *[]apiv1.DNSHostConfig {
+	if hosts == nil {
+		return nil
+	}
+	result := make([]apiv1.DNSHostConfig, 0, len(*hosts))
+	for _, h := range *hosts {
+		result = append(result, apiv1.DNSHostConfig{IP: h.IP, Hostnames: h.Hostnames})
+	}
+	return &result
+}
+
+func dnsPluginsFromAPI(plugins *[]apiv1.DNSPluginConfig) *[]DNSPlugin {
+	if plugins == nil {
+		return nil
+	}
+	result := make([]DNSPlugin, 0, len(*plugins))
+	for _, p := range *plugins {
+		result = append(result, DNSPlugin{Name: p.Name, Parameters: p.Parameters, ConfigBlock: p.ConfigBlock})
+	}
+	return &result
+}
+
+func dnsPluginsToAPI(plugins *[]DNSPlugin) *[]apiv1.DNSPluginConfig {
+	if plugins == nil {
+		return nil
+	}
+	result := make([]apiv1.DNSPluginConfig, 0, len(*plugins))
+	for _, p := range *plugins {
+		result = append(result, apiv1.DNSPluginConfig{Name: p.Name, Parameters: p.Parameters, ConfigBlock: p.ConfigBlock})
+	}
+	return &result
+}
+
+func dnsServersFromAPI(servers *[]apiv1.DNSServerConfig) *[]DNSServer {
+	if servers == nil {
+		return nil
+	}
+	result := make([]DNSServer, 0, len(*servers))
+	for _, s := range *servers {
+		result = append(result, DNSServer{Zones: s.Zones, Port: s.Port, Plugins: getField(dnsPluginsFromAPI(&s.Plugins))})
+	}
+	return &result
+}
+
+func dnsServersToAPI(servers *[]DNSServer) *[]apiv1.DNSServerConfig {
+	if servers == nil {
+		return nil
+	}
+	result := make([]apiv1.DNSServerConfig, 0, len(*servers))
+	for _, s := range *servers {
+		result = append(result, apiv1.DNSServerConfig{Zones: s.Zones, Port: s.Port, Plugins: getField(dnsPluginsToAPI(&s.Plugins))})
+	}
+	return &result
+}
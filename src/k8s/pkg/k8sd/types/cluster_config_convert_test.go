@@ -54,6 +54,27 @@ func TestClusterConfigFromBootstrapConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ControlPlaneEndpoint",
+			bootstrap: apiv1.BootstrapConfig{
+				ClusterConfig: apiv1.UserFacingClusterConfig{
+					ControlPlaneEndpoint: apiv1.ControlPlaneEndpointConfig{
+						Address:       utils.Pointer("k8s.internal:443"),
+						UseForWorkers: utils.Pointer(true),
+					},
+				},
+			},
+			expectConfig: types.ClusterConfig{
+				APIServer: types.APIServer{
+					AuthorizationMode:              utils.Pointer("Node,RBAC"),
+					ControlPlaneEndpoint:           utils.Pointer("k8s.internal:443"),
+					ControlPlaneEndpointForWorkers: utils.Pointer(true),
+				},
+				Datastore: types.Datastore{
+					Type: utils.Pointer("k8s-dqlite"),
+				},
+			},
+		},
 		{
 			name: "ExternalDatastore",
 			bootstrap: apiv1.BootstrapConfig{
@@ -86,6 +107,11 @@ func TestClusterConfigFromBootstrapConfig(t *testing.T) {
 					DNS: apiv1.DNSConfig{
 						Enabled:       utils.Pointer(true),
 						ClusterDomain: utils.Pointer("cluster.local"),
+						CacheTTL:      utils.Pointer(60),
+						StubZones:     utils.Pointer([]apiv1.DNSStubZoneConfig{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10"}}}),
+						Hosts:         utils.Pointer([]apiv1.DNSHostConfig{{IP: "10.0.0.20", Hostnames: []string{"git"}}}),
+						ExtraPlugins:  utils.Pointer([]apiv1.DNSPluginConfig{{Name: "log"}}),
+						ExtraServers:  utils.Pointer([]apiv1.DNSServerConfig{{Zones: []string{"example.com"}, Plugins: []apiv1.DNSPluginConfig{{Name: "whoami"}}}}),
 					},
 					Ingress: apiv1.IngressConfig{
 						Enabled: utils.Pointer(true),
@@ -135,7 +161,12 @@ func TestClusterConfigFromBootstrapConfig(t *testing.T) {
 					ServiceCIDR: utils.Pointer("10.200.0.0/16"),
 				},
 				DNS: types.DNS{
-					Enabled: utils.Pointer(true),
+					Enabled:      utils.Pointer(true),
+					CacheTTL:     utils.Pointer(60),
+					StubZones:    utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10"}}}),
+					Hosts:        utils.Pointer([]types.DNSHostEntry{{IP: "10.0.0.20", Hostnames: []string{"git"}}}),
+					ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "log"}}),
+					ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"example.com"}, Plugins: []types.DNSPlugin{{Name: "whoami"}}}}),
 				},
 				Ingress: types.Ingress{
 					Enabled: utils.Pointer(true),
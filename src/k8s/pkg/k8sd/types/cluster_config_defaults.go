@@ -40,6 +40,16 @@ func (c *ClusterConfig) SetDefaults() {
 	if len(c.DNS.GetUpstreamNameservers()) == 0 {
 		c.DNS.UpstreamNameservers = utils.Pointer([]string{"/etc/resolv.conf"})
 	}
+	if c.DNS.CacheTTL == nil {
+		c.DNS.CacheTTL = utils.Pointer(30)
+	}
+	// node-local-dns
+	if c.NodeLocalDNS.Enabled == nil {
+		c.NodeLocalDNS.Enabled = utils.Pointer(false)
+	}
+	if c.NodeLocalDNS.GetLocalIP() == "" {
+		c.NodeLocalDNS.LocalIP = utils.Pointer("169.254.20.10")
+	}
 	// local storage
 	if c.LocalStorage.Enabled == nil {
 		c.LocalStorage.Enabled = utils.Pointer(false)
@@ -33,6 +33,11 @@ func TestSetDefaults(t *testing.T) {
 		DNS: types.DNS{
 			Enabled:             utils.Pointer(false),
 			UpstreamNameservers: utils.Pointer([]string{"/etc/resolv.conf"}),
+			CacheTTL:            utils.Pointer(30),
+		},
+		NodeLocalDNS: types.NodeLocalDNS{
+			Enabled: utils.Pointer(false),
+			LocalIP: utils.Pointer("169.254.20.10"),
 		},
 		LocalStorage: types.LocalStorage{
 			Enabled:       utils.Pointer(false),
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+// DiffUserFacingClusterConfig returns the options that differ between two user-facing cluster configurations.
+// Options are identified by their dotted path, e.g. "load-balancer.cidrs". Lists are compared as a whole.
+// The changes are sorted by key.
+func DiffUserFacingClusterConfig(old apiv1.UserFacingClusterConfig, new apiv1.UserFacingClusterConfig) ([]apiv1.ClusterConfigChange, error) {
+	oldValues, err := flattenConfig(old)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten old config: %w", err)
+	}
+	newValues, err := flattenConfig(new)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten new config: %w", err)
+	}
+
+	keys := make(map[string]struct{}, len(oldValues)+len(newValues))
+	for key := range oldValues {
+		keys[key] = struct{}{}
+	}
+	for key := range newValues {
+		keys[key] = struct{}{}
+	}
+
+	changes := []apiv1.ClusterConfigChange{}
+	for key := range keys {
+		if !reflect.DeepEqual(oldValues[key], newValues[key]) {
+			changes = append(changes, apiv1.ClusterConfigChange{Key: key, Old: oldValues[key], New: newValues[key]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+// flattenConfig returns the JSON values of config by dotted path.
+func flattenConfig(config apiv1.UserFacingClusterConfig) (map[string]any, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	values := make(map[string]any)
+	flattenInto(values, "", m)
+	return values, nil
+}
+
+func flattenInto(values map[string]any, prefix string, m map[string]any) {
+	for key, value := range m {
+		if nested, ok := value.(map[string]any); ok {
+			flattenInto(values, prefix+key+".", nested)
+		} else {
+			values[prefix+key] = value
+		}
+	}
+}
+
+// DiffRedactedClusterConfig returns the changes of the user-facing options from old to new, with credentials redacted.
+// Changes of credentials are included, but their values are replaced by RedactedValue, see ClusterConfig.Redacted.
+func DiffRedactedClusterConfig(old ClusterConfig, new ClusterConfig) ([]apiv1.ClusterConfigChange, error) {
+	changes, err := DiffUserFacingClusterConfig(old.ToUserFacing(), new.ToUserFacing())
+	if err != nil {
+		return nil, err
+	}
+	oldValues, err := flattenConfig(old.Redacted().ToUserFacing())
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten old config: %w", err)
+	}
+	newValues, err := flattenConfig(new.Redacted().ToUserFacing())
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten new config: %w", err)
+	}
+	for i, change := range changes {
+		changes[i].Old, changes[i].New = oldValues[change.Key], newValues[change.Key]
+	}
+	return changes, nil
+}
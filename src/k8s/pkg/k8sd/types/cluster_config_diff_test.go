@@ -0,0 +1,84 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiffUserFacingClusterConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		old    apiv1.UserFacingClusterConfig
+		new    apiv1.UserFacingClusterConfig
+		expect []apiv1.ClusterConfigChange
+	}{
+		{
+			name:   "Empty",
+			expect: []apiv1.ClusterConfigChange{},
+		},
+		{
+			name:   "Unchanged",
+			old:    apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+			new:    apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{Enabled: utils.Pointer(true)}},
+			expect: []apiv1.ClusterConfigChange{},
+		},
+		{
+			name: "Changed",
+			old: apiv1.UserFacingClusterConfig{
+				DNS:          apiv1.DNSConfig{Enabled: utils.Pointer(false), ClusterDomain: utils.Pointer("cluster.local")},
+				LoadBalancer: apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{"10.0.0.0/24"})},
+			},
+			new: apiv1.UserFacingClusterConfig{
+				DNS:           apiv1.DNSConfig{Enabled: utils.Pointer(true), ClusterDomain: utils.Pointer("cluster.local")},
+				LoadBalancer:  apiv1.LoadBalancerConfig{CIDRs: utils.Pointer([]string{"10.0.0.0/24", "10.0.1.0/24"})},
+				ImageRegistry: utils.Pointer("registry.internal:5000"),
+			},
+			expect: []apiv1.ClusterConfigChange{
+				{Key: "dns.enabled", Old: false, New: true},
+				{Key: "image-registry", New: "registry.internal:5000"},
+				{Key: "load-balancer.cidrs", Old: []any{"10.0.0.0/24"}, New: []any{"10.0.0.0/24", "10.0.1.0/24"}},
+			},
+		},
+		{
+			name:   "Removed",
+			old:    apiv1.UserFacingClusterConfig{DNS: apiv1.DNSConfig{CacheTTL: utils.Pointer(30)}},
+			expect: []apiv1.ClusterConfigChange{{Key: "dns.cache-ttl", Old: float64(30)}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			changes, err := types.DiffUserFacingClusterConfig(tc.old, tc.new)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changes).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestDiffRedactedClusterConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	old := types.ClusterConfig{
+		DNS: types.DNS{Enabled: utils.Pointer(false)},
+		Containerd: types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+			{Host: "docker.io", Username: "user", Password: "old"},
+		})},
+	}
+	new := types.ClusterConfig{
+		DNS: types.DNS{Enabled: utils.Pointer(true)},
+		Containerd: types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{
+			{Host: "docker.io", Username: "user", Password: "new"},
+		})},
+	}
+
+	changes, err := types.DiffRedactedClusterConfig(old, new)
+	g.Expect(err).To(BeNil())
+	g.Expect(changes).To(HaveLen(2))
+	g.Expect(changes[0].Key).To(Equal("containerd.registries"))
+	g.Expect(changes[0].Old).To(Equal([]any{map[string]any{"host": "docker.io", "username": "user", "password": types.RedactedValue}}))
+	g.Expect(changes[0].New).To(Equal(changes[0].Old))
+	g.Expect(changes[1]).To(Equal(apiv1.ClusterConfigChange{Key: "dns.enabled", Old: false, New: true}))
+}
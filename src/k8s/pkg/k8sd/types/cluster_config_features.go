@@ -1,8 +1,40 @@
 package types
 
 type DNS struct {
-	Enabled             *bool     `json:"enabled,omitempty"`
-	UpstreamNameservers *[]string `json:"upstream-nameservers,omitempty"`
+	Enabled             *bool           `json:"enabled,omitempty"`
+	UpstreamNameservers *[]string       `json:"upstream-nameservers,omitempty"`
+	CacheTTL            *int            `json:"cache-ttl,omitempty"`
+	StubZones           *[]DNSStubZone  `json:"stub-zones,omitempty"`
+	Hosts               *[]DNSHostEntry `json:"hosts,omitempty"`
+	ExtraPlugins        *[]DNSPlugin    `json:"extra-plugins,omitempty"`
+	ExtraServers        *[]DNSServer    `json:"extra-servers,omitempty"`
+}
+
+type DNSStubZone struct {
+	Zone        string   `json:"zone"`
+	Nameservers []string `json:"nameservers"`
+}
+
+type DNSHostEntry struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+type DNSPlugin struct {
+	Name        string `json:"name"`
+	Parameters  string `json:"parameters,omitempty"`
+	ConfigBlock string `json:"config-block,omitempty"`
+}
+
+type DNSServer struct {
+	Zones   []string    `json:"zones"`
+	Port    int         `json:"port,omitempty"`
+	Plugins []DNSPlugin `json:"plugins"`
+}
+
+type NodeLocalDNS struct {
+	Enabled *bool   `json:"enabled,omitempty"`
+	LocalIP *string `json:"local-ip,omitempty"`
 }
 
 type Ingress struct {
@@ -46,8 +78,17 @@ type LocalStorage struct {
 
 func (c DNS) GetEnabled() bool                 { return getField(c.Enabled) }
 func (c DNS) GetUpstreamNameservers() []string { return getField(c.UpstreamNameservers) }
+func (c DNS) GetCacheTTL() int                 { return getField(c.CacheTTL) }
+func (c DNS) GetStubZones() []DNSStubZone      { return getField(c.StubZones) }
+func (c DNS) GetHosts() []DNSHostEntry         { return getField(c.Hosts) }
+func (c DNS) GetExtraPlugins() []DNSPlugin     { return getField(c.ExtraPlugins) }
+func (c DNS) GetExtraServers() []DNSServer     { return getField(c.ExtraServers) }
 func (c DNS) Empty() bool                      { return c == DNS{} }
 
+func (c NodeLocalDNS) GetEnabled() bool   { return getField(c.Enabled) }
+func (c NodeLocalDNS) GetLocalIP() string { return getField(c.LocalIP) }
+func (c NodeLocalDNS) Empty() bool        { return c == NodeLocalDNS{} }
+
 func (c Ingress) GetEnabled() bool             { return getField(c.Enabled) }
 func (c Ingress) GetDefaultTLSSecret() string  { return getField(c.DefaultTLSSecret) }
 func (c Ingress) GetEnableProxyProtocol() bool { return getField(c.EnableProxyProtocol) }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"reflect"
 )
 
 // MergeClusterConfig applies updates from non-empty values of the new ClusterConfig to an existing one.
@@ -48,10 +49,15 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		{name: "service CIDR", val: &config.Network.ServiceCIDR, old: existing.Network.ServiceCIDR, new: new.Network.ServiceCIDR},
 		// apiserver
 		{name: "kube-apiserver authorization mode", val: &config.APIServer.AuthorizationMode, old: existing.APIServer.AuthorizationMode, new: new.APIServer.AuthorizationMode, allowChange: true},
+		{name: "control plane endpoint", val: &config.APIServer.ControlPlaneEndpoint, old: existing.APIServer.ControlPlaneEndpoint, new: new.APIServer.ControlPlaneEndpoint, allowChange: true},
 		// kubelet
 		{name: "kubelet cluster DNS", val: &config.Kubelet.ClusterDNS, old: existing.Kubelet.ClusterDNS, new: new.Kubelet.ClusterDNS, allowChange: !existing.DNS.GetEnabled() || !new.DNS.GetEnabled()},
 		{name: "kubelet cluster domain", val: &config.Kubelet.ClusterDomain, old: existing.Kubelet.ClusterDomain, new: new.Kubelet.ClusterDomain, allowChange: true},
 		{name: "kubelet cloud provider", val: &config.Kubelet.CloudProvider, old: existing.Kubelet.CloudProvider, new: new.Kubelet.CloudProvider, allowChange: true},
+		// containerd
+		{name: "image registry", val: &config.Containerd.ImageRegistry, old: existing.Containerd.ImageRegistry, new: new.Containerd.ImageRegistry, allowChange: true},
+		// node-local-dns
+		{name: "node-local-dns local IP", val: &config.NodeLocalDNS.LocalIP, old: existing.NodeLocalDNS.LocalIP, new: new.NodeLocalDNS.LocalIP, allowChange: true},
 		// ingress
 		{name: "ingress default TLS secret", val: &config.Ingress.DefaultTLSSecret, old: existing.Ingress.DefaultTLSSecret, new: new.Ingress.DefaultTLSSecret, allowChange: true},
 		// load balancer
@@ -89,6 +95,29 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		return ClusterConfig{}, fmt.Errorf("prevented update of load balancer IP ranges: %w", err)
 	}
 
+	// update containerd registries
+	if config.Containerd.Registries, err = mergeSliceFieldFunc(existing.Containerd.Registries, new.Containerd.Registries, true, func(a, b ContainerdRegistry) bool { return reflect.DeepEqual(a, b) }); err != nil {
+		return ClusterConfig{}, fmt.Errorf("prevented update of containerd registries: %w", err)
+	}
+	// update containerd runtime handlers
+	if config.Containerd.RuntimeHandlers, err = mergeSliceFieldFunc(existing.Containerd.RuntimeHandlers, new.Containerd.RuntimeHandlers, true, func(a, b ContainerdRuntimeHandler) bool { return reflect.DeepEqual(a, b) }); err != nil {
+		return ClusterConfig{}, fmt.Errorf("prevented update of containerd runtime handlers: %w", err)
+	}
+
+	// update DNS fields
+	if config.DNS.StubZones, err = mergeSliceFieldFunc(existing.DNS.StubZones, new.DNS.StubZones, true, func(a, b DNSStubZone) bool { return reflect.DeepEqual(a, b) }); err != nil {
+		return ClusterConfig{}, fmt.Errorf("prevented update of DNS stub zones: %w", err)
+	}
+	if config.DNS.Hosts, err = mergeSliceFieldFunc(existing.DNS.Hosts, new.DNS.Hosts, true, func(a, b DNSHostEntry) bool { return reflect.DeepEqual(a, b) }); err != nil {
+		return ClusterConfig{}, fmt.Errorf("prevented update of DNS hosts: %w", err)
+	}
+	if config.DNS.ExtraPlugins, err = mergeSliceFieldFunc(existing.DNS.ExtraPlugins, new.DNS.ExtraPlugins, true, func(a, b DNSPlugin) bool { return a == b }); err != nil {
+		return ClusterConfig{}, fmt.Errorf("prevented update of DNS extra plugins: %w", err)
+	}
+	if config.DNS.ExtraServers, err = mergeSliceFieldFunc(existing.DNS.ExtraServers, new.DNS.ExtraServers, true, func(a, b DNSServer) bool { return reflect.DeepEqual(a, b) }); err != nil {
+		return ClusterConfig{}, fmt.Errorf("prevented update of DNS extra servers: %w", err)
+	}
+
 	// update int fields
 	for _, i := range []struct {
 		name        string
@@ -101,6 +130,8 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		{name: "kube-apiserver secure port", val: &config.APIServer.SecurePort, old: existing.APIServer.SecurePort, new: new.APIServer.SecurePort},
 		// datastore
 		{name: "k8s-dqlite port", val: &config.Datastore.K8sDqlitePort, old: existing.Datastore.K8sDqlitePort, new: new.Datastore.K8sDqlitePort},
+		// DNS
+		{name: "DNS cache TTL", val: &config.DNS.CacheTTL, old: existing.DNS.CacheTTL, new: new.DNS.CacheTTL, allowChange: true},
 		// load-balancer
 		{name: "load balancer BGP local ASN", val: &config.LoadBalancer.BGPLocalASN, old: existing.LoadBalancer.BGPLocalASN, new: new.LoadBalancer.BGPLocalASN, allowChange: true},
 		{name: "load balancer BGP peer ASN", val: &config.LoadBalancer.BGPPeerASN, old: existing.LoadBalancer.BGPPeerASN, new: new.LoadBalancer.BGPPeerASN, allowChange: true},
@@ -119,10 +150,14 @@ func MergeClusterConfig(existing ClusterConfig, new ClusterConfig) (ClusterConfi
 		new         *bool
 		allowChange bool
 	}{
+		// apiserver
+		{name: "control plane endpoint for workers", val: &config.APIServer.ControlPlaneEndpointForWorkers, old: existing.APIServer.ControlPlaneEndpointForWorkers, new: new.APIServer.ControlPlaneEndpointForWorkers, allowChange: true},
 		// network
 		{name: "network enabled", val: &config.Network.Enabled, old: existing.Network.Enabled, new: new.Network.Enabled, allowChange: true},
 		// DNS
 		{name: "DNS enabled", val: &config.DNS.Enabled, old: existing.DNS.Enabled, new: new.DNS.Enabled, allowChange: true},
+		// node-local-dns
+		{name: "node-local-dns enabled", val: &config.NodeLocalDNS.Enabled, old: existing.NodeLocalDNS.Enabled, new: new.NodeLocalDNS.Enabled, allowChange: true},
 		// gateway
 		{name: "gateway enabled", val: &config.Gateway.Enabled, old: existing.Gateway.Enabled, new: new.Gateway.Enabled, allowChange: true},
 		// ingress
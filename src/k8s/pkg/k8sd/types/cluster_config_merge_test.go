@@ -101,9 +101,16 @@ func TestMergeClusterConfig(t *testing.T) {
 		generateMergeClusterConfigTestCases("Network/ServiceCIDR", false, "10.152.183.0/24", "10.152.184.0/24", func(c *types.ClusterConfig, v any) { c.Network.ServiceCIDR = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("APIServer/SecurePort", false, 6443, 16443, func(c *types.ClusterConfig, v any) { c.APIServer.SecurePort = utils.Pointer(v.(int)) }),
 		generateMergeClusterConfigTestCases("APIServer/AuthorizationMode", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.APIServer.AuthorizationMode = utils.Pointer(v.(string)) }),
+		generateMergeClusterConfigTestCases("APIServer/ControlPlaneEndpoint", true, "10.0.0.100", "k8s.internal:443", func(c *types.ClusterConfig, v any) {
+			c.APIServer.ControlPlaneEndpoint = utils.Pointer(v.(string))
+		}),
+		generateMergeClusterConfigTestCases("APIServer/ControlPlaneEndpointForWorkers", true, true, false, func(c *types.ClusterConfig, v any) {
+			c.APIServer.ControlPlaneEndpoint = utils.Pointer("10.0.0.100")
+			c.APIServer.ControlPlaneEndpointForWorkers = utils.Pointer(v.(bool))
+		}),
 		generateMergeClusterConfigTestCases("Kubelet/CloudProvider", true, "v1", "v2", func(c *types.ClusterConfig, v any) { c.Kubelet.CloudProvider = utils.Pointer(v.(string)) }),
 		generateMergeClusterConfigTestCases("Kubelet/ClusterDNS/AllowChange", true, "1.1.1.1", "2.2.2.2", func(c *types.ClusterConfig, v any) { c.Kubelet.ClusterDNS = utils.Pointer(v.(string)) }),
-		generateMergeClusterConfigTestCases("Kubelet/ClusterDNS/PreventChangeIfDNSEnabled", false, "1.1.1.1", "2.2.2.2", func(c *types.ClusterConfig, v any) {
+		generateMergeClusterConfigTestCases("Kubelet/ClusterDNS/PreventChangeIfDNSEnabled", false, "10.152.183.11", "10.152.183.12", func(c *types.ClusterConfig, v any) {
 			c.DNS.Enabled = utils.Pointer(true)
 			c.Kubelet.ClusterDNS = utils.Pointer(v.(string))
 		}),
@@ -113,6 +120,27 @@ func TestMergeClusterConfig(t *testing.T) {
 		generateMergeClusterConfigTestCases("DNS/UpstreamNameservers", true, []string{"c1"}, []string{"c2"}, func(c *types.ClusterConfig, v any) {
 			c.DNS.UpstreamNameservers = utils.Pointer(v.([]string))
 		}),
+		generateMergeClusterConfigTestCases("NodeLocalDNS/Enable", true, false, true, func(c *types.ClusterConfig, v any) {
+			c.DNS.Enabled = utils.Pointer(true)
+			c.NodeLocalDNS.LocalIP = utils.Pointer("169.254.20.10")
+			c.NodeLocalDNS.Enabled = utils.Pointer(v.(bool))
+		}),
+		generateMergeClusterConfigTestCases("NodeLocalDNS/LocalIP", true, "169.254.20.10", "169.254.20.11", func(c *types.ClusterConfig, v any) {
+			c.NodeLocalDNS.LocalIP = utils.Pointer(v.(string))
+		}),
+		generateMergeClusterConfigTestCases("DNS/CacheTTL", true, 30, 60, func(c *types.ClusterConfig, v any) { c.DNS.CacheTTL = utils.Pointer(v.(int)) }),
+		generateMergeClusterConfigTestCases("DNS/StubZones", true, []types.DNSStubZone{{Zone: "a.internal", Nameservers: []string{"10.0.0.10"}}}, []types.DNSStubZone{{Zone: "b.internal", Nameservers: []string{"10.0.0.11"}}}, func(c *types.ClusterConfig, v any) {
+			c.DNS.StubZones = utils.Pointer(v.([]types.DNSStubZone))
+		}),
+		generateMergeClusterConfigTestCases("DNS/Hosts", true, []types.DNSHostEntry{{IP: "10.0.0.20", Hostnames: []string{"a"}}}, []types.DNSHostEntry{{IP: "10.0.0.21", Hostnames: []string{"b"}}}, func(c *types.ClusterConfig, v any) {
+			c.DNS.Hosts = utils.Pointer(v.([]types.DNSHostEntry))
+		}),
+		generateMergeClusterConfigTestCases("DNS/ExtraPlugins", true, []types.DNSPlugin{{Name: "log"}}, []types.DNSPlugin{{Name: "rewrite", Parameters: "name a b"}}, func(c *types.ClusterConfig, v any) {
+			c.DNS.ExtraPlugins = utils.Pointer(v.([]types.DNSPlugin))
+		}),
+		generateMergeClusterConfigTestCases("DNS/ExtraServers", true, []types.DNSServer{{Zones: []string{"a.com"}, Plugins: []types.DNSPlugin{{Name: "whoami"}}}}, []types.DNSServer{{Zones: []string{"b.com"}, Plugins: []types.DNSPlugin{{Name: "whoami"}}}}, func(c *types.ClusterConfig, v any) {
+			c.DNS.ExtraServers = utils.Pointer(v.([]types.DNSServer))
+		}),
 		generateMergeClusterConfigTestCases("Ingress/Enable", true, false, true, func(c *types.ClusterConfig, v any) {
 			c.Network.Enabled = utils.Pointer(true)
 			c.Ingress.Enabled = utils.Pointer(v.(bool))
@@ -147,7 +175,7 @@ func TestMergeClusterConfig(t *testing.T) {
 		generateMergeClusterConfigTestCases("LoadBalancer/CIDRs", true, []string{"172.16.101.0/24"}, []string{"172.16.102.0/24"}, func(c *types.ClusterConfig, v any) {
 			c.LoadBalancer.CIDRs = utils.Pointer(v.([]string))
 		}),
-		generateMergeClusterConfigTestCases("LoadBalancer/IPRanges", true, []types.LoadBalancer_IPRange{{Start: "10.0.0.10", Stop: "10.0.0.20"}}, []types.LoadBalancer_IPRange{{Start: "10.1.0.10", Stop: "10.1.0.20"}}, func(c *types.ClusterConfig, v any) {
+		generateMergeClusterConfigTestCases("LoadBalancer/IPRanges", true, []types.LoadBalancer_IPRange{{Start: "10.0.0.10", Stop: "10.0.0.20"}}, []types.LoadBalancer_IPRange{{Start: "10.2.0.10", Stop: "10.2.0.20"}}, func(c *types.ClusterConfig, v any) {
 			c.LoadBalancer.IPRanges = utils.Pointer(v.([]types.LoadBalancer_IPRange))
 		}),
 		generateMergeClusterConfigTestCases("LoadBalancer/L2Mode/Enable", true, true, false, func(c *types.ClusterConfig, v any) { c.LoadBalancer.L2Mode = utils.Pointer(v.(bool)) }),
@@ -231,7 +259,7 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 			name: "Kubelet/AllowSetClusterDNS/EnableDNSAfter",
 			old: types.ClusterConfig{
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("1.1.1.1"),
+					ClusterDNS: utils.Pointer("10.152.183.11"),
 				},
 			},
 			new: types.ClusterConfig{
@@ -239,7 +267,7 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 					Enabled: utils.Pointer(true),
 				},
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("2.2.2.2"),
+					ClusterDNS: utils.Pointer("10.152.183.12"),
 				},
 			},
 			expectMerged: types.ClusterConfig{
@@ -247,7 +275,7 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 					Enabled: utils.Pointer(true),
 				},
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("2.2.2.2"),
+					ClusterDNS: utils.Pointer("10.152.183.12"),
 				},
 			},
 		},
@@ -255,17 +283,17 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 			name: "Kubelet/AllowSetClusterDNS/KeepDNSDisabled",
 			old: types.ClusterConfig{
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("1.1.1.1"),
+					ClusterDNS: utils.Pointer("10.152.183.11"),
 				},
 			},
 			new: types.ClusterConfig{
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("2.2.2.2"),
+					ClusterDNS: utils.Pointer("10.152.183.12"),
 				},
 			},
 			expectMerged: types.ClusterConfig{
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("2.2.2.2"),
+					ClusterDNS: utils.Pointer("10.152.183.12"),
 				},
 			},
 		},
@@ -278,7 +306,7 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 			},
 			new: types.ClusterConfig{
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("2.2.2.2"),
+					ClusterDNS: utils.Pointer("10.152.183.12"),
 				},
 			},
 			expectMerged: types.ClusterConfig{
@@ -286,7 +314,7 @@ func TestMergeClusterConfig_Scenarios(t *testing.T) {
 					Enabled: utils.Pointer(true),
 				},
 				Kubelet: types.Kubelet{
-					ClusterDNS: utils.Pointer("2.2.2.2"),
+					ClusterDNS: utils.Pointer("10.152.183.12"),
 				},
 			},
 		},
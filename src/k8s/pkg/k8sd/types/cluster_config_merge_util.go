@@ -39,6 +39,24 @@ func mergeSliceField[T comparable](old *[]T, new *[]T, allowChange bool) (*[]T,
 	return new, nil
 }
 
+// mergeSliceFieldFunc is like mergeSliceField, but uses eq to compare elements of the slices.
+func mergeSliceFieldFunc[T any](old *[]T, new *[]T, allowChange bool, eq func(T, T) bool) (*[]T, error) {
+	// old value is not set, use new
+	if old == nil {
+		return new, nil
+	}
+	// new value is not set, or same as old
+	if new == nil || slices.EqualFunc(*new, *old, eq) {
+		return old, nil
+	}
+
+	// both values are not-empty
+	if !allowChange {
+		return nil, fmt.Errorf("value has changed")
+	}
+	return new, nil
+}
+
 func getField[T any](val *T) T {
 	if val != nil {
 		return *val
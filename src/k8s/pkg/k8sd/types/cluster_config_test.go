@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
 	. "github.com/onsi/gomega"
 )
 
@@ -12,3 +13,33 @@ func TestClusterConfigEmpty(t *testing.T) {
 
 	g.Expect(types.ClusterConfig{}.Empty()).To(BeTrue())
 }
+
+func TestClusterConfigNodeKubelet(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		config           types.ClusterConfig
+		expectClusterDNS string
+	}{
+		{
+			name:             "NodeLocalDNSDisabled",
+			config:           types.ClusterConfig{Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10")}, NodeLocalDNS: types.NodeLocalDNS{Enabled: utils.Pointer(false), LocalIP: utils.Pointer("169.254.20.10")}},
+			expectClusterDNS: "10.152.183.10",
+		},
+		{
+			name:             "NodeLocalDNSEnabled",
+			config:           types.ClusterConfig{Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10")}, NodeLocalDNS: types.NodeLocalDNS{Enabled: utils.Pointer(true), LocalIP: utils.Pointer("169.254.20.10")}},
+			expectClusterDNS: "169.254.20.10",
+		},
+		{
+			name:             "NodeLocalDNSEnabledWithoutClusterDNS",
+			config:           types.ClusterConfig{NodeLocalDNS: types.NodeLocalDNS{Enabled: utils.Pointer(true), LocalIP: utils.Pointer("169.254.20.10")}},
+			expectClusterDNS: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(tc.config.NodeKubelet().GetClusterDNS()).To(Equal(tc.expectClusterDNS))
+		})
+	}
+}
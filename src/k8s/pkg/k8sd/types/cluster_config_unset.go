@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+)
+
+// ClusterConfigUnsetMask returns a cluster configuration where the fields that hold the given user-facing options are set.
+// The options are dotted paths of the user-facing cluster configuration, e.g. "load-balancer.bgp-local-asn".
+// The fields of the mask are set to their zero value. Use the mask with ClusterConfig.Unset to clear the options.
+// ClusterConfigUnsetMask returns an error if an option is not known or is a section of options.
+func ClusterConfigUnsetMask(keys []string) (ClusterConfig, error) {
+	var u apiv1.UserFacingClusterConfig
+	for _, key := range keys {
+		field, ok := userFacingField(reflect.ValueOf(&u).Elem(), key)
+		if !ok || field.Kind() != reflect.Pointer || field.Type().Elem().Kind() == reflect.Struct {
+			return ClusterConfig{}, fmt.Errorf("unknown option key %q", key)
+		}
+		value := reflect.New(field.Type().Elem())
+		if value.Elem().Kind() == reflect.Slice {
+			value.Elem().Set(reflect.MakeSlice(value.Elem().Type(), 0, 0))
+		}
+		field.Set(value)
+	}
+	return ClusterConfigFromUserFacing(u)
+}
+
+// ClusterConfigUnsetKeys returns the user-facing options that are set in current but not in target, in the format of ClusterConfigUnsetMask.
+// An update to target must unset these options to restore target, e.g. when rolling back to an earlier revision.
+func ClusterConfigUnsetKeys(current apiv1.UserFacingClusterConfig, target apiv1.UserFacingClusterConfig) []string {
+	var keys []string
+	appendUnsetKeys(&keys, "", reflect.ValueOf(current), reflect.ValueOf(target))
+	return keys
+}
+
+func appendUnsetKeys(keys *[]string, prefix string, current reflect.Value, target reflect.Value) {
+	for i := 0; i < current.NumField(); i++ {
+		name, _, _ := strings.Cut(current.Type().Field(i).Tag.Get("json"), ",")
+		field, targetField := current.Field(i), target.Field(i)
+		switch {
+		case field.Kind() == reflect.Struct:
+			appendUnsetKeys(keys, prefix+name+".", field, targetField)
+		case field.Kind() == reflect.Pointer && !field.IsNil() && targetField.IsNil():
+			*keys = append(*keys, prefix+name)
+		}
+	}
+}
+
+// userFacingField returns the field of the user-facing cluster configuration v for the dotted json path key.
+func userFacingField(v reflect.Value, key string) (reflect.Value, bool) {
+	for _, name := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			if tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ","); tag == name {
+				v, found = v.Field(i), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// Unset returns a copy of the cluster configuration where the fields that are set in mask are cleared.
+// See ClusterConfigUnsetMask.
+func (c ClusterConfig) Unset(mask ClusterConfig) ClusterConfig {
+	unsetFields(reflect.ValueOf(&c).Elem(), reflect.ValueOf(mask))
+	return c
+}
+
+func unsetFields(v reflect.Value, mask reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field, maskField := v.Field(i), mask.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch maskField.Kind() {
+		case reflect.Struct:
+			unsetFields(field, maskField)
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			if !maskField.IsNil() {
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package types_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	"github.com/canonical/k8s/pkg/utils"
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterConfigUnset(t *testing.T) {
+	g := NewWithT(t)
+
+	config := types.ClusterConfig{
+		Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10"), CloudProvider: utils.Pointer("external")},
+		LoadBalancer: types.LoadBalancer{
+			Enabled:     utils.Pointer(true),
+			BGPLocalASN: utils.Pointer(64512),
+			BGPPeerASN:  utils.Pointer(64513),
+		},
+		Containerd: types.Containerd{ImageRegistry: utils.Pointer("registry.internal:5000")},
+	}
+
+	mask, err := types.ClusterConfigUnsetMask([]string{"load-balancer.bgp-local-asn", "dns.service-ip", "image-registry"})
+	g.Expect(err).To(BeNil())
+	g.Expect(config.Unset(mask)).To(Equal(types.ClusterConfig{
+		Kubelet:      types.Kubelet{CloudProvider: utils.Pointer("external")},
+		LoadBalancer: types.LoadBalancer{Enabled: utils.Pointer(true), BGPPeerASN: utils.Pointer(64513)},
+	}))
+
+	// the original configuration is not changed
+	g.Expect(config.LoadBalancer.BGPLocalASN).To(Equal(utils.Pointer(64512)))
+
+	for _, key := range []string{"unknown", "load-balancer", "load-balancer.unknown", "load-balancer.bgp-local-asn.value"} {
+		_, err := types.ClusterConfigUnsetMask([]string{key})
+		g.Expect(err).To(HaveOccurred(), key)
+	}
+}
+
+func TestClusterConfigUnsetKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	current := apiv1.UserFacingClusterConfig{
+		DNS:           apiv1.DNSConfig{Enabled: utils.Pointer(true), ServiceIP: utils.Pointer("10.152.183.10")},
+		LoadBalancer:  apiv1.LoadBalancerConfig{Enabled: utils.Pointer(true), BGPLocalASN: utils.Pointer(64512)},
+		ImageRegistry: utils.Pointer("registry.internal:5000"),
+	}
+	target := apiv1.UserFacingClusterConfig{
+		DNS:          apiv1.DNSConfig{Enabled: utils.Pointer(false)},
+		LoadBalancer: apiv1.LoadBalancerConfig{Enabled: utils.Pointer(true)},
+	}
+
+	keys := types.ClusterConfigUnsetKeys(current, target)
+	g.Expect(keys).To(Equal([]string{"dns.service-ip", "load-balancer.bgp-local-asn", "image-registry"}))
+
+	// the keys are accepted by ClusterConfigUnsetMask
+	_, err := types.ClusterConfigUnsetMask(keys)
+	g.Expect(err).To(BeNil())
+
+	g.Expect(types.ClusterConfigUnsetKeys(target, target)).To(BeEmpty())
+}
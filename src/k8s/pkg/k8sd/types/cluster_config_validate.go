@@ -1,13 +1,41 @@
 package types
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"net"
 	"net/netip"
 	"net/url"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/canonical/k8s/pkg/utils"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+var dnsPluginNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// builtinDNSPlugins are the plugins that k8sd configures in the CoreDNS server block of the cluster domain.
+// Most CoreDNS plugins can only be used once per server block, so extra plugins must not repeat them.
+var builtinDNSPlugins = map[string]string{
+	"errors":      "",
+	"health":      "",
+	"ready":       "",
+	"hosts":       "use dns.hosts instead",
+	"kubernetes":  "",
+	"prometheus":  "",
+	"forward":     "use dns.upstream-nameservers or dns.stub-zones instead",
+	"cache":       "use dns.cache-ttl instead",
+	"loop":        "",
+	"reload":      "",
+	"loadbalance": "",
+}
+
 func validateCIDRs(cidrString string) error {
 	cidrs := strings.Split(cidrString, ",")
 	if v := len(cidrs); v != 1 && v != 2 {
@@ -21,66 +49,303 @@ func validateCIDRs(cidrString string) error {
 	return nil
 }
 
+func validateControlPlaneEndpoint(endpoint string) error {
+	host, port := splitControlPlaneEndpoint(endpoint)
+	if port != "" {
+		if v, err := strconv.Atoi(port); err != nil || v <= 0 || v > 65535 {
+			return fmt.Errorf("port of %q must be between 1 and 65535", endpoint)
+		}
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return fmt.Errorf("%q must be an IP address or DNS name, optionally with a port: %s", endpoint, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func validateContainerdRuntimeHandler(handler ContainerdRuntimeHandler) error {
+	if errs := validation.IsDNS1123Label(handler.Name); len(errs) > 0 {
+		return fmt.Errorf("name must be a valid DNS label: %s", strings.Join(errs, ", "))
+	}
+	if handler.Name == "runc" {
+		return fmt.Errorf("name %q is reserved for the default runtime", handler.Name)
+	}
+	if handler.BinaryPath != "" && !path.IsAbs(handler.BinaryPath) {
+		return fmt.Errorf("binary-path must be an absolute path")
+	}
+	for k, v := range handler.NodeSelector {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("node-selector key %q is not a valid label key: %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("node-selector value %q is not a valid label value: %s", v, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateContainerdRegistry(registry ContainerdRegistry) error {
+	if registry.Host == "" {
+		return fmt.Errorf("host must be set")
+	}
+	if strings.ContainsAny(registry.Host, "/\\") || registry.Host == "." || registry.Host == ".." {
+		return fmt.Errorf("host must be a registry name, not a path")
+	}
+	for _, u := range registry.URLs {
+		if parsed, err := url.Parse(u); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("%q is not a valid URL", u)
+		}
+	}
+	for _, capability := range registry.Capabilities {
+		switch capability {
+		case "pull", "resolve", "push":
+		default:
+			return fmt.Errorf("capability %q must be one of pull, resolve, push", capability)
+		}
+	}
+	if registry.CACert != "" {
+		block, _ := pem.Decode([]byte(registry.CACert))
+		if block == nil {
+			return fmt.Errorf("ca-crt is not a PEM encoded certificate")
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("ca-crt is not a valid certificate: %w", err)
+		}
+	}
+	if (registry.ClientCert == "") != (registry.ClientKey == "") {
+		return fmt.Errorf("client-crt and client-key must be set together")
+	}
+	if registry.ClientCert != "" {
+		if _, err := tls.X509KeyPair([]byte(registry.ClientCert), []byte(registry.ClientKey)); err != nil {
+			return fmt.Errorf("client-crt and client-key are not a valid key pair: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateDNSZone(zone string) error {
+	if zone == "." {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(zone, ".")); len(errs) > 0 {
+		return fmt.Errorf("%q is not a valid DNS zone: %s", zone, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func validateDNSNameserver(nameserver string) error {
+	host, port := nameserver, ""
+	if h, p, err := net.SplitHostPort(nameserver); err == nil {
+		host, port = h, p
+	}
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%q is not a valid nameserver address", nameserver)
+	}
+	if port != "" {
+		if v, err := strconv.Atoi(port); err != nil || v <= 0 || v > 65535 {
+			return fmt.Errorf("%q does not have a valid port", nameserver)
+		}
+	}
+	return nil
+}
+
+func validateDNSPlugin(plugin DNSPlugin) error {
+	if !dnsPluginNameRegexp.MatchString(plugin.Name) {
+		return fmt.Errorf("name %q must consist of lower case alphanumeric characters and underscores", plugin.Name)
+	}
+	if strings.ContainsAny(plugin.Parameters, "{}\n") {
+		return fmt.Errorf("parameters must be on a single line and must not contain braces")
+	}
+	// an unbalanced config block would break out of the plugin and corrupt the Corefile
+	depth := 0
+	for _, r := range plugin.ConfigBlock {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("config-block has unbalanced braces")
+	}
+	return nil
+}
+
+// ValidationIssue is a problem with a single field of a ClusterConfig.
+type ValidationIssue struct {
+	// Field is the path of the field, e.g. "dns.service-ip".
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+// ValidationResult is the list of problems found in a ClusterConfig.
+type ValidationResult struct {
+	// Errors are problems that prevent the configuration from being applied.
+	Errors []ValidationIssue
+	// Warnings are problems that are likely to cause issues, but do not prevent the configuration from being applied.
+	Warnings []ValidationIssue
+}
+
+// Err returns an error with all validation errors, or nil if there are none.
+func (r ValidationResult) Err() error {
+	errs := make([]error, 0, len(r.Errors))
+	for _, issue := range r.Errors {
+		errs = append(errs, errors.New(issue.String()))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *ValidationResult) errorf(field string, format string, args ...any) {
+	r.Errors = append(r.Errors, ValidationIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *ValidationResult) warnf(field string, format string, args ...any) {
+	r.Warnings = append(r.Warnings, ValidationIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateOptions are extra inputs for ValidateAll.
+type ValidateOptions struct {
+	// HostNetworks are the networks of the host interfaces. Cluster CIDRs that overlap with them are reported as warnings.
+	HostNetworks []*net.IPNet
+}
+
 // Validate that a ClusterConfig does not have conflicting or incompatible options.
+// Validate returns an error that includes all problems found by ValidateAll.
 func (c *ClusterConfig) Validate() error {
+	return c.ValidateAll(ValidateOptions{}).Err()
+}
+
+// ValidateAll checks a ClusterConfig for conflicting or incompatible options and reports all problems at once.
+func (c ClusterConfig) ValidateAll(opts ValidateOptions) ValidationResult {
+	var r ValidationResult
+
 	// check: validate that PodCIDR and ServiceCIDR are configured
 	if err := validateCIDRs(c.Network.GetPodCIDR()); err != nil {
-		return fmt.Errorf("invalid pod CIDR: %w", err)
+		r.errorf("pod-cidr", "invalid pod CIDR: %v", err)
 	}
 	if err := validateCIDRs(c.Network.GetServiceCIDR()); err != nil {
-		return fmt.Errorf("invalid service CIDR: %w", err)
+		r.errorf("service-cidr", "invalid service CIDR: %v", err)
+	}
+
+	// check: cluster networks must not overlap with each other or with the host
+	podCIDRs := parseCIDRs(c.Network.GetPodCIDR())
+	serviceCIDRs := parseCIDRs(c.Network.GetServiceCIDR())
+	for _, podCIDR := range podCIDRs {
+		for _, serviceCIDR := range serviceCIDRs {
+			if utils.CIDRsOverlap(podCIDR, serviceCIDR) {
+				r.errorf("service-cidr", "service CIDR %s overlaps with pod CIDR %s", serviceCIDR, podCIDR)
+			}
+		}
+	}
+	for _, hostNetwork := range opts.HostNetworks {
+		for _, podCIDR := range podCIDRs {
+			if utils.CIDRsOverlap(podCIDR, hostNetwork) {
+				r.warnf("pod-cidr", "pod CIDR %s overlaps with host network %s", podCIDR, hostNetwork)
+			}
+		}
+		for _, serviceCIDR := range serviceCIDRs {
+			if utils.CIDRsOverlap(serviceCIDR, hostNetwork) {
+				r.warnf("service-cidr", "service CIDR %s overlaps with host network %s", serviceCIDR, hostNetwork)
+			}
+		}
+	}
+
+	// check: ensure kube-apiserver secure port is a valid port number
+	if c.APIServer.SecurePort != nil {
+		if v := c.APIServer.GetSecurePort(); v <= 0 || v > 65535 {
+			r.errorf("apiserver.port", "must be between 1 and 65535, but found %d instead", v)
+		}
+	}
+
+	// check: control plane endpoint
+	if v := c.APIServer.GetControlPlaneEndpoint(); v != "" {
+		if err := validateControlPlaneEndpoint(v); err != nil {
+			r.errorf("control-plane-endpoint.address", "%v", err)
+		}
+	} else if c.APIServer.GetControlPlaneEndpointForWorkers() {
+		r.errorf("control-plane-endpoint.use-for-workers", "requires control-plane-endpoint.address to be set")
 	}
 
 	// check: ensure network is enabled if any of ingress, gateway, load-balancer are enabled
 	if !c.Network.GetEnabled() {
 		if c.Gateway.GetEnabled() {
-			return fmt.Errorf("gateway requires network to be enabled")
+			r.errorf("gateway.enabled", "gateway requires network to be enabled")
 		}
 		if c.LoadBalancer.GetEnabled() {
-			return fmt.Errorf("load-balancer requires network to be enabled")
+			r.errorf("load-balancer.enabled", "load-balancer requires network to be enabled")
 		}
 		if c.Ingress.GetEnabled() {
-			return fmt.Errorf("ingress requires network to be enabled")
+			r.errorf("ingress.enabled", "ingress requires network to be enabled")
 		}
 	}
 
 	// check: load-balancer CIDRs
 	for _, cidr := range c.LoadBalancer.GetCIDRs() {
-		// Handle CIDR
-		if _, _, err := net.ParseCIDR(cidr); err != nil {
-			return fmt.Errorf("load-balancer configuration contains an invalid CIDR %q: %w", cidr, err)
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			r.errorf("load-balancer.cidrs", "invalid CIDR %q: %v", cidr, err)
+			continue
+		}
+		for _, clusterCIDR := range append(podCIDRs, serviceCIDRs...) {
+			if utils.CIDRsOverlap(ipNet, clusterCIDR) {
+				r.errorf("load-balancer.cidrs", "CIDR %s overlaps with cluster CIDR %s", ipNet, clusterCIDR)
+			}
 		}
 	}
 
 	for _, ipRange := range c.LoadBalancer.GetIPRanges() {
 		start, err := netip.ParseAddr(ipRange.Start)
 		if err != nil {
-			return fmt.Errorf("load-balancer configuration contains an IP range (%#v) with an invalid start IP: %w", ipRange, err)
+			r.errorf("load-balancer.cidrs", "IP range %s-%s has an invalid start IP: %v", ipRange.Start, ipRange.Stop, err)
+			continue
 		}
 		stop, err := netip.ParseAddr(ipRange.Stop)
 		if err != nil {
-			return fmt.Errorf("load-balancer configuration contains an IP range (%#v) with an invalid stop IP: %w", ipRange, err)
+			r.errorf("load-balancer.cidrs", "IP range %s-%s has an invalid stop IP: %v", ipRange.Start, ipRange.Stop, err)
+			continue
 		}
 
 		// Check if stop is greater than start
 		if stop.Less(start) {
-			return fmt.Errorf("load-balancer configuration contains an IP range (%#v) with start IP greater than the stop IP", ipRange)
+			r.errorf("load-balancer.cidrs", "IP range %s-%s has a start IP greater than the stop IP", ipRange.Start, ipRange.Stop)
+			continue
+		}
+
+		for _, clusterCIDR := range append(podCIDRs, serviceCIDRs...) {
+			if ipRangeOverlapsCIDR(start, stop, clusterCIDR) {
+				r.errorf("load-balancer.cidrs", "IP range %s-%s overlaps with cluster CIDR %s", ipRange.Start, ipRange.Stop, clusterCIDR)
+			}
 		}
 	}
 
+	if c.LoadBalancer.GetEnabled() && len(c.LoadBalancer.GetCIDRs()) == 0 && len(c.LoadBalancer.GetIPRanges()) == 0 {
+		r.warnf("load-balancer.cidrs", "no addresses are configured, LoadBalancer services will not get an external IP")
+	}
+
 	// check: load-balancer BGP mode configuration
 	if c.LoadBalancer.GetBGPMode() {
 		if c.LoadBalancer.GetBGPLocalASN() == 0 {
-			return fmt.Errorf("load-balancer.bgp-local-asn must be set when load-balancer.bgp-mode is enabled")
+			r.errorf("load-balancer.bgp-local-asn", "must be set when load-balancer.bgp-mode is enabled")
 		}
 		if c.LoadBalancer.GetBGPPeerAddress() == "" {
-			return fmt.Errorf("load-balancer.bgp-peer-address must be set when load-balancer.bgp-mode is enabled")
+			r.errorf("load-balancer.bgp-peer-address", "must be set when load-balancer.bgp-mode is enabled")
 		}
 		if c.LoadBalancer.GetBGPPeerPort() == 0 {
-			return fmt.Errorf("load-balancer.bgp-peer-port must be set when load-balancer.bgp-mode is enabled")
+			r.errorf("load-balancer.bgp-peer-port", "must be set when load-balancer.bgp-mode is enabled")
 		}
 		if c.LoadBalancer.GetBGPPeerASN() == 0 {
-			return fmt.Errorf("load-balancer.bgp-peer-asn must be set when load-balancer.bgp-mode is enabled")
+			r.errorf("load-balancer.bgp-peer-asn", "must be set when load-balancer.bgp-mode is enabled")
 		}
 	}
 
@@ -88,29 +353,190 @@ func (c *ClusterConfig) Validate() error {
 	switch c.LocalStorage.GetReclaimPolicy() {
 	case "", "Retain", "Recycle", "Delete":
 	default:
-		return fmt.Errorf("local-storage.reclaim-policy must be one of: Retrain, Recycle, Delete")
+		r.errorf("local-storage.reclaim-policy", "must be one of: Retain, Recycle, Delete")
 	}
 
 	// check: local-storage.local-path must be set if enabled
 	if c.LocalStorage.GetEnabled() && c.LocalStorage.GetLocalPath() == "" {
-		return fmt.Errorf("local-storage.local-path must be set when local-storage is enabled")
+		r.errorf("local-storage.local-path", "must be set when local-storage is enabled")
 	}
 
 	// check: ensure cluster DNS is a valid IP address
 	if v := c.Kubelet.GetClusterDNS(); v != "" {
-		if net.ParseIP(v) == nil {
-			return fmt.Errorf("dns.service-ip must be a valid IP address")
+		if ip := net.ParseIP(v); ip == nil {
+			r.errorf("dns.service-ip", "must be a valid IP address")
+		} else if c.DNS.GetEnabled() {
+			// the DNS service is deployed in the cluster, so its address must be a service IP
+			inServiceCIDR := false
+			for _, serviceCIDR := range serviceCIDRs {
+				inServiceCIDR = inServiceCIDR || serviceCIDR.Contains(ip)
+			}
+			if len(serviceCIDRs) > 0 && !inServiceCIDR {
+				r.errorf("dns.service-ip", "%s is not part of the service CIDR %s", v, c.Network.GetServiceCIDR())
+			}
+			for _, serviceCIDR := range serviceCIDRs {
+				if firstIP, err := utils.GetFirstIP(serviceCIDR.String()); err == nil && firstIP.Equal(ip) {
+					r.errorf("dns.service-ip", "%s is reserved for the kubernetes service", v)
+				}
+			}
 		}
+	}
 
-		// TODO: ensure dns.service-ip is part of new.Network.ServiceCIDR
+	// check: DNS configuration
+	if v := c.DNS.GetCacheTTL(); v < 0 {
+		r.errorf("dns.cache-ttl", "must not be negative, but found %d instead", v)
+	}
+	for _, host := range c.DNS.GetHosts() {
+		if net.ParseIP(host.IP) == nil {
+			r.errorf("dns.hosts", "invalid IP address %q", host.IP)
+		}
+		if len(host.Hostnames) == 0 {
+			r.errorf("dns.hosts", "entry for %q must have at least one hostname", host.IP)
+		}
+		for _, hostname := range host.Hostnames {
+			if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(hostname, ".")); len(errs) > 0 {
+				r.errorf("dns.hosts", "entry for %q contains invalid hostname %q: %s", host.IP, hostname, strings.Join(errs, ", "))
+			}
+		}
+	}
+	extraPlugins := make(map[string]struct{}, len(c.DNS.GetExtraPlugins()))
+	for _, plugin := range c.DNS.GetExtraPlugins() {
+		if err := validateDNSPlugin(plugin); err != nil {
+			r.errorf("dns.extra-plugins", "invalid plugin %q: %v", plugin.Name, err)
+		}
+		if hint, ok := builtinDNSPlugins[plugin.Name]; ok {
+			if hint != "" {
+				r.errorf("dns.extra-plugins", "plugin %q is already configured by k8sd, %s", plugin.Name, hint)
+			} else {
+				r.errorf("dns.extra-plugins", "plugin %q is already configured by k8sd", plugin.Name)
+			}
+		}
+		if _, ok := extraPlugins[plugin.Name]; ok {
+			r.errorf("dns.extra-plugins", "plugin %q is listed more than once", plugin.Name)
+		}
+		extraPlugins[plugin.Name] = struct{}{}
+	}
+	// every zone can only be served once per port, and the cluster domain is served on "." port 53
+	dnsZones := map[string]struct{}{".:53": {}}
+	for _, stubZone := range c.DNS.GetStubZones() {
+		if stubZone.Zone == "." {
+			r.errorf("dns.stub-zones", "cannot contain the root zone, use dns.upstream-nameservers instead")
+			continue
+		}
+		if err := validateDNSZone(stubZone.Zone); err != nil {
+			r.errorf("dns.stub-zones", "invalid zone: %v", err)
+		}
+		if len(stubZone.Nameservers) == 0 {
+			r.errorf("dns.stub-zones", "zone %q must have at least one nameserver", stubZone.Zone)
+		}
+		for _, nameserver := range stubZone.Nameservers {
+			if err := validateDNSNameserver(nameserver); err != nil {
+				r.errorf("dns.stub-zones", "zone %q contains invalid nameserver: %v", stubZone.Zone, err)
+			}
+		}
+		key := fmt.Sprintf("%s.:53", strings.TrimSuffix(stubZone.Zone, "."))
+		if _, ok := dnsZones[key]; ok {
+			r.errorf("dns.stub-zones", "duplicate zone %q", stubZone.Zone)
+		}
+		dnsZones[key] = struct{}{}
+	}
+	for _, server := range c.DNS.GetExtraServers() {
+		if server.Port < 0 || server.Port > 65535 {
+			r.errorf("dns.extra-servers", "port must be between 1 and 65535, but found %d instead", server.Port)
+		}
+		port := server.Port
+		if port == 0 {
+			port = 53
+		}
+		if len(server.Zones) == 0 {
+			r.errorf("dns.extra-servers", "entries must have at least one zone")
+		}
+		if len(server.Plugins) == 0 {
+			r.errorf("dns.extra-servers", "entry for %v must have at least one plugin", server.Zones)
+		}
+		for _, zone := range server.Zones {
+			if err := validateDNSZone(zone); err != nil {
+				r.errorf("dns.extra-servers", "invalid zone: %v", err)
+			}
+			key := fmt.Sprintf("%s.:%d", strings.TrimSuffix(zone, "."), port)
+			if _, ok := dnsZones[key]; ok {
+				r.errorf("dns.extra-servers", "zone %q on port %d is already served", zone, port)
+			}
+			dnsZones[key] = struct{}{}
+		}
+		for _, plugin := range server.Plugins {
+			if err := validateDNSPlugin(plugin); err != nil {
+				r.errorf("dns.extra-servers", "entry for %v contains invalid plugin %q: %v", server.Zones, plugin.Name, err)
+			}
+		}
+	}
+
+	// check: node-local-dns requires DNS and listens on a link-local address
+	if c.NodeLocalDNS.GetEnabled() && !c.DNS.GetEnabled() {
+		r.errorf("node-local-dns.enabled", "node-local-dns requires dns to be enabled")
+	}
+	if v := c.NodeLocalDNS.GetLocalIP(); v != "" {
+		if ip := net.ParseIP(v); ip == nil || !ip.IsLinkLocalUnicast() {
+			r.errorf("node-local-dns.local-ip", "must be a link-local IP address, e.g. 169.254.20.10")
+		}
+	}
+
+	// check: containerd registries
+	for _, registry := range c.Containerd.GetRegistries() {
+		if err := validateContainerdRegistry(registry); err != nil {
+			r.errorf("containerd.registries", "invalid registry %q: %v", registry.Host, err)
+		}
+	}
+
+	// check: image registry
+	if v := c.Containerd.GetImageRegistry(); v != "" {
+		if parsed, err := url.Parse("//" + v); err != nil || parsed.Host == "" || strings.Contains(v, "://") || strings.ContainsAny(v, " \t@") {
+			r.errorf("image-registry", "must be a registry host with an optional path, e.g. registry.internal:5000/mirror")
+		}
+	}
+
+	// check: containerd runtime handlers
+	runtimeHandlers := make(map[string]struct{}, len(c.Containerd.GetRuntimeHandlers()))
+	for _, handler := range c.Containerd.GetRuntimeHandlers() {
+		if err := validateContainerdRuntimeHandler(handler); err != nil {
+			r.errorf("containerd.runtime-handlers", "invalid runtime handler %q: %v", handler.Name, err)
+		}
+		if _, ok := runtimeHandlers[handler.Name]; ok {
+			r.errorf("containerd.runtime-handlers", "duplicate runtime handler %q", handler.Name)
+		}
+		runtimeHandlers[handler.Name] = struct{}{}
 	}
 
 	// check: all external datastore servers are valid URLs
 	for _, server := range c.Datastore.GetExternalServers() {
 		if _, err := url.Parse(server); err != nil {
-			return fmt.Errorf("datastore.external-servers contains invalid address: %s", server)
+			r.errorf("datastore-servers", "invalid address: %s", server)
 		}
 	}
 
-	return nil
+	return r
+}
+
+// parseCIDRs returns the valid CIDRs of a comma-separated list. Invalid CIDRs are ignored.
+func parseCIDRs(cidrString string) []*net.IPNet {
+	var result []*net.IPNet
+	for _, cidr := range strings.Split(cidrString, ",") {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			result = append(result, ipNet)
+		}
+	}
+	return result
+}
+
+// ipRangeOverlapsCIDR returns true if any address from start to stop is part of cidr.
+func ipRangeOverlapsCIDR(start, stop netip.Addr, cidr *net.IPNet) bool {
+	prefix, err := netip.ParsePrefix(cidr.String())
+	if err != nil {
+		return false
+	}
+	first := prefix.Masked().Addr()
+	if start.BitLen() != first.BitLen() {
+		return false
+	}
+	return prefix.Contains(start) || prefix.Contains(stop) || (start.Compare(first) <= 0 && stop.Compare(first) >= 0)
 }
@@ -1,9 +1,11 @@
 package types_test
 
 import (
-	"github.com/canonical/k8s/pkg/utils"
+	"net"
 	"testing"
 
+	"github.com/canonical/k8s/pkg/utils"
+
 	"github.com/canonical/k8s/pkg/k8sd/types"
 	. "github.com/onsi/gomega"
 )
@@ -26,7 +28,7 @@ func TestValidateCIDR(t *testing.T) {
 				config := types.ClusterConfig{
 					Network: types.Network{
 						PodCIDR:     utils.Pointer(tc.cidr),
-						ServiceCIDR: utils.Pointer("10.1.0.0/16"),
+						ServiceCIDR: utils.Pointer("10.152.183.0/24"),
 					},
 				}
 				err := config.Validate()
@@ -40,7 +42,7 @@ func TestValidateCIDR(t *testing.T) {
 				g := NewWithT(t)
 				config := types.ClusterConfig{
 					Network: types.Network{
-						PodCIDR:     utils.Pointer("10.1.0.0/16"),
+						PodCIDR:     utils.Pointer("10.152.183.0/24"),
 						ServiceCIDR: utils.Pointer(tc.cidr),
 					},
 				}
@@ -128,3 +130,360 @@ func TestValidateExternalServers(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAPIServerSecurePort(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		port      int
+		expectErr bool
+	}{
+		{name: "Default", port: 6443},
+		{name: "Custom", port: 16443},
+		{name: "Negative", port: -1, expectErr: true},
+		{name: "TooLarge", port: 70000, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{APIServer: types.APIServer{SecurePort: utils.Pointer(tc.port)}}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateContainerdRegistries(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		registry  types.ContainerdRegistry
+		expectErr bool
+	}{
+		{name: "Mirror", registry: types.ContainerdRegistry{Host: "docker.io", URLs: []string{"https://mirror.internal:5000"}}},
+		{name: "Capabilities", registry: types.ContainerdRegistry{Host: "docker.io", URLs: []string{"https://mirror.internal"}, Capabilities: []string{"pull", "resolve", "push"}}},
+		{name: "NoHost", registry: types.ContainerdRegistry{URLs: []string{"https://mirror.internal"}}, expectErr: true},
+		{name: "PathHost", registry: types.ContainerdRegistry{Host: "../etc", URLs: []string{"https://mirror.internal"}}, expectErr: true},
+		{name: "InvalidURL", registry: types.ContainerdRegistry{Host: "docker.io", URLs: []string{"mirror.internal"}}, expectErr: true},
+		{name: "InvalidCapability", registry: types.ContainerdRegistry{Host: "docker.io", Capabilities: []string{"delete"}}, expectErr: true},
+		{name: "InvalidCACert", registry: types.ContainerdRegistry{Host: "docker.io", CACert: "not a certificate"}, expectErr: true},
+		{name: "ClientCertWithoutKey", registry: types.ContainerdRegistry{Host: "docker.io", ClientCert: "cert"}, expectErr: true},
+		{name: "InvalidClientKeyPair", registry: types.ContainerdRegistry{Host: "docker.io", ClientCert: "cert", ClientKey: "key"}, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{Containerd: types.Containerd{Registries: utils.Pointer([]types.ContainerdRegistry{tc.registry})}}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateContainerdRuntimeHandlers(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		handlers  []types.ContainerdRuntimeHandler
+		expectErr bool
+	}{
+		{name: "Valid", handlers: []types.ContainerdRuntimeHandler{{Name: "gvisor", RuntimeType: "io.containerd.runsc.v1", BinaryPath: "/usr/bin/runsc", NodeSelector: map[string]string{"example.com/sandbox": "gvisor"}}}},
+		{name: "Multiple", handlers: []types.ContainerdRuntimeHandler{{Name: "gvisor"}, {Name: "kata"}}},
+		{name: "NoName", handlers: []types.ContainerdRuntimeHandler{{RuntimeType: "io.containerd.runsc.v1"}}, expectErr: true},
+		{name: "InvalidName", handlers: []types.ContainerdRuntimeHandler{{Name: "Not_Valid"}}, expectErr: true},
+		{name: "Reserved", handlers: []types.ContainerdRuntimeHandler{{Name: "runc"}}, expectErr: true},
+		{name: "Duplicate", handlers: []types.ContainerdRuntimeHandler{{Name: "gvisor"}, {Name: "gvisor"}}, expectErr: true},
+		{name: "RelativeBinaryPath", handlers: []types.ContainerdRuntimeHandler{{Name: "gvisor", BinaryPath: "bin/runsc"}}, expectErr: true},
+		{name: "InvalidNodeSelector", handlers: []types.ContainerdRuntimeHandler{{Name: "gvisor", NodeSelector: map[string]string{"sandbox": "not valid"}}}, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{Containerd: types.Containerd{RuntimeHandlers: utils.Pointer(tc.handlers)}}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateControlPlaneEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		endpoint      string
+		useForWorkers bool
+		expectErr     bool
+	}{
+		{name: "Host", endpoint: "k8s.internal"},
+		{name: "HostPort", endpoint: "k8s.internal:443"},
+		{name: "IPv4", endpoint: "10.0.0.100"},
+		{name: "IPv6", endpoint: "[fd00::100]:6443"},
+		{name: "UseForWorkers", endpoint: "10.0.0.100", useForWorkers: true},
+		{name: "UseForWorkersWithoutEndpoint", useForWorkers: true, expectErr: true},
+		{name: "URL", endpoint: "https://k8s.internal", expectErr: true},
+		{name: "InvalidPort", endpoint: "k8s.internal:0", expectErr: true},
+		{name: "InvalidHost", endpoint: "k8s_internal", expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{APIServer: types.APIServer{ControlPlaneEndpointForWorkers: utils.Pointer(tc.useForWorkers)}}
+			if tc.endpoint != "" {
+				config.APIServer.ControlPlaneEndpoint = utils.Pointer(tc.endpoint)
+			}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateImageRegistry(t *testing.T) {
+	for _, tc := range []struct {
+		registry  string
+		expectErr bool
+	}{
+		{registry: "registry.internal"},
+		{registry: "registry.internal:5000"},
+		{registry: "registry.internal:5000/mirror/path"},
+		{registry: "https://registry.internal", expectErr: true},
+		{registry: "user@registry.internal", expectErr: true},
+		{registry: "registry internal", expectErr: true},
+		{registry: "/mirror", expectErr: true},
+	} {
+		t.Run(tc.registry, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{Containerd: types.Containerd{ImageRegistry: utils.Pointer(tc.registry)}}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateDNS(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		dns       types.DNS
+		expectErr bool
+	}{
+		{name: "Defaults"},
+		{name: "CacheTTL", dns: types.DNS{CacheTTL: utils.Pointer(0)}},
+		{name: "NegativeCacheTTL", dns: types.DNS{CacheTTL: utils.Pointer(-1)}, expectErr: true},
+		{name: "StubZone", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10", "10.0.0.11:5353", "[fd00::10]:53"}}})}},
+		{name: "StubZoneRoot", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: ".", Nameservers: []string{"10.0.0.10"}}})}, expectErr: true},
+		{name: "StubZoneInvalidZone", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: "not a zone", Nameservers: []string{"10.0.0.10"}}})}, expectErr: true},
+		{name: "StubZoneNoNameservers", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal"}})}, expectErr: true},
+		{name: "StubZoneInvalidNameserver", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"ns.corp.internal"}}})}, expectErr: true},
+		{name: "StubZoneInvalidPort", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10:70000"}}})}, expectErr: true},
+		{name: "StubZoneDuplicate", dns: types.DNS{StubZones: utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10"}}, {Zone: "corp.internal.", Nameservers: []string{"10.0.0.11"}}})}, expectErr: true},
+		{name: "Hosts", dns: types.DNS{Hosts: utils.Pointer([]types.DNSHostEntry{{IP: "10.0.0.20", Hostnames: []string{"git.corp.internal", "git"}}})}},
+		{name: "HostsInvalidIP", dns: types.DNS{Hosts: utils.Pointer([]types.DNSHostEntry{{IP: "10.0.0", Hostnames: []string{"git"}}})}, expectErr: true},
+		{name: "HostsNoHostnames", dns: types.DNS{Hosts: utils.Pointer([]types.DNSHostEntry{{IP: "10.0.0.20"}})}, expectErr: true},
+		{name: "HostsInvalidHostname", dns: types.DNS{Hosts: utils.Pointer([]types.DNSHostEntry{{IP: "10.0.0.20", Hostnames: []string{"Not_Valid"}}})}, expectErr: true},
+		{name: "ExtraPlugin", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "log"}, {Name: "rewrite", Parameters: "name old.internal new.internal"}})}},
+		{name: "ExtraPluginInvalidName", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "log {"}})}, expectErr: true},
+		{name: "ExtraPluginInvalidParameters", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "log", Parameters: "} evil {"}})}, expectErr: true},
+		{name: "ExtraPluginUnbalancedConfigBlock", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "log", ConfigBlock: "}\n. {"}})}, expectErr: true},
+		{name: "ExtraPluginBuiltin", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "forward", Parameters: ". 1.1.1.1"}})}, expectErr: true},
+		{name: "ExtraPluginBuiltinCache", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "cache", Parameters: "60"}})}, expectErr: true},
+		{name: "ExtraPluginDuplicate", dns: types.DNS{ExtraPlugins: utils.Pointer([]types.DNSPlugin{{Name: "log"}, {Name: "log", Parameters: "."}})}, expectErr: true},
+		{name: "ExtraServer", dns: types.DNS{ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"example.com"}, Plugins: []types.DNSPlugin{{Name: "file", Parameters: "/etc/coredns/example.db"}}}})}},
+		{name: "ExtraServerRootOtherPort", dns: types.DNS{ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"."}, Port: 5353, Plugins: []types.DNSPlugin{{Name: "whoami"}}}})}},
+		{name: "ExtraServerRoot", dns: types.DNS{ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"."}, Plugins: []types.DNSPlugin{{Name: "whoami"}}}})}, expectErr: true},
+		{name: "ExtraServerStubZoneConflict", dns: types.DNS{
+			StubZones:    utils.Pointer([]types.DNSStubZone{{Zone: "corp.internal", Nameservers: []string{"10.0.0.10"}}}),
+			ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"corp.internal"}, Plugins: []types.DNSPlugin{{Name: "whoami"}}}}),
+		}, expectErr: true},
+		{name: "ExtraServerNoZones", dns: types.DNS{ExtraServers: utils.Pointer([]types.DNSServer{{Plugins: []types.DNSPlugin{{Name: "whoami"}}}})}, expectErr: true},
+		{name: "ExtraServerNoPlugins", dns: types.DNS{ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"example.com"}}})}, expectErr: true},
+		{name: "ExtraServerInvalidPort", dns: types.DNS{ExtraServers: utils.Pointer([]types.DNSServer{{Zones: []string{"example.com"}, Port: 65536, Plugins: []types.DNSPlugin{{Name: "whoami"}}}})}, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{DNS: tc.dns}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateNodeLocalDNS(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		dns       bool
+		localIP   string
+		expectErr bool
+	}{
+		{name: "Default", dns: true},
+		{name: "IPv6", dns: true, localIP: "fe80::20:10"},
+		{name: "NoDNS", dns: false, expectErr: true},
+		{name: "NotLinkLocal", dns: true, localIP: "10.0.0.10", expectErr: true},
+		{name: "InvalidIP", dns: true, localIP: "169.254.20", expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := types.ClusterConfig{
+				DNS:          types.DNS{Enabled: utils.Pointer(tc.dns)},
+				NodeLocalDNS: types.NodeLocalDNS{Enabled: utils.Pointer(true)},
+			}
+			if tc.localIP != "" {
+				config.NodeLocalDNS.LocalIP = utils.Pointer(tc.localIP)
+			}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	mustParseCIDR := func(cidr string) *net.IPNet {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		return ipNet
+	}
+
+	for _, tc := range []struct {
+		name           string
+		config         types.ClusterConfig
+		opts           types.ValidateOptions
+		expectErrors   []string
+		expectWarnings []string
+	}{
+		{
+			name: "Valid",
+			config: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(true)},
+				DNS:     types.DNS{Enabled: utils.Pointer(true)},
+				Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.10")},
+			},
+		},
+		{
+			name:         "PodServiceOverlap",
+			config:       types.ClusterConfig{Network: types.Network{PodCIDR: utils.Pointer("10.1.0.0/16"), ServiceCIDR: utils.Pointer("10.1.100.0/24")}},
+			expectErrors: []string{"service-cidr"},
+		},
+		{
+			name: "LoadBalancerOverlap",
+			config: types.ClusterConfig{
+				Network: types.Network{Enabled: utils.Pointer(true)},
+				LoadBalancer: types.LoadBalancer{
+					Enabled:  utils.Pointer(true),
+					CIDRs:    utils.Pointer([]string{"10.1.20.0/24", "192.168.0.0/24"}),
+					IPRanges: utils.Pointer([]types.LoadBalancer_IPRange{{Start: "10.152.183.100", Stop: "10.152.184.10"}, {Start: "192.168.1.10", Stop: "192.168.1.20"}}),
+				},
+			},
+			expectErrors: []string{"load-balancer.cidrs", "load-balancer.cidrs"},
+		},
+		{
+			name:           "LoadBalancerNoAddresses",
+			config:         types.ClusterConfig{Network: types.Network{Enabled: utils.Pointer(true)}, LoadBalancer: types.LoadBalancer{Enabled: utils.Pointer(true)}},
+			expectWarnings: []string{"load-balancer.cidrs"},
+		},
+		{
+			name: "DNSServiceIPOutsideServiceCIDR",
+			config: types.ClusterConfig{
+				DNS:     types.DNS{Enabled: utils.Pointer(true)},
+				Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.0.0.10")},
+			},
+			expectErrors: []string{"dns.service-ip"},
+		},
+		{
+			name: "DNSServiceIPReserved",
+			config: types.ClusterConfig{
+				DNS:     types.DNS{Enabled: utils.Pointer(true)},
+				Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.152.183.1")},
+			},
+			expectErrors: []string{"dns.service-ip"},
+		},
+		{
+			name: "ExternalDNSServiceIP",
+			config: types.ClusterConfig{
+				DNS:     types.DNS{Enabled: utils.Pointer(false)},
+				Kubelet: types.Kubelet{ClusterDNS: utils.Pointer("10.0.0.10")},
+			},
+		},
+		{
+			name:           "HostNetworkOverlap",
+			config:         types.ClusterConfig{},
+			opts:           types.ValidateOptions{HostNetworks: []*net.IPNet{mustParseCIDR("10.1.0.0/24"), mustParseCIDR("192.168.0.0/24")}},
+			expectWarnings: []string{"pod-cidr"},
+		},
+		{
+			name: "MultipleErrors",
+			config: types.ClusterConfig{
+				Gateway:      types.Gateway{Enabled: utils.Pointer(true)},
+				Ingress:      types.Ingress{Enabled: utils.Pointer(true)},
+				LocalStorage: types.LocalStorage{ReclaimPolicy: utils.Pointer("Keep")},
+			},
+			expectErrors: []string{"gateway.enabled", "ingress.enabled", "local-storage.reclaim-policy"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := tc.config
+			config.SetDefaults()
+
+			result := config.ValidateAll(tc.opts)
+
+			errorFields := make([]string, 0, len(result.Errors))
+			for _, issue := range result.Errors {
+				errorFields = append(errorFields, issue.Field)
+			}
+			warningFields := make([]string, 0, len(result.Warnings))
+			for _, issue := range result.Warnings {
+				warningFields = append(warningFields, issue.Field)
+			}
+			g.Expect(errorFields).To(ConsistOf(tc.expectErrors))
+			g.Expect(warningFields).To(ConsistOf(tc.expectWarnings))
+
+			if len(tc.expectErrors) > 0 {
+				g.Expect(config.Validate()).To(HaveOccurred())
+			} else {
+				g.Expect(config.Validate()).To(Succeed())
+			}
+		})
+	}
+}
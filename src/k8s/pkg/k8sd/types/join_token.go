@@ -0,0 +1,63 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// JoinTokenPorts are the cluster ports that a node listens on after joining.
+// The ports are included in join tokens, so that the joining node can check them before it joins.
+type JoinTokenPorts struct {
+	// APIServer is the secure port of kube-apiserver, which is also used by the apiserver proxy on worker nodes.
+	APIServer int `json:"apiserver,omitempty"`
+	// K8sDqlite is the port of k8s-dqlite. K8sDqlite is 0 if the cluster does not use k8s-dqlite.
+	K8sDqlite int `json:"k8s-dqlite,omitempty"`
+}
+
+// controlPlaneTokenPortsKey is the field of the control plane join token that holds the ports.
+// Control plane join tokens are generated by microcluster, which ignores unknown fields.
+const controlPlaneTokenPortsKey = "k8s_ports"
+
+// WithControlPlaneTokenPorts returns the control plane join token with the ports added to it.
+func WithControlPlaneTokenPorts(token string, ports JoinTokenPorts) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to deserialize token: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+	b, err := json.Marshal(ports)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ports: %w", err)
+	}
+	fields[controlPlaneTokenPortsKey] = b
+	if raw, err = json.Marshal(fields); err != nil {
+		return "", fmt.Errorf("failed to marshal token: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// ControlPlaneTokenPorts returns the ports included in a control plane join token.
+// ControlPlaneTokenPorts returns nil if the token does not include them, e.g. because it was created by an older version.
+func ControlPlaneTokenPorts(token string) (*JoinTokenPorts, error) {
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize token: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+	b, ok := fields[controlPlaneTokenPortsKey]
+	if !ok {
+		return nil, nil
+	}
+	var ports JoinTokenPorts
+	if err := json.Unmarshal(b, &ports); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ports: %w", err)
+	}
+	return &ports, nil
+}
@@ -0,0 +1,50 @@
+package types_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/k8sd/types"
+	. "github.com/onsi/gomega"
+)
+
+func TestControlPlaneTokenPorts(t *testing.T) {
+	g := NewWithT(t)
+
+	microclusterToken := map[string]any{
+		"name":           "node2",
+		"secret":         "mysecret",
+		"fingerprint":    "fingerprint",
+		"join_addresses": []string{"10.0.0.1:6400"},
+	}
+	b, err := json.Marshal(microclusterToken)
+	g.Expect(err).To(BeNil())
+	token := base64.StdEncoding.EncodeToString(b)
+
+	ports, err := types.ControlPlaneTokenPorts(token)
+	g.Expect(err).To(BeNil())
+	g.Expect(ports).To(BeNil())
+
+	withPorts, err := types.WithControlPlaneTokenPorts(token, types.JoinTokenPorts{APIServer: 16443, K8sDqlite: 19000})
+	g.Expect(err).To(BeNil())
+
+	ports, err = types.ControlPlaneTokenPorts(withPorts)
+	g.Expect(err).To(BeNil())
+	g.Expect(ports).To(Equal(&types.JoinTokenPorts{APIServer: 16443, K8sDqlite: 19000}))
+
+	// the fields of the microcluster token are kept
+	b, err = base64.StdEncoding.DecodeString(withPorts)
+	g.Expect(err).To(BeNil())
+	var decoded map[string]any
+	g.Expect(json.Unmarshal(b, &decoded)).To(Succeed())
+	for k, v := range microclusterToken {
+		g.Expect(decoded).To(HaveKey(k))
+		if s, ok := v.(string); ok {
+			g.Expect(decoded[k]).To(Equal(s))
+		}
+	}
+
+	_, err = types.ControlPlaneTokenPorts("not a token")
+	g.Expect(err).To(HaveOccurred())
+}
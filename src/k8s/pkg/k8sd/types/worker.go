@@ -16,6 +16,8 @@ type InternalWorkerNodeToken struct {
 	JoinAddresses []string `json:"join_addresses"`
 	// Fingerprint is used for verification of the control-plane certificate.
 	Fingerprint string `json:"fingerprint"`
+	// Ports are the cluster ports that the worker node listens on. Ports is nil for tokens created by older versions.
+	Ports *JoinTokenPorts `json:"ports,omitempty"`
 }
 
 // internalWorkerNodeTokenSerializeMagicString is used to validate serialized worker node tokens.
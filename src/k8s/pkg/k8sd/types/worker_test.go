@@ -13,6 +13,7 @@ func TestWorkerTokenEncode(t *testing.T) {
 		Secret:        "mysecret",
 		JoinAddresses: []string{"addr1:1010", "addr2:1212"},
 		Fingerprint:   "fingerprint",
+		Ports:         &types.JoinTokenPorts{APIServer: 6443},
 	}
 
 	g := NewWithT(t)
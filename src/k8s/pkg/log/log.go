@@ -0,0 +1,72 @@
+// Package log provides the structured logger of the k8s services.
+// The logger is passed to the components with the context, see NewContext and FromContext.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const (
+	// FormatText formats log messages as key=value pairs.
+	FormatText = "text"
+	// FormatJSON formats log messages as JSON objects.
+	FormatJSON = "json"
+)
+
+// Options configure a logger.
+type Options struct {
+	// Debug logs debug messages. Debug takes precedence over Verbose.
+	Debug bool
+	// Verbose logs informational messages. Otherwise, only warnings and errors are logged.
+	Verbose bool
+	// Format is FormatText or FormatJSON. Defaults to FormatText.
+	Format string
+}
+
+// Level returns the minimum level of the messages that are logged.
+func (o Options) Level() slog.Level {
+	switch {
+	case o.Debug:
+		return slog.LevelDebug
+	case o.Verbose:
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// New creates a new logger that writes to w.
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level()}
+	switch opts.Format {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, handlerOpts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, handlerOpts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q, must be one of %s or %s", opts.Format, FormatText, FormatJSON)
+	}
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of ctx, or the default logger if ctx does not carry one.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// WithComponent returns a copy of ctx whose logger adds the component name to all messages.
+func WithComponent(ctx context.Context, component string) context.Context {
+	return NewContext(ctx, FromContext(ctx).With("component", component))
+}
@@ -0,0 +1,60 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/canonical/k8s/pkg/log"
+	. "github.com/onsi/gomega"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		opts          log.Options
+		expectedLevel slog.Level
+	}{
+		{name: "Default", opts: log.Options{}, expectedLevel: slog.LevelWarn},
+		{name: "Verbose", opts: log.Options{Verbose: true}, expectedLevel: slog.LevelInfo},
+		{name: "Debug", opts: log.Options{Debug: true, Verbose: true}, expectedLevel: slog.LevelDebug},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			logger, err := log.New(&bytes.Buffer{}, tc.opts)
+			g.Expect(err).To(BeNil())
+			g.Expect(logger.Enabled(context.Background(), tc.expectedLevel)).To(BeTrue())
+			g.Expect(logger.Enabled(context.Background(), tc.expectedLevel-1)).To(BeFalse())
+		})
+	}
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := log.New(&bytes.Buffer{}, log.Options{Format: "xml"})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestContext(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(log.FromContext(context.Background())).To(Equal(slog.Default()))
+
+	buf := &bytes.Buffer{}
+	logger, err := log.New(buf, log.Options{Verbose: true, Format: log.FormatJSON})
+	g.Expect(err).To(BeNil())
+
+	ctx := log.WithComponent(log.NewContext(context.Background(), logger), "feature-controller")
+	log.FromContext(ctx).Info("Reconciled feature", "feature", "dns")
+
+	var entry map[string]any
+	g.Expect(json.Unmarshal(buf.Bytes(), &entry)).To(Succeed())
+	g.Expect(entry).To(HaveKeyWithValue("level", "INFO"))
+	g.Expect(entry).To(HaveKeyWithValue("msg", "Reconciled feature"))
+	g.Expect(entry).To(HaveKeyWithValue("component", "feature-controller"))
+	g.Expect(entry).To(HaveKeyWithValue("feature", "dns"))
+}
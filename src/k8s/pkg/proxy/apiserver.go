@@ -3,9 +3,13 @@ package proxy
 import (
 	"context"
 	"fmt"
-	"log"
+	"net"
 	"reflect"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/canonical/k8s/pkg/log"
 )
 
 // APIServerProxy is a TCP proxy that forwards requests to the API Servers of the cluster.
@@ -16,17 +20,45 @@ type APIServerProxy struct {
 	// EndpointsConfigFile is the config file with the initial kube-apiserver endpoints.
 	EndpointsConfigFile string
 
-	// RefreshCh signals the proxy to update the list of known kube-apiserver endpoints. If the list
-	// of kube-apiserver endpoints have changed, the endpoints config file is updated, and the proxy
-	// will restart automatically.
-	RefreshCh <-chan time.Time
+	// RefreshInterval is the resync interval of the watch on the kube-apiserver endpoints of the cluster. Zero disables
+	// the watch. If the list of kube-apiserver endpoints changes, the endpoints config file is updated, and the proxy
+	// starts using the new endpoints without closing its listener. The endpoints config file is used on start.
+	RefreshInterval time.Duration
 
-	// Kubeconfig is the kubeconfig file to use to refresh the kube-apiserver endpoints.
+	// Kubeconfig is the kubeconfig file to use to watch the kube-apiserver endpoints.
+	// The credentials must allow to list and watch EndpointSlices in the default namespace.
 	KubeconfigFile string
+
+	// HealthCheckKubeconfigFile is the kubeconfig file with the CA and client certificate to use to check the health
+	// of the endpoints. Defaults to KubeconfigFile.
+	HealthCheckKubeconfigFile string
+
+	// HealthCheck configures the active health checks of the kube-apiserver endpoints.
+	// Endpoints that fail the health checks stop receiving new connections.
+	HealthCheck HealthCheckOptions
+
+	// Strategy is how the proxy picks the kube-apiserver endpoint for a new connection. Defaults to round robin.
+	Strategy Strategy
+
+	// DrainTimeout is how long connections to removed kube-apiserver endpoints stay open.
+	// Zero keeps them open until they are closed by the client or the endpoint.
+	DrainTimeout time.Duration
+
+	// StatusAddress is the address to serve the JSON status of the proxy (/status) and Prometheus metrics (/metrics).
+	// Empty to disable.
+	StatusAddress string
+
+	mu sync.Mutex
+	// tp is the running proxy
+	tp *tcpproxy
 }
 
 // Run starts the proxy.
 func (p *APIServerProxy) Run(ctx context.Context) error {
+	ctx = log.WithComponent(ctx, "apiserver-proxy")
+	if p.StatusAddress != "" {
+		go p.serveStatus(ctx)
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -39,52 +71,133 @@ func (p *APIServerProxy) Run(ctx context.Context) error {
 			return fmt.Errorf("failed to load endpoints configuration: %w", err)
 		}
 
-		proxyCtx, cancel := context.WithCancel(ctx)
-		go p.startProxy(proxyCtx, cancel, cfg.Endpoints)
-		go p.watchForNewEndpoints(proxyCtx, cancel, cfg.Endpoints)
+		if err := p.runProxy(ctx, cfg.Endpoints); err != nil {
+			log.FromContext(ctx).Error("API server proxy failed, restarting", "error", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// runProxy serves client connections until ctx is cancelled or the listener fails.
+// Endpoint changes are applied in place, without closing the listener.
+func (p *APIServerProxy) runProxy(ctx context.Context, endpoints []string) error {
+	srvs, err := parseEndpoints(endpoints)
+	if err != nil {
+		return fmt.Errorf("invalid endpoints: %w", err)
+	}
+
+	var healthCheck healthCheckFunc
+	if p.HealthCheck.Interval > 0 {
+		kubeconfigFile := p.HealthCheckKubeconfigFile
+		if kubeconfigFile == "" {
+			kubeconfigFile = p.KubeconfigFile
+		}
+		if healthCheck, err = newReadyzHealthCheck(kubeconfigFile); err != nil {
+			log.FromContext(ctx).Warn("Failed to configure endpoint health checks, falling back to TCP checks", "kubeconfig", kubeconfigFile, "error", err)
+		}
+	}
+
+	l, err := net.Listen("tcp", p.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to start listener: %w", err)
+	}
+
+	tp := &tcpproxy{
+		Listener:        l,
+		Endpoints:       srvs,
+		MonitorInterval: time.Minute,
+		Strategy:        p.Strategy,
+		DrainTimeout:    p.DrainTimeout,
+		Logger:          log.FromContext(ctx),
+
+		HealthCheck:        healthCheck,
+		HealthCheckOptions: p.HealthCheck,
+
+		donec: make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	p.tp = tp
+	p.mu.Unlock()
+
+	proxyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go p.watchForNewEndpoints(proxyCtx, tp, endpoints)
+	go func() {
 		<-proxyCtx.Done()
+		tp.Stop()
+	}()
+
+	tp.Logger.Info("Starting proxy", "address", p.ListenAddress, "strategy", tp.Strategy)
+	if err := tp.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("proxy failed: %w", err)
 	}
+	return nil
 }
 
-func (p *APIServerProxy) startProxy(ctx context.Context, cancel func(), endpoints []string) {
-	if err := startProxy(ctx, p.ListenAddress, endpoints); err != nil {
-		log.Println(fmt.Errorf("apiserver proxy failed: %w", err))
+// defaultUpstreamPort returns the port to use for kube-apiserver endpoints that do not specify one.
+// The proxy listens on the same port as the kube-apiserver instances of the cluster.
+func (p *APIServerProxy) defaultUpstreamPort() int {
+	_, portString, err := net.SplitHostPort(p.ListenAddress)
+	if err != nil {
+		return 6443
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil || port == 0 {
+		return 6443
 	}
-	cancel()
+	return port
 }
 
-func (p *APIServerProxy) watchForNewEndpoints(ctx context.Context, cancel func(), endpoints []string) {
-	if p.RefreshCh == nil {
+// watchForNewEndpoints watches the kube-apiserver endpoints of the cluster. When they change, the endpoints config file
+// is updated and the proxy starts using the new endpoints.
+func (p *APIServerProxy) watchForNewEndpoints(ctx context.Context, tp *tcpproxy, endpoints []string) {
+	if p.RefreshInterval == 0 {
 		return
 	}
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-p.RefreshCh:
-		}
+	logger := log.FromContext(ctx)
 
-		// TODO: use k8s.GetKubernetesEndpoints instead
-		newEndpoints, err := getKubernetesEndpoints(ctx, p.KubeconfigFile)
+	onChange := func(newEndpoints []string) {
 		switch {
-		case err != nil:
-			log.Println(fmt.Errorf("failed to retrieve kubernetes endpoints: %w", err))
-			continue
 		case len(newEndpoints) == 0:
-			log.Println("warning: empty list of endpoints, skipping update")
-			continue
-		case len(newEndpoints) == len(endpoints) && reflect.DeepEqual(newEndpoints, endpoints):
-			continue
+			logger.Warn("Empty list of endpoints, skipping update")
+			return
+		case reflect.DeepEqual(newEndpoints, endpoints):
+			return
+		}
+		logger.Info("Updating endpoints", "endpoints", newEndpoints)
+
+		srvs, err := parseEndpoints(newEndpoints)
+		if err != nil {
+			logger.Error("Failed to parse new endpoints", "endpoints", newEndpoints, "error", err)
+			return
 		}
-		log.Println("updating endpoints")
 
 		if err := WriteEndpointsConfig(newEndpoints, p.EndpointsConfigFile); err != nil {
-			log.Printf("failed to update configuration file with new endpoints: %s", err)
-			continue
+			logger.Error("Failed to update configuration file with new endpoints", "file", p.EndpointsConfigFile, "error", err)
+			return
 		}
 
-		// cancel context in order to restart the proxy
-		cancel()
-		return
+		tp.SetEndpoints(srvs)
+		endpoints = newEndpoints
+	}
+	onError := func(err error) {
+		logger.Warn("Lost watch on kubernetes endpoints, reconnecting", "error", err)
+	}
+
+	for {
+		if err := watchKubernetesEndpoints(ctx, p.KubeconfigFile, p.defaultUpstreamPort(), p.RefreshInterval, onChange, onError); err != nil {
+			logger.Error("Failed to watch kubernetes endpoints, retrying", "kubeconfig", p.KubeconfigFile, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
 	}
 }
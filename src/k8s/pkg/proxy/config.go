@@ -3,8 +3,12 @@ package proxy
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Configuration is the format of the apiserver proxy endpoints config file.
@@ -38,3 +42,20 @@ func WriteEndpointsConfig(endpoints []string, file string) error {
 	}
 	return nil
 }
+
+// ListenAddressFromKubeconfig returns a listen address on the port of the server in the kubeconfig file.
+// k8sd writes the kubeconfig files of worker nodes with the local proxy as server, on the kube-apiserver port of the cluster.
+func ListenAddressFromKubeconfig(file string) (string, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", file)
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	u, err := url.Parse(config.Host)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse server %q: %w", config.Host, err)
+	}
+	if u.Port() == "" {
+		return "", fmt.Errorf("server %q has no port", config.Host)
+	}
+	return net.JoinHostPort("", u.Port()), nil
+}
@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestListenAddressFromKubeconfig(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		server         string
+		expectedAddr   string
+		expectedErrors bool
+	}{
+		{name: "DefaultPort", server: "https://127.0.0.1:6443", expectedAddr: ":6443"},
+		{name: "CustomPort", server: "https://127.0.0.1:16443", expectedAddr: ":16443"},
+		{name: "NoPort", server: "https://127.0.0.1", expectedErrors: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			file := filepath.Join(t.TempDir(), "proxy.conf")
+			g.Expect(os.WriteFile(file, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: k8s
+  cluster:
+    server: `+tc.server+`
+contexts:
+- name: k8s
+  context:
+    cluster: k8s
+    user: k8s
+current-context: k8s
+users:
+- name: k8s
+  user: {}
+`), 0600)).To(Succeed())
+
+			addr, err := ListenAddressFromKubeconfig(file)
+			if tc.expectedErrors {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(addr).To(Equal(tc.expectedAddr))
+			}
+		})
+	}
+}
@@ -4,29 +4,49 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
-	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-func parseAddresses(endpoint *corev1.Endpoints) []string {
-	if endpoint == nil {
-		return nil
-	}
-	addresses := make([]string, 0, len(endpoint.Subsets))
-	for _, subset := range endpoint.Subsets {
-		portNumber := 6443
-		for _, port := range subset.Ports {
-			if port.Name == "https" {
-				portNumber = int(port.Port)
+// parseEndpointSlices returns the list of ready addresses from the EndpointSlices of the kubernetes service.
+// defaultPort is used for slices that do not specify an "https" port.
+func parseEndpointSlices(slices []*discoveryv1.EndpointSlice, defaultPort int) []string {
+	seen := make(map[string]struct{})
+	addresses := []string{}
+	for _, slice := range slices {
+		if slice == nil {
+			continue
+		}
+		portNumber := defaultPort
+		for _, port := range slice.Ports {
+			if port.Name != nil && *port.Name == "https" && port.Port != nil {
+				portNumber = int(*port.Port)
 				break
 			}
 		}
 
-		for _, addr := range subset.Addresses {
-			addresses = append(addresses, fmt.Sprintf("%s:%d", addr.IP, portNumber))
+		for _, endpoint := range slice.Endpoints {
+			// endpoints without a ready condition are considered ready
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			for _, addr := range endpoint.Addresses {
+				address := fmt.Sprintf("%s:%d", addr, portNumber)
+				if slice.AddressType == discoveryv1.AddressTypeIPv6 {
+					address = fmt.Sprintf("[%s]:%d", addr, portNumber)
+				}
+				if _, ok := seen[address]; !ok {
+					seen[address] = struct{}{}
+					addresses = append(addresses, address)
+				}
+			}
 		}
 	}
 
@@ -34,19 +54,53 @@ func parseAddresses(endpoint *corev1.Endpoints) []string {
 	return addresses
 }
 
-func getKubernetesEndpoints(ctx context.Context, kubeconfigFile string) ([]string, error) {
+// watchKubernetesEndpoints watches the EndpointSlices of the kubernetes service and calls onChange with the ready
+// kube-apiserver addresses whenever they change, and at least every resyncInterval. The watch is re-established
+// when the connection to the kube-apiserver is lost. watchKubernetesEndpoints blocks until ctx is cancelled.
+func watchKubernetesEndpoints(ctx context.Context, kubeconfigFile string, defaultPort int, resyncInterval time.Duration, onChange func([]string), onError func(error)) error {
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read load kubeconfig: %w", err)
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize kubernetes client: %w", err)
+		return fmt.Errorf("failed to initialize kubernetes client: %w", err)
 	}
+	return watchEndpointSlices(ctx, clientset, defaultPort, resyncInterval, onChange, onError)
+}
 
-	endpoint, err := clientset.CoreV1().Endpoints("default").Get(ctx, "kubernetes", metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve endpoints for kubernetes service: %w", err)
+func watchEndpointSlices(ctx context.Context, clientset kubernetes.Interface, defaultPort int, resyncInterval time.Duration, onChange func([]string), onError func(error)) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, resyncInterval,
+		informers.WithNamespace(metav1.NamespaceDefault),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = labels.Set{discoveryv1.LabelServiceName: "kubernetes"}.String()
+		}),
+	)
+	informer := factory.Discovery().V1().EndpointSlices()
+	if err := informer.Informer().SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		onError(err)
+	}); err != nil {
+		return fmt.Errorf("failed to set watch error handler: %w", err)
 	}
-	return parseAddresses(endpoint), nil
+
+	notify := func() {
+		slices, err := informer.Lister().List(labels.Everything())
+		if err != nil {
+			onError(fmt.Errorf("failed to list endpoint slices: %w", err))
+			return
+		}
+		onChange(parseEndpointSlices(slices, defaultPort))
+	}
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { notify() },
+		UpdateFunc: func(any, any) { notify() },
+		DeleteFunc: func(any) { notify() },
+	}); err != nil {
+		return fmt.Errorf("failed to add event handler: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
 }
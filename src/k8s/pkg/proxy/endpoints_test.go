@@ -1,74 +1,140 @@
 package proxy
 
 import (
+	"context"
 	"reflect"
 	"testing"
+	"time"
 
-	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
-func TestParseAddresses(t *testing.T) {
+func endpointSlice(port int32, addresses ...string) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{AddressType: discoveryv1.AddressTypeIPv4}
+	for _, address := range addresses {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{Addresses: []string{address}})
+	}
+	if port != 0 {
+		name := "https"
+		slice.Ports = []discoveryv1.EndpointPort{{Name: &name, Port: &port}}
+	}
+	return slice
+}
+
+func TestParseEndpointSlices(t *testing.T) {
+	notReady := false
 	for _, tc := range []struct {
-		name      string
-		endpoints *v1.Endpoints
-		addresses []string
+		name        string
+		slices      []*discoveryv1.EndpointSlice
+		defaultPort int
+		addresses   []string
 	}{
 		{
-			name: "nil",
+			name:      "nil",
+			addresses: []string{},
 		},
 		{
-			name: "one",
-			endpoints: &v1.Endpoints{
-				Subsets: []v1.EndpointSubset{
-					{Addresses: []v1.EndpointAddress{{IP: "1.1.1.1"}}},
-				},
-			},
+			name:      "one",
+			slices:    []*discoveryv1.EndpointSlice{endpointSlice(0, "1.1.1.1")},
 			addresses: []string{"1.1.1.1:6443"},
 		},
 		{
-			name: "two",
-			endpoints: &v1.Endpoints{
-				Subsets: []v1.EndpointSubset{
-					{Addresses: []v1.EndpointAddress{{IP: "1.1.1.1"}, {IP: "2.2.2.2"}}},
-				},
-			},
+			name:      "two",
+			slices:    []*discoveryv1.EndpointSlice{endpointSlice(0, "1.1.1.1", "2.2.2.2")},
 			addresses: []string{"1.1.1.1:6443", "2.2.2.2:6443"},
 		},
 		{
-			name: "multiple-subsets",
-			endpoints: &v1.Endpoints{
-				Subsets: []v1.EndpointSubset{
-					{Addresses: []v1.EndpointAddress{{IP: "1.1.1.1"}, {IP: "2.2.2.2"}}},
-					{Addresses: []v1.EndpointAddress{{IP: "3.3.3.3"}}},
-				},
-			},
+			name:      "multiple-slices",
+			slices:    []*discoveryv1.EndpointSlice{endpointSlice(0, "1.1.1.1", "2.2.2.2"), endpointSlice(0, "3.3.3.3")},
 			addresses: []string{"1.1.1.1:6443", "2.2.2.2:6443", "3.3.3.3:6443"},
 		},
 		{
-			name: "override-port",
-			endpoints: &v1.Endpoints{
-				Subsets: []v1.EndpointSubset{
-					{Addresses: []v1.EndpointAddress{{IP: "1.1.1.1"}, {IP: "2.2.2.2"}}},
-					{Addresses: []v1.EndpointAddress{{IP: "3.3.3.3"}}, Ports: []v1.EndpointPort{{Port: int32(10000), Name: "https"}}},
-				},
-			},
+			name:      "override-port",
+			slices:    []*discoveryv1.EndpointSlice{endpointSlice(0, "1.1.1.1", "2.2.2.2"), endpointSlice(10000, "3.3.3.3")},
 			addresses: []string{"1.1.1.1:6443", "2.2.2.2:6443", "3.3.3.3:10000"},
 		},
 		{
-			name: "sort",
-			endpoints: &v1.Endpoints{
-				Subsets: []v1.EndpointSubset{
-					{Addresses: []v1.EndpointAddress{{IP: "3.3.3.3"}, {IP: "1.1.1.1"}}},
-					{Addresses: []v1.EndpointAddress{{IP: "2.2.2.2"}}, Ports: []v1.EndpointPort{{Port: int32(10000), Name: "https"}}},
-				},
-			},
+			name:        "custom-default-port",
+			slices:      []*discoveryv1.EndpointSlice{endpointSlice(0, "1.1.1.1", "2.2.2.2"), endpointSlice(10000, "3.3.3.3")},
+			defaultPort: 16443,
+			addresses:   []string{"1.1.1.1:16443", "2.2.2.2:16443", "3.3.3.3:10000"},
+		},
+		{
+			name:      "sort",
+			slices:    []*discoveryv1.EndpointSlice{endpointSlice(0, "3.3.3.3", "1.1.1.1"), endpointSlice(10000, "2.2.2.2")},
 			addresses: []string{"1.1.1.1:6443", "2.2.2.2:10000", "3.3.3.3:6443"},
 		},
+		{
+			name:      "duplicates",
+			slices:    []*discoveryv1.EndpointSlice{endpointSlice(0, "1.1.1.1"), endpointSlice(0, "1.1.1.1", "2.2.2.2")},
+			addresses: []string{"1.1.1.1:6443", "2.2.2.2:6443"},
+		},
+		{
+			name: "not-ready",
+			slices: []*discoveryv1.EndpointSlice{{
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}},
+					{Addresses: []string{"2.2.2.2"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+				},
+			}},
+			addresses: []string{"1.1.1.1:6443"},
+		},
+		{
+			name: "ipv6",
+			slices: []*discoveryv1.EndpointSlice{{
+				AddressType: discoveryv1.AddressTypeIPv6,
+				Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"fd00::1"}}},
+			}},
+			addresses: []string{"[fd00::1]:6443"},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			if parsed := parseAddresses(tc.endpoints); !reflect.DeepEqual(parsed, tc.addresses) {
+			defaultPort := tc.defaultPort
+			if defaultPort == 0 {
+				defaultPort = 6443
+			}
+			if parsed := parseEndpointSlices(tc.slices, defaultPort); !reflect.DeepEqual(parsed, tc.addresses) {
 				t.Fatalf("expected addresses to be %v but they were %v instead", tc.addresses, parsed)
 			}
 		})
 	}
 }
+
+func TestWatchEndpointSlices(t *testing.T) {
+	slice := endpointSlice(0, "1.1.1.1")
+	slice.Name = "kubernetes"
+	slice.Namespace = "default"
+	slice.Labels = map[string]string{discoveryv1.LabelServiceName: "kubernetes"}
+	other := endpointSlice(0, "9.9.9.9")
+	other.Name = "other"
+	other.Namespace = "default"
+	other.Labels = map[string]string{discoveryv1.LabelServiceName: "other"}
+	clientset := fake.NewSimpleClientset(slice, other)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string, 10)
+	go watchEndpointSlices(ctx, clientset, 6443, time.Hour, func(addresses []string) { changes <- addresses }, func(error) {})
+
+	expectChange := func(expected []string) {
+		t.Helper()
+		select {
+		case addresses := <-changes:
+			if !reflect.DeepEqual(addresses, expected) {
+				t.Fatalf("expected addresses to be %v but they were %v instead", expected, addresses)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for addresses %v", expected)
+		}
+	}
+	expectChange([]string{"1.1.1.1:6443"})
+
+	slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{Addresses: []string{"2.2.2.2"}})
+	if _, err := clientset.DiscoveryV1().EndpointSlices("default").Update(ctx, slice, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update endpoint slice: %v", err)
+	}
+	expectChange([]string{"1.1.1.1:6443", "2.2.2.2:6443"})
+}
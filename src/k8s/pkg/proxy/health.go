@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// HealthCheckOptions configure the active health checks of the kube-apiserver endpoints.
+type HealthCheckOptions struct {
+	// Interval is the time between health checks of each endpoint. Zero disables active health checks.
+	Interval time.Duration
+	// Timeout is the max time to wait for a health check. Defaults to Interval.
+	Timeout time.Duration
+	// HealthyThreshold is the number of consecutive successful checks for an inactive endpoint to be activated.
+	HealthyThreshold int
+	// UnhealthyThreshold is the number of consecutive failed checks for an active endpoint to be deactivated.
+	UnhealthyThreshold int
+}
+
+// healthCheckFunc checks whether the kube-apiserver at addr is ready to serve requests.
+type healthCheckFunc func(ctx context.Context, addr string) error
+
+// newReadyzHealthCheck returns a health check that queries the /readyz endpoint of the kube-apiserver.
+// The TLS configuration (CA and client certificate) is loaded from the kubeconfig file.
+func newReadyzHealthCheck(kubeconfigFile string) (healthCheckFunc, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS configuration from kubeconfig: %w", err)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true},
+	}
+	return func(ctx context.Context, addr string) error {
+		return checkReadyz(ctx, client, addr)
+	}, nil
+}
+
+// checkReadyz queries https://<addr>/readyz and fails unless the kube-apiserver responds with 200 OK.
+func checkReadyz(ctx context.Context, client *http.Client, addr string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/readyz", addr), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query readyz: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("readyz returned %s: %s", resp.Status, body)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckReadyz(t *testing.T) {
+	ready := true
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !ready {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("[-]etcd failed"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "https://")
+
+	if err := checkReadyz(context.Background(), server.Client(), addr); err != nil {
+		t.Fatalf("expected ready endpoint to pass the check but it failed: %v", err)
+	}
+
+	ready = false
+	if err := checkReadyz(context.Background(), server.Client(), addr); err == nil || !strings.Contains(err.Error(), "etcd failed") {
+		t.Fatalf("expected not ready endpoint to fail the check with the response body but the error was %v", err)
+	}
+
+	if err := checkReadyz(context.Background(), http.DefaultClient, addr); err == nil {
+		t.Fatal("expected the check to fail with an untrusted certificate")
+	}
+}
+
+func TestRecordHealthCheck(t *testing.T) {
+	errFailed := errors.New("failed")
+	for _, tc := range []struct {
+		name           string
+		inactive       bool
+		results        []error
+		expectInactive bool
+		expectChanged  []bool
+	}{
+		{
+			name:           "ActiveBelowThreshold",
+			results:        []error{errFailed, errFailed},
+			expectInactive: false,
+			expectChanged:  []bool{false, false},
+		},
+		{
+			name:           "ActiveReachesThreshold",
+			results:        []error{errFailed, errFailed, errFailed},
+			expectInactive: true,
+			expectChanged:  []bool{false, false, true},
+		},
+		{
+			name:           "ActiveFailuresNotConsecutive",
+			results:        []error{errFailed, errFailed, nil, errFailed, errFailed},
+			expectInactive: false,
+			expectChanged:  []bool{false, false, false, false, false},
+		},
+		{
+			name:           "InactiveReachesThreshold",
+			inactive:       true,
+			results:        []error{nil, nil},
+			expectInactive: false,
+			expectChanged:  []bool{false, true},
+		},
+		{
+			name:           "InactiveSuccessesNotConsecutive",
+			inactive:       true,
+			results:        []error{nil, errFailed, nil},
+			expectInactive: true,
+			expectChanged:  []bool{false, false, false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &remote{inactive: tc.inactive}
+			for i, err := range tc.results {
+				if changed := r.recordHealthCheck(err, 2, 3); changed != tc.expectChanged[i] {
+					t.Fatalf("expected check %d to return %v but it returned %v", i, tc.expectChanged[i], changed)
+				}
+			}
+			if r.isActive() == tc.expectInactive {
+				t.Fatalf("expected inactive to be %v", tc.expectInactive)
+			}
+		})
+	}
+}
+
+func TestCheckRemotes(t *testing.T) {
+	notReady := map[string]bool{"10.0.0.2:6443": true}
+	tp := &tcpproxy{
+		remotes: []*remote{{srv: &net.SRV{}, addr: "10.0.0.1:6443"}, {srv: &net.SRV{}, addr: "10.0.0.2:6443"}},
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		HealthCheck: func(ctx context.Context, addr string) error {
+			if notReady[addr] {
+				return errors.New("not ready")
+			}
+			return nil
+		},
+		HealthCheckOptions: HealthCheckOptions{Timeout: time.Second, HealthyThreshold: 1, UnhealthyThreshold: 2},
+	}
+
+	tp.checkRemotes(context.Background())
+	if !tp.remotes[1].isActive() {
+		t.Fatal("expected endpoint to stay active below the unhealthy threshold")
+	}
+
+	tp.checkRemotes(context.Background())
+	if !tp.remotes[0].isActive() {
+		t.Fatal("expected ready endpoint to stay active")
+	}
+	if tp.remotes[1].isActive() {
+		t.Fatal("expected not ready endpoint to be deactivated")
+	}
+	for i := 0; i < 10; i++ {
+		if picked := tp.pick(); picked != tp.remotes[0] {
+			t.Fatalf("expected only the ready endpoint to be picked but %s was picked", picked.addr)
+		}
+	}
+
+	notReady = map[string]bool{}
+	tp.checkRemotes(context.Background())
+	if !tp.remotes[1].isActive() {
+		t.Fatal("expected endpoint to be activated once it is ready")
+	}
+}
@@ -0,0 +1,163 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	apiv1 "github.com/canonical/k8s/api/v1"
+	"github.com/canonical/k8s/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// StatusAddress returns the address of the status endpoint of the k8s-apiserver-proxy on worker nodes.
+// The proxy listens on the kube-apiserver securePort, so the status endpoint uses the next port, e.g. 127.0.0.1:6444.
+func StatusAddress(securePort int) string {
+	return fmt.Sprintf("127.0.0.1:%d", securePort+1)
+}
+
+// status returns the status of the endpoints of the proxy.
+func (tp *tcpproxy) status() []apiv1.APIServerProxyEndpoint {
+	tp.mu.Lock()
+	remotes := tp.remotes
+	tp.mu.Unlock()
+
+	endpoints := make([]apiv1.APIServerProxyEndpoint, 0, len(remotes))
+	for _, r := range remotes {
+		r.mu.Lock()
+		endpoints = append(endpoints, apiv1.APIServerProxyEndpoint{
+			Address:           r.addr,
+			Active:            !r.inactive,
+			Connections:       len(r.conns),
+			ConnectionsTotal:  r.connsTotal,
+			DialFailuresTotal: r.dialFailuresTotal,
+		})
+		r.mu.Unlock()
+	}
+	return endpoints
+}
+
+// Status returns the status of the running proxy.
+func (p *APIServerProxy) Status() apiv1.APIServerProxyStatus {
+	status := apiv1.APIServerProxyStatus{
+		ListenAddress: p.ListenAddress,
+		Strategy:      string(StrategyRoundRobin),
+		Endpoints:     []apiv1.APIServerProxyEndpoint{},
+	}
+	if p.Strategy != "" {
+		status.Strategy = string(p.Strategy)
+	}
+
+	p.mu.Lock()
+	tp := p.tp
+	p.mu.Unlock()
+	if tp != nil {
+		status.Endpoints = tp.status()
+	}
+	return status
+}
+
+var (
+	endpointActiveDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_active",
+		"Whether the kube-apiserver endpoint receives new connections.",
+		[]string{"endpoint"}, nil,
+	)
+	endpointConnectionsDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_connections",
+		"Number of open connections to the kube-apiserver endpoint.",
+		[]string{"endpoint"}, nil,
+	)
+	endpointConnectionsTotalDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_connections_total",
+		"Number of connections to the kube-apiserver endpoint.",
+		[]string{"endpoint"}, nil,
+	)
+	endpointDialFailuresTotalDesc = prometheus.NewDesc(
+		"k8s_apiserver_proxy_endpoint_dial_failures_total",
+		"Number of failed connection attempts to the kube-apiserver endpoint.",
+		[]string{"endpoint"}, nil,
+	)
+)
+
+// Describe implements prometheus.Collector.
+func (p *APIServerProxy) Describe(ch chan<- *prometheus.Desc) {
+	ch <- endpointActiveDesc
+	ch <- endpointConnectionsDesc
+	ch <- endpointConnectionsTotalDesc
+	ch <- endpointDialFailuresTotalDesc
+}
+
+// Collect implements prometheus.Collector.
+func (p *APIServerProxy) Collect(ch chan<- prometheus.Metric) {
+	for _, endpoint := range p.Status().Endpoints {
+		active := 0.0
+		if endpoint.Active {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(endpointActiveDesc, prometheus.GaugeValue, active, endpoint.Address)
+		ch <- prometheus.MustNewConstMetric(endpointConnectionsDesc, prometheus.GaugeValue, float64(endpoint.Connections), endpoint.Address)
+		ch <- prometheus.MustNewConstMetric(endpointConnectionsTotalDesc, prometheus.CounterValue, float64(endpoint.ConnectionsTotal), endpoint.Address)
+		ch <- prometheus.MustNewConstMetric(endpointDialFailuresTotalDesc, prometheus.CounterValue, float64(endpoint.DialFailuresTotal), endpoint.Address)
+	}
+}
+
+// statusHandler serves the JSON status of the proxy on /status and Prometheus metrics on /metrics.
+func (p *APIServerProxy) statusHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		p,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(p.Status()); err != nil {
+			log.FromContext(r.Context()).Error("Failed to write status", "error", err)
+		}
+	})
+	return mux
+}
+
+// serveStatus serves the status endpoints on StatusAddress until ctx is cancelled.
+func (p *APIServerProxy) serveStatus(ctx context.Context) {
+	server := &http.Server{Addr: p.StatusAddress, Handler: p.statusHandler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	log.FromContext(ctx).Info("Serving status and metrics", "address", p.StatusAddress)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.FromContext(ctx).Error("Failed to serve status and metrics", "address", p.StatusAddress, "error", err)
+	}
+}
+
+// GetStatus retrieves the status of the k8s-apiserver-proxy from its status endpoint at address.
+func GetStatus(ctx context.Context, address string) (apiv1.APIServerProxyStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/status", address), nil)
+	if err != nil {
+		return apiv1.APIServerProxyStatus{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return apiv1.APIServerProxyStatus{}, fmt.Errorf("failed to query status: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiv1.APIServerProxyStatus{}, fmt.Errorf("status endpoint returned %s", resp.Status)
+	}
+
+	var status apiv1.APIServerProxyStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return apiv1.APIServerProxyStatus{}, fmt.Errorf("failed to parse status: %w", err)
+	}
+	return status, nil
+}
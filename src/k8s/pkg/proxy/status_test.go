@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusHandler(t *testing.T) {
+	p := &APIServerProxy{ListenAddress: "127.0.0.1:6443", Strategy: StrategyLeastConnections}
+	tp := &tcpproxy{}
+	tp.remotes = []*remote{
+		{srv: &net.SRV{}, addr: "10.0.0.1:6443"},
+		{srv: &net.SRV{}, addr: "10.0.0.2:6443"},
+	}
+	tp.remotes[0].track(&net.TCPConn{})
+	tp.remotes[0].recordDial(nil)
+	tp.remotes[1].recordDial(errors.New("connection refused"))
+	tp.remotes[1].inactivate()
+	p.tp = tp
+
+	server := httptest.NewServer(p.statusHandler())
+	defer server.Close()
+
+	status, err := GetStatus(context.Background(), strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+	if status.ListenAddress != "127.0.0.1:6443" || status.Strategy != "least-connections" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if len(status.Endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints but got %+v", status.Endpoints)
+	}
+	if e := status.Endpoints[0]; !e.Active || e.Connections != 1 || e.ConnectionsTotal != 1 {
+		t.Fatalf("unexpected status of active endpoint %+v", e)
+	}
+	if e := status.Endpoints[1]; e.Active || e.DialFailuresTotal != 1 {
+		t.Fatalf("unexpected status of inactive endpoint %+v", e)
+	}
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+	for _, expected := range []string{
+		`k8s_apiserver_proxy_endpoint_active{endpoint="10.0.0.1:6443"} 1`,
+		`k8s_apiserver_proxy_endpoint_active{endpoint="10.0.0.2:6443"} 0`,
+		`k8s_apiserver_proxy_endpoint_connections{endpoint="10.0.0.1:6443"} 1`,
+		`k8s_apiserver_proxy_endpoint_dial_failures_total{endpoint="10.0.0.2:6443"} 1`,
+	} {
+		if !strings.Contains(string(b), expected) {
+			t.Fatalf("expected metrics to contain %q but they were:\n%s", expected, b)
+		}
+	}
+}
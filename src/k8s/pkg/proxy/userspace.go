@@ -20,9 +20,10 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math/rand"
 	"net"
 	"sync"
@@ -34,12 +35,89 @@ type remote struct {
 	srv      *net.SRV
 	addr     string
 	inactive bool
+
+	// consecutive health check results, used with active health checks
+	healthChecksPassed int
+	healthChecksFailed int
+
+	// conns are the open connections to the remote
+	conns map[net.Conn]struct{}
+
+	connsTotal        uint64
+	dialFailuresTotal uint64
+}
+
+// recordDial records the result of a connection attempt to the remote.
+func (r *remote) recordDial(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.dialFailuresTotal++
+	} else {
+		r.connsTotal++
+	}
+}
+
+func (r *remote) track(conn net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conns == nil {
+		r.conns = make(map[net.Conn]struct{})
+	}
+	r.conns[conn] = struct{}{}
+}
+
+func (r *remote) untrack(conn net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, conn)
+}
+
+func (r *remote) connCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.conns)
+}
+
+// closeConns closes all open connections to the remote.
+func (r *remote) closeConns() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for conn := range r.conns {
+		conn.Close()
+	}
+	return len(r.conns)
 }
 
 func (r *remote) inactivate() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.inactive = true
+	r.healthChecksPassed = 0
+}
+
+// recordHealthCheck records the result of a health check. An active remote is deactivated after
+// unhealthyThreshold consecutive failures, an inactive remote is activated after healthyThreshold consecutive successes.
+// recordHealthCheck returns true if the remote was activated or deactivated.
+func (r *remote) recordHealthCheck(err error, healthyThreshold int, unhealthyThreshold int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.healthChecksPassed = 0
+		r.healthChecksFailed++
+		if !r.inactive && r.healthChecksFailed >= unhealthyThreshold {
+			r.inactive = true
+			return true
+		}
+		return false
+	}
+	r.healthChecksFailed = 0
+	r.healthChecksPassed++
+	if r.inactive && r.healthChecksPassed >= healthyThreshold {
+		r.inactive = false
+		return true
+	}
+	return false
 }
 
 func (r *remote) tryReactivate() error {
@@ -60,10 +138,31 @@ func (r *remote) isActive() bool {
 	return !r.inactive
 }
 
+// Strategy is how the proxy picks the endpoint for a new connection.
+type Strategy string
+
+const (
+	// StrategyRoundRobin picks endpoints in a round robin fashion, respecting their priority and weight.
+	StrategyRoundRobin Strategy = "round-robin"
+	// StrategyLeastConnections picks the endpoint with the fewest open connections in the best priority class.
+	StrategyLeastConnections Strategy = "least-connections"
+)
+
 type tcpproxy struct {
-	Listener        net.Listener
+	Listener net.Listener
+	// Endpoints are the initial endpoints. They are ignored if SetEndpoints is called before Run.
 	Endpoints       []*net.SRV
 	MonitorInterval time.Duration
+	// Strategy is how endpoints are picked. Defaults to StrategyRoundRobin.
+	Strategy Strategy
+	// DrainTimeout is how long connections to removed endpoints stay open. Zero keeps them open until they are closed.
+	DrainTimeout time.Duration
+	// Logger logs endpoint changes. Defaults to the default logger.
+	Logger *slog.Logger
+	// HealthCheck actively checks the endpoints. If nil, inactive endpoints are reactivated once they accept TCP connections.
+	HealthCheck healthCheckFunc
+	// HealthCheckOptions configure the active health checks.
+	HealthCheckOptions HealthCheckOptions
 
 	donec chan struct{}
 
@@ -73,22 +172,38 @@ type tcpproxy struct {
 }
 
 func (tp *tcpproxy) Run() error {
-	tp.donec = make(chan struct{})
+	if tp.donec == nil {
+		tp.donec = make(chan struct{})
+	}
 	if tp.MonitorInterval == 0 {
 		tp.MonitorInterval = 5 * time.Minute
 	}
-	for _, srv := range tp.Endpoints {
-		addr := fmt.Sprintf("%s:%d", srv.Target, srv.Port)
-		tp.remotes = append(tp.remotes, &remote{srv: srv, addr: addr})
+	if tp.Logger == nil {
+		tp.Logger = slog.Default()
 	}
-
-	eps := []string{}
-	for _, ep := range tp.Endpoints {
-		eps = append(eps, fmt.Sprintf("%s:%d", ep.Target, ep.Port))
+	if tp.HealthCheck != nil {
+		if tp.HealthCheckOptions.Timeout == 0 {
+			tp.HealthCheckOptions.Timeout = tp.HealthCheckOptions.Interval
+		}
+		tp.HealthCheckOptions.HealthyThreshold = max(tp.HealthCheckOptions.HealthyThreshold, 1)
+		tp.HealthCheckOptions.UnhealthyThreshold = max(tp.HealthCheckOptions.UnhealthyThreshold, 1)
 	}
-	log.Printf("ready to proxy client requests to %v\n", eps)
+	tp.mu.Lock()
+	initialized := tp.remotes != nil
+	tp.mu.Unlock()
+	if !initialized {
+		tp.SetEndpoints(tp.Endpoints)
+	}
+	if tp.Strategy == "" {
+		tp.Strategy = StrategyRoundRobin
+	}
+	tp.Logger.Info("Ready to proxy client requests", "endpoints", tp.endpointAddresses())
 
-	go tp.runMonitor()
+	if tp.HealthCheck != nil && tp.HealthCheckOptions.Interval > 0 {
+		go tp.runHealthChecks()
+	} else {
+		go tp.runMonitor()
+	}
 	for {
 		in, err := tp.Listener.Accept()
 		if err != nil {
@@ -99,7 +214,67 @@ func (tp *tcpproxy) Run() error {
 	}
 }
 
+// SetEndpoints replaces the endpoints of the proxy without closing the listener.
+// Endpoints that are kept retain their state and connections. New connections are not sent to removed endpoints,
+// and connections to them are closed after DrainTimeout.
+func (tp *tcpproxy) SetEndpoints(endpoints []*net.SRV) {
+	tp.mu.Lock()
+	existing := make(map[string]*remote, len(tp.remotes))
+	for _, r := range tp.remotes {
+		existing[r.addr] = r
+	}
+	remotes := make([]*remote, 0, len(endpoints))
+	for _, srv := range endpoints {
+		addr := fmt.Sprintf("%s:%d", srv.Target, srv.Port)
+		if r, ok := existing[addr]; ok {
+			r.srv = srv
+			remotes = append(remotes, r)
+			delete(existing, addr)
+			continue
+		}
+		remotes = append(remotes, &remote{srv: srv, addr: addr})
+	}
+	tp.remotes = remotes
+	tp.mu.Unlock()
+
+	for _, r := range existing {
+		tp.drain(r)
+	}
+}
+
+// drain closes the connections to a removed endpoint after DrainTimeout.
+func (tp *tcpproxy) drain(r *remote) {
+	count := r.connCount()
+	if count == 0 {
+		return
+	}
+	if tp.DrainTimeout == 0 {
+		tp.Logger.Info("Removed endpoint, keeping open connections", "endpoint", r.addr, "connections", count)
+		return
+	}
+	tp.Logger.Info("Removed endpoint, draining open connections", "endpoint", r.addr, "connections", count, "timeout", tp.DrainTimeout)
+	time.AfterFunc(tp.DrainTimeout, func() {
+		if closed := r.closeConns(); closed > 0 {
+			tp.Logger.Info("Closed remaining connections to removed endpoint", "endpoint", r.addr, "connections", closed)
+		}
+	})
+}
+
+func (tp *tcpproxy) endpointAddresses() []string {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	addrs := make([]string, 0, len(tp.remotes))
+	for _, r := range tp.remotes {
+		addrs = append(addrs, r.addr)
+	}
+	return addrs
+}
+
 func (tp *tcpproxy) pick() *remote {
+	if tp.Strategy == StrategyLeastConnections {
+		return tp.pickLeastConnections()
+	}
+
 	var weighted []*remote
 	var unweighted []*remote
 
@@ -156,32 +331,82 @@ func (tp *tcpproxy) pick() *remote {
 	return nil
 }
 
+// pickLeastConnections picks the active endpoint with the fewest open connections in the best priority class.
+// Ties are broken in a round robin fashion.
+func (tp *tcpproxy) pickLeastConnections() *remote {
+	var picked *remote
+	pickedConns := 0
+	for i := range tp.remotes {
+		r := tp.remotes[(tp.pickCount+i)%len(tp.remotes)]
+		if !r.isActive() {
+			continue
+		}
+		conns := r.connCount()
+		if picked == nil || r.srv.Priority < picked.srv.Priority || (r.srv.Priority == picked.srv.Priority && conns < pickedConns) {
+			picked, pickedConns = r, conns
+		}
+	}
+	tp.pickCount++
+	return picked
+}
+
+// pickFallback picks an endpoint that was not tried yet in a round robin fashion, ignoring whether it is active.
+// It is used when no endpoint is active, e.g. when all endpoints fail their health checks, so that connections are
+// still attempted instead of being refused.
+func (tp *tcpproxy) pickFallback(tried map[*remote]struct{}) *remote {
+	for i := 0; i < len(tp.remotes); i++ {
+		r := tp.remotes[tp.pickCount%len(tp.remotes)]
+		tp.pickCount++
+		if _, ok := tried[r]; !ok {
+			return r
+		}
+	}
+	return nil
+}
+
 func (tp *tcpproxy) serve(in net.Conn) {
 	var (
-		err error
-		out net.Conn
+		err    error
+		out    net.Conn
+		target *remote
 	)
 
+	// tried are the endpoints that failed to accept the connection
+	tried := make(map[*remote]struct{})
 	for {
 		tp.mu.Lock()
-		remote := tp.pick()
+		target = tp.pick()
+		if target == nil {
+			target = tp.pickFallback(tried)
+			if target != nil {
+				tp.Logger.Debug("No active endpoints, trying inactive endpoint", "endpoint", target.addr)
+			}
+		}
+		if target != nil {
+			// track the client connection before dialing, so that concurrent picks see it
+			target.track(in)
+		}
 		tp.mu.Unlock()
-		if remote == nil {
+		if target == nil {
 			break
 		}
 		// TODO: add timeout
-		out, err = net.Dial("tcp", remote.addr)
+		out, err = net.Dial("tcp", target.addr)
+		target.recordDial(err)
 		if err == nil {
 			break
 		}
-		remote.inactivate()
-		log.Printf("deactivated endpoint %v for interval %v, error was %q", remote.addr, tp.MonitorInterval, err)
+		target.untrack(in)
+		tried[target] = struct{}{}
+		target.inactivate()
+		tp.Logger.Warn("Deactivated endpoint", "endpoint", target.addr, "interval", tp.MonitorInterval, "error", err)
 	}
 
 	if out == nil {
 		in.Close()
 		return
 	}
+	defer target.untrack(in)
 
 	go func() {
 		io.Copy(in, out)
@@ -205,9 +430,9 @@ func (tp *tcpproxy) runMonitor() {
 				}
 				go func(r *remote) {
 					if err := r.tryReactivate(); err != nil {
-						log.Printf("failed to activate endpoint %v (stay inactive for another interval %v)\n", r.addr, tp.MonitorInterval)
+						tp.Logger.Debug("Failed to activate endpoint, staying inactive for another interval", "endpoint", r.addr, "interval", tp.MonitorInterval, "error", err)
 					} else {
-						log.Printf("activated endpoint %v\n", r.addr)
+						tp.Logger.Info("Activated endpoint", "endpoint", r.addr)
 					}
 				}(rem)
 			}
@@ -218,6 +443,61 @@ func (tp *tcpproxy) runMonitor() {
 	}
 }
 
+// runHealthChecks checks all endpoints every interval, and activates or deactivates them based on the results.
+func (tp *tcpproxy) runHealthChecks() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-tp.donec
+		cancel()
+	}()
+
+	ticker := time.NewTicker(tp.HealthCheckOptions.Interval)
+	defer ticker.Stop()
+	for {
+		tp.checkRemotes(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkRemotes runs one health check against every endpoint and waits for all of them to complete.
+func (tp *tcpproxy) checkRemotes(ctx context.Context) {
+	tp.mu.Lock()
+	remotes := tp.remotes
+	tp.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, r := range remotes {
+		wg.Add(1)
+		go func(r *remote) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, tp.HealthCheckOptions.Timeout)
+			defer cancel()
+			err := tp.HealthCheck(checkCtx, r.addr)
+			if ctx.Err() != nil {
+				return
+			}
+			if !r.recordHealthCheck(err, tp.HealthCheckOptions.HealthyThreshold, tp.HealthCheckOptions.UnhealthyThreshold) {
+				if err != nil {
+					tp.Logger.Debug("Endpoint health check failed", "endpoint", r.addr, "error", err)
+				}
+				return
+			}
+			if r.isActive() {
+				tp.Logger.Info("Activated endpoint after successful health checks", "endpoint", r.addr)
+			} else {
+				tp.Logger.Warn("Deactivated endpoint after failed health checks", "endpoint", r.addr, "error", err)
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
 func (tp *tcpproxy) Stop() {
 	// graceful shutdown?
 	// shutdown current connections?
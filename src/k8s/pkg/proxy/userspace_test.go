@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+)
+
+// startEchoServer starts a TCP server that prefixes every message with its name.
+func startEchoServer(t *testing.T, name string) *net.SRV {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 1)
+				for {
+					if _, err := conn.Read(buf); err != nil {
+						return
+					}
+					if _, err := conn.Write([]byte(name)); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	addr := l.Addr().(*net.TCPAddr)
+	return &net.SRV{Target: addr.IP.String(), Port: uint16(addr.Port)}
+}
+
+func startTestProxy(t *testing.T, tp *tcpproxy) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	tp.Listener = l
+	tp.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	tp.donec = make(chan struct{})
+	go tp.Run()
+	t.Cleanup(tp.Stop)
+	return l.Addr().String()
+}
+
+// roundTrip sends a byte over conn and returns the name of the server that answered.
+func roundTrip(conn net.Conn) (string, error) {
+	if _, err := conn.Write([]byte("x")); err != nil {
+		return "", err
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 1)
+	if _, err := conn.Read(buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func TestSetEndpoints(t *testing.T) {
+	a, b := startEchoServer(t, "a"), startEchoServer(t, "b")
+	tp := &tcpproxy{Endpoints: []*net.SRV{a}, DrainTimeout: 100 * time.Millisecond}
+	addr := startTestProxy(t, tp)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	if name, err := roundTrip(conn); err != nil || name != "a" {
+		t.Fatalf("expected connection to a but got %q (error %v)", name, err)
+	}
+
+	tp.SetEndpoints([]*net.SRV{b})
+
+	// the listener is kept, new connections go to the new endpoint
+	newConn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to connect to proxy after endpoint update: %v", err)
+	}
+	defer newConn.Close()
+	if name, err := roundTrip(newConn); err != nil || name != "b" {
+		t.Fatalf("expected new connection to b but got %q (error %v)", name, err)
+	}
+
+	// the existing connection keeps working while draining
+	if name, err := roundTrip(conn); err != nil || name != "a" {
+		t.Fatalf("expected draining connection to stay on a but got %q (error %v)", name, err)
+	}
+
+	// and is closed after the drain timeout
+	time.Sleep(200 * time.Millisecond)
+	if _, err := roundTrip(conn); err == nil {
+		t.Fatal("expected draining connection to be closed after the drain timeout")
+	}
+	if name, err := roundTrip(newConn); err != nil || name != "b" {
+		t.Fatalf("expected connection to b to stay open but got %q (error %v)", name, err)
+	}
+}
+
+func TestSetEndpointsKeepsState(t *testing.T) {
+	tp := &tcpproxy{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	a, b := &net.SRV{Target: "10.0.0.1", Port: 6443}, &net.SRV{Target: "10.0.0.2", Port: 6443}
+
+	tp.SetEndpoints([]*net.SRV{a, b})
+	tp.remotes[1].inactivate()
+	inactive := tp.remotes[1]
+
+	tp.SetEndpoints([]*net.SRV{b, {Target: "10.0.0.3", Port: 6443}})
+	if len(tp.remotes) != 2 || tp.remotes[0] != inactive || tp.remotes[0].isActive() {
+		t.Fatalf("expected kept endpoint to retain its state")
+	}
+	if !tp.remotes[1].isActive() {
+		t.Fatalf("expected new endpoint to be active")
+	}
+}
+
+func TestPickLeastConnections(t *testing.T) {
+	tp := &tcpproxy{Strategy: StrategyLeastConnections}
+	for i := 1; i <= 3; i++ {
+		tp.remotes = append(tp.remotes, &remote{srv: &net.SRV{}, addr: fmt.Sprintf("10.0.0.%d:6443", i)})
+	}
+
+	// connections are spread evenly across the endpoints
+	counts := map[string]int{}
+	for i := 0; i < 9; i++ {
+		picked := tp.pick()
+		picked.track(&net.TCPConn{})
+		counts[picked.addr]++
+	}
+	for _, r := range tp.remotes {
+		if counts[r.addr] != 3 {
+			t.Fatalf("expected 3 connections to each endpoint but got %v", counts)
+		}
+	}
+
+	// inactive endpoints are skipped
+	tp.remotes[0].conns = nil
+	tp.remotes[0].inactivate()
+	if picked := tp.pick(); picked == tp.remotes[0] {
+		t.Fatalf("expected inactive endpoint not to be picked")
+	}
+
+	// endpoints in a better priority class are preferred
+	tp.remotes[2].srv = &net.SRV{Priority: 0}
+	tp.remotes[1].srv = &net.SRV{Priority: 1}
+	tp.remotes[2].track(&net.TCPConn{})
+	if picked := tp.pick(); picked != tp.remotes[2] {
+		t.Fatalf("expected endpoint with the best priority to be picked but got %s", picked.addr)
+	}
+}
+
+func TestServeWithoutActiveEndpoints(t *testing.T) {
+	a := startEchoServer(t, "a")
+	tp := &tcpproxy{}
+	tp.SetEndpoints([]*net.SRV{{Target: "127.0.0.1", Port: 1}, a})
+	for _, r := range tp.remotes {
+		r.inactivate()
+	}
+	addr := startTestProxy(t, tp)
+
+	// all endpoints are tried when none is active
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	if name, err := roundTrip(conn); err != nil || name != "a" {
+		t.Fatalf("expected connection to be proxied to a but got %q: %v", name, err)
+	}
+}
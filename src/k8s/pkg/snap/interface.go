@@ -16,9 +16,10 @@ type Snap interface {
 	UID() int // UID is the user ID to set on config files.
 	GID() int // GID is the group ID to set on config files.
 
-	StartService(ctx context.Context, serviceName string) error   // snapctl start $service
-	StopService(ctx context.Context, serviceName string) error    // snapctl stop $service
-	RestartService(ctx context.Context, serviceName string) error // snapctl restart $service
+	StartService(ctx context.Context, serviceName string) error          // snapctl start $service
+	StopService(ctx context.Context, serviceName string) error           // snapctl stop $service
+	RestartService(ctx context.Context, serviceName string) error        // snapctl restart $service
+	ServiceActive(ctx context.Context, serviceName string) (bool, error) // snapctl services $service
 
 	SnapctlGet(ctx context.Context, args ...string) ([]byte, error) // snapctl get $args...
 	SnapctlSet(ctx context.Context, args ...string) error           // snapctl set $args...
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/canonical/k8s/pkg/client/dqlite"
@@ -40,6 +41,7 @@ type Mock struct {
 	HelmClient                  helm.Client
 	K8sDqliteClient             *dqlite.Client
 	SnapctlGet                  map[string][]byte
+	InactiveServices            []string
 }
 
 // Snap is a mock implementation for snap.Snap.
@@ -50,6 +52,8 @@ type Snap struct {
 	StopServiceErr           error
 	RestartServiceCalledWith []string
 	RestartServiceErr        error
+	ServiceActiveCalledWith  []string
+	ServiceActiveErr         error
 
 	SnapctlSetCalledWith [][]string
 	SnapctlSetErr        error
@@ -84,6 +88,14 @@ func (s *Snap) RestartService(ctx context.Context, name string) error {
 	return s.RestartServiceErr
 }
 
+func (s *Snap) ServiceActive(ctx context.Context, name string) (bool, error) {
+	s.ServiceActiveCalledWith = append(s.ServiceActiveCalledWith, name)
+	if s.ServiceActiveErr != nil {
+		return false, s.ServiceActiveErr
+	}
+	return !slices.Contains(s.Mock.InactiveServices, name), nil
+}
+
 func (s *Snap) Strict() bool {
 	return s.Mock.Strict
 }
@@ -12,6 +12,8 @@ type Runner struct {
 	CalledWithCommand []string
 	Err               error
 	Log               bool
+	// Stdout is written to the standard output of the command, if it is captured.
+	Stdout []byte
 }
 
 // Run is a mock implementation of CommandRunner.
@@ -21,5 +23,14 @@ func (m *Runner) Run(ctx context.Context, command []string, opts ...func(*exec.C
 	}
 	m.CalledWithCommand = append(m.CalledWithCommand, strings.Join(command, " "))
 	m.CalledWithCtx = ctx
+	if len(m.Stdout) > 0 {
+		cmd := &exec.Cmd{}
+		for _, o := range opts {
+			o(cmd)
+		}
+		if cmd.Stdout != nil {
+			cmd.Stdout.Write(m.Stdout)
+		}
+	}
 	return m.Err
 }
@@ -69,6 +69,26 @@ func (s *snap) RestartService(ctx context.Context, name string) error {
 	return s.runCommand(ctx, []string{"snapctl", "restart", serviceName(name)})
 }
 
+// ServiceActive returns true if a k8s service is active. The name can be either prefixed or not.
+func (s *snap) ServiceActive(ctx context.Context, name string) (bool, error) {
+	var b bytes.Buffer
+	if err := s.runCommand(ctx, []string{"snapctl", "services", serviceName(name)}, func(c *exec.Cmd) { c.Stdout = &b }); err != nil {
+		return false, err
+	}
+
+	// Service      Startup  Current  Notes
+	// k8s.kubelet  enabled  active   -
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) < 2 {
+		return false, fmt.Errorf("unexpected output of snapctl services: %q", b.String())
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 3 {
+		return false, fmt.Errorf("unexpected output of snapctl services: %q", b.String())
+	}
+	return fields[2] == "active", nil
+}
+
 type snapcraftYml struct {
 	Confinement string `yaml:"confinement"`
 }
@@ -65,4 +65,35 @@ func TestServices(t *testing.T) {
 			g.Expect(err).NotTo(BeNil())
 		})
 	})
+
+	t.Run("ServiceActive", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			stdout string
+			expect bool
+		}{
+			{name: "Active", stdout: "Service      Startup  Current  Notes\nk8s.kubelet  enabled  active   -\n", expect: true},
+			{name: "Inactive", stdout: "Service      Startup   Current   Notes\nk8s.kubelet  disabled  inactive  -\n", expect: false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				g := NewWithT(t)
+				mockRunner := &mock.Runner{Stdout: []byte(tc.stdout)}
+				snap := snap.NewSnap("testdir", "testdir", snap.WithCommandRunner(mockRunner.Run))
+
+				active, err := snap.ServiceActive(context.Background(), "kubelet")
+				g.Expect(err).To(BeNil())
+				g.Expect(active).To(Equal(tc.expect))
+				g.Expect(mockRunner.CalledWithCommand).To(ConsistOf("snapctl services k8s.kubelet"))
+			})
+		}
+
+		t.Run("Fail", func(t *testing.T) {
+			g := NewWithT(t)
+			mockRunner := &mock.Runner{Err: fmt.Errorf("some error")}
+			snap := snap.NewSnap("testdir", "testdir", snap.WithCommandRunner(mockRunner.Run))
+
+			_, err := snap.ServiceActive(context.Background(), "kubelet")
+			g.Expect(err).NotTo(BeNil())
+		})
+	})
 }
@@ -3,8 +3,10 @@ package snaputil
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/canonical/k8s/pkg/snap"
+	"github.com/canonical/k8s/pkg/utils"
 )
 
 var (
@@ -75,3 +77,28 @@ func StopK8sDqliteServices(ctx context.Context, snap snap.Snap) error {
 	}
 	return nil
 }
+
+// ServiceStates returns the state ("active", "inactive" or "unknown") of the k8s services that are expected to run on the node.
+// k8s-dqlite is only included if the node is a member of the k8s-dqlite cluster.
+func ServiceStates(ctx context.Context, snap snap.Snap, worker bool) map[string]string {
+	services := controlPlaneServices
+	if worker {
+		services = workerServices
+	} else if exists, _ := utils.FileExists(snap.K8sDqliteStateDir(), "cluster.yaml"); exists {
+		services = append(slices.Clone(services), "k8s-dqlite")
+	}
+
+	states := make(map[string]string, len(services))
+	for _, service := range services {
+		active, err := snap.ServiceActive(ctx, service)
+		switch {
+		case err != nil:
+			states[service] = "unknown"
+		case active:
+			states[service] = "active"
+		default:
+			states[service] = "inactive"
+		}
+	}
+	return states
+}
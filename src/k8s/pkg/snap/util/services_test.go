@@ -3,6 +3,8 @@ package snaputil
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/canonical/k8s/pkg/snap/mock"
@@ -108,3 +110,36 @@ func TestStopK8sDqliteServices(t *testing.T) {
 		g.Expect(StopK8sDqliteServices(context.Background(), mock)).NotTo(Succeed())
 	})
 }
+
+func TestServiceStates(t *testing.T) {
+	t.Run("Worker", func(t *testing.T) {
+		g := NewWithT(t)
+		mock := &mock.Snap{Mock: mock.Mock{InactiveServices: []string{"kube-proxy"}}}
+
+		states := ServiceStates(context.Background(), mock, true)
+		g.Expect(states).To(HaveLen(len(workerServices)))
+		g.Expect(states).To(HaveKeyWithValue("kubelet", "active"))
+		g.Expect(states).To(HaveKeyWithValue("kube-proxy", "inactive"))
+	})
+
+	t.Run("ControlPlaneWithK8sDqlite", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(dir, "cluster.yaml"), []byte{}, 0600)).To(Succeed())
+		mock := &mock.Snap{Mock: mock.Mock{K8sDqliteStateDir: dir}}
+
+		states := ServiceStates(context.Background(), mock, false)
+		g.Expect(states).To(HaveLen(len(controlPlaneServices) + 1))
+		g.Expect(states).To(HaveKeyWithValue("k8s-dqlite", "active"))
+		g.Expect(states).To(HaveKeyWithValue("kube-apiserver", "active"))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		g := NewWithT(t)
+		mock := &mock.Snap{ServiceActiveErr: fmt.Errorf("snapctl failed")}
+
+		states := ServiceStates(context.Background(), mock, false)
+		g.Expect(states).To(HaveLen(len(controlPlaneServices)))
+		g.Expect(states).To(HaveKeyWithValue("kubelet", "unknown"))
+	})
+}
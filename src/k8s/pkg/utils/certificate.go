@@ -5,7 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 )
@@ -18,7 +18,7 @@ func SplitIPAndDNSSANs(extraSANs []string) ([]net.IP, []string) {
 
 	for _, san := range extraSANs {
 		if san == "" {
-			log.Println("Skipping empty SAN")
+			slog.Debug("Skipping empty SAN")
 			continue
 		}
 
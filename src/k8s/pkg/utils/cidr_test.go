@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"net"
 	"testing"
 
 	"github.com/canonical/k8s/pkg/utils"
@@ -68,3 +69,26 @@ func TestGetKubernetesServiceIPsFromServiceCIDRs(t *testing.T) {
 		}
 	})
 }
+
+func TestCIDRsOverlap(t *testing.T) {
+	for _, tc := range []struct {
+		a, b   string
+		expect bool
+	}{
+		{a: "10.1.0.0/16", b: "10.1.2.0/24", expect: true},
+		{a: "10.1.2.0/24", b: "10.1.0.0/16", expect: true},
+		{a: "10.1.0.0/16", b: "10.1.0.0/16", expect: true},
+		{a: "10.1.0.0/16", b: "10.2.0.0/16", expect: false},
+		{a: "10.1.0.0/16", b: "fd01::/64", expect: false},
+		{a: "fd01::/64", b: "fd01::/48", expect: true},
+	} {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			g := NewWithT(t)
+			_, a, err := net.ParseCIDR(tc.a)
+			g.Expect(err).To(BeNil())
+			_, b, err := net.ParseCIDR(tc.b)
+			g.Expect(err).To(BeNil())
+			g.Expect(utils.CIDRsOverlap(a, b)).To(Equal(tc.expect))
+		})
+	}
+}
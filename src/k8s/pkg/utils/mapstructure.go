@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"unicode"
@@ -27,6 +28,24 @@ func YAMLToStringSliceHookFunc(f reflect.Kind, t reflect.Kind, data interface{})
 	return result, nil
 }
 
+// YAMLToStructSliceHookFunc returns a mapstructure.DecodeHookFunc that converts string to a slice of structs by parsing YAML.
+// YAMLToStructSliceHookFunc fails if the string is not a YAML list of objects.
+func YAMLToStructSliceHookFunc(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Struct {
+		return data, nil
+	}
+
+	result := []map[string]interface{}{}
+	if data.(string) == "" {
+		return result, nil
+	}
+	if err := yaml.Unmarshal([]byte(data.(string)), &result); err != nil {
+		return nil, fmt.Errorf("value is not a YAML list of objects: %w", err)
+	}
+
+	return result, nil
+}
+
 // StringToFieldsSliceHookFunc is like mapstructure.StringToSliceHookFunc() but uses strings.Fields() and filters whitespace.
 func StringToFieldsSliceHookFunc(r rune) mapstructure.DecodeHookFunc {
 	return func(f reflect.Kind, t reflect.Kind, data interface{}) (interface{}, error) {